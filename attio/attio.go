@@ -0,0 +1,175 @@
+// Package attio is a typed Go client for the Attio REST API.
+//
+//	client := attio.New(os.Getenv("ATTIO_API_KEY"), attio.WithRetries(5))
+//	people, err := client.QueryRecords(ctx, "people", attio.Query{Limit: 10})
+//	for _, person := range people {
+//		if name := person.Value("name"); name != nil && name.PersonalName != nil {
+//			fmt.Println(name.PersonalName.FullName)
+//		}
+//	}
+//
+// The models in models_gen.go are generated from the bundled openapi.json.
+// Attribute values are unions: AttributeType names the variant field that is
+// set. Endpoints without a typed method are reachable through Raw and Do.
+package attio
+
+//go:generate go run ../internal/modelgen -o models_gen.go
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/failup-ventures/attio-cli/internal/api"
+)
+
+// Self describes the token a client authenticates with (GET /v2/self).
+type Self = api.Self
+
+// Error is returned for non-2xx API responses.
+type Error = api.AttioError
+
+// Client calls the Attio API. It is safe for concurrent use.
+type Client struct {
+	api *api.Client
+}
+
+type options struct {
+	baseURL    string
+	httpClient *http.Client
+	retries    *int
+	timeout    time.Duration
+	userAgent  string
+	cassette   *api.CassetteTransport
+}
+
+// Option configures a Client.
+type Option func(*options)
+
+// WithBaseURL points the client at another API host, such as a mock server.
+func WithBaseURL(baseURL string) Option {
+	return func(o *options) { o.baseURL = baseURL }
+}
+
+// WithHTTPClient sends requests through httpClient. Retries still wrap its
+// transport.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(o *options) { o.httpClient = httpClient }
+}
+
+// WithRetries sets how many times 429 and 5xx responses are retried. The
+// default is 3; 0 disables retries.
+func WithRetries(retries int) Option {
+	return func(o *options) { o.retries = &retries }
+}
+
+// WithTimeout sets the per-request timeout. The default is 30 seconds.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) { o.timeout = timeout }
+}
+
+// WithUserAgent sets the User-Agent header.
+func WithUserAgent(userAgent string) Option {
+	return func(o *options) { o.userAgent = userAgent }
+}
+
+// Cassette modes for WithCassette.
+const (
+	CassetteRecord = api.CassetteRecord
+	CassetteReplay = api.CassetteReplay
+)
+
+// WithCassette records every request and response to dir as JSON files
+// (CassetteRecord), or replays them from dir without touching the network
+// (CassetteReplay). match lists the request parts a replay compares, any of
+// "method", "path", "query" and "body"; the default is all four. Requests
+// fail with an error for an unknown mode or match key.
+func WithCassette(mode string, dir string, match ...string) Option {
+	return func(o *options) {
+		o.cassette = &api.CassetteTransport{Mode: mode, Dir: dir, Match: match}
+	}
+}
+
+// New returns a client authenticating with apiKey.
+func New(apiKey string, opts ...Option) *Client {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	c := api.NewClient(apiKey, o.baseURL)
+	if o.httpClient != nil {
+		c.SetHTTPClient(o.httpClient)
+	}
+	if o.retries != nil {
+		c.SetMaxRetries(*o.retries)
+	}
+	if o.timeout > 0 {
+		c.SetTimeout(o.timeout)
+	}
+	if o.userAgent != "" {
+		c.SetUserAgent(o.userAgent)
+	}
+	c.SetCassette(o.cassette)
+	return &Client{api: c}
+}
+
+// Self returns the workspace and scopes of the client's token.
+func (c *Client) Self(ctx context.Context) (*Self, error) {
+	return c.api.GetSelf(ctx)
+}
+
+// Raw sends a request to any API path and returns the decoded JSON, or nil
+// for an empty response.
+func (c *Client) Raw(ctx context.Context, method string, path string, body any) (any, error) {
+	return c.api.Raw(ctx, method, path, body)
+}
+
+// Do sends a request to any API path and decodes the response into out.
+func (c *Client) Do(ctx context.Context, method string, path string, body any, out any) error {
+	raw, err := c.api.Raw(ctx, method, path, body)
+	if err != nil || out == nil {
+		return err
+	}
+	return convert(raw, out)
+}
+
+// convert decodes an untyped API response into a model.
+func convert(in any, out any) error {
+	b, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+func one[T any](in map[string]any, err error) (*T, error) {
+	if err != nil {
+		return nil, err
+	}
+	var out T
+	if err := convert(in, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func many[T any](in []map[string]any, err error) ([]T, error) {
+	if err != nil {
+		return nil, err
+	}
+	out := make([]T, 0, len(in))
+	if err := convert(in, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func page[T any](in []map[string]any, next string, err error) ([]T, string, error) {
+	items, err := many[T](in, err)
+	return items, next, err
+}
@@ -0,0 +1,169 @@
+package attio
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/failup-ventures/attio-cli/mockserver"
+)
+
+func newMockClient(t *testing.T, opts ...Option) *Client {
+	t.Helper()
+	handler, err := mockserver.New(nil)
+	if err != nil {
+		t.Fatalf("new mock server: %v", err)
+	}
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return New("test", append([]Option{WithBaseURL(srv.URL)}, opts...)...)
+}
+
+func TestTypedRecordsAndEntries(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := newMockClient(t)
+
+	acme, err := client.CreateRecord(ctx, "companies", map[string]any{"name": "Acme", "categories": []string{"SaaS"}})
+	if err != nil {
+		t.Fatalf("create company: %v", err)
+	}
+	ada, err := client.CreateRecord(ctx, "people", map[string]any{
+		"name":            "Ada Lovelace",
+		"email_addresses": []string{"ada@acme.com"},
+		"company":         acme.ID.RecordID,
+	})
+	if err != nil {
+		t.Fatalf("create person: %v", err)
+	}
+
+	got, err := client.GetRecord(ctx, "people", ada.ID.RecordID)
+	if err != nil {
+		t.Fatalf("get person: %v", err)
+	}
+	if name := got.Value("name"); name == nil || name.PersonalName == nil || name.PersonalName.FullName != "Ada Lovelace" {
+		t.Fatalf("expected a personal-name value, got %+v", got.Values["name"])
+	}
+	if email := got.Value("email_addresses"); email == nil || email.EmailAddress == nil || email.EmailAddress.EmailDomain != "acme.com" {
+		t.Fatalf("expected an email-address value, got %+v", got.Values["email_addresses"])
+	}
+	if company := got.Value("company"); company == nil || company.RecordReference == nil || company.RecordReference.TargetRecordID != acme.ID.RecordID {
+		t.Fatalf("expected a record-reference value, got %+v", got.Values["company"])
+	}
+	if category := acme.Value("categories"); category == nil || category.Select == nil || category.Select.Option.Title != "SaaS" {
+		t.Fatalf("expected a select value, got %+v", acme.Values["categories"])
+	}
+
+	people, err := client.QueryRecords(ctx, "people", Query{Filter: map[string]any{"email_addresses": "ada@acme.com"}})
+	if err != nil || len(people) != 1 || people[0].ID.RecordID != ada.ID.RecordID {
+		t.Fatalf("query people: %v %+v", err, people)
+	}
+
+	// Values survive a round trip through the union's MarshalJSON.
+	b, err := json.Marshal(got.Values["name"][0])
+	if err != nil {
+		t.Fatalf("marshal value: %v", err)
+	}
+	var raw map[string]any
+	_ = json.Unmarshal(b, &raw)
+	if raw["attribute_type"] != "personal-name" || raw["full_name"] != "Ada Lovelace" || raw["active_from"] == "" {
+		t.Fatalf("unexpected marshalled value: %s", b)
+	}
+
+	attrs, err := client.ListAttributes(ctx, "objects", "deals", false)
+	if err != nil || len(attrs) == 0 {
+		t.Fatalf("list deal attributes: %v", err)
+	}
+	statuses, err := client.ListStatuses(ctx, "objects", "deals", "stage", false)
+	if err != nil || len(statuses) == 0 || statuses[0].Title != "Lead" {
+		t.Fatalf("list statuses: %v %+v", err, statuses)
+	}
+	deal, err := client.CreateRecord(ctx, "deals", map[string]any{"name": "Big deal", "stage": "Lead"})
+	if err != nil {
+		t.Fatalf("create deal: %v", err)
+	}
+	if stage := deal.Value("stage"); stage == nil || stage.Status == nil || stage.Status.Status.ID.StatusID != statuses[0].ID.StatusID {
+		t.Fatalf("expected a status value, got %+v", deal.Values["stage"])
+	}
+
+	if _, err := client.Raw(ctx, http.MethodPost, "/v2/lists", map[string]any{"data": map[string]any{
+		"name": "Pipeline", "api_slug": "pipeline", "parent_object": "companies", "workspace_access": "full-access", "workspace_member_access": []any{},
+	}}); err != nil {
+		t.Fatalf("create list: %v", err)
+	}
+	entry, err := client.CreateEntry(ctx, "pipeline", "companies", acme.ID.RecordID, nil)
+	if err != nil || entry.ParentRecordID != acme.ID.RecordID {
+		t.Fatalf("create entry: %v %+v", err, entry)
+	}
+
+	_, err = client.GetRecord(ctx, "people", "00000000-0000-4000-8000-000000000000")
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected a typed 404, got %v", err)
+	}
+}
+
+func TestOptions(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.Header.Get("User-Agent") != "my-app/1.0" || r.Header.Get("X-Test") != "yes" {
+			t.Errorf("unexpected headers: %v", r.Header)
+		}
+		_, _ = w.Write([]byte(`{"active":true,"workspace_name":"Test"}`))
+	}))
+	defer srv.Close()
+
+	httpClient := &http.Client{Transport: headerTransport{}}
+	client := New("test", WithBaseURL(srv.URL), WithHTTPClient(httpClient), WithUserAgent("my-app/1.0"))
+	self, err := client.Self(context.Background())
+	if err != nil || self.WorkspaceName != "Test" || calls.Load() != 2 {
+		t.Fatalf("expected a retried success, got %v %+v after %d calls", err, self, calls.Load())
+	}
+
+	calls.Store(0)
+	client = New("test", WithBaseURL(srv.URL), WithHTTPClient(httpClient), WithUserAgent("my-app/1.0"), WithRetries(0))
+	if _, err := client.Self(context.Background()); err == nil || calls.Load() != 1 {
+		t.Fatalf("expected WithRetries(0) to fail on the first 503, got %v after %d calls", err, calls.Load())
+	}
+}
+
+type headerTransport struct{}
+
+func (headerTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.Header.Set("X-Test", "yes")
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestCassetteOption(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	t.Setenv("ATTIO_HTTP_REPLAY", dir)
+
+	// The SDK ignores the CLI's cassette variables.
+	client := newMockClient(t)
+	if _, err := client.Self(ctx); err != nil {
+		t.Fatalf("self: %v", err)
+	}
+
+	if _, err := newMockClient(t, WithCassette(CassetteRecord, dir)).Self(ctx); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	self, err := New("", WithBaseURL("http://127.0.0.1:1"), WithRetries(0), WithCassette(CassetteReplay, dir, "method", "path")).Self(ctx)
+	if err != nil || self.WorkspaceID == "" {
+		t.Fatalf("expected a replayed self, got %+v (%v)", self, err)
+	}
+	if _, err := New("", WithCassette(CassetteReplay, dir, "url")).Self(ctx); err == nil {
+		t.Fatalf("expected an unknown match key to fail")
+	}
+}
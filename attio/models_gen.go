@@ -0,0 +1,964 @@
+// Code generated by modelgen from openapi.json. DO NOT EDIT.
+
+package attio
+
+import "encoding/json"
+
+// Object is a workspace object such as people, companies or a custom object.
+type Object struct {
+	ID ObjectID `json:"id"`
+	// A unique, human-readable slug to access the object through URLs and API
+	// calls.
+	APISlug *string `json:"api_slug"`
+	// The singular form of the object's name.
+	SingularNoun *string `json:"singular_noun"`
+	// The plural form of the object's name.
+	PluralNoun *string `json:"plural_noun"`
+	// When the object was created.
+	CreatedAt string `json:"created_at"`
+}
+
+// List is a workspace list. Its entries point at records of the parent object.
+type List struct {
+	ID ListID `json:"id"`
+	// A human-readable slug for use in URLs and responses.
+	APISlug string `json:"api_slug"`
+	// The name of the list, as viewed in the UI.
+	Name string `json:"name"`
+	// A UUID or slug to identify the allowed object type for records added to this
+	// list.
+	ParentObject []string `json:"parent_object"`
+	// The level of access granted to all members of the workspace for this list.
+	// One of "full-access", "read-and-write" or "read-only".
+	WorkspaceAccess *string `json:"workspace_access"`
+	// The level of access granted to specific workspace members for this list.
+	WorkspaceMemberAccess []ListWorkspaceMemberAccess `json:"workspace_member_access"`
+	// The actor which created this list.
+	CreatedByActor Actor `json:"created_by_actor"`
+	// When the list was created.
+	CreatedAt string `json:"created_at"`
+}
+
+// Attribute describes one attribute of an object or list.
+type Attribute struct {
+	ID AttributeID `json:"id"`
+	// A title for the attribute, to be displayed across the app.
+	Title string `json:"title"`
+	// A text description of the attribute.
+	Description *string `json:"description"`
+	// A unique slug for the attribute for use in API responses and URLs.
+	APISlug string `json:"api_slug"`
+	// The type of the attribute.
+	Type string `json:"type"`
+	// `true` if this is an Attio system-defined attribute, `false` if defined by a
+	// user or non-Attio system.
+	IsSystemAttribute bool `json:"is_system_attribute"`
+	// Whether or not this attribute can be written to.
+	IsWritable bool `json:"is_writable"`
+	// When `is_required` is `true`, new records/entries must have a value for this
+	// attribute.
+	IsRequired bool `json:"is_required"`
+	// Whether or not new values for this attribute must be unique.
+	IsUnique bool `json:"is_unique"`
+	// Whether or not this attribute can have multiple values.
+	IsMultiselect bool `json:"is_multiselect"`
+	// Whether this attribute has a default value enabled.
+	IsDefaultValueEnabled bool `json:"is_default_value_enabled"`
+	// Whether this attribute has been archived.
+	IsArchived bool `json:"is_archived"`
+	// The default value for this attribute.
+	DefaultValue *DefaultValue `json:"default_value"`
+	// If this attribute is related to another attribute, this is an object that
+	// includes an `id` property that identifies the other attribute.
+	Relationship *AttributeRelationship `json:"relationship"`
+	// When this attribute was created.
+	CreatedAt string `json:"created_at"`
+	// Additional, type-dependent configuration for the attribute.
+	Config AttributeConfig `json:"config"`
+}
+
+// SelectOption is one option of a select attribute.
+type SelectOption struct {
+	ID SelectOptionID `json:"id"`
+	// The title of the select option
+	Title string `json:"title"`
+	// Whether or not to archive the select option.
+	IsArchived bool `json:"is_archived"`
+}
+
+// Status is one status of a status attribute.
+type Status struct {
+	ID StatusID `json:"id"`
+	// The title of the status
+	Title string `json:"title"`
+	// Whether or not to archive the status.
+	IsArchived bool `json:"is_archived"`
+	// Whether arriving at this status triggers a celebration effect in the UI
+	CelebrationEnabled bool `json:"celebration_enabled"`
+	// Target time for a record to spend in given status expressed as a ISO-8601
+	// duration string
+	TargetTimeInStatus *string `json:"target_time_in_status"`
+}
+
+// Record is a record of an object. Values are keyed by attribute slug.
+type Record struct {
+	ID RecordID `json:"id"`
+	// When this record was created.
+	CreatedAt string `json:"created_at"`
+	// A URL that links directly to the record page in the Attio web application.
+	WebURL string `json:"web_url"`
+	// A record type with an attribute `api_slug` as the key, and an array of value
+	// objects as the values.
+	Values map[string][]AttributeValue `json:"values"`
+}
+
+// Entry is a record's entry in a list. EntryValues hold the list's own
+// attributes.
+type Entry struct {
+	ID EntryID `json:"id"`
+	// A UUID identifying the record that is parent of the list entry.
+	ParentRecordID string `json:"parent_record_id"`
+	// A UUID or slug identifying the object that the parent record belongs to.
+	ParentObject string `json:"parent_object"`
+	// When this entry was created.
+	CreatedAt string `json:"created_at"`
+	// A list of attribute values for the list entry (not attribute values for its
+	// parent record).
+	EntryValues map[string][]AttributeValue `json:"entry_values"`
+}
+
+// Note is a note attached to a record.
+type Note struct {
+	ID NoteID `json:"id"`
+	// The slug or ID of the parent object the note belongs to.
+	ParentObject string `json:"parent_object"`
+	// The ID of the parent record the note belongs to.
+	ParentRecordID string `json:"parent_record_id"`
+	// The note title.
+	Title string `json:"title"`
+	// The ID of the meeting associated with this note, or null if no meeting is
+	// associated.
+	MeetingID *string `json:"meeting_id"`
+	// The plaintext representation of the note content.
+	ContentPlaintext string `json:"content_plaintext"`
+	// The markdown representation of the note content.
+	ContentMarkdown string `json:"content_markdown"`
+	// An array of records or workspace members that are @-tagged in the note
+	// content.
+	Tags []NoteTag `json:"tags"`
+	// The actor that created this note.
+	CreatedByActor Actor `json:"created_by_actor"`
+	// When the note was created.
+	CreatedAt string `json:"created_at"`
+}
+
+// Task is a task, optionally linked to records and assigned to members.
+type Task struct {
+	ID TaskID `json:"id"`
+	// The plaintext representation of the task content.
+	ContentPlaintext string `json:"content_plaintext"`
+	// The deadline date of the task.
+	DeadlineAt *string `json:"deadline_at"`
+	// Whether the task has been completed.
+	IsCompleted bool `json:"is_completed"`
+	// Records linked to the task.
+	LinkedRecords []TaskLinkedRecord `json:"linked_records"`
+	// Workspace members assigned to this task.
+	Assignees []ActorReference `json:"assignees"`
+	// The actor that created this task.
+	CreatedByActor Actor `json:"created_by_actor"`
+	// When the task was created.
+	CreatedAt string `json:"created_at"`
+}
+
+// Thread is a comment thread on a record or entry.
+type Thread struct {
+	ID ThreadID `json:"id"`
+	// An array of comments in the thread, sorted by `created_at`.
+	Comments []Comment `json:"comments"`
+	// When the thread was created.
+	CreatedAt string `json:"created_at"`
+}
+
+// Comment is one comment in a thread.
+type Comment struct {
+	ID CommentID `json:"id"`
+	// The ID of the thread the comment belongs to.
+	ThreadID string `json:"thread_id"`
+	// A plaintext representation of the content of the comment.
+	ContentPlaintext string `json:"content_plaintext"`
+	// The entry the comment belongs to, `null` for comments on records.
+	Entry *CommentEntry `json:"entry"`
+	// The record the comment belongs to.
+	Record CommentRecord `json:"record"`
+	// Whether the comment is resolved.
+	ResolvedAt *string `json:"resolved_at"`
+	// The actor that resolved this comment.
+	ResolvedBy Actor `json:"resolved_by"`
+	// When the note was created.
+	CreatedAt string `json:"created_at"`
+	// Who wrote this comment.
+	Author Actor `json:"author"`
+}
+
+// Meeting is a calendar meeting.
+type Meeting struct {
+	ID MeetingID `json:"id"`
+	// The title of the meeting.
+	Title string `json:"title"`
+	// The description of the meeting.
+	Description string `json:"description"`
+	// Whether or not the meeting is an all day event.
+	IsAllDay     bool                 `json:"is_all_day"`
+	Start        MeetingTime          `json:"start"`
+	End          MeetingTime          `json:"end"`
+	Participants []MeetingParticipant `json:"participants"`
+	// A list of records that are linked to the meeting.
+	LinkedRecords []MeetingLinkedRecord `json:"linked_records"`
+	// Timestamp representing when the meeting was created.
+	CreatedAt string `json:"created_at"`
+	// The actor that created this meeting.
+	CreatedByActor Actor `json:"created_by_actor"`
+}
+
+// CallRecording is a recording of a meeting.
+type CallRecording struct {
+	ID CallRecordingID `json:"id"`
+	// The status of the call recording. One of "processing", "completed" or
+	// "failed".
+	Status string `json:"status"`
+	// A URL that links directly to the call recording in the Attio web
+	// application.
+	WebURL string `json:"web_url"`
+	// The actor that created this call recording.
+	CreatedByActor Actor `json:"created_by_actor"`
+	// The timestamp of when the call recording was created.
+	CreatedAt string `json:"created_at"`
+}
+
+// Webhook is a webhook subscription. The signing secret is only returned on
+// create.
+type Webhook struct {
+	// URL where the webhook events will be delivered to.
+	TargetURL string `json:"target_url"`
+	// One or more events the webhook is subscribed to.
+	Subscriptions []WebhookSubscription `json:"subscriptions"`
+	ID            WebhookID             `json:"id"`
+	// The state of the webhook. One of "active", "degraded" or "inactive".
+	Status string `json:"status"`
+	// When the webhook was created.
+	CreatedAt string `json:"created_at"`
+}
+
+// WorkspaceMember is a member of the workspace.
+type WorkspaceMember struct {
+	ID WorkspaceMemberID `json:"id"`
+	// The first name of the user.
+	FirstName string `json:"first_name"`
+	// The last name of the user.
+	LastName string `json:"last_name"`
+	// A URL to the user's avatar image.
+	AvatarURL *string `json:"avatar_url"`
+	// The user's email address.
+	EmailAddress string `json:"email_address"`
+	// When the workspace member was created.
+	CreatedAt string `json:"created_at"`
+	// Whether the workspace member is suspended or not and what level of
+	// privileges they have inside the workspace. One of "admin", "member" or
+	// "suspended".
+	AccessLevel string `json:"access_level"`
+}
+
+// Actor identifies who performed an action: a workspace member, API token, app
+// or the system.
+type Actor struct {
+	// An ID to identify the actor.
+	ID *string `json:"id,omitempty"`
+	// The type of actor. One of "api-token", "workspace-member", "system" or
+	// "app".
+	Type *string `json:"type,omitempty"`
+}
+
+// ActorReference points at a workspace member or other actor.
+type ActorReference struct {
+	// The type of actor. One of "api-token", "workspace-member", "system" or
+	// "app".
+	ReferencedActorType string `json:"referenced_actor_type"`
+	// The ID of the workspace member actor assigned to this task.
+	ReferencedActorID string `json:"referenced_actor_id"`
+}
+
+// ActorReferenceValue holds the fields of an AttributeValue with attribute_type
+// "actor-reference".
+type ActorReferenceValue struct {
+	// The type of the referenced actor. One of "api-token", "workspace-member",
+	// "system" or "app".
+	ReferencedActorType string `json:"referenced_actor_type"`
+	// The ID of the referenced actor.
+	ReferencedActorID *string `json:"referenced_actor_id"`
+}
+
+// AttributeConfig is the config of an Attribute.
+type AttributeConfig struct {
+	// Configuration available for attributes of type "currency".
+	Currency AttributeConfigCurrency `json:"currency"`
+	// Configuration available for attributes of type "record-reference".
+	RecordReference AttributeConfigRecordReference `json:"record_reference"`
+}
+
+// AttributeConfigCurrency is the currency of an AttributeConfig.
+type AttributeConfigCurrency struct {
+	// The ISO4217 code representing the currency that values for this attribute
+	// should be stored in.
+	DefaultCurrencyCode *string `json:"default_currency_code"`
+	// How the currency should be displayed across the app. One of "code", "name",
+	// "narrowSymbol" or "symbol".
+	DisplayType *string `json:"display_type"`
+}
+
+// AttributeConfigRecordReference is the record_reference of an AttributeConfig.
+type AttributeConfigRecordReference struct {
+	// A list of UUIDs to indicate which objects records are allowed to belong to.
+	AllowedObjectIDs []string `json:"allowed_object_ids"`
+}
+
+// AttributeID is the id of an Attribute.
+type AttributeID struct {
+	// A UUID representing the workspace this attribute belongs to.
+	WorkspaceID string `json:"workspace_id"`
+	// A UUID to identify the object or list that this attribute belongs to
+	ObjectID string `json:"object_id"`
+	// A UUID to identify this attribute.
+	AttributeID string `json:"attribute_id"`
+}
+
+// AttributeRelationship is the relationship of an Attribute.
+type AttributeRelationship struct {
+	ID AttributeID `json:"id"`
+	// The slug of the object that the related attribute belongs to.
+	ObjectSlug string `json:"object_slug"`
+	// The title of the related attribute.
+	Title string `json:"title"`
+	// The API slug identifying the related attribute.
+	APISlug string `json:"api_slug"`
+	// Whether the related attribute supports selecting multiple values.
+	IsMultiselect bool `json:"is_multiselect"`
+}
+
+// AttributeValue is one value of a record or entry attribute. AttributeType
+// says which variant field is set.
+type AttributeValue struct {
+	// The point in time at which this value was made "active".
+	ActiveFrom string `json:"active_from"`
+	// The point in time at which this value was deactivated.
+	ActiveUntil *string `json:"active_until"`
+	// The actor that created this value.
+	CreatedByActor Actor `json:"created_by_actor"`
+	// The attribute type of the value.
+	AttributeType string `json:"attribute_type"`
+
+	ActorReference  *ActorReferenceValue  `json:"-"`
+	Checkbox        *CheckboxValue        `json:"-"`
+	Currency        *CurrencyValue        `json:"-"`
+	Date            *DateValue            `json:"-"`
+	Domain          *DomainValue          `json:"-"`
+	EmailAddress    *EmailAddressValue    `json:"-"`
+	RecordReference *RecordReferenceValue `json:"-"`
+	Interaction     *InteractionValue     `json:"-"`
+	Location        *LocationValue        `json:"-"`
+	Number          *NumberValue          `json:"-"`
+	PersonalName    *PersonalNameValue    `json:"-"`
+	PhoneNumber     *PhoneNumberValue     `json:"-"`
+	Status          *StatusValue          `json:"-"`
+	Rating          *RatingValue          `json:"-"`
+	Select          *SelectValue          `json:"-"`
+	Text            *TextValue            `json:"-"`
+	Timestamp       *TimestampValue       `json:"-"`
+}
+
+// UnmarshalJSON decodes the shared fields and the variant named by AttributeType.
+func (v *AttributeValue) UnmarshalJSON(data []byte) error {
+	type plain AttributeValue
+	if err := json.Unmarshal(data, (*plain)(v)); err != nil {
+		return err
+	}
+	switch v.AttributeType {
+	case "actor-reference":
+		v.ActorReference = new(ActorReferenceValue)
+		return json.Unmarshal(data, v.ActorReference)
+	case "checkbox":
+		v.Checkbox = new(CheckboxValue)
+		return json.Unmarshal(data, v.Checkbox)
+	case "currency":
+		v.Currency = new(CurrencyValue)
+		return json.Unmarshal(data, v.Currency)
+	case "date":
+		v.Date = new(DateValue)
+		return json.Unmarshal(data, v.Date)
+	case "domain":
+		v.Domain = new(DomainValue)
+		return json.Unmarshal(data, v.Domain)
+	case "email-address":
+		v.EmailAddress = new(EmailAddressValue)
+		return json.Unmarshal(data, v.EmailAddress)
+	case "record-reference":
+		v.RecordReference = new(RecordReferenceValue)
+		return json.Unmarshal(data, v.RecordReference)
+	case "interaction":
+		v.Interaction = new(InteractionValue)
+		return json.Unmarshal(data, v.Interaction)
+	case "location":
+		v.Location = new(LocationValue)
+		return json.Unmarshal(data, v.Location)
+	case "number":
+		v.Number = new(NumberValue)
+		return json.Unmarshal(data, v.Number)
+	case "personal-name":
+		v.PersonalName = new(PersonalNameValue)
+		return json.Unmarshal(data, v.PersonalName)
+	case "phone-number":
+		v.PhoneNumber = new(PhoneNumberValue)
+		return json.Unmarshal(data, v.PhoneNumber)
+	case "status":
+		v.Status = new(StatusValue)
+		return json.Unmarshal(data, v.Status)
+	case "rating":
+		v.Rating = new(RatingValue)
+		return json.Unmarshal(data, v.Rating)
+	case "select":
+		v.Select = new(SelectValue)
+		return json.Unmarshal(data, v.Select)
+	case "text":
+		v.Text = new(TextValue)
+		return json.Unmarshal(data, v.Text)
+	case "timestamp":
+		v.Timestamp = new(TimestampValue)
+		return json.Unmarshal(data, v.Timestamp)
+	}
+	return nil
+}
+
+// MarshalJSON writes the shared fields and the set variant as one object.
+func (v AttributeValue) MarshalJSON() ([]byte, error) {
+	type plain AttributeValue
+	var variant any
+	switch {
+	case v.ActorReference != nil:
+		variant = v.ActorReference
+	case v.Checkbox != nil:
+		variant = v.Checkbox
+	case v.Currency != nil:
+		variant = v.Currency
+	case v.Date != nil:
+		variant = v.Date
+	case v.Domain != nil:
+		variant = v.Domain
+	case v.EmailAddress != nil:
+		variant = v.EmailAddress
+	case v.RecordReference != nil:
+		variant = v.RecordReference
+	case v.Interaction != nil:
+		variant = v.Interaction
+	case v.Location != nil:
+		variant = v.Location
+	case v.Number != nil:
+		variant = v.Number
+	case v.PersonalName != nil:
+		variant = v.PersonalName
+	case v.PhoneNumber != nil:
+		variant = v.PhoneNumber
+	case v.Status != nil:
+		variant = v.Status
+	case v.Rating != nil:
+		variant = v.Rating
+	case v.Select != nil:
+		variant = v.Select
+	case v.Text != nil:
+		variant = v.Text
+	case v.Timestamp != nil:
+		variant = v.Timestamp
+	}
+	return mergeJSON(plain(v), variant)
+}
+
+// CallRecordingID is the id of a CallRecording.
+type CallRecordingID struct {
+	// The ID of the workspace this call recording belongs to.
+	WorkspaceID string `json:"workspace_id"`
+	// The ID of the meeting associated with this call recording.
+	MeetingID string `json:"meeting_id"`
+	// The call recording ID of the call recording.
+	CallRecordingID string `json:"call_recording_id"`
+}
+
+// CheckboxValue holds the fields of an AttributeValue with attribute_type
+// "checkbox".
+type CheckboxValue struct {
+	// A boolean representing whether the checkbox is checked or not.
+	Value bool `json:"value"`
+}
+
+// CommentEntry is the entry of a Comment.
+type CommentEntry struct {
+	// The ID of the entry the comment belongs to.
+	EntryID string `json:"entry_id"`
+	// The ID of the list the entry belongs to.
+	ListID string `json:"list_id"`
+}
+
+// CommentID is the id of a Comment.
+type CommentID struct {
+	// The ID of the workspace the comment belongs to.
+	WorkspaceID string `json:"workspace_id"`
+	// The ID of the comment.
+	CommentID string `json:"comment_id"`
+}
+
+// CommentRecord is the record of a Comment.
+type CommentRecord struct {
+	// The ID of the record the comment belongs to.
+	RecordID string `json:"record_id"`
+	// The ID of the object the record belongs to.
+	ObjectID string `json:"object_id"`
+}
+
+// CurrencyValue holds the fields of an AttributeValue with attribute_type
+// "currency".
+type CurrencyValue struct {
+	// A numerical representation of the currency value.
+	CurrencyValue float64 `json:"currency_value"`
+	// The ISO4217 currency code representing the currency that the value is stored
+	// in.
+	CurrencyCode *string `json:"currency_code,omitempty"`
+}
+
+// DateValue holds the fields of an AttributeValue with attribute_type "date".
+type DateValue struct {
+	// A date represents a single calendar year, month and day, independent of
+	// timezone.
+	Value string `json:"value"`
+}
+
+// DefaultValue is the value an attribute takes when none is given. Type says
+// which variant field is set.
+type DefaultValue struct {
+	Type string `json:"type"`
+
+	Dynamic *DynamicValue `json:"-"`
+	Static  *StaticValue  `json:"-"`
+}
+
+// UnmarshalJSON decodes the shared fields and the variant named by Type.
+func (v *DefaultValue) UnmarshalJSON(data []byte) error {
+	type plain DefaultValue
+	if err := json.Unmarshal(data, (*plain)(v)); err != nil {
+		return err
+	}
+	switch v.Type {
+	case "dynamic":
+		v.Dynamic = new(DynamicValue)
+		return json.Unmarshal(data, v.Dynamic)
+	case "static":
+		v.Static = new(StaticValue)
+		return json.Unmarshal(data, v.Static)
+	}
+	return nil
+}
+
+// MarshalJSON writes the shared fields and the set variant as one object.
+func (v DefaultValue) MarshalJSON() ([]byte, error) {
+	type plain DefaultValue
+	var variant any
+	switch {
+	case v.Dynamic != nil:
+		variant = v.Dynamic
+	case v.Static != nil:
+		variant = v.Static
+	}
+	return mergeJSON(plain(v), variant)
+}
+
+// DomainValue holds the fields of an AttributeValue with attribute_type
+// "domain".
+type DomainValue struct {
+	Domain     string `json:"domain"`
+	RootDomain string `json:"root_domain"`
+}
+
+// DynamicValue holds the fields of a DefaultValue with type "dynamic".
+type DynamicValue struct {
+	Template string `json:"template"`
+}
+
+// EmailAddressValue holds the fields of an AttributeValue with attribute_type
+// "email-address".
+type EmailAddressValue struct {
+	OriginalEmailAddress string `json:"original_email_address"`
+	EmailAddress         string `json:"email_address"`
+	EmailDomain          string `json:"email_domain"`
+	EmailRootDomain      string `json:"email_root_domain"`
+	EmailLocalSpecifier  string `json:"email_local_specifier"`
+}
+
+// EntryID is the id of an Entry.
+type EntryID struct {
+	// A UUID identifying the workspace this entry belongs to.
+	WorkspaceID string `json:"workspace_id"`
+	// A UUID identifying the list this entry is in.
+	ListID string `json:"list_id"`
+	// A UUID identifying this entry.
+	EntryID string `json:"entry_id"`
+}
+
+// InteractionValue holds the fields of an AttributeValue with attribute_type
+// "interaction".
+type InteractionValue struct {
+	// The type of interaction e.g. One of "calendar-event", "call", "chat-thread",
+	// "email", "in-person-meeting" or "meeting".
+	InteractionType string `json:"interaction_type"`
+	// When the interaction occurred.
+	InteractedAt string `json:"interacted_at"`
+	// The actor that created this value.
+	OwnerActor Actor `json:"owner_actor"`
+}
+
+// ListID is the id of a List.
+type ListID struct {
+	// A UUID to identify the workspace this list belongs to.
+	WorkspaceID string `json:"workspace_id"`
+	// A UUID to identify this list.
+	ListID string `json:"list_id"`
+}
+
+// ListWorkspaceMemberAccess is one of the workspace_member_access of a List.
+type ListWorkspaceMemberAccess struct {
+	// A UUID to identify the workspace member to grant access to.
+	WorkspaceMemberID string `json:"workspace_member_id"`
+	// The level of access to the list. One of "full-access", "read-and-write" or
+	// "read-only".
+	Level string `json:"level"`
+}
+
+// LocationValue holds the fields of an AttributeValue with attribute_type
+// "location".
+type LocationValue struct {
+	// The first line of the address.
+	Line1 *string `json:"line_1"`
+	// The second line of the address.
+	Line2 *string `json:"line_2"`
+	// The third line of the address.
+	Line3 *string `json:"line_3"`
+	// The fourth line of the address.
+	Line4 *string `json:"line_4"`
+	// The town, neighborhood or area the location is in.
+	Locality *string `json:"locality"`
+	// The state, county, province or region that the location is in.
+	Region *string `json:"region"`
+	// The postcode or zip code for the location.
+	Postcode *string `json:"postcode"`
+	// The ISO 3166-1 alpha-2 country code for the country this location is in.
+	CountryCode *string `json:"country_code"`
+	// The latitude of the location.
+	Latitude *string `json:"latitude"`
+	// The longitude of the location.
+	Longitude *string `json:"longitude"`
+}
+
+// MeetingID is the id of a Meeting.
+type MeetingID struct {
+	// The ID of the workspace the meeting belongs to.
+	WorkspaceID string `json:"workspace_id"`
+	// The ID of the Attio meeting.
+	MeetingID string `json:"meeting_id"`
+}
+
+// MeetingLinkedRecord is one of the linked_records of a Meeting.
+type MeetingLinkedRecord struct {
+	// The slug of the object the meeting linked record belongs to.
+	ObjectSlug string `json:"object_slug"`
+	// The ID of the object the meeting linked record belongs to.
+	ObjectID string `json:"object_id"`
+	// The ID of the meeting linked record.
+	RecordID string `json:"record_id"`
+}
+
+// MeetingParticipant is one of the participants of a Meeting.
+type MeetingParticipant struct {
+	// The status of the individual meeting participant. One of "accepted",
+	// "tentative", "declined" or "pending".
+	Status string `json:"status"`
+	// Whether or not the participant is the organizer of the meeting.
+	IsOrganizer bool `json:"is_organizer"`
+	// The normalized email address of the meeting participant.
+	EmailAddress *string `json:"email_address"`
+}
+
+// MeetingTime is a datetime with timezone, or a date for all-day meetings.
+type MeetingTime struct {
+	// If a non-all day event, a datetime representing when the meeting starts.
+	Datetime string `json:"datetime,omitempty"`
+	// The IANA timezone in which the meeting starts, if available.
+	Timezone *string `json:"timezone,omitempty"`
+	// If an all day event, a date representing when the meeting starts.
+	Date string `json:"date,omitempty"`
+}
+
+// NoteID is the id of a Note.
+type NoteID struct {
+	// The ID of the workspace the note belongs to.
+	WorkspaceID string `json:"workspace_id"`
+	// The ID of the note.
+	NoteID string `json:"note_id"`
+}
+
+// NoteTag is one of the tags of a Note.
+type NoteTag struct {
+	// The type of entity tagged in the note.
+	Type string `json:"type"`
+
+	WorkspaceMember *WorkspaceMemberTag `json:"-"`
+	Record          *RecordTag          `json:"-"`
+}
+
+// UnmarshalJSON decodes the shared fields and the variant named by Type.
+func (v *NoteTag) UnmarshalJSON(data []byte) error {
+	type plain NoteTag
+	if err := json.Unmarshal(data, (*plain)(v)); err != nil {
+		return err
+	}
+	switch v.Type {
+	case "workspace-member":
+		v.WorkspaceMember = new(WorkspaceMemberTag)
+		return json.Unmarshal(data, v.WorkspaceMember)
+	case "record":
+		v.Record = new(RecordTag)
+		return json.Unmarshal(data, v.Record)
+	}
+	return nil
+}
+
+// MarshalJSON writes the shared fields and the set variant as one object.
+func (v NoteTag) MarshalJSON() ([]byte, error) {
+	type plain NoteTag
+	var variant any
+	switch {
+	case v.WorkspaceMember != nil:
+		variant = v.WorkspaceMember
+	case v.Record != nil:
+		variant = v.Record
+	}
+	return mergeJSON(plain(v), variant)
+}
+
+// NumberValue holds the fields of an AttributeValue with attribute_type
+// "number".
+type NumberValue struct {
+	// Numbers are persisted as 64 bit floats.
+	Value float64 `json:"value"`
+}
+
+// ObjectID is the id of an Object.
+type ObjectID struct {
+	// A UUID to identify the workspace this object belongs to.
+	WorkspaceID string `json:"workspace_id"`
+	// A UUID to identify the object.
+	ObjectID string `json:"object_id"`
+}
+
+// PersonalNameValue holds the fields of an AttributeValue with attribute_type
+// "personal-name".
+type PersonalNameValue struct {
+	// The first name.
+	FirstName string `json:"first_name"`
+	// The last name.
+	LastName string `json:"last_name"`
+	// The full name.
+	FullName string `json:"full_name"`
+}
+
+// PhoneNumberValue holds the fields of an AttributeValue with attribute_type
+// "phone-number".
+type PhoneNumberValue struct {
+	// The raw, original phone number, as inputted.
+	OriginalPhoneNumber string `json:"original_phone_number"`
+	// The ISO 3166-1 alpha-2 country code representing the country that this phone
+	// number belongs to.
+	CountryCode string `json:"country_code"`
+	PhoneNumber string `json:"phone_number"`
+}
+
+// RatingValue holds the fields of an AttributeValue with attribute_type
+// "rating".
+type RatingValue struct {
+	// A number between 0 and 5 (inclusive) to represent a star rating.
+	Value float64 `json:"value"`
+}
+
+// RecordID is the id of a Record.
+type RecordID struct {
+	// A UUID identifying the workspace this record belongs to.
+	WorkspaceID string `json:"workspace_id"`
+	// A UUID identifying the object this record belongs to.
+	ObjectID string `json:"object_id"`
+	// A UUID identifying this record.
+	RecordID string `json:"record_id"`
+}
+
+// RecordReferenceValue holds the fields of an AttributeValue with
+// attribute_type "record-reference".
+type RecordReferenceValue struct {
+	// A slug identifying the object that the referenced record belongs to.
+	TargetObject string `json:"target_object"`
+	// A UUID to identify the referenced record.
+	TargetRecordID string `json:"target_record_id"`
+}
+
+// RecordTag holds the fields of a NoteTag with type "record".
+type RecordTag struct {
+	// The slug or ID of the object that the tagged record belongs to.
+	Object string `json:"object"`
+	// The ID of the record that is tagged in the note.
+	RecordID string `json:"record_id"`
+}
+
+// SelectOptionID is the id of a SelectOption.
+type SelectOptionID struct {
+	// The ID of the workspace
+	WorkspaceID string `json:"workspace_id"`
+	// The ID of the object
+	ObjectID string `json:"object_id"`
+	// The ID of the attribute
+	AttributeID string `json:"attribute_id"`
+	// The ID of the select option
+	OptionID string `json:"option_id"`
+}
+
+// SelectValue holds the fields of an AttributeValue with attribute_type
+// "select".
+type SelectValue struct {
+	Option SelectOption `json:"option"`
+}
+
+// StaticValue holds the fields of a DefaultValue with type "static".
+type StaticValue struct {
+	Template []AttributeValue `json:"template"`
+}
+
+// StatusID is the id of a Status.
+type StatusID struct {
+	// The ID of the workspace
+	WorkspaceID string `json:"workspace_id"`
+	// The ID of the object
+	ObjectID string `json:"object_id"`
+	// The ID of the attribute
+	AttributeID string `json:"attribute_id"`
+	// The ID of the status
+	StatusID string `json:"status_id"`
+}
+
+// StatusValue holds the fields of an AttributeValue with attribute_type
+// "status".
+type StatusValue struct {
+	Status Status `json:"status"`
+}
+
+// TaskID is the id of a Task.
+type TaskID struct {
+	// The ID of the workspace the task belongs to.
+	WorkspaceID string `json:"workspace_id"`
+	// The ID of the task.
+	TaskID string `json:"task_id"`
+}
+
+// TaskLinkedRecord is one of the linked_records of a Task.
+type TaskLinkedRecord struct {
+	// The ID of the parent object the task refers to.
+	TargetObjectID string `json:"target_object_id"`
+	// The ID of the parent record the task refers to.
+	TargetRecordID string `json:"target_record_id"`
+}
+
+// TextValue holds the fields of an AttributeValue with attribute_type "text".
+type TextValue struct {
+	// A raw text field.
+	Value string `json:"value"`
+}
+
+// ThreadID is the id of a Thread.
+type ThreadID struct {
+	// The ID of the workspace the thread belongs to.
+	WorkspaceID string `json:"workspace_id"`
+	// The ID of the thread.
+	ThreadID string `json:"thread_id"`
+}
+
+// TimestampValue holds the fields of an AttributeValue with attribute_type
+// "timestamp".
+type TimestampValue struct {
+	// A timestamp value represents a single, universal moment in time using an ISO
+	// 8601 formatted string.
+	Value string `json:"value"`
+}
+
+// WebhookFilterCondition compares one event field with a value.
+type WebhookFilterCondition struct {
+	Field    string `json:"field"`
+	Operator string `json:"operator"`
+	Value    string `json:"value"`
+}
+
+// WebhookID is the id of a Webhook.
+type WebhookID struct {
+	// The ID of the workspace the webhook belongs to.
+	WorkspaceID string `json:"workspace_id"`
+	// The ID of the webhook.
+	WebhookID string `json:"webhook_id"`
+}
+
+// WebhookSubscription is one of the subscriptions of a Webhook.
+type WebhookSubscription struct {
+	// Type of event the webhook is subscribed to.
+	EventType string `json:"event_type"`
+	// Filters to determine whether the webhook event should be sent.
+	Filter *WebhookSubscriptionFilter `json:"filter"`
+}
+
+// WebhookSubscriptionFilter is the filter of a WebhookSubscription.
+type WebhookSubscriptionFilter struct {
+	Or  []WebhookFilterCondition `json:"$or,omitempty"`
+	And []WebhookFilterCondition `json:"$and,omitempty"`
+}
+
+// WorkspaceMemberID is the id of a WorkspaceMember.
+type WorkspaceMemberID struct {
+	// The ID of the workspace the workspace member belongs to.
+	WorkspaceID string `json:"workspace_id"`
+	// The ID of the workspace member.
+	WorkspaceMemberID string `json:"workspace_member_id"`
+}
+
+// WorkspaceMemberTag holds the fields of a NoteTag with type
+// "workspace-member".
+type WorkspaceMemberTag struct {
+	// The ID of the workspace member that is tagged in the note.
+	WorkspaceMemberID string `json:"workspace_member_id"`
+}
+
+// mergeJSON marshals base and extra and joins them into a single object.
+func mergeJSON(base any, extra any) ([]byte, error) {
+	out, err := json.Marshal(base)
+	if err != nil || extra == nil {
+		return out, err
+	}
+	more, err := json.Marshal(extra)
+	if err != nil {
+		return nil, err
+	}
+	if len(more) <= 2 {
+		return out, nil
+	}
+	if len(out) <= 2 {
+		return more, nil
+	}
+	return append(append(out[:len(out)-1], ','), more[1:]...), nil
+}
@@ -0,0 +1,116 @@
+package attio
+
+import "context"
+
+// Query filters and pages QueryRecords and QueryEntries. Filter and Sorts
+// take the API's JSON shapes; zero Limit and Offset use the API defaults.
+type Query struct {
+	Filter any
+	Sorts  any
+	Limit  int
+	Offset int
+}
+
+// Value returns the first active value of attribute, or nil when it has none.
+func (r *Record) Value(attribute string) *AttributeValue {
+	return activeValue(r.Values[attribute])
+}
+
+// Value returns the first active value of a list attribute, or nil when it
+// has none.
+func (e *Entry) Value(attribute string) *AttributeValue {
+	return activeValue(e.EntryValues[attribute])
+}
+
+func activeValue(values []AttributeValue) *AttributeValue {
+	for i := range values {
+		if values[i].ActiveUntil == nil {
+			return &values[i]
+		}
+	}
+	return nil
+}
+
+// QueryRecords returns one page of object's records matching q.
+func (c *Client) QueryRecords(ctx context.Context, object string, q Query) ([]Record, error) {
+	return many[Record](c.api.QueryRecords(ctx, object, q.Filter, q.Sorts, q.Limit, q.Offset))
+}
+
+func (c *Client) GetRecord(ctx context.Context, object string, recordID string) (*Record, error) {
+	return one[Record](c.api.GetRecord(ctx, object, recordID))
+}
+
+// CreateRecord creates a record. values are keyed by attribute slug and use
+// the API's input value shapes.
+func (c *Client) CreateRecord(ctx context.Context, object string, values map[string]any) (*Record, error) {
+	return one[Record](c.api.CreateRecord(ctx, object, map[string]any{"values": values}))
+}
+
+// AssertRecord updates the record whose unique matchingAttribute equals the
+// one in values, or creates it when there is none.
+func (c *Client) AssertRecord(ctx context.Context, object string, matchingAttribute string, values map[string]any) (*Record, error) {
+	return one[Record](c.api.AssertRecord(ctx, object, matchingAttribute, map[string]any{"values": values}))
+}
+
+// UpdateRecord writes values, appending to multiselect attributes.
+func (c *Client) UpdateRecord(ctx context.Context, object string, recordID string, values map[string]any) (*Record, error) {
+	return one[Record](c.api.UpdateRecord(ctx, object, recordID, map[string]any{"values": values}))
+}
+
+// ReplaceRecord writes values, replacing multiselect attributes.
+func (c *Client) ReplaceRecord(ctx context.Context, object string, recordID string, values map[string]any) (*Record, error) {
+	return one[Record](c.api.ReplaceRecord(ctx, object, recordID, map[string]any{"values": values}))
+}
+
+func (c *Client) DeleteRecord(ctx context.Context, object string, recordID string) error {
+	return c.api.DeleteRecord(ctx, object, recordID)
+}
+
+// RecordAttributeValues returns attribute's values on a record, including
+// replaced ones when showHistoric is set.
+func (c *Client) RecordAttributeValues(ctx context.Context, object string, recordID string, attribute string, showHistoric bool) ([]AttributeValue, error) {
+	return many[AttributeValue](c.api.ListRecordAttributeValues(ctx, object, recordID, attribute, showHistoric, 0, 0))
+}
+
+// QueryEntries returns one page of list's entries matching q.
+func (c *Client) QueryEntries(ctx context.Context, list string, q Query) ([]Entry, error) {
+	return many[Entry](c.api.QueryEntries(ctx, list, q.Filter, q.Sorts, q.Limit, q.Offset))
+}
+
+func (c *Client) GetEntry(ctx context.Context, list string, entryID string) (*Entry, error) {
+	return one[Entry](c.api.GetEntry(ctx, list, entryID))
+}
+
+// CreateEntry adds a record to list with the given list attribute values.
+func (c *Client) CreateEntry(ctx context.Context, list string, parentObject string, parentRecordID string, values map[string]any) (*Entry, error) {
+	if values == nil {
+		values = map[string]any{}
+	}
+	return one[Entry](c.api.CreateEntry(ctx, list, map[string]any{
+		"parent_object":    parentObject,
+		"parent_record_id": parentRecordID,
+		"entry_values":     values,
+	}))
+}
+
+// UpdateEntry writes list attribute values, appending to multiselect
+// attributes.
+func (c *Client) UpdateEntry(ctx context.Context, list string, entryID string, values map[string]any) (*Entry, error) {
+	return one[Entry](c.api.UpdateEntry(ctx, list, entryID, map[string]any{"entry_values": values}))
+}
+
+// ReplaceEntry writes list attribute values, replacing multiselect
+// attributes.
+func (c *Client) ReplaceEntry(ctx context.Context, list string, entryID string, values map[string]any) (*Entry, error) {
+	return one[Entry](c.api.ReplaceEntry(ctx, list, entryID, map[string]any{"entry_values": values}))
+}
+
+func (c *Client) DeleteEntry(ctx context.Context, list string, entryID string) error {
+	return c.api.DeleteEntry(ctx, list, entryID)
+}
+
+// EntryAttributeValues returns attribute's values on an entry, including
+// replaced ones when showHistoric is set.
+func (c *Client) EntryAttributeValues(ctx context.Context, list string, entryID string, attribute string, showHistoric bool) ([]AttributeValue, error) {
+	return many[AttributeValue](c.api.ListEntryAttributeValues(ctx, list, entryID, attribute, showHistoric, 0, 0))
+}
@@ -0,0 +1,112 @@
+package attio
+
+import "context"
+
+// NoteQuery filters ListNotes. Leave the parent fields empty for all notes.
+type NoteQuery struct {
+	ParentObject   string
+	ParentRecordID string
+	Limit          int
+	Offset         int
+}
+
+func (c *Client) ListNotes(ctx context.Context, q NoteQuery) ([]Note, error) {
+	return many[Note](c.api.ListNotes(ctx, q.ParentObject, q.ParentRecordID, q.Limit, q.Offset))
+}
+
+func (c *Client) GetNote(ctx context.Context, noteID string) (*Note, error) {
+	return one[Note](c.api.GetNote(ctx, noteID))
+}
+
+// TaskQuery filters ListTasks. Sort is "created_at:asc" or
+// "created_at:desc"; a nil IsCompleted returns both states.
+type TaskQuery struct {
+	LinkedObject   string
+	LinkedRecordID string
+	Assignee       string
+	IsCompleted    *bool
+	Sort           string
+	Limit          int
+	Offset         int
+}
+
+func (c *Client) ListTasks(ctx context.Context, q TaskQuery) ([]Task, error) {
+	return many[Task](c.api.ListTasks(ctx, q.Limit, q.Offset, q.Sort, q.LinkedObject, q.LinkedRecordID, q.Assignee, q.IsCompleted))
+}
+
+func (c *Client) GetTask(ctx context.Context, taskID string) (*Task, error) {
+	return one[Task](c.api.GetTask(ctx, taskID))
+}
+
+// ThreadQuery selects the threads of one record (Object and RecordID) or one
+// entry (List and EntryID).
+type ThreadQuery struct {
+	Object   string
+	RecordID string
+	List     string
+	EntryID  string
+	Limit    int
+	Offset   int
+}
+
+func (c *Client) ListThreads(ctx context.Context, q ThreadQuery) ([]Thread, error) {
+	return many[Thread](c.api.ListThreads(ctx, q.Object, q.RecordID, q.List, q.EntryID, q.Limit, q.Offset))
+}
+
+func (c *Client) GetThread(ctx context.Context, threadID string) (*Thread, error) {
+	return one[Thread](c.api.GetThread(ctx, threadID))
+}
+
+func (c *Client) GetComment(ctx context.Context, commentID string) (*Comment, error) {
+	return one[Comment](c.api.GetComment(ctx, commentID))
+}
+
+// MeetingQuery filters ListMeetings. LinkedObject and LinkedRecordID go
+// together; Sort is "start_asc" or "start_desc".
+type MeetingQuery struct {
+	Participants   string
+	LinkedObject   string
+	LinkedRecordID string
+	EndsFrom       string
+	StartsBefore   string
+	Timezone       string
+	Sort           string
+	Limit          int
+	Cursor         string
+}
+
+// ListMeetings returns one page of meetings and the cursor of the next page,
+// which is empty on the last page.
+func (c *Client) ListMeetings(ctx context.Context, q MeetingQuery) ([]Meeting, string, error) {
+	return page[Meeting](c.api.ListMeetings(ctx, q.Limit, q.Cursor, q.Sort, q.Participants, q.LinkedObject, q.LinkedRecordID, q.EndsFrom, q.StartsBefore, q.Timezone))
+}
+
+func (c *Client) GetMeeting(ctx context.Context, meetingID string) (*Meeting, error) {
+	return one[Meeting](c.api.GetMeeting(ctx, meetingID))
+}
+
+// ListCallRecordings returns one page of a meeting's recordings and the
+// cursor of the next page.
+func (c *Client) ListCallRecordings(ctx context.Context, meetingID string, limit int, cursor string) ([]CallRecording, string, error) {
+	return page[CallRecording](c.api.ListCallRecordings(ctx, meetingID, limit, cursor))
+}
+
+func (c *Client) GetCallRecording(ctx context.Context, meetingID string, callRecordingID string) (*CallRecording, error) {
+	return one[CallRecording](c.api.GetCallRecording(ctx, meetingID, callRecordingID))
+}
+
+func (c *Client) ListWebhooks(ctx context.Context, limit int, offset int) ([]Webhook, error) {
+	return many[Webhook](c.api.ListWebhooks(ctx, limit, offset))
+}
+
+func (c *Client) GetWebhook(ctx context.Context, webhookID string) (*Webhook, error) {
+	return one[Webhook](c.api.GetWebhook(ctx, webhookID))
+}
+
+func (c *Client) ListWorkspaceMembers(ctx context.Context) ([]WorkspaceMember, error) {
+	return many[WorkspaceMember](c.api.ListMembers(ctx))
+}
+
+func (c *Client) GetWorkspaceMember(ctx context.Context, workspaceMemberID string) (*WorkspaceMember, error) {
+	return one[WorkspaceMember](c.api.GetMember(ctx, workspaceMemberID))
+}
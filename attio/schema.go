@@ -0,0 +1,37 @@
+package attio
+
+import "context"
+
+func (c *Client) ListObjects(ctx context.Context) ([]Object, error) {
+	return many[Object](c.api.ListObjects(ctx))
+}
+
+func (c *Client) GetObject(ctx context.Context, object string) (*Object, error) {
+	return one[Object](c.api.GetObject(ctx, object))
+}
+
+func (c *Client) ListLists(ctx context.Context) ([]List, error) {
+	return many[List](c.api.ListLists(ctx))
+}
+
+func (c *Client) GetList(ctx context.Context, list string) (*List, error) {
+	return one[List](c.api.GetList(ctx, list))
+}
+
+// ListAttributes returns the attributes of an object or list. target is
+// "objects" or "lists".
+func (c *Client) ListAttributes(ctx context.Context, target string, identifier string, showArchived bool) ([]Attribute, error) {
+	return many[Attribute](c.api.ListAttributes(ctx, target, identifier, showArchived, 0, 0))
+}
+
+func (c *Client) GetAttribute(ctx context.Context, target string, identifier string, attribute string) (*Attribute, error) {
+	return one[Attribute](c.api.GetAttribute(ctx, target, identifier, attribute))
+}
+
+func (c *Client) ListSelectOptions(ctx context.Context, target string, identifier string, attribute string, showArchived bool) ([]SelectOption, error) {
+	return many[SelectOption](c.api.ListSelectOptions(ctx, target, identifier, attribute, showArchived))
+}
+
+func (c *Client) ListStatuses(ctx context.Context, target string, identifier string, attribute string, showArchived bool) ([]Status, error) {
+	return many[Status](c.api.ListStatuses(ctx, target, identifier, attribute, showArchived))
+}
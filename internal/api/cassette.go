@@ -0,0 +1,402 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/failup-ventures/attio-cli/internal/audit"
+)
+
+// Cassette modes for CassetteTransport.
+const (
+	CassetteRecord = "record"
+	CassetteReplay = "replay"
+)
+
+// Match keys accepted by CassetteTransport.Match.
+const (
+	MatchMethod = "method"
+	MatchPath   = "path"
+	MatchQuery  = "query"
+	MatchBody   = "body"
+)
+
+// DefaultCassetteMatch compares every part of the request.
+var DefaultCassetteMatch = []string{MatchMethod, MatchPath, MatchQuery, MatchBody}
+
+// ErrCassetteMiss is returned when a replayed request has no recording. It is
+// never retried.
+var ErrCassetteMiss = errors.New("cassette: no recorded interaction")
+
+// errCassetteConfig marks an invalid CassetteTransport. Like a miss, it is
+// never retried.
+var errCassetteConfig = errors.New("cassette")
+
+// sanitizedHeaders are never written to a cassette. JSON bodies have the
+// values of secret-looking keys, such as a webhook's "secret", redacted.
+var sanitizedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// CassetteTransport records HTTP interactions to Dir, one JSON file each, or
+// replays them from Dir without touching the network. Replayed requests are
+// matched on the Match keys; identical requests are served in recorded order.
+type CassetteTransport struct {
+	Base  http.RoundTripper
+	Mode  string
+	Dir   string
+	Match []string
+
+	mu           sync.Mutex
+	loaded       bool
+	interactions []cassetteInteraction
+	used         []bool
+}
+
+type cassetteInteraction struct {
+	Request  cassetteRequest  `json:"request"`
+	Response cassetteResponse `json:"response"`
+}
+
+type cassetteRequest struct {
+	Method  string          `json:"method"`
+	URL     string          `json:"url"`
+	Headers http.Header     `json:"headers,omitempty"`
+	Body    json.RawMessage `json:"body,omitempty"`
+	Text    string          `json:"body_text,omitempty"`
+}
+
+type cassetteResponse struct {
+	Status  int             `json:"status"`
+	Headers http.Header     `json:"headers,omitempty"`
+	Body    json.RawMessage `json:"body,omitempty"`
+	Text    string          `json:"body_text,omitempty"`
+}
+
+// ReplayingFromEnv reports whether ATTIO_HTTP_REPLAY is set, in which case no
+// request reaches the network and credentials are not needed.
+func ReplayingFromEnv() bool {
+	return strings.TrimSpace(os.Getenv("ATTIO_HTTP_REPLAY")) != ""
+}
+
+// CassetteFromEnv returns the CassetteTransport ATTIO_HTTP_REPLAY or
+// ATTIO_HTTP_RECORD asks for (replay wins if both are set), or nil when
+// neither is. ATTIO_HTTP_MATCH overrides the comma-separated match keys.
+func CassetteFromEnv() (*CassetteTransport, error) {
+	mode, dir := CassetteReplay, strings.TrimSpace(os.Getenv("ATTIO_HTTP_REPLAY"))
+	if dir == "" {
+		mode, dir = CassetteRecord, strings.TrimSpace(os.Getenv("ATTIO_HTTP_RECORD"))
+	}
+	if dir == "" {
+		return nil, nil
+	}
+	var match []string
+	for _, key := range strings.Split(os.Getenv("ATTIO_HTTP_MATCH"), ",") {
+		if key = strings.ToLower(strings.TrimSpace(key)); key != "" {
+			match = append(match, key)
+		}
+	}
+	t := &CassetteTransport{Mode: mode, Dir: dir, Match: match}
+	if err := t.check(); err != nil {
+		return nil, fmt.Errorf("ATTIO_HTTP_MATCH: %w", err)
+	}
+	return t, nil
+}
+
+// check rejects an unknown mode or match key, which would otherwise record
+// nothing or match every request.
+func (t *CassetteTransport) check() error {
+	if t.Mode != CassetteRecord && t.Mode != CassetteReplay {
+		return fmt.Errorf("%w: unknown mode %q (expected %s or %s)", errCassetteConfig, t.Mode, CassetteRecord, CassetteReplay)
+	}
+	for _, key := range t.Match {
+		switch key {
+		case MatchMethod, MatchPath, MatchQuery, MatchBody:
+		default:
+			return fmt.Errorf("%w: unknown match key %q (expected %s)", errCassetteConfig, key, strings.Join(DefaultCassetteMatch, ", "))
+		}
+	}
+	return nil
+}
+
+func (t *CassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.check(); err != nil {
+		return nil, err
+	}
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, fmt.Errorf("cassette: read request body: %w", err)
+	}
+	recorded := cassetteRequest{
+		Method:  req.Method,
+		URL:     req.URL.RequestURI(),
+		Headers: sanitizeHeaders(req.Header),
+	}
+	recorded.Body, recorded.Text = encodeCassetteBody(body)
+
+	if t.Mode == CassetteReplay {
+		return t.replay(req, recorded)
+	}
+	return t.record(req, recorded)
+}
+
+func (t *CassetteTransport) record(req *http.Request, recorded cassetteRequest) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	b, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("cassette: read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(b))
+
+	interaction := cassetteInteraction{
+		Request:  recorded,
+		Response: cassetteResponse{Status: resp.StatusCode, Headers: sanitizeHeaders(resp.Header)},
+	}
+	if json.Valid(b) {
+		interaction.Response.Body = audit.Redact(b)
+	} else {
+		interaction.Response.Text = string(b)
+	}
+	if err := t.save(interaction); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+var cassetteNameUnsafe = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// save writes the interaction as the next numbered file in Dir. Files are
+// created exclusively so concurrent or consecutive processes never clobber
+// each other's recordings.
+func (t *CassetteTransport) save(interaction cassetteInteraction) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if err := os.MkdirAll(t.Dir, 0o700); err != nil {
+		return fmt.Errorf("cassette: %w", err)
+	}
+	b, err := json.MarshalIndent(interaction, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cassette: marshal interaction: %w", err)
+	}
+	existing, err := cassetteFiles(t.Dir)
+	if err != nil {
+		return err
+	}
+	path, _, _ := strings.Cut(interaction.Request.URL, "?")
+	slug := strings.Trim(cassetteNameUnsafe.ReplaceAllString(path, "-"), "-")
+	for seq := len(existing) + 1; ; seq++ {
+		name := filepath.Join(t.Dir, fmt.Sprintf("%04d-%s-%s.json", seq, strings.ToLower(interaction.Request.Method), slug))
+		f, err := os.OpenFile(name, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("cassette: %w", err)
+		}
+		_, werr := f.Write(append(b, '\n'))
+		if cerr := f.Close(); werr == nil {
+			werr = cerr
+		}
+		if werr != nil {
+			return fmt.Errorf("cassette: write %s: %w", name, werr)
+		}
+		slog.Debug("cassette recorded", "file", name)
+		return nil
+	}
+}
+
+func (t *CassetteTransport) replay(req *http.Request, recorded cassetteRequest) (*http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.loaded {
+		if err := t.load(); err != nil {
+			return nil, err
+		}
+	}
+	match := t.Match
+	if len(match) == 0 {
+		match = DefaultCassetteMatch
+	}
+
+	// Prefer the first unused match so repeated requests (pagination,
+	// polling) replay in order; once exhausted, keep serving the last one.
+	found := -1
+	for i, interaction := range t.interactions {
+		if !cassetteRequestsMatch(match, interaction.Request, recorded) {
+			continue
+		}
+		found = i
+		if !t.used[i] {
+			break
+		}
+	}
+	if found < 0 {
+		return nil, fmt.Errorf("%w in %s matches %s %s", ErrCassetteMiss, t.Dir, recorded.Method, recorded.URL)
+	}
+	t.used[found] = true
+	slog.Debug("cassette replayed", "method", recorded.Method, "url", recorded.URL, "index", found)
+
+	out := t.interactions[found].Response
+	body := []byte(out.Text)
+	if len(out.Body) > 0 {
+		body = out.Body
+	}
+	headers := out.Headers.Clone()
+	if headers == nil {
+		headers = http.Header{}
+	}
+	headers.Del("Content-Length")
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", out.Status, http.StatusText(out.Status)),
+		StatusCode:    out.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        headers,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+func (t *CassetteTransport) load() error {
+	files, err := cassetteFiles(t.Dir)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("cassette: no recordings found in %s", t.Dir)
+	}
+	for _, name := range files {
+		b, err := os.ReadFile(filepath.Join(t.Dir, name)) //nolint:gosec // user-specified cassette directory
+		if err != nil {
+			return fmt.Errorf("cassette: %w", err)
+		}
+		var interaction cassetteInteraction
+		if err := json.Unmarshal(b, &interaction); err != nil {
+			return fmt.Errorf("cassette: parse %s: %w", name, err)
+		}
+		if len(interaction.Request.Body) > 0 {
+			interaction.Request.Body, _ = encodeCassetteBody(interaction.Request.Body)
+		}
+		t.interactions = append(t.interactions, interaction)
+	}
+	t.used = make([]bool, len(t.interactions))
+	t.loaded = true
+	return nil
+}
+
+func cassetteFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cassette: %w", err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func cassetteRequestsMatch(keys []string, recorded cassetteRequest, actual cassetteRequest) bool {
+	recordedPath, recordedQuery, _ := strings.Cut(recorded.URL, "?")
+	actualPath, actualQuery, _ := strings.Cut(actual.URL, "?")
+	for _, key := range keys {
+		switch key {
+		case MatchMethod:
+			if !strings.EqualFold(recorded.Method, actual.Method) {
+				return false
+			}
+		case MatchPath:
+			if recordedPath != actualPath {
+				return false
+			}
+		case MatchQuery:
+			if recordedQuery != actualQuery {
+				return false
+			}
+		case MatchBody:
+			if !bytes.Equal(recorded.Body, actual.Body) || recorded.Text != actual.Text {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		return io.ReadAll(body)
+	}
+	b, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(b))
+	return b, nil
+}
+
+// encodeCassetteBody stores JSON request bodies in canonical form (sorted
+// keys) so matching ignores formatting, and anything else as text.
+func encodeCassetteBody(b []byte) (json.RawMessage, string) {
+	if len(bytes.TrimSpace(b)) == 0 {
+		return nil, ""
+	}
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, string(b)
+	}
+	canonical, err := json.Marshal(v)
+	if err != nil {
+		return nil, string(b)
+	}
+	// Replayed requests are redacted the same way, so body matching still
+	// works.
+	return audit.Redact(canonical), ""
+}
+
+func sanitizeHeaders(h http.Header) http.Header {
+	if len(h) == 0 {
+		return nil
+	}
+	out := h.Clone()
+	for _, name := range sanitizedHeaders {
+		out.Del(name)
+	}
+	return out
+}
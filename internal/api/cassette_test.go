@@ -0,0 +1,121 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCassetteRecordAndReplay(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		offset, _ := body["offset"].(float64)
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": []map[string]any{{"n": offset}}})
+	}))
+
+	t.Setenv("ATTIO_HTTP_RECORD", dir)
+	recorder := cassetteClient(t, "secret-key", srv.URL)
+	for _, offset := range []int{0, 5} {
+		if _, err := recorder.QueryRecords(ctx, "people", map[string]any{"name": "Ada"}, nil, 1, offset); err != nil {
+			t.Fatalf("record: %v", err)
+		}
+	}
+	srv.Close()
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 2 || filepath.Base(files[0]) != "0001-post-v2-objects-people-records-query.json" {
+		t.Fatalf("unexpected cassette files: %v", files)
+	}
+	for _, file := range files {
+		b, _ := os.ReadFile(file)
+		if strings.Contains(string(b), "secret-key") || strings.Contains(string(b), "Authorization") {
+			t.Fatalf("cassette %s leaks credentials:\n%s", file, b)
+		}
+	}
+
+	t.Setenv("ATTIO_HTTP_RECORD", "")
+	t.Setenv("ATTIO_HTTP_REPLAY", dir)
+	replayer := cassetteClient(t, "other-key", srv.URL)
+	got, err := replayer.QueryRecords(ctx, "people", map[string]any{"name": "Ada"}, nil, 1, 5)
+	if err != nil || len(got) != 1 || got[0]["n"] != float64(5) {
+		t.Fatalf("expected replayed second page, got %v (%v)", got, err)
+	}
+	if _, err := replayer.QueryRecords(ctx, "people", map[string]any{"name": "Bob"}, nil, 1, 0); err == nil || !strings.Contains(err.Error(), "no recorded interaction") {
+		t.Fatalf("expected miss for a different body, got %v", err)
+	}
+
+	// Matching on method and path only serves identical requests in order.
+	t.Setenv("ATTIO_HTTP_MATCH", "method,path")
+	loose := cassetteClient(t, "", srv.URL)
+	for _, want := range []float64{0, 5, 5} {
+		got, err := loose.QueryRecords(ctx, "people", nil, nil, 10, 0)
+		if err != nil || got[0]["n"] != want {
+			t.Fatalf("expected n=%v, got %v (%v)", want, got, err)
+		}
+	}
+
+	// The environment only applies to clients that ask for it.
+	plain := NewClient("", srv.URL)
+	plain.SetMaxRetries(0)
+	if _, err := plain.QueryRecords(ctx, "people", nil, nil, 10, 0); err == nil || errors.Is(err, ErrCassetteMiss) {
+		t.Fatalf("expected a plain client to reach the (closed) server, got %v", err)
+	}
+
+	t.Setenv("ATTIO_HTTP_MATCH", "method,url")
+	if _, err := CassetteFromEnv(); err == nil || !strings.Contains(err.Error(), `unknown match key "url"`) {
+		t.Fatalf("expected an unknown match key to be rejected, got %v", err)
+	}
+}
+
+func TestCassetteRedactsSecretsInBodies(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"id":{"webhook_id":"wh-1"},"target_url":"https://example.com/hook","secret":"whsec-response"}}`))
+	}))
+	t.Setenv("ATTIO_HTTP_RECORD", dir)
+	data := map[string]any{"target_url": "https://example.com/hook", "secret": "whsec-request"}
+	if _, err := cassetteClient(t, "key", srv.URL).CreateWebhook(ctx, data); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	srv.Close()
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 1 {
+		t.Fatalf("unexpected cassette files: %v", files)
+	}
+	b, _ := os.ReadFile(files[0])
+	if strings.Contains(string(b), "whsec-") || !strings.Contains(string(b), "https://example.com/hook") {
+		t.Fatalf("expected the secrets to be redacted and the rest kept:\n%s", b)
+	}
+
+	// The replayed request is redacted before matching, so it still matches.
+	t.Setenv("ATTIO_HTTP_RECORD", "")
+	t.Setenv("ATTIO_HTTP_REPLAY", dir)
+	got, err := cassetteClient(t, "", srv.URL).CreateWebhook(ctx, data)
+	if err != nil || got["secret"] != "[REDACTED]" {
+		t.Fatalf("expected the redacted recording to replay, got %v (%v)", got, err)
+	}
+}
+
+func cassetteClient(t *testing.T, apiKey string, baseURL string) *Client {
+	t.Helper()
+	cassette, err := CassetteFromEnv()
+	if err != nil {
+		t.Fatalf("cassette: %v", err)
+	}
+	client := NewClient(apiKey, baseURL)
+	client.SetCassette(cassette)
+	return client
+}
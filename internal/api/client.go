@@ -16,11 +16,32 @@ const defaultUserAgent = "attio-cli/dev"
 
 type Client struct {
 	httpClient *http.Client
+	transport  *RetryTransport
 	baseURL    string
 	apiKey     string
 	userAgent  string
+	guard      RequestGuard
+	auditor    Auditor
 }
 
+// RequestGuard can veto a request before it is sent, for example to keep a
+// read-only session from changing data.
+type RequestGuard func(method string, path string) error
+
+// AuditRecord describes a non-GET request after it was sent. Status is 0
+// and Err is set when no response arrived.
+type AuditRecord struct {
+	Method   string
+	Path     string
+	Body     []byte
+	Status   int
+	Response []byte
+	Err      error
+}
+
+// Auditor is called with every non-GET request the client sends.
+type Auditor func(ctx context.Context, record AuditRecord)
+
 func NewClient(apiKey string, baseURL string) *Client {
 	if strings.TrimSpace(baseURL) == "" {
 		baseURL = defaultBaseURL
@@ -37,6 +58,7 @@ func NewClient(apiKey string, baseURL string) *Client {
 			Timeout:   30 * time.Second,
 			Transport: transport,
 		},
+		transport: transport,
 		baseURL:   baseURL,
 		apiKey:    apiKey,
 		userAgent: defaultUserAgent,
@@ -47,6 +69,16 @@ func (c *Client) SetUserAgent(userAgent string) {
 	c.userAgent = strings.TrimSpace(userAgent)
 }
 
+// SetRequestGuard checks every request with guard before sending it.
+func (c *Client) SetRequestGuard(guard RequestGuard) {
+	c.guard = guard
+}
+
+// SetAuditor reports every non-GET request to auditor once it completes.
+func (c *Client) SetAuditor(auditor Auditor) {
+	c.auditor = auditor
+}
+
 func (c *Client) SetTimeout(timeout time.Duration) {
 	if timeout <= 0 {
 		return
@@ -54,6 +86,40 @@ func (c *Client) SetTimeout(timeout time.Duration) {
 	c.httpClient.Timeout = timeout
 }
 
+// SetHTTPClient sends requests through a copy of httpClient. Retries and
+// rate limiting still apply, in front of httpClient's own transport.
+func (c *Client) SetHTTPClient(httpClient *http.Client) {
+	if httpClient == nil {
+		return
+	}
+	copied := *httpClient
+	c.transport.Base = copied.Transport
+	copied.Transport = c.transport
+	c.httpClient = &copied
+}
+
+// SetCassette records requests to, or replays them from, cassette. It sits
+// behind retries and rate limiting, in front of the current transport, so
+// call it after SetHTTPClient.
+func (c *Client) SetCassette(cassette *CassetteTransport) {
+	if cassette == nil {
+		return
+	}
+	cassette.Base = c.transport.Base
+	c.transport.Base = cassette
+}
+
+// SetMaxRetries sets how many times 429 and 5xx responses are retried.
+func (c *Client) SetMaxRetries(maxRetries int) {
+	c.transport.MaxRetries = maxRetries
+}
+
+// SetRateLimiter makes the client wait for limiter before every request.
+// Clients sharing a limiter share its budgets and Retry-After pauses.
+func (c *Client) SetRateLimiter(limiter *RateLimiter) {
+	c.transport.Limiter = limiter
+}
+
 func (c *Client) GetSelf(ctx context.Context) (*Self, error) {
 	var out Self
 	if err := c.do(ctx, http.MethodGet, "/v2/self", nil, &out); err != nil {
@@ -62,14 +128,31 @@ func (c *Client) GetSelf(ctx context.Context) (*Self, error) {
 	return &out, nil
 }
 
+// Raw sends a request to an arbitrary API path (which may include a query
+// string) and returns the decoded JSON response, or nil for an empty body.
+func (c *Client) Raw(ctx context.Context, method string, path string, body any) (any, error) {
+	var out any
+	if err := c.do(ctx, method, path, body, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *Client) do(ctx context.Context, method string, path string, body any, result any) error {
+	if c.guard != nil {
+		if err := c.guard(method, path); err != nil {
+			return err
+		}
+	}
 	var bodyReader io.Reader
+	var bodyBytes []byte
 	if body != nil {
 		b, err := json.Marshal(body)
 		if err != nil {
 			return fmt.Errorf("marshal request: %w", err)
 		}
 		bodyReader = bytes.NewReader(b)
+		bodyBytes = b
 	}
 
 	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bodyReader)
@@ -85,22 +168,39 @@ func (c *Client) do(ctx context.Context, method string, path string, body any, r
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
 
+	audited := c.auditor != nil && method != http.MethodGet && method != http.MethodHead
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
+		if audited {
+			c.auditor(ctx, AuditRecord{Method: method, Path: path, Body: bodyBytes, Err: err})
+		}
 		return fmt.Errorf("execute request: %w", err)
 	}
 	defer drainAndClose(resp.Body)
 
+	if audited {
+		respBytes, readErr := io.ReadAll(resp.Body)
+		c.auditor(ctx, AuditRecord{Method: method, Path: path, Body: bodyBytes, Status: resp.StatusCode, Response: respBytes, Err: readErr})
+		resp.Body = io.NopCloser(bytes.NewReader(respBytes))
+	}
+
 	if resp.StatusCode >= 400 {
 		return parseAPIError(resp)
 	}
 
-	if result == nil || resp.StatusCode == http.StatusNoContent {
+	if result == nil || resp.StatusCode == http.StatusNoContent || resp.ContentLength == 0 {
 		return nil
 	}
 
-	dec := json.NewDecoder(resp.Body)
-	if err := dec.Decode(result); err != nil {
+	// An empty body leaves result untouched; a body cut short is an error.
+	respBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+	if len(bytes.TrimSpace(respBytes)) == 0 {
+		return nil
+	}
+	if err := json.NewDecoder(bytes.NewReader(respBytes)).Decode(result); err != nil {
 		return fmt.Errorf("decode response: %w", err)
 	}
 	return nil
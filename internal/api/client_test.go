@@ -151,3 +151,24 @@ func TestClientUserAgentAndTimeoutSetters(t *testing.T) {
 		t.Fatalf("expected ok=true, got %#v", out)
 	}
 }
+
+func TestClientRawEmptyBodyAndDroppedConnection(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/drop" {
+			panic(http.ErrAbortHandler)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-key", srv.URL)
+	out, err := client.Raw(context.Background(), http.MethodPost, "/v2/empty", map[string]any{})
+	if err != nil || out != nil {
+		t.Fatalf("expected an empty body to give nil, got %#v, %v", out, err)
+	}
+	if _, err := client.Raw(context.Background(), http.MethodPost, "/v2/drop", map[string]any{}); err == nil {
+		t.Fatal("expected a dropped connection to fail")
+	}
+}
@@ -4,6 +4,27 @@ import "context"
 
 // FetchAllOffset collects all pages from an offset-paginated endpoint.
 func FetchAllOffset[T any](ctx context.Context, limit int, maxPages int, fetch func(offset int) ([]T, error)) ([]T, error) {
+	all := make([]T, 0)
+	err := EachOffsetPage(ctx, limit, maxPages, fetch, func(items []T) error {
+		all = append(all, items...)
+		return nil
+	})
+	return all, err
+}
+
+// FetchAllCursor collects all pages from a cursor-paginated endpoint.
+func FetchAllCursor[T any](ctx context.Context, maxPages int, fetch func(cursor string) ([]T, string, error)) ([]T, error) {
+	all := make([]T, 0)
+	err := EachCursorPage(ctx, maxPages, fetch, func(items []T) error {
+		all = append(all, items...)
+		return nil
+	})
+	return all, err
+}
+
+// EachOffsetPage calls fn with each page from an offset-paginated endpoint as
+// soon as it is fetched. An error from fn stops pagination and is returned.
+func EachOffsetPage[T any](ctx context.Context, limit int, maxPages int, fetch func(offset int) ([]T, error), fn func(items []T) error) error {
 	if limit <= 0 {
 		limit = 1
 	}
@@ -11,46 +32,49 @@ func FetchAllOffset[T any](ctx context.Context, limit int, maxPages int, fetch f
 		maxPages = 100
 	}
 
-	all := make([]T, 0)
 	offset := 0
 	for page := 0; page < maxPages; page++ {
 		if err := ctx.Err(); err != nil {
-			return all, err
+			return err
 		}
 		items, err := fetch(offset)
 		if err != nil {
-			return all, err
+			return err
+		}
+		if err := fn(items); err != nil {
+			return err
 		}
-		all = append(all, items...)
 		if len(items) < limit {
 			break
 		}
 		offset += len(items)
 	}
-	return all, nil
+	return nil
 }
 
-// FetchAllCursor collects all pages from a cursor-paginated endpoint.
-func FetchAllCursor[T any](ctx context.Context, maxPages int, fetch func(cursor string) ([]T, string, error)) ([]T, error) {
+// EachCursorPage calls fn with each page from a cursor-paginated endpoint as
+// soon as it is fetched. An error from fn stops pagination and is returned.
+func EachCursorPage[T any](ctx context.Context, maxPages int, fetch func(cursor string) ([]T, string, error), fn func(items []T) error) error {
 	if maxPages <= 0 {
 		maxPages = 100
 	}
 
-	all := make([]T, 0)
 	cursor := ""
 	for page := 0; page < maxPages; page++ {
 		if err := ctx.Err(); err != nil {
-			return all, err
+			return err
 		}
 		items, nextCursor, err := fetch(cursor)
 		if err != nil {
-			return all, err
+			return err
+		}
+		if err := fn(items); err != nil {
+			return err
 		}
-		all = append(all, items...)
 		if nextCursor == "" {
 			break
 		}
 		cursor = nextCursor
 	}
-	return all, nil
+	return nil
 }
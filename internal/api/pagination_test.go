@@ -110,3 +110,36 @@ func TestFetchAllCursorContextCanceledAfterFirstPage(t *testing.T) {
 		t.Fatalf("expected partial first page, got %#v", items)
 	}
 }
+
+func TestEachOffsetPageStreamsPages(t *testing.T) {
+	t.Parallel()
+
+	var seen [][]int
+	fetched := 0
+	err := EachOffsetPage(context.Background(), 2, 2, func(offset int) ([]int, error) {
+		fetched++
+		return []int{offset, offset + 1}, nil
+	}, func(items []int) error {
+		if len(seen) != fetched-1 {
+			t.Fatalf("expected callback before the next fetch")
+		}
+		seen = append(seen, items)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(seen) != 2 || seen[1][0] != 2 {
+		t.Fatalf("expected 2 pages capped by maxPages, got %v", seen)
+	}
+
+	stop := errors.New("stop")
+	err = EachCursorPage(context.Background(), 10, func(cursor string) ([]int, string, error) {
+		return []int{1}, "next", nil
+	}, func(items []int) error {
+		return stop
+	})
+	if !errors.Is(err, stop) {
+		t.Fatalf("expected callback error to stop pagination, got %v", err)
+	}
+}
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultRateLimit mirrors Attio's published limits of 100 read and 25 write
+// requests per second.
+var DefaultRateLimit = RateLimit{
+	Read:  Bucket{Rate: 100, Burst: 100},
+	Write: Bucket{Rate: 25, Burst: 25},
+}
+
+var now = time.Now
+
+// waitFor blocks for d or until ctx is done.
+var waitFor = func(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Bucket is a token bucket budget. A zero Rate means unlimited.
+type Bucket struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimit holds separate budgets for reads (GET/HEAD) and writes.
+type RateLimit struct {
+	Read  Bucket
+	Write Bucket
+}
+
+func (l RateLimit) String() string {
+	return "read=" + l.Read.String() + ",write=" + l.Write.String()
+}
+
+func (b Bucket) String() string {
+	if b.Rate <= 0 {
+		return "off"
+	}
+	return strconv.FormatFloat(b.Rate, 'f', -1, 64) + ":" + strconv.Itoa(b.Burst)
+}
+
+// ParseRateLimit parses "<rps>[:<burst>]" (applied to reads and writes),
+// "read=<rps>[:<burst>],write=<rps>[:<burst>]" or "off". Budgets not named
+// keep their DefaultRateLimit value.
+func ParseRateLimit(spec string) (RateLimit, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return DefaultRateLimit, nil
+	}
+	if !strings.Contains(spec, "=") {
+		b, err := parseBucket(spec)
+		if err != nil {
+			return RateLimit{}, err
+		}
+		return RateLimit{Read: b, Write: b}, nil
+	}
+
+	limit := DefaultRateLimit
+	for _, part := range strings.Split(spec, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		b, err := parseBucket(value)
+		if err != nil {
+			return RateLimit{}, err
+		}
+		switch strings.TrimSpace(key) {
+		case "read":
+			limit.Read = b
+		case "write":
+			limit.Write = b
+		default:
+			return RateLimit{}, fmt.Errorf("invalid rate limit %q: unknown budget %q (expected read or write)", spec, key)
+		}
+	}
+	return limit, nil
+}
+
+func parseBucket(value string) (Bucket, error) {
+	value = strings.TrimSpace(value)
+	if value == "off" || value == "0" {
+		return Bucket{}, nil
+	}
+	rateRaw, burstRaw, hasBurst := strings.Cut(value, ":")
+	rate, err := strconv.ParseFloat(strings.TrimSuffix(rateRaw, "/s"), 64)
+	if err != nil || rate < 0 {
+		return Bucket{}, fmt.Errorf("invalid rate limit %q (expected requests per second, e.g. 10 or 10:20)", value)
+	}
+	burst := int(rate)
+	if hasBurst {
+		burst, err = strconv.Atoi(burstRaw)
+		if err != nil || burst <= 0 {
+			return Bucket{}, fmt.Errorf("invalid rate limit burst %q", burstRaw)
+		}
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return Bucket{Rate: rate, Burst: burst}, nil
+}
+
+// RateLimiter is a client-side token bucket limiter shared by every request
+// made with the same profile. A Retry-After from the server pauses all
+// callers, not just the request that received it.
+type RateLimiter struct {
+	mu          sync.Mutex
+	limit       RateLimit
+	read        bucketState
+	write       bucketState
+	pausedUntil time.Time
+}
+
+type bucketState struct {
+	tokens float64
+	last   time.Time
+}
+
+func NewRateLimiter(limit RateLimit) *RateLimiter {
+	t := now()
+	return &RateLimiter{
+		limit: limit,
+		read:  bucketState{tokens: float64(limit.Read.Burst), last: t},
+		write: bucketState{tokens: float64(limit.Write.Burst), last: t},
+	}
+}
+
+func (l *RateLimiter) Limit() RateLimit {
+	return l.limit
+}
+
+// Wait blocks until a request with method may be sent. Tokens are reserved
+// up front so concurrent callers queue in arrival order.
+func (l *RateLimiter) Wait(ctx context.Context, method string) error {
+	if l == nil {
+		return nil
+	}
+	name, budget, state := "read", l.limit.Read, &l.read
+	if method != http.MethodGet && method != http.MethodHead {
+		name, budget, state = "write", l.limit.Write, &l.write
+	}
+
+	l.mu.Lock()
+	t := now()
+	var wait time.Duration
+	if l.pausedUntil.After(t) {
+		wait = l.pausedUntil.Sub(t)
+	}
+	if budget.Rate > 0 {
+		elapsed := t.Sub(state.last).Seconds()
+		state.tokens = min(float64(budget.Burst), state.tokens+elapsed*budget.Rate)
+		state.last = t
+		state.tokens--
+		if state.tokens < 0 {
+			wait = max(wait, time.Duration(-state.tokens/budget.Rate*float64(time.Second)))
+		}
+	}
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	slog.Debug("rate limit wait", "budget", name, "wait", wait)
+	return waitFor(ctx, wait)
+}
+
+// Pause holds back every caller for d, e.g. after a 429 with Retry-After.
+func (l *RateLimiter) Pause(d time.Duration) {
+	if l == nil || d <= 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	until := now().Add(d)
+	if until.After(l.pausedUntil) {
+		l.pausedUntil = until
+		slog.Debug("rate limit pause", "wait", d)
+	}
+}
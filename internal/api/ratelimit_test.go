@@ -0,0 +1,114 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func fakeRateClock(t *testing.T) (*time.Time, *[]time.Duration) {
+	t.Helper()
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var waits []time.Duration
+	origNow, origWait := now, waitFor
+	now = func() time.Time { return clock }
+	waitFor = func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		clock = clock.Add(d)
+		return nil
+	}
+	t.Cleanup(func() { now, waitFor = origNow, origWait })
+	return &clock, &waits
+}
+
+func TestParseRateLimit(t *testing.T) {
+	cases := map[string]string{
+		"":                     "read=100:100,write=25:25",
+		"10":                   "read=10:10,write=10:10",
+		"2.5/s:5":              "read=2.5:5,write=2.5:5",
+		"write=5":              "read=100:100,write=5:5",
+		"read=50:80,write=off": "read=50:80,write=off",
+		"off":                  "read=off,write=off",
+	}
+	for spec, want := range cases {
+		got, err := ParseRateLimit(spec)
+		if err != nil || got.String() != want {
+			t.Fatalf("ParseRateLimit(%q) = %s, %v; want %s", spec, got, err, want)
+		}
+	}
+	for _, spec := range []string{"fast", "10:0", "reads=5", "-1"} {
+		if _, err := ParseRateLimit(spec); err == nil {
+			t.Fatalf("expected error for %q", spec)
+		}
+	}
+}
+
+func TestRateLimiterSeparatesBudgetsAndPauses(t *testing.T) {
+	clock, waits := fakeRateClock(t)
+	limiter := NewRateLimiter(RateLimit{Read: Bucket{Rate: 10, Burst: 2}, Write: Bucket{Rate: 1, Burst: 1}})
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_ = limiter.Wait(ctx, http.MethodGet)
+	}
+	if len(*waits) != 1 || (*waits)[0] != 100*time.Millisecond {
+		t.Fatalf("expected third read to wait 100ms, got %v", *waits)
+	}
+
+	_ = limiter.Wait(ctx, http.MethodPost)
+	if len(*waits) != 1 {
+		t.Fatalf("writes must not spend the read budget, got %v", *waits)
+	}
+	_ = limiter.Wait(ctx, http.MethodPatch)
+	if len(*waits) != 2 || (*waits)[1] != time.Second {
+		t.Fatalf("expected second write to wait 1s, got %v", *waits)
+	}
+
+	*clock = clock.Add(time.Minute)
+	limiter.Pause(3 * time.Second)
+	_ = limiter.Wait(ctx, http.MethodGet)
+	if len(*waits) != 3 || (*waits)[2] != 3*time.Second {
+		t.Fatalf("expected pause to hold back reads, got %v", *waits)
+	}
+}
+
+func TestRetryTransportPausesSharedLimiter(t *testing.T) {
+	_, waits := fakeRateClock(t)
+	origSleep := sleep
+	sleep = func(time.Duration) {}
+	t.Cleanup(func() { sleep = origSleep })
+
+	limiter := NewRateLimiter(RateLimit{})
+	attempts := 0
+	rt := &RetryTransport{
+		Base: roundTripperFunc(func(_ *http.Request) (*http.Response, error) {
+			attempts++
+			if attempts == 1 {
+				return &http.Response{
+					StatusCode: http.StatusTooManyRequests,
+					Header:     http.Header{"Retry-After": []string{"2"}},
+					Body:       io.NopCloser(strings.NewReader("rate limited")),
+				}, nil
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("ok"))}, nil
+		}),
+		MaxRetries: 1,
+		Limiter:    limiter,
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	// sleep is stubbed, so the retry is held back by the limiter pause that
+	// every other caller sharing it would also see.
+	if len(*waits) != 1 || (*waits)[0] != 2*time.Second {
+		t.Fatalf("expected Retry-After to pause the limiter, got %v", *waits)
+	}
+}
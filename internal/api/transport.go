@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -12,10 +13,12 @@ import (
 
 var sleep = time.Sleep
 
-// RetryTransport retries 429 and 5xx responses.
+// RetryTransport retries 429 and 5xx responses. When Limiter is set every
+// attempt waits for its budget and a Retry-After pauses the whole limiter.
 type RetryTransport struct {
 	Base       http.RoundTripper
 	MaxRetries int
+	Limiter    *RateLimiter
 }
 
 func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
@@ -38,10 +41,13 @@ func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 			return nil, fmt.Errorf("clone request: %w", err)
 		}
 
+		if err := t.Limiter.Wait(req.Context(), req.Method); err != nil {
+			return nil, err
+		}
 		resp, err := base.RoundTrip(attemptReq)
 		if err != nil {
 			lastErr = err
-			if attempt == maxRetries {
+			if attempt == maxRetries || errors.Is(err, ErrCassetteMiss) || errors.Is(err, errCassetteConfig) {
 				return nil, err
 			}
 			wait := backoff(attempt)
@@ -56,6 +62,9 @@ func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 		}
 
 		wait := retryDelay(resp.Header.Get("Retry-After"), attempt)
+		if _, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			t.Limiter.Pause(wait)
+		}
 		slog.Debug("retryable response", "status", resp.StatusCode, "attempt", attempt+1, "wait", wait)
 		drainAndClose(resp.Body)
 		sleep(wait)
@@ -0,0 +1,218 @@
+// Package audit keeps an append-only JSONL log of the requests the CLI sends
+// to change data, so changes in a workspace can be traced back to a command.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Body capture modes.
+const (
+	BodyNone = "none"
+	BodyHash = "hash"
+	BodyFull = "full"
+)
+
+// DefaultBodyMode stores a hash of each request body but not the body.
+const DefaultBodyMode = BodyHash
+
+// Redacted replaces secret values in captured bodies.
+const Redacted = "[REDACTED]"
+
+// Entry is one line of the audit log.
+type Entry struct {
+	Time       time.Time       `json:"time"`
+	Profile    string          `json:"profile,omitempty"`
+	Workspace  string          `json:"workspace,omitempty"`
+	Command    string          `json:"command,omitempty"`
+	Method     string          `json:"method"`
+	Path       string          `json:"path"`
+	Status     int             `json:"status,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	BodySHA256 string          `json:"body_sha256,omitempty"`
+	Body       json.RawMessage `json:"body,omitempty"`
+	ResourceID string          `json:"resource_id,omitempty"`
+}
+
+// ParseBodyMode validates a body capture mode; "" means DefaultBodyMode.
+func ParseBodyMode(mode string) (string, error) {
+	switch mode = strings.ToLower(strings.TrimSpace(mode)); mode {
+	case "":
+		return DefaultBodyMode, nil
+	case BodyNone, BodyHash, BodyFull:
+		return mode, nil
+	}
+	return "", fmt.Errorf("invalid audit body mode %q (expected none, hash or full)", mode)
+}
+
+// CaptureBody fills in the body fields of e from a request body according to
+// mode. Full bodies are redacted first.
+func (e *Entry) CaptureBody(body []byte, mode string) {
+	if len(body) == 0 || mode == BodyNone {
+		return
+	}
+	sum := sha256.Sum256(body)
+	e.BodySHA256 = hex.EncodeToString(sum[:])
+	if mode == BodyFull {
+		e.Body = Redact(body)
+	}
+}
+
+// secretKeyParts mark JSON keys whose values are never written to the log.
+var secretKeyParts = []string{"secret", "token", "password", "api_key", "apikey", "authorization", "signature", "credential"}
+
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, part := range secretKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact replaces the values of secret-looking keys in a JSON body. Bodies
+// that are not JSON are dropped entirely, since they cannot be inspected.
+func Redact(body []byte) json.RawMessage {
+	var v any
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		b, _ := json.Marshal(Redacted)
+		return b
+	}
+	b, err := json.Marshal(redactValue(v))
+	if err != nil {
+		b, _ = json.Marshal(Redacted)
+	}
+	return b
+}
+
+func redactValue(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, child := range t {
+			if isSecretKey(k) {
+				t[k] = Redacted
+				continue
+			}
+			t[k] = redactValue(child)
+		}
+	case []any:
+		for i, child := range t {
+			t[i] = redactValue(child)
+		}
+	}
+	return v
+}
+
+var appendMu sync.Mutex
+
+// Append writes e as one line at the end of the log at path, creating the
+// file and its directory if needed.
+func Append(path string, e Entry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshal audit entry: %w", err)
+	}
+	appendMu.Lock()
+	defer appendMu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("create audit log dir: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("open audit log: %w", err)
+	}
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write audit log: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+	return nil
+}
+
+// Filter selects entries from the log. Zero fields match everything.
+type Filter struct {
+	Since time.Time
+	Until time.Time
+	// Command matches the command path or any of its parents, e.g. "records".
+	Command string
+	// Resource matches the resource ID or any segment of the request path,
+	// so an object slug finds every change to that object.
+	Resource string
+	Profile  string
+}
+
+func (f Filter) match(e Entry) bool {
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && e.Time.After(f.Until) {
+		return false
+	}
+	if f.Command != "" && e.Command != f.Command && !strings.HasPrefix(e.Command, f.Command+" ") {
+		return false
+	}
+	if f.Resource != "" && e.ResourceID != f.Resource && !hasPathSegment(e.Path, f.Resource) {
+		return false
+	}
+	if f.Profile != "" && e.Profile != f.Profile {
+		return false
+	}
+	return true
+}
+
+func hasPathSegment(path string, segment string) bool {
+	path, _, _ = strings.Cut(path, "?")
+	for _, s := range strings.Split(path, "/") {
+		if s == segment {
+			return true
+		}
+	}
+	return false
+}
+
+// Read returns the entries of the log at path that match f, oldest first. A
+// missing log has no entries. Lines that cannot be parsed are skipped.
+func Read(path string, f Filter) ([]Entry, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open audit log: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		if f.match(e) {
+			entries = append(entries, e)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read audit log: %w", err)
+	}
+	return entries, nil
+}
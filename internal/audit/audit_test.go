@@ -0,0 +1,84 @@
+package audit
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCaptureBodyRedactsSecrets(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{"data":{"target_url":"https://example.com","secret":"s1","nested":[{"API_Key":"k1","name":"ok"}],"count":12345678901234567890}}`)
+	var hashed, full, none Entry
+	hashed.CaptureBody(body, BodyHash)
+	full.CaptureBody(body, BodyFull)
+	none.CaptureBody(body, BodyNone)
+
+	if len(hashed.BodySHA256) != 64 || hashed.Body != nil {
+		t.Fatalf("hash mode should store only the hash: %+v", hashed)
+	}
+	if none.BodySHA256 != "" || none.Body != nil {
+		t.Fatalf("none mode should store nothing: %+v", none)
+	}
+	got := string(full.Body)
+	if full.BodySHA256 != hashed.BodySHA256 || strings.Contains(got, "s1") || strings.Contains(got, "k1") ||
+		!strings.Contains(got, `"name":"ok"`) || !strings.Contains(got, "12345678901234567890") {
+		t.Fatalf("unexpected redacted body: %s", got)
+	}
+	if string(Redact([]byte("not json"))) != `"[REDACTED]"` {
+		t.Fatalf("expected non-JSON bodies to be dropped")
+	}
+	if _, err := ParseBodyMode("everything"); err == nil {
+		t.Fatal("expected an invalid mode to fail")
+	}
+}
+
+func TestAppendAndRead(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "nested", "audit.jsonl")
+	if entries, err := Read(path, Filter{}); err != nil || len(entries) != 0 {
+		t.Fatalf("expected a missing log to be empty, got %v %v", entries, err)
+	}
+	base := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for i, e := range []Entry{
+		{Command: "records create", Method: "POST", Path: "/v2/objects/people/records", ResourceID: "r1", Profile: "default"},
+		{Command: "records delete", Method: "DELETE", Path: "/v2/objects/people/records/r1", ResourceID: "r1", Profile: "default"},
+		{Command: "tasks create", Method: "POST", Path: "/v2/tasks", ResourceID: "t1", Profile: "work"},
+	} {
+		e.Time = base.Add(time.Duration(i) * time.Hour)
+		if err := Append(path, e); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+
+	count := func(f Filter) int {
+		t.Helper()
+		entries, err := Read(path, f)
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		return len(entries)
+	}
+	cases := map[string]struct {
+		filter Filter
+		want   int
+	}{
+		"all":            {Filter{}, 3},
+		"command parent": {Filter{Command: "records"}, 2},
+		"command exact":  {Filter{Command: "records delete"}, 1},
+		"command prefix": {Filter{Command: "record"}, 0},
+		"resource id":    {Filter{Resource: "r1"}, 2},
+		"object slug":    {Filter{Resource: "people"}, 2},
+		"since":          {Filter{Since: base.Add(time.Hour)}, 2},
+		"until":          {Filter{Until: base.Add(30 * time.Minute)}, 1},
+		"profile":        {Filter{Profile: "work"}, 1},
+	}
+	for name, tc := range cases {
+		if got := count(tc.filter); got != tc.want {
+			t.Errorf("%s: got %d entries, want %d", name, got, tc.want)
+		}
+	}
+}
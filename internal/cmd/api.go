@@ -0,0 +1,352 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/failup-ventures/attio-cli/internal/api"
+	"github.com/failup-ventures/attio-cli/internal/openapi"
+	"github.com/failup-ventures/attio-cli/internal/outfmt"
+)
+
+const defaultAPIPageSize = 100
+
+var apiMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+type APICmd struct {
+	Method         string   `arg:"" name:"method" help:"HTTP method (GET|POST|PUT|PATCH|DELETE)"`
+	Path           string   `arg:"" name:"path" help:"API path, e.g. /v2/objects/people/records/query ('/v2' may be omitted)"`
+	Fields         []string `name:"field" short:"F" help:"Add a typed parameter key=value (true/false/null/numbers/JSON are decoded; @file reads a file); nest with key[sub], append with key[]" sep:"none"`
+	RawFields      []string `name:"raw-field" short:"f" help:"Add a string parameter key=value" sep:"none"`
+	Input          string   `name:"input" help:"Request body JSON file ('-' for stdin); fields become query parameters"`
+	Paginate       bool     `name:"paginate" help:"Fetch all pages of an offset or cursor paginated endpoint"`
+	MaxPages       int      `name:"max-pages" help:"Maximum pages to fetch with --paginate (0 = default of 100)" default:"0"`
+	SkipValidation bool     `name:"skip-validation" help:"Do not check the path against the bundled openapi.json"`
+}
+
+func (c *APICmd) Run(ctx context.Context, flags *RootFlags) error {
+	method := strings.ToUpper(strings.TrimSpace(c.Method))
+	if !containsString(apiMethods, method) {
+		return newUsageError(fmt.Errorf("unsupported method %q (expected one of %s)", c.Method, strings.Join(apiMethods, ", ")))
+	}
+	path := normalizeAPIPath(c.Path)
+
+	var route openapi.Route
+	if !c.SkipValidation {
+		matched, allowed, ok, err := openapi.Match(method, path)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			if len(allowed) > 0 {
+				return newUsageError(fmt.Errorf("%s is not supported for %s (allowed: %s); use --skip-validation to send it anyway", method, path, strings.Join(allowed, ", ")))
+			}
+			return newUsageError(fmt.Errorf("unknown API path %s; use --skip-validation to send it anyway", path))
+		}
+		route = matched
+	}
+	if c.Paginate && route.Pagination == openapi.PaginationNone {
+		if c.SkipValidation {
+			return newUsageError(errors.New("--paginate cannot be combined with --skip-validation"))
+		}
+		return newUsageError(fmt.Errorf("%s %s is not a paginated endpoint", method, route.Path))
+	}
+
+	params := map[string]any{}
+	for _, field := range c.Fields {
+		if err := addAPIField(params, field, true); err != nil {
+			return err
+		}
+	}
+	for _, field := range c.RawFields {
+		if err := addAPIField(params, field, false); err != nil {
+			return err
+		}
+	}
+
+	query := url.Values{}
+	var body map[string]any
+	switch {
+	case c.Input != "":
+		input := c.Input
+		if input != "-" && !strings.HasPrefix(input, "@") {
+			input = "@" + input
+		}
+		var err error
+		if body, err = readJSONObjectInput(input); err != nil {
+			return err
+		}
+		if err := addQueryParams(query, params); err != nil {
+			return err
+		}
+	case method == http.MethodGet || method == http.MethodDelete:
+		if err := addQueryParams(query, params); err != nil {
+			return err
+		}
+	default:
+		body = params
+	}
+	if ok, err := maybeDryRun(ctx, "api", map[string]any{"method": method, "path": withQuery(path, query), "body": body}); ok || err != nil {
+		return err
+	}
+
+	client, err := requireClient(flags.Profile)
+	if err != nil {
+		return err
+	}
+
+	if !c.Paginate {
+		var payload any
+		if body != nil {
+			payload = body
+		}
+		resp, err := client.Raw(ctx, method, withQuery(path, query), payload)
+		if err != nil {
+			return err
+		}
+		return writeAPIResponse(ctx, resp)
+	}
+
+	items, err := c.fetchAllPages(ctx, client, route, method, path, query, body)
+	if err != nil {
+		return err
+	}
+	return writeAPIResponse(ctx, map[string]any{"data": items})
+}
+
+func (c *APICmd) fetchAllPages(ctx context.Context, client *api.Client, route openapi.Route, method string, path string, query url.Values, body map[string]any) ([]any, error) {
+	if route.Pagination == openapi.PaginationCursor {
+		return api.FetchAllCursor(ctx, c.MaxPages, func(cursor string) ([]any, string, error) {
+			q := cloneValues(query)
+			if cursor != "" {
+				q.Set("cursor", cursor)
+			}
+			resp, err := client.Raw(ctx, method, withQuery(path, q), bodyOrNil(body))
+			if err != nil {
+				return nil, "", err
+			}
+			m, _ := resp.(map[string]any)
+			return apiDataArray(resp), mapString(mapMap(m, "pagination"), "next_cursor"), nil
+		})
+	}
+
+	limit := defaultAPIPageSize
+	if route.OffsetInBody {
+		if n, ok := intFromAnyValue(body["limit"]); ok && n > 0 {
+			limit = n
+		}
+	} else if n, err := strconv.Atoi(query.Get("limit")); err == nil && n > 0 {
+		limit = n
+	}
+	return api.FetchAllOffset(ctx, limit, c.MaxPages, func(offset int) ([]any, error) {
+		q := cloneValues(query)
+		var payload any = bodyOrNil(body)
+		if route.OffsetInBody {
+			b := make(map[string]any, len(body)+2)
+			for k, v := range body {
+				b[k] = v
+			}
+			b["limit"] = limit
+			b["offset"] = offset
+			payload = b
+		} else {
+			q.Set("limit", strconv.Itoa(limit))
+			q.Set("offset", strconv.Itoa(offset))
+		}
+		resp, err := client.Raw(ctx, method, withQuery(path, q), payload)
+		if err != nil {
+			return nil, err
+		}
+		return apiDataArray(resp), nil
+	})
+}
+
+func writeAPIResponse(ctx context.Context, resp any) error {
+	if resp == nil {
+		resp = map[string]any{}
+	}
+	return outfmt.WriteJSON(ctx, os.Stdout, resp)
+}
+
+func apiDataArray(resp any) []any {
+	m, _ := resp.(map[string]any)
+	items, _ := m["data"].([]any)
+	return items
+}
+
+// normalizeAPIPath adds the leading slash and the /v2 prefix when omitted.
+func normalizeAPIPath(path string) string {
+	path = strings.TrimSpace(path)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	if !strings.HasPrefix(path, "/v2/") && path != "/v2" && !strings.HasPrefix(path, "/scim/") {
+		path = "/v2" + path
+	}
+	return path
+}
+
+// addAPIField parses key=value into params using gh-style keys: "a[b][c]"
+// nests objects and a trailing "[]" appends to an array.
+func addAPIField(params map[string]any, field string, typed bool) error {
+	key, raw, ok := strings.Cut(field, "=")
+	if !ok || key == "" {
+		return newUsageError(fmt.Errorf("invalid field %q (expected key=value)", field))
+	}
+	var value any = raw
+	if typed {
+		parsed, err := parseAPIFieldValue(raw)
+		if err != nil {
+			return err
+		}
+		value = parsed
+	}
+
+	parts, err := splitAPIFieldKey(key)
+	if err != nil {
+		return err
+	}
+	appendValue := parts[len(parts)-1] == ""
+	if appendValue {
+		parts = parts[:len(parts)-1]
+	}
+	current := params
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := current[part].(map[string]any)
+		if !ok {
+			if _, exists := current[part]; exists {
+				return newUsageError(fmt.Errorf("field %q conflicts with an earlier value", key))
+			}
+			next = map[string]any{}
+			current[part] = next
+		}
+		current = next
+	}
+	leaf := parts[len(parts)-1]
+	if appendValue {
+		existing, _ := current[leaf].([]any)
+		current[leaf] = append(existing, value)
+	} else {
+		current[leaf] = value
+	}
+	return nil
+}
+
+func splitAPIFieldKey(key string) ([]string, error) {
+	head, rest, _ := strings.Cut(key, "[")
+	if head == "" {
+		return nil, newUsageError(fmt.Errorf("invalid field key %q", key))
+	}
+	parts := []string{head}
+	if rest == "" {
+		return parts, nil
+	}
+	rest = "[" + rest
+	for rest != "" {
+		if !strings.HasPrefix(rest, "[") {
+			return nil, newUsageError(fmt.Errorf("invalid field key %q", key))
+		}
+		end := strings.IndexByte(rest, ']')
+		if end < 0 {
+			return nil, newUsageError(fmt.Errorf("invalid field key %q", key))
+		}
+		parts = append(parts, rest[1:end])
+		rest = rest[end+1:]
+	}
+	for _, part := range parts[:len(parts)-1] {
+		if part == "" {
+			return nil, newUsageError(fmt.Errorf("invalid field key %q ([] must come last)", key))
+		}
+	}
+	return parts, nil
+}
+
+func parseAPIFieldValue(raw string) (any, error) {
+	if strings.HasPrefix(raw, "@") {
+		b, err := readRawInput(raw)
+		if err != nil {
+			return nil, err
+		}
+		return strings.TrimRight(string(b), "\n"), nil
+	}
+	switch raw {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	if n, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return n, nil
+	}
+	if f, err := strconv.ParseFloat(raw, 64); err == nil {
+		return f, nil
+	}
+	if strings.HasPrefix(raw, "{") || strings.HasPrefix(raw, "[") {
+		var v any
+		if err := json.Unmarshal([]byte(raw), &v); err != nil {
+			return nil, newUsageError(fmt.Errorf("invalid JSON field value %q: %w", raw, err))
+		}
+		return v, nil
+	}
+	return raw, nil
+}
+
+func addQueryParams(query url.Values, params map[string]any) error {
+	for key, value := range params {
+		switch v := value.(type) {
+		case map[string]any:
+			return newUsageError(fmt.Errorf("field %q cannot be nested in a query string", key))
+		case []any:
+			for _, item := range v {
+				query.Add(key, anyString(item))
+			}
+		default:
+			query.Set(key, anyString(v))
+		}
+	}
+	return nil
+}
+
+func withQuery(path string, query url.Values) string {
+	if len(query) == 0 {
+		return path
+	}
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return path + sep + query.Encode()
+}
+
+func cloneValues(values url.Values) url.Values {
+	out := make(url.Values, len(values))
+	for k, v := range values {
+		out[k] = append([]string(nil), v...)
+	}
+	return out
+}
+
+func bodyOrNil(body map[string]any) any {
+	if body == nil {
+		return nil
+	}
+	return body
+}
+
+func containsString(values []string, want string) bool {
+	for _, v := range values {
+		if v == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExecuteAPIRequestBodyAndQuery(t *testing.T) {
+	setupCLIEnv(t)
+
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer env-key" {
+			t.Fatalf("expected profile auth to be applied")
+		}
+		var body any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		b, _ := json.Marshal(body)
+		requests = append(requests, r.Method+" "+r.URL.RequestURI()+" "+string(b))
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": []map[string]any{{"id": map[string]any{"record_id": "r1"}, "name": "Ada"}}})
+	}))
+	defer srv.Close()
+	t.Setenv("ATTIO_API_KEY", "env-key")
+	t.Setenv("ATTIO_BASE_URL", srv.URL)
+
+	stdout, stderr, err := captureExecute(t, []string{
+		"--json", "--select", "data.0.name",
+		"api", "post", "objects/people/records/query",
+		"-F", "limit=5", "-F", `filter[name][$contains]=Ada`, "-F", `sorts=[{"attribute":"name","direction":"asc"}]`, "-f", "note=5",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v\nstderr=%s", err, stderr)
+	}
+	if !strings.Contains(stdout, `"data.0.name": "Ada"`) {
+		t.Fatalf("expected --select to apply to raw response, got %q", stdout)
+	}
+
+	if _, stderr, err := captureExecute(t, []string{"api", "GET", "/v2/notes", "-F", "limit=2", "-f", "parent_object=people"}); err != nil {
+		t.Fatalf("unexpected error: %v\nstderr=%s", err, stderr)
+	}
+
+	input := filepath.Join(t.TempDir(), "body.json")
+	if err := os.WriteFile(input, []byte(`{"data":{"values":{"name":"Ada"}}}`), 0o600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+	if _, stderr, err := captureExecute(t, []string{"api", "PUT", "/v2/objects/people/records", "--input", input, "-f", "matching_attribute=email_addresses"}); err != nil {
+		t.Fatalf("unexpected error: %v\nstderr=%s", err, stderr)
+	}
+
+	want := []string{
+		`POST /v2/objects/people/records/query {"filter":{"name":{"$contains":"Ada"}},"limit":5,"note":"5","sorts":[{"attribute":"name","direction":"asc"}]}`,
+		`GET /v2/notes?limit=2&parent_object=people null`,
+		`PUT /v2/objects/people/records?matching_attribute=email_addresses {"data":{"values":{"name":"Ada"}}}`,
+		// The audit log looks up the workspace after the first write.
+		`GET /v2/self null`,
+	}
+	if strings.Join(requests, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected requests:\n%s", strings.Join(requests, "\n"))
+	}
+}
+
+func TestExecuteAPIPaginate(t *testing.T) {
+	setupCLIEnv(t)
+
+	var offsets []float64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/lists/deals/entries/query":
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			offset, _ := body["offset"].(float64)
+			offsets = append(offsets, offset)
+			items := []map[string]any{{"n": offset}, {"n": offset + 1}}
+			if offset >= 2 {
+				items = items[:1]
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": items})
+		case "/v2/meetings":
+			if r.URL.Query().Get("cursor") == "" {
+				_ = json.NewEncoder(w).Encode(map[string]any{"data": []any{"m1"}, "pagination": map[string]any{"next_cursor": "c2"}})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": []any{"m2"}, "pagination": map[string]any{"next_cursor": nil}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	t.Setenv("ATTIO_API_KEY", "env-key")
+	t.Setenv("ATTIO_BASE_URL", srv.URL)
+
+	stdout, stderr, err := captureExecute(t, []string{"--json", "--results-only", "api", "POST", "/v2/lists/deals/entries/query", "--paginate", "-F", "limit=2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v\nstderr=%s", err, stderr)
+	}
+	var items []map[string]any
+	if err := json.Unmarshal([]byte(stdout), &items); err != nil || len(items) != 3 {
+		t.Fatalf("expected 3 items across pages, got %s (%v)", stdout, err)
+	}
+	if len(offsets) != 2 || offsets[1] != 2 {
+		t.Fatalf("unexpected offsets: %v", offsets)
+	}
+
+	stdout, stderr, err = captureExecute(t, []string{"--json", "api", "GET", "/v2/meetings", "--paginate"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v\nstderr=%s", err, stderr)
+	}
+	if !strings.Contains(stdout, `"m1"`) || !strings.Contains(stdout, `"m2"`) {
+		t.Fatalf("expected both cursor pages, got %s", stdout)
+	}
+}
+
+func TestExecuteAPIValidation(t *testing.T) {
+	setupCLIEnv(t)
+	t.Setenv("ATTIO_API_KEY", "env-key")
+
+	cases := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"api", "GET", "/v2/nope"}, "unknown API path /v2/nope"},
+		{[]string{"api", "DELETE", "/v2/objects/people"}, "allowed: GET, PATCH"},
+		{[]string{"api", "GET", "/v2/objects", "--paginate"}, "not a paginated endpoint"},
+		{[]string{"api", "TRACE", "/v2/objects"}, "unsupported method"},
+		{[]string{"api", "POST", "/v2/notes", "-F", "bad"}, "expected key=value"},
+	}
+	for _, tc := range cases {
+		_, stderr, err := captureExecute(t, tc.args)
+		if ExitCode(err) != ExitCodeUsage || !strings.Contains(stderr, tc.want) {
+			t.Fatalf("%v: expected usage error containing %q, got %v / %q", tc.args, tc.want, err, stderr)
+		}
+	}
+
+	stdout, stderr, err := captureExecute(t, []string{"--json", "--dry-run", "api", "PATCH", "/v2/tasks/t1", "-F", "data[is_completed]=true"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v\nstderr=%s", err, stderr)
+	}
+	if !strings.Contains(stdout, `"is_completed": true`) || !strings.Contains(stdout, `"path": "/v2/tasks/t1"`) {
+		t.Fatalf("unexpected dry-run output: %s", stdout)
+	}
+}
+
+func TestCompleteAPIArguments(t *testing.T) {
+	got, err := completeWords(2, []string{"attio", "api", "PA"})
+	if err != nil || strings.Join(got, ",") != "PATCH" {
+		t.Fatalf("unexpected method completions: %v (%v)", got, err)
+	}
+	got, err = completeWords(4, []string{"attio", "--profile", "work", "api", "g"})
+	if err != nil || strings.Join(got, ",") != "GET" {
+		t.Fatalf("unexpected method completions after global flags: %v (%v)", got, err)
+	}
+	got, err = completeWords(5, []string{"attio", "--profile", "work", "api", "GET", "/v2/se"})
+	if err != nil || strings.Join(got, ",") != "/v2/self" {
+		t.Fatalf("unexpected path completions: %v (%v)", got, err)
+	}
+}
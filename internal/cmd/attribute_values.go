@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/failup-ventures/attio-cli/internal/api"
+)
+
+// attributeDef is the subset of attribute metadata the CLI needs to build and
+// check attribute values.
+type attributeDef struct {
+	ID            string
+	Slug          string
+	Title         string
+	Type          string
+	Multiselect   bool
+	Required      bool
+	Unique        bool
+	Writable      bool
+	Archived      bool
+	CurrencyCode  string
+	TargetObjects []string
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+func attributeDefFromMap(m map[string]any) attributeDef {
+	def := attributeDef{
+		ID:       mapString(mapMap(m, "id"), "attribute_id"),
+		Slug:     mapString(m, "api_slug"),
+		Title:    mapString(m, "title"),
+		Type:     mapString(m, "type"),
+		Writable: true,
+	}
+	if def.ID == "" {
+		def.ID = idString(m["id"])
+	}
+	if def.Type == "" {
+		def.Type = mapString(m, "api_type")
+	}
+	def.Multiselect, _ = m["is_multiselect"].(bool)
+	def.Required, _ = m["is_required"].(bool)
+	def.Unique, _ = m["is_unique"].(bool)
+	def.Archived, _ = m["is_archived"].(bool)
+	if writable, ok := m["is_writable"].(bool); ok {
+		def.Writable = writable
+	}
+
+	cfg := mapMap(m, "config")
+	def.CurrencyCode = mapString(mapMap(cfg, "currency"), "default_currency_code")
+	for _, key := range []string{"allowed_object_ids", "allowed_objects"} {
+		allowed, _ := mapMap(cfg, "record_reference")[key].([]any)
+		for _, item := range allowed {
+			if s := anyString(item); s != "" {
+				def.TargetObjects = append(def.TargetObjects, s)
+			}
+		}
+		if len(def.TargetObjects) > 0 {
+			break
+		}
+	}
+	return def
+}
+
+// listAttributeDefs loads the non-archived attributes of an object or list.
+func listAttributeDefs(ctx context.Context, client *api.Client, target string, identifier string) ([]attributeDef, error) {
+	attrs, err := client.ListAttributes(ctx, target, identifier, false, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	return attributeDefsFromList(attrs), nil
+}
+
+// attributeDefsFromList parses an attribute listing, leaving out archived
+// attributes.
+func attributeDefsFromList(attrs []map[string]any) []attributeDef {
+	defs := make([]attributeDef, 0, len(attrs))
+	for _, attr := range attrs {
+		def := attributeDefFromMap(attr)
+		if def.Slug == "" || def.Archived {
+			continue
+		}
+		defs = append(defs, def)
+	}
+	return defs
+}
+
+// attributeValuesFromString converts a raw string into the array of input values
+// Attio expects for the attribute. Multiselect attributes split on ';'.
+func attributeValuesFromString(def attributeDef, raw string) ([]any, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return []any{}, nil
+	}
+
+	parts := []string{raw}
+	if def.Multiselect {
+		parts = parts[:0]
+		for _, p := range strings.Split(raw, ";") {
+			if p = strings.TrimSpace(p); p != "" {
+				parts = append(parts, p)
+			}
+		}
+	}
+
+	values := make([]any, 0, len(parts))
+	for _, part := range parts {
+		v, err := attributeValueFromString(def, part)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// attributeValueFromString converts a single raw string into one Attio input value.
+func attributeValueFromString(def attributeDef, raw string) (any, error) {
+	raw = strings.TrimSpace(raw)
+	switch def.Type {
+	case "text":
+		return map[string]any{"value": raw}, nil
+	case "number":
+		n, err := parseLooseNumber(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid number %q", def.Slug, raw)
+		}
+		return map[string]any{"value": n}, nil
+	case "checkbox":
+		b, ok := parseLooseBool(raw)
+		if !ok {
+			return nil, fmt.Errorf("%s: invalid checkbox value %q (expected true or false)", def.Slug, raw)
+		}
+		return map[string]any{"value": b}, nil
+	case "rating":
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 || n > 5 {
+			return nil, fmt.Errorf("%s: rating must be an integer between 0 and 5", def.Slug)
+		}
+		return map[string]any{"value": float64(n)}, nil
+	case "currency":
+		return currencyValueFromString(def, raw)
+	case "date", "timestamp":
+		return map[string]any{"value": raw}, nil
+	case "status":
+		return map[string]any{"status": raw}, nil
+	case "select":
+		return map[string]any{"option": raw}, nil
+	case "domain":
+		return map[string]any{"domain": raw}, nil
+	case "email-address":
+		return map[string]any{"email_address": raw}, nil
+	case "phone-number":
+		return map[string]any{"original_phone_number": raw}, nil
+	case "personal-name":
+		return personalNameFromString(raw), nil
+	case "actor-reference":
+		if strings.Contains(raw, "@") {
+			return map[string]any{"workspace_member_email_address": raw}, nil
+		}
+		return map[string]any{"referenced_actor_type": "workspace-member", "referenced_actor_id": raw}, nil
+	case "record-reference":
+		return recordReferenceFromString(def, raw)
+	default:
+		return nil, fmt.Errorf("%s: attributes of type %q cannot be set from a plain value; use --data", def.Slug, def.Type)
+	}
+}
+
+func currencyValueFromString(def attributeDef, raw string) (any, error) {
+	amount, currency := raw, ""
+	fields := strings.Fields(raw)
+	if len(fields) == 2 {
+		code, number := fields[0], fields[1]
+		if _, err := parseLooseNumber(code); err == nil {
+			code, number = number, code
+		}
+		code = strings.ToUpper(code)
+		if def.CurrencyCode != "" && code != def.CurrencyCode {
+			return nil, fmt.Errorf("%s: currency %s does not match attribute currency %s", def.Slug, code, def.CurrencyCode)
+		}
+		amount, currency = number, code
+	}
+	n, err := parseLooseNumber(amount)
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid currency amount %q", def.Slug, raw)
+	}
+	if currency != "" {
+		return map[string]any{"currency_value": n, "currency_code": currency}, nil
+	}
+	return map[string]any{"currency_value": n}, nil
+}
+
+func personalNameFromString(raw string) map[string]any {
+	first, last := "", ""
+	if before, after, ok := strings.Cut(raw, ","); ok {
+		last = strings.TrimSpace(before)
+		first = strings.TrimSpace(after)
+	} else {
+		fields := strings.Fields(raw)
+		switch len(fields) {
+		case 0:
+		case 1:
+			first = fields[0]
+		default:
+			first = strings.Join(fields[:len(fields)-1], " ")
+			last = fields[len(fields)-1]
+		}
+	}
+	return map[string]any{
+		"first_name": first,
+		"last_name":  last,
+		"full_name":  strings.TrimSpace(first + " " + last),
+	}
+}
+
+// recordReferenceFromString accepts a record UUID, an email address or a domain,
+// optionally prefixed with the target object ("companies:acme.com").
+func recordReferenceFromString(def attributeDef, raw string) (any, error) {
+	target := ""
+	value := raw
+	if before, after, ok := strings.Cut(raw, ":"); ok && !strings.Contains(before, "@") {
+		target = strings.TrimSpace(before)
+		value = strings.TrimSpace(after)
+	}
+	if target == "" && len(def.TargetObjects) == 1 {
+		target = def.TargetObjects[0]
+	}
+	if target == "" {
+		return nil, fmt.Errorf("%s: cannot infer target object; use <object>:<value>", def.Slug)
+	}
+
+	switch {
+	case uuidPattern.MatchString(value):
+		return map[string]any{"target_object": target, "target_record_id": value}, nil
+	case strings.Contains(value, "@"):
+		return map[string]any{"target_object": target, "email_addresses": []any{map[string]any{"email_address": value}}}, nil
+	case strings.Contains(value, "."):
+		return map[string]any{"target_object": target, "domains": []any{map[string]any{"domain": value}}}, nil
+	default:
+		return nil, fmt.Errorf("%s: expected a record ID, email address or domain, got %q", def.Slug, raw)
+	}
+}
+
+func parseLooseNumber(raw string) (float64, error) {
+	raw = strings.ReplaceAll(strings.TrimSpace(raw), ",", "")
+	return strconv.ParseFloat(raw, 64)
+}
+
+func parseLooseBool(raw string) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "true", "t", "yes", "y", "1", "x":
+		return true, true
+	case "false", "f", "no", "n", "0":
+		return false, true
+	default:
+		return false, false
+	}
+}
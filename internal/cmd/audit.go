@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/failup-ventures/attio-cli/internal/api"
+	"github.com/failup-ventures/attio-cli/internal/audit"
+	"github.com/failup-ventures/attio-cli/internal/config"
+	"github.com/failup-ventures/attio-cli/internal/outfmt"
+)
+
+type AuditCmd struct {
+	Log AuditLogCmd `cmd:"" help:"Show the requests this CLI sent to change data"`
+}
+
+type AuditLogCmd struct {
+	Since       string `name:"since" help:"Only entries at or after this time (RFC 3339, YYYY-MM-DD, or a duration ago such as 24h or 7d)"`
+	Until       string `name:"until" help:"Only entries at or before this time (same formats as --since; a date includes that whole day)"`
+	Command     string `name:"command" help:"Only entries from this command path or its subcommands, e.g. 'records delete'"`
+	Resource    string `name:"resource" help:"Only entries for this resource ID or path segment, e.g. a record ID or object slug"`
+	AllProfiles bool   `name:"all-profiles" help:"Include entries from every profile, not just the selected one"`
+	Limit       int    `name:"limit" help:"Show only the most recent N entries (0 for all)" default:"50"`
+}
+
+func (c *AuditLogCmd) Run(ctx context.Context, flags *RootFlags) error {
+	path, _, err := config.ResolveAuditLog(flags.Profile)
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		return newUsageError(errors.New("the audit log is turned off (audit_log or ATTIO_AUDIT_LOG is \"off\")"))
+	}
+	if c.Limit < 0 {
+		return newUsageError(errors.New("--limit must be 0 or greater"))
+	}
+
+	now := time.Now()
+	filter := audit.Filter{
+		Command:  normalizeCommandPath(c.Command),
+		Resource: strings.TrimSpace(c.Resource),
+	}
+	if filter.Since, err = parseAuditTime(c.Since, now, false); err != nil {
+		return newUsageError(fmt.Errorf("--since: %w", err))
+	}
+	if filter.Until, err = parseAuditTime(c.Until, now, true); err != nil {
+		return newUsageError(fmt.Errorf("--until: %w", err))
+	}
+	if !c.AllProfiles {
+		filter.Profile = config.ResolveProfile(flags.Profile)
+	}
+
+	entries, err := audit.Read(path, filter)
+	if err != nil {
+		return err
+	}
+	if c.Limit > 0 && len(entries) > c.Limit {
+		entries = entries[len(entries)-c.Limit:]
+	}
+	if err := maybeFailEmpty(ctx, len(entries)); err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		if entries == nil {
+			entries = []audit.Entry{}
+		}
+		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{"data": entries})
+	}
+
+	w, done := tableWriter(ctx)
+	defer done()
+	_, _ = fmt.Fprintln(w, "TIME\tPROFILE\tCOMMAND\tMETHOD\tPATH\tSTATUS\tRESOURCE_ID")
+	for _, e := range entries {
+		status := strconv.Itoa(e.Status)
+		if e.Error != "" {
+			status = "error"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			e.Time.Local().Format(time.RFC3339),
+			e.Profile,
+			e.Command,
+			e.Method,
+			e.Path,
+			status,
+			e.ResourceID,
+		)
+	}
+	return nil
+}
+
+// parseAuditTime accepts RFC 3339, a date, or a duration before now ("24h",
+// "7d"). "" is the zero time. A date is the start of that day, or its last
+// instant with endOfDay, so --until includes the whole day.
+func parseAuditTime(value string, now time.Time, endOfDay bool) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		if endOfDay {
+			t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+		return t, nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (expected RFC 3339, YYYY-MM-DD, or a duration such as 24h or 7d)", value)
+}
+
+var (
+	auditMu     sync.Mutex
+	commandPath string
+	// auditWorkspaces caches the workspace of each profile and base URL for
+	// this process.
+	auditWorkspaces = map[string]string{}
+)
+
+func setCommandPath(command string) {
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	commandPath = command
+}
+
+func currentCommandPath() string {
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	return commandPath
+}
+
+// newAuditor returns the auditor that appends client's writes for profile to
+// the audit log, or nil when the log is turned off.
+func newAuditor(profile string, client *api.Client) (api.Auditor, error) {
+	path, mode, err := config.ResolveAuditLog(profile)
+	if err != nil {
+		return nil, err
+	}
+	if mode, err = audit.ParseBodyMode(mode); err != nil {
+		return nil, fmt.Errorf("audit_body: %w", err)
+	}
+	if path == "" {
+		return nil, nil
+	}
+	profile = config.ResolveProfile(profile)
+	return func(ctx context.Context, rec api.AuditRecord) {
+		// Query and search endpoints use POST but change nothing.
+		if isReadRequest(rec.Method, rec.Path) {
+			return
+		}
+		entry := audit.Entry{
+			Time:       time.Now().UTC(),
+			Profile:    profile,
+			Workspace:  auditWorkspace(ctx, profile, client),
+			Command:    currentCommandPath(),
+			Method:     rec.Method,
+			Path:       rec.Path,
+			Status:     rec.Status,
+			ResourceID: auditResourceID(rec),
+		}
+		if rec.Err != nil {
+			entry.Error = rec.Err.Error()
+		}
+		entry.CaptureBody(rec.Body, mode)
+		if err := audit.Append(path, entry); err != nil {
+			slog.Warn("audit log", "error", err)
+		}
+	}, nil
+}
+
+func auditWorkspace(ctx context.Context, profile string, client *api.Client) string {
+	key := profile + "|" + config.ResolveBaseURL(profile)
+	auditMu.Lock()
+	workspace, ok := auditWorkspaces[key]
+	auditMu.Unlock()
+	if ok {
+		return workspace
+	}
+	self, err := client.GetSelf(ctx)
+	if err != nil {
+		// Leave the workspace out of this entry and ask again for the next.
+		slog.Debug("audit: look up workspace", "error", err)
+		return ""
+	}
+	workspace = firstNonEmpty(self.WorkspaceSlug, self.WorkspaceName, self.WorkspaceID)
+	auditMu.Lock()
+	auditWorkspaces[key] = workspace
+	auditMu.Unlock()
+	return workspace
+}
+
+// auditResourceID takes the ID of the returned resource, falling back to the
+// last path segment for updates and deletes.
+func auditResourceID(rec api.AuditRecord) string {
+	var resp struct {
+		Data struct {
+			ID any `json:"id"`
+		} `json:"data"`
+	}
+	if len(rec.Response) > 0 && json.Unmarshal(rec.Response, &resp) == nil {
+		if id := idString(resp.Data.ID); id != "" {
+			return id
+		}
+	}
+	if rec.Method == http.MethodPost {
+		return ""
+	}
+	segments := strings.Split(strings.Trim(stripQuery(rec.Path), "/"), "/")
+	return segments[len(segments)-1]
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
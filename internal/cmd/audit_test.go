@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/failup-ventures/attio-cli/internal/api"
+	"github.com/failup-ventures/attio-cli/internal/audit"
+	"github.com/failup-ventures/attio-cli/mockserver"
+)
+
+func TestAuditLogRecordsWrites(t *testing.T) {
+	setupCLIEnv(t)
+	handler, err := mockserver.New(nil)
+	if err != nil {
+		t.Fatalf("new mock server: %v", err)
+	}
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+	t.Setenv("ATTIO_API_KEY", "mock")
+	t.Setenv("ATTIO_BASE_URL", srv.URL)
+	logPath := filepath.Join(t.TempDir(), "audit.jsonl")
+	t.Setenv("ATTIO_AUDIT_LOG", logPath)
+	t.Setenv("ATTIO_AUDIT_BODY", audit.BodyFull)
+
+	stdout, stderr, err := captureExecute(t, []string{"--id-only", "records", "create", "people", "--data", `{"values":{"name":"Ada Lovelace"}}`})
+	if err != nil {
+		t.Fatalf("create: %v stderr=%s", err, stderr)
+	}
+	id := strings.TrimSpace(stdout)
+	for _, args := range [][]string{
+		{"records", "query", "people"},
+		{"records", "update", "people", id, "--data", `{"values":{"name":"Ada King"}}`},
+		{"--yes", "records", "delete", "people", id},
+	} {
+		if _, stderr, err := captureExecute(t, args); err != nil {
+			t.Fatalf("%v: %v stderr=%s", args, err, stderr)
+		}
+	}
+	// Failed writes are logged too, with secrets redacted from the body.
+	_, _, _ = captureExecute(t, []string{"api", "POST", "/v2/objects/missing/records", "-F", "data[values][name]=x", "-F", "data[access_token]=s3cret"})
+
+	raw, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("read log: %v", err)
+	}
+	if strings.Contains(string(raw), "s3cret") {
+		t.Fatalf("expected secrets to be redacted:\n%s", raw)
+	}
+
+	stdout, stderr, err = captureExecute(t, []string{"--json", "audit", "log"})
+	if err != nil {
+		t.Fatalf("audit log: %v stderr=%s", err, stderr)
+	}
+	var got struct {
+		Data []audit.Entry `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &got); err != nil {
+		t.Fatalf("decode: %v\n%s", err, stdout)
+	}
+	var summary []string
+	for _, e := range got.Data {
+		summary = append(summary, e.Command+" "+e.Method+" "+e.ResourceID)
+		if e.Workspace != "mock-workspace" || e.Profile != "default" || e.Time.IsZero() {
+			t.Fatalf("unexpected entry: %+v", e)
+		}
+	}
+	want := "records create POST " + id + "|records update PATCH " + id + "|records delete DELETE " + id + "|api POST "
+	if strings.Join(summary, "|") != want {
+		t.Fatalf("unexpected entries:\n%s", strings.Join(summary, "\n"))
+	}
+	if got.Data[0].BodySHA256 == "" || !strings.Contains(string(got.Data[0].Body), "Ada Lovelace") || got.Data[3].Status < 400 {
+		t.Fatalf("unexpected body capture or status: %+v %+v", got.Data[0], got.Data[3])
+	}
+
+	stdout, _, err = captureExecute(t, []string{"audit", "log", "--command", "records delete", "--resource", id, "--since", "1h"})
+	if err != nil || strings.Count(stdout, "\n") != 2 || !strings.Contains(stdout, "DELETE") {
+		t.Fatalf("expected one filtered row, got %v\n%s", err, stdout)
+	}
+	future := time.Now().Add(time.Hour).Format(time.RFC3339)
+	if _, _, err := captureExecute(t, []string{"--fail-empty", "audit", "log", "--since", future}); ExitCode(err) != ExitCodeNoResult {
+		t.Fatalf("expected no entries after %s, got %v", future, err)
+	}
+	// A date as --until includes the whole day.
+	today := time.Now().Format("2006-01-02")
+	stdout, _, err = captureExecute(t, []string{"--json", "audit", "log", "--since", today, "--until", today})
+	if err != nil || strings.Count(stdout, `"method"`) != 4 {
+		t.Fatalf("expected today's 4 entries, got %v\n%s", err, stdout)
+	}
+	if _, _, err := captureExecute(t, []string{"audit", "log", "--since", "yesterday"}); ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected an invalid --since to be a usage error, got %v", err)
+	}
+
+	t.Setenv("ATTIO_AUDIT_LOG", "off")
+	if _, _, err := captureExecute(t, []string{"--yes", "tasks", "create", "--content", "x"}); err != nil {
+		t.Fatalf("tasks create: %v", err)
+	}
+	after, _ := os.ReadFile(logPath)
+	if len(after) != len(raw) {
+		t.Fatal("expected nothing to be logged with ATTIO_AUDIT_LOG=off")
+	}
+}
+
+func TestAuditWorkspaceRetriesFailedLookup(t *testing.T) {
+	setupCLIEnv(t)
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(`{"active":true,"workspace_slug":"acme"}`))
+	}))
+	defer srv.Close()
+	t.Setenv("ATTIO_BASE_URL", srv.URL)
+	t.Cleanup(func() {
+		auditMu.Lock()
+		defer auditMu.Unlock()
+		auditWorkspaces = map[string]string{}
+	})
+
+	client := api.NewClient("key", srv.URL)
+	client.SetMaxRetries(0)
+	ctx := context.Background()
+	for _, want := range []string{"", "acme", "acme"} {
+		if got := auditWorkspace(ctx, "default", client); got != want {
+			t.Fatalf("expected workspace %q, got %q", want, got)
+		}
+	}
+	if n := calls.Load(); n != 2 {
+		t.Fatalf("expected the failed lookup to be retried once and the result cached, got %d calls", n)
+	}
+}
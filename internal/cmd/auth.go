@@ -7,6 +7,7 @@ import (
 	"io"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/99designs/keyring"
 
@@ -16,17 +17,31 @@ import (
 )
 
 type AuthCmd struct {
-	Login  AuthLoginCmd  `cmd:"" help:"Store API key in keyring"`
-	Logout AuthLogoutCmd `cmd:"" help:"Remove API key from keyring"`
+	Login  AuthLoginCmd  `cmd:"" help:"Store API key or OAuth tokens in keyring"`
+	Logout AuthLogoutCmd `cmd:"" help:"Remove API key and OAuth tokens from keyring"`
 	Status AuthStatusCmd `cmd:"" help:"Show auth status"`
 }
 
 type AuthLoginCmd struct {
-	APIKey string `name:"api-key" help:"Attio API key to store. If omitted, reads from stdin when piped."`
+	APIKey       string `name:"api-key" help:"Attio API key to store. If omitted, reads from stdin when piped."`
+	OAuth        bool   `name:"oauth" help:"Log in as a workspace member with OAuth (authorization code + PKCE) instead of an API key"`
+	ClientID     string `name:"client-id" env:"ATTIO_CLIENT_ID" help:"OAuth client ID of your Attio app"`
+	ClientSecret string `name:"client-secret" env:"ATTIO_CLIENT_SECRET" help:"OAuth client secret, for apps that require one"`
+	Scopes       string `name:"scopes" help:"Comma-separated OAuth scopes to request (default: the app's scopes)"`
+	CallbackPort int    `name:"callback-port" default:"8910" help:"Local port for the OAuth redirect URI http://127.0.0.1:<port>/callback"`
+	NoBrowser    bool   `name:"no-browser" help:"Print the authorization URL without opening a browser"`
+	AuthorizeURL string `name:"authorize-url" default:"https://app.attio.com/authorize" hidden:""`
+	TokenURL     string `name:"token-url" default:"https://app.attio.com/oauth/token" hidden:""`
 }
 
 func (c *AuthLoginCmd) Run(ctx context.Context, flags *RootFlags) error {
 	profile := config.ResolveProfile(flags.Profile)
+	if c.OAuth {
+		if strings.TrimSpace(c.APIKey) != "" {
+			return newUsageError(errors.New("use either --api-key or --oauth"))
+		}
+		return c.runOAuth(ctx, profile)
+	}
 	apiKey := strings.TrimSpace(c.APIKey)
 	if apiKey == "" {
 		apiKey = readKeyFromStdin()
@@ -41,6 +56,9 @@ func (c *AuthLoginCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if err := config.StoreAPIKey(profile, apiKey); err != nil {
 		return err
 	}
+	if err := config.RemoveOAuthCredentials(profile); err != nil && !errors.Is(err, keyring.ErrKeyNotFound) {
+		return err
+	}
 
 	if outfmt.IsJSON(ctx) {
 		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{
@@ -61,52 +79,126 @@ func (c *AuthLogoutCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if ok, err := maybeDryRun(ctx, "auth logout", map[string]any{"profile": profile}); ok || err != nil {
 		return err
 	}
-	err := config.RemoveAPIKey(profile)
-	removed := true
-	if errors.Is(err, keyring.ErrKeyNotFound) {
-		removed = false
-		err = nil
+	removedKey, err := removeCredential(config.RemoveAPIKey, profile)
+	if err != nil {
+		return err
 	}
+	removedOAuth, err := removeCredential(config.RemoveOAuthCredentials, profile)
 	if err != nil {
 		return err
 	}
 
 	if outfmt.IsJSON(ctx) {
 		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{
-			"removed": removed,
+			"removed": removedKey || removedOAuth,
 			"profile": profile,
 		})
 	}
 	u := ui.FromContext(ctx)
-	if removed {
+	if removedKey {
 		u.Out().Successf("Removed API key for profile %q", profile)
-	} else {
+	}
+	if removedOAuth {
+		u.Out().Successf("Removed OAuth tokens for profile %q", profile)
+	}
+	if !removedKey && !removedOAuth {
 		u.Out().Printf("No API key stored for profile %q", profile)
 	}
 	return nil
 }
 
-type AuthStatusCmd struct{}
+// removeCredential runs remove and reports whether there was anything to
+// remove.
+func removeCredential(remove func(string) error, profile string) (bool, error) {
+	err := remove(profile)
+	if errors.Is(err, keyring.ErrKeyNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+type AuthStatusCmd struct {
+	Verify bool `name:"verify" help:"Ask the API for the token's type, expiry and scopes (always done for OAuth tokens)"`
+}
 
 func (c *AuthStatusCmd) Run(ctx context.Context, flags *RootFlags) error {
 	status := config.AuthStatus(flags.Profile)
+	if status.Resolved && (c.Verify || status.ResolvedSource == config.AuthSourceOAuth) {
+		if err := describeToken(ctx, flags.Profile, &status); err != nil && c.Verify {
+			return err
+		}
+	}
+	status.Settings = settingSources(ctx)
 	if outfmt.IsJSON(ctx) {
 		return outfmt.WriteJSON(ctx, os.Stdout, status)
 	}
 
 	w, done := tableWriter(ctx)
-	defer done()
 	_, _ = fmt.Fprintln(w, "FIELD\tVALUE")
 	_, _ = fmt.Fprintf(w, "profile\t%s\n", status.Profile)
 	_, _ = fmt.Fprintf(w, "base_url\t%s\n", status.BaseURL)
 	_, _ = fmt.Fprintf(w, "has_env\t%t\n", status.HasEnv)
+	_, _ = fmt.Fprintf(w, "has_command\t%t\n", status.HasCommand)
 	_, _ = fmt.Fprintf(w, "has_keyring\t%t\n", status.HasKeyring)
+	_, _ = fmt.Fprintf(w, "has_oauth\t%t\n", status.HasOAuth)
 	_, _ = fmt.Fprintf(w, "has_config\t%t\n", status.HasConfig)
 	_, _ = fmt.Fprintf(w, "resolved\t%t\n", status.Resolved)
 	if status.Resolved {
 		_, _ = fmt.Fprintf(w, "resolved_source\t%s\n", status.ResolvedSource)
 		_, _ = fmt.Fprintf(w, "api_key\t%s\n", status.MaskedKey)
 	}
+	if status.TokenType != "" {
+		_, _ = fmt.Fprintf(w, "token_type\t%s\n", status.TokenType)
+	}
+	if status.ExpiresAt != "" {
+		_, _ = fmt.Fprintf(w, "expires_at\t%s\n", status.ExpiresAt)
+	}
+	if len(status.Scopes) > 0 {
+		_, _ = fmt.Fprintf(w, "scopes\t%s\n", strings.Join(status.Scopes, " "))
+	}
+	if status.Error != "" {
+		_, _ = fmt.Fprintf(w, "error\t%s\n", status.Error)
+	}
+	done()
+	writeSettingSources(ctx, status.Settings)
+	return nil
+}
+
+// writeSettingSources prints each effective setting with the flag, variable
+// or file it came from.
+func writeSettingSources(ctx context.Context, settings []config.SettingSource) {
+	if len(settings) == 0 {
+		return
+	}
+	w, done := tableWriter(ctx)
+	defer done()
+	_, _ = fmt.Fprintln(w, "")
+	_, _ = fmt.Fprintln(w, "SETTING\tVALUE\tSOURCE")
+	for _, s := range settings {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", s.Setting, s.Value, s.Source)
+	}
+}
+
+// describeToken fills in the token type, expiry and scopes reported by
+// GET /v2/self, refreshing an expired OAuth token on the way.
+func describeToken(ctx context.Context, profile string, status *config.Status) error {
+	client, err := requireClient(profile)
+	if err != nil {
+		return err
+	}
+	self, err := client.GetSelf(ctx)
+	if err != nil {
+		return err
+	}
+	if self.TokenType != "" {
+		status.TokenType = self.TokenType
+	}
+	if self.Exp != nil {
+		status.ExpiresAt = time.Unix(*self.Exp, 0).UTC().Format(time.RFC3339)
+	}
+	if scopes := strings.Fields(self.Scope); len(scopes) > 0 {
+		status.Scopes = scopes
+	}
 	return nil
 }
 
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/99designs/keyring"
+
+	"github.com/failup-ventures/attio-cli/internal/config"
+	"github.com/failup-ventures/attio-cli/internal/oauth"
+	"github.com/failup-ventures/attio-cli/internal/outfmt"
+	"github.com/failup-ventures/attio-cli/internal/ui"
+)
+
+// oauthLoginTimeout bounds how long login waits for the browser redirect.
+const oauthLoginTimeout = 5 * time.Minute
+
+// openBrowser opens url in the user's browser. Tests replace it to follow
+// the authorization redirect themselves.
+var openBrowser = func(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	return cmd.Start()
+}
+
+func (c *AuthLoginCmd) runOAuth(ctx context.Context, profile string) error {
+	clientID := strings.TrimSpace(c.ClientID)
+	if clientID == "" {
+		return newUsageError(errors.New("--oauth requires --client-id (or ATTIO_CLIENT_ID)"))
+	}
+	redirectURL := fmt.Sprintf("http://127.0.0.1:%d%s", c.CallbackPort, oauth.CallbackPath)
+	cfg := oauth.Config{
+		ClientID:     clientID,
+		ClientSecret: strings.TrimSpace(c.ClientSecret),
+		AuthorizeURL: c.AuthorizeURL,
+		TokenURL:     c.TokenURL,
+		RedirectURL:  redirectURL,
+		Scopes:       splitCommaList(c.Scopes),
+	}
+	if ok, err := maybeDryRun(ctx, "auth login --oauth", map[string]any{
+		"profile":       profile,
+		"client_id":     clientID,
+		"redirect_uri":  redirectURL,
+		"authorize_url": cfg.AuthorizeURL,
+		"scopes":        cfg.Scopes,
+	}); ok || err != nil {
+		return err
+	}
+
+	verifier, challenge, err := oauth.NewVerifier()
+	if err != nil {
+		return err
+	}
+	state, err := oauth.NewState()
+	if err != nil {
+		return err
+	}
+	listener, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(c.CallbackPort)))
+	if err != nil {
+		return fmt.Errorf("listen for the OAuth callback: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, oauthLoginTimeout)
+	defer cancel()
+
+	authURL := cfg.AuthCodeURL(state, challenge)
+	_, _ = fmt.Fprintf(os.Stderr, "Open this URL to authorize attio-cli:\n\n  %s\n\n", authURL)
+	if !c.NoBrowser {
+		if err := openBrowser(authURL); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Could not open a browser (%v); open the URL manually.\n", err)
+		}
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "Waiting for the redirect to %s ...\n", redirectURL)
+
+	code, err := oauth.WaitForCode(ctx, listener, state)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("timed out after %s waiting for the OAuth redirect", oauthLoginTimeout)
+		}
+		return err
+	}
+	tok, err := cfg.Exchange(ctx, http.DefaultClient, code, verifier)
+	if err != nil {
+		return err
+	}
+
+	creds := config.OAuthCredentials{Token: *tok, ClientID: cfg.ClientID, ClientSecret: cfg.ClientSecret, TokenURL: cfg.TokenURL}
+	if err := config.StoreOAuthCredentials(profile, creds); err != nil {
+		return err
+	}
+	// The newest login wins: drop a stored API key so the token is used.
+	if err := config.RemoveAPIKey(profile); err != nil && !errors.Is(err, keyring.ErrKeyNotFound) {
+		return err
+	}
+
+	expiresAt := ""
+	if !tok.Expiry.IsZero() {
+		expiresAt = tok.Expiry.Format(time.RFC3339)
+	}
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{
+			"saved":      true,
+			"profile":    profile,
+			"source":     config.AuthSourceOAuth,
+			"token_type": tok.TokenType,
+			"expires_at": expiresAt,
+			"scopes":     strings.Fields(tok.Scope),
+		})
+	}
+	u := ui.FromContext(ctx)
+	u.Out().Successf("Stored OAuth tokens for profile %q in keyring", profile)
+	return nil
+}
+
+// freshOAuthToken returns the profile's OAuth access token, refreshing and
+// re-storing it first when it has expired.
+func freshOAuthToken(profile string) (string, error) {
+	creds, err := config.LoadOAuthCredentials(profile)
+	if err != nil {
+		return "", err
+	}
+	if !creds.Expired(time.Now()) {
+		return creds.AccessToken, nil
+	}
+	if creds.RefreshToken == "" {
+		return "", &config.AuthRequiredError{
+			Message: fmt.Sprintf("OAuth token for profile %q expired. Run: attio auth login --oauth --client-id %s", config.ResolveProfile(profile), creds.ClientID),
+		}
+	}
+
+	_, timeout := getClientRuntimeOptions()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	cfg := oauth.Config{ClientID: creds.ClientID, ClientSecret: creds.ClientSecret, TokenURL: creds.TokenURL}
+	tok, err := cfg.Refresh(ctx, http.DefaultClient, creds.RefreshToken)
+	if err != nil {
+		var oauthErr *oauth.Error
+		if errors.As(err, &oauthErr) && oauthErr.Code == "invalid_grant" {
+			return "", &config.AuthRequiredError{
+				Message: fmt.Sprintf("OAuth refresh token for profile %q is no longer valid. Run: attio auth login --oauth --client-id %s", config.ResolveProfile(profile), creds.ClientID),
+			}
+		}
+		return "", fmt.Errorf("refresh OAuth token: %w", err)
+	}
+	creds.Token = *tok
+	if err := config.StoreOAuthCredentials(profile, *creds); err != nil {
+		return "", err
+	}
+	return tok.AccessToken, nil
+}
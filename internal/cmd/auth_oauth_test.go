@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/failup-ventures/attio-cli/internal/config"
+)
+
+// fakeOAuthServer is an authorization server and API in one: /authorize
+// approves immediately, /oauth/token issues tokens and /v2/self requires
+// the latest access token.
+type fakeOAuthServer struct {
+	mu        sync.Mutex
+	challenge string
+	access    string
+	refreshes int
+}
+
+func (f *fakeOAuthServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.URL.Path {
+	case "/authorize":
+		q := r.URL.Query()
+		f.challenge = q.Get("code_challenge")
+		redirect := q.Get("redirect_uri") + "?code=auth-code&state=" + url.QueryEscape(q.Get("state"))
+		http.Redirect(w, r, redirect, http.StatusFound)
+	case "/oauth/token":
+		_ = r.ParseForm()
+		switch r.PostForm.Get("grant_type") {
+		case "authorization_code":
+			sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+			if base64.RawURLEncoding.EncodeToString(sum[:]) != f.challenge || r.PostForm.Get("code") != "auth-code" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+				return
+			}
+			f.access = "access-1"
+		case "refresh_token":
+			if r.PostForm.Get("refresh_token") != "refresh-1" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+				return
+			}
+			f.refreshes++
+			f.access = "access-" + strconv.Itoa(f.refreshes+1)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": f.access, "refresh_token": "refresh-1", "token_type": "Bearer", "expires_in": 3600, "scope": "record:read",
+		})
+	case "/v2/self":
+		if r.Header.Get("Authorization") != "Bearer "+f.access {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"status_code":401,"type":"auth_error","code":"unauthorized","message":"stale token"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"active":true,"token_type":"Bearer","scope":"record:read user_management:read","exp":1900000000,"workspace_name":"Acme"}`))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func freePort(t *testing.T) int {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer func() { _ = l.Close() }()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+func TestAuthLoginOAuthAndRefresh(t *testing.T) {
+	setupCLIEnv(t)
+	fake := &fakeOAuthServer{}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+	t.Setenv("ATTIO_BASE_URL", srv.URL)
+
+	orig := openBrowser
+	openBrowser = func(authURL string) error {
+		go func() {
+			if resp, err := http.Get(authURL); err == nil {
+				_ = resp.Body.Close()
+			}
+		}()
+		return nil
+	}
+	t.Cleanup(func() { openBrowser = orig })
+
+	if _, stderr, err := captureExecute(t, []string{"auth", "login", "--api-key", "old-key"}); err != nil {
+		t.Fatalf("api key login: %v stderr=%s", err, stderr)
+	}
+	stdout, stderr, err := captureExecute(t, []string{
+		"--json", "auth", "login", "--oauth", "--client-id", "app",
+		"--callback-port", strconv.Itoa(freePort(t)),
+		"--authorize-url", srv.URL + "/authorize", "--token-url", srv.URL + "/oauth/token",
+	})
+	if err != nil {
+		t.Fatalf("oauth login: %v stderr=%s", err, stderr)
+	}
+	if !strings.Contains(stdout, `"source": "oauth"`) || !strings.Contains(stderr, "code_challenge_method=S256") {
+		t.Fatalf("unexpected login output: %s / %s", stdout, stderr)
+	}
+	if _, err := config.LoadAPIKey("default"); err == nil {
+		t.Fatalf("expected the OAuth login to replace the stored API key")
+	}
+
+	stdout, stderr, err = captureExecute(t, []string{"--json", "auth", "status"})
+	if err != nil {
+		t.Fatalf("auth status: %v stderr=%s", err, stderr)
+	}
+	var status config.Status
+	if err := json.Unmarshal([]byte(stdout), &status); err != nil {
+		t.Fatalf("decode status: %v\n%s", err, stdout)
+	}
+	if status.ResolvedSource != config.AuthSourceOAuth || status.TokenType != "Bearer" ||
+		status.ExpiresAt != "2030-03-17T17:46:40Z" || strings.Join(status.Scopes, ",") != "record:read,user_management:read" {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+
+	// An expired token is refreshed and re-stored before the request.
+	creds, err := config.LoadOAuthCredentials("default")
+	if err != nil {
+		t.Fatalf("load credentials: %v", err)
+	}
+	creds.Expiry = time.Now().Add(-time.Minute)
+	if err := config.StoreOAuthCredentials("default", *creds); err != nil {
+		t.Fatalf("store credentials: %v", err)
+	}
+	if _, stderr, err := captureExecute(t, []string{"self"}); err != nil {
+		t.Fatalf("self after expiry: %v stderr=%s", err, stderr)
+	}
+	creds, _ = config.LoadOAuthCredentials("default")
+	if fake.refreshes != 1 || creds.AccessToken != "access-2" || creds.Expired(time.Now()) {
+		t.Fatalf("expected one refresh to access-2, got %d refreshes and %+v", fake.refreshes, creds.Token)
+	}
+
+	// Without a refresh token the user is asked to log in again.
+	creds.RefreshToken = ""
+	creds.Expiry = time.Now().Add(-time.Minute)
+	_ = config.StoreOAuthCredentials("default", *creds)
+	_, stderr, err = captureExecute(t, []string{"self"})
+	if ExitCode(err) != ExitCodeAuth || !strings.Contains(stderr, "auth login --oauth") {
+		t.Fatalf("expected an auth error, got %v / %s", err, stderr)
+	}
+
+	stdout, _, err = captureExecute(t, []string{"auth", "logout"})
+	if err != nil || !strings.Contains(stdout, "Removed OAuth tokens") {
+		t.Fatalf("unexpected logout: %v %s", err, stdout)
+	}
+}
+
+func TestAuthLoginOAuthRequiresClientID(t *testing.T) {
+	setupCLIEnv(t)
+	t.Setenv("ATTIO_CLIENT_ID", "")
+	_, _, err := captureExecute(t, []string{"auth", "login", "--oauth"})
+	if ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected a usage error, got %v", err)
+	}
+}
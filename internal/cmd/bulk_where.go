@@ -0,0 +1,395 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/failup-ventures/attio-cli/internal/api"
+	"github.com/failup-ventures/attio-cli/internal/outfmt"
+	"github.com/failup-ventures/attio-cli/internal/undo"
+)
+
+// maxBulkConcurrency caps --concurrency; the rate limiter paces requests
+// beyond that anyway.
+const maxBulkConcurrency = 16
+
+// BulkFlags select the records or entries an update-where or delete-where
+// command changes, and bound how many and how fast.
+type BulkFlags struct {
+	Filter      string `name:"filter" help:"Filter JSON object selecting what to change ('{}' matches everything)"`
+	Where       string `name:"where" help:"Filter expression selecting what to change, e.g. 'stage = \"Lost\"'"`
+	Max         int    `name:"max" help:"Refuse to run when more than this many match (0 for no cap)" default:"100"`
+	Concurrency int    `name:"concurrency" help:"Number of requests to run at once" default:"4"`
+}
+
+func (f BulkFlags) filter(root string) (any, error) {
+	if strings.TrimSpace(f.Filter) == "" && strings.TrimSpace(f.Where) == "" {
+		return nil, newUsageError(errors.New("--filter or --where is required; pass --filter '{}' to match everything"))
+	}
+	if f.Max < 0 {
+		return nil, newUsageError(errors.New("--max must be 0 or greater"))
+	}
+	if f.Concurrency < 1 || f.Concurrency > maxBulkConcurrency {
+		return nil, newUsageError(fmt.Errorf("--concurrency must be between 1 and %d", maxBulkConcurrency))
+	}
+	return resolveQueryFilter(f.Filter, f.Where, root)
+}
+
+// bulkOp describes one update-where or delete-where run.
+type bulkOp struct {
+	command    string
+	verb       string
+	kind       string
+	parentKey  string
+	parent     string
+	filter     any
+	data       map[string]any
+	undoAction string
+	undoKeys   []string
+	query      func(ctx context.Context, limit int, offset int) ([]map[string]any, error)
+	apply      func(ctx context.Context, id string) error
+	describe   func(item map[string]any) string
+}
+
+func (op bulkOp) noun(n int) string {
+	if op.kind == undo.KindEntry {
+		return pluralWord(n, "entry", "entries")
+	}
+	return pluralWord(n, "record", "records")
+}
+
+// bulkResult is the outcome for one matched record or entry.
+type bulkResult struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+var errTooManyMatches = errors.New("too many matches")
+
+// collectBulkMatches pages through everything filter matches, stopping as
+// soon as more than max items have been seen.
+func collectBulkMatches(ctx context.Context, op bulkOp, max int) ([]map[string]any, error) {
+	limit := 500
+	if max > 0 && max+1 < limit {
+		limit = max + 1
+	}
+	seen := map[string]bool{}
+	var matches []map[string]any
+	err := api.EachOffsetPage(ctx, limit, 1<<20, func(offset int) ([]map[string]any, error) {
+		return op.query(ctx, limit, offset)
+	}, func(items []map[string]any) error {
+		for _, item := range items {
+			id := idString(item["id"])
+			if id == "" || seen[id] {
+				continue
+			}
+			seen[id] = true
+			matches = append(matches, item)
+		}
+		if max > 0 && len(matches) > max {
+			return errTooManyMatches
+		}
+		return nil
+	})
+	if errors.Is(err, errTooManyMatches) {
+		return nil, newUsageError(fmt.Errorf("more than %d %s in %s match; narrow the filter or raise --max", max, op.noun(max), op.parent))
+	}
+	return matches, err
+}
+
+// runBulkWhere finds the matches of op's filter, shows and confirms them,
+// and applies op to each with a bounded worker pool.
+func runBulkWhere(ctx context.Context, flags *RootFlags, f BulkFlags, op bulkOp) error {
+	matches, err := collectBulkMatches(ctx, op, f.Max)
+	if err != nil {
+		return err
+	}
+	ids := make([]string, 0, len(matches))
+	sample := make([]string, 0, len(matches))
+	for _, item := range matches {
+		ids = append(ids, idString(item["id"]))
+		sample = append(sample, op.describe(item))
+	}
+
+	preview := map[string]any{op.parentKey: op.parent, "filter": op.filter, "matched": len(ids), op.kind + "_ids": ids}
+	if op.data != nil {
+		preview["data"] = op.data
+	}
+	if ok, err := maybeDryRun(ctx, op.command, preview); ok || err != nil {
+		return err
+	}
+	if len(matches) == 0 {
+		if err := maybeFailEmpty(ctx, 0); err != nil {
+			return err
+		}
+		return writeBulkSummary(ctx, op, nil)
+	}
+
+	action := fmt.Sprintf("%s %d %s in %s", op.verb, len(ids), op.noun(len(ids)), op.parent)
+	if err := confirmBulkMatches(ctx, flags, action, op.parent, sample); err != nil {
+		return err
+	}
+
+	snapshot := snapshotFetched(flags.Profile, op.kind, op.undoAction, op.parent, matches, op.undoKeys)
+	results := runBulkPool(ctx, ids, f.Concurrency, op.apply)
+	if snapshot != nil {
+		kept := snapshot.Items[:0]
+		for i, item := range snapshot.Items {
+			if results[i].Status == "ok" {
+				kept = append(kept, item)
+			}
+		}
+		snapshot.Items = kept
+		saveSnapshot(ctx, snapshot, len(kept))
+	}
+
+	if err := writeBulkSummary(ctx, op, results); err != nil {
+		return err
+	}
+	if failed := countBulkFailures(results); failed > 0 {
+		return &ExitError{Code: ExitCodeGeneric, Err: fmt.Errorf("%d of %d %s could not be %sd", failed, len(results), op.noun(len(results)), op.verb)}
+	}
+	return nil
+}
+
+// runBulkPool calls fn for each ID with at most n calls at a time. Results
+// are in the order of ids; IDs not reached before ctx is cancelled are
+// reported as skipped.
+func runBulkPool(ctx context.Context, ids []string, n int, fn func(ctx context.Context, id string) error) []bulkResult {
+	results := make([]bulkResult, len(ids))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < n && w < len(ids); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if err := fn(ctx, ids[i]); err != nil {
+					results[i] = bulkResult{ID: ids[i], Status: "failed", Error: err.Error()}
+					continue
+				}
+				results[i] = bulkResult{ID: ids[i], Status: "ok"}
+			}
+		}()
+	}
+	next := 0
+feed:
+	for ; next < len(ids); next++ {
+		select {
+		case jobs <- next:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	for i := next; i < len(ids); i++ {
+		results[i] = bulkResult{ID: ids[i], Status: "skipped", Error: ctx.Err().Error()}
+	}
+	return results
+}
+
+func countBulkFailures(results []bulkResult) int {
+	n := 0
+	for _, r := range results {
+		if r.Status != "ok" {
+			n++
+		}
+	}
+	return n
+}
+
+func writeBulkSummary(ctx context.Context, op bulkOp, results []bulkResult) error {
+	failed := countBulkFailures(results)
+	if outfmt.IsJSON(ctx) {
+		if results == nil {
+			results = []bulkResult{}
+		}
+		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{"data": map[string]any{
+			op.parentKey: op.parent,
+			"matched":    len(results),
+			"succeeded":  len(results) - failed,
+			"failed":     failed,
+			"results":    results,
+		}})
+	}
+	if failed > 0 {
+		w, done := tableWriter(ctx)
+		_, _ = fmt.Fprintln(w, "ID\tSTATUS\tERROR")
+		for _, r := range results {
+			if r.Status != "ok" {
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", r.ID, r.Status, r.Error)
+			}
+		}
+		done()
+	}
+	if len(results) == 0 {
+		_, _ = fmt.Fprintf(os.Stdout, "No %s in %s match\n", op.noun(0), op.parent)
+		return nil
+	}
+	past := strings.ToUpper(op.verb[:1]) + op.verb[1:] + "d"
+	_, _ = fmt.Fprintf(os.Stdout, "%s %d of %d %s in %s", past, len(results)-failed, len(results), op.noun(len(results)), op.parent)
+	if failed > 0 {
+		_, _ = fmt.Fprintf(os.Stdout, " (%d failed)", failed)
+	}
+	_, _ = fmt.Fprintln(os.Stdout)
+	return nil
+}
+
+func describeRecordMatch(record map[string]any) string {
+	return describeResource("record", idString(record["id"]),
+		recordValueSummary(record, "name", "full_name", "company_name"),
+		recordValueSummary(record, "email_addresses", "email_address"),
+	)
+}
+
+func describeEntryMatch(entry map[string]any) string {
+	parent := mapString(entry, "parent_record_id")
+	if parent != "" {
+		parent = "record " + parent
+	}
+	return describeResource("entry", idString(entry["id"]), parent)
+}
+
+type RecordsUpdateWhereCmd struct {
+	Object     string `arg:"" name:"object" help:"Object slug or UUID" required:""`
+	Data       string `name:"data" help:"Record data JSON applied to every match; supports '-' or @file.json"`
+	ValueFlags `embed:""`
+	BulkFlags  `embed:""`
+}
+
+func (c *RecordsUpdateWhereCmd) Run(ctx context.Context, flags *RootFlags) error {
+	client, err := requireClient(flags.Profile)
+	if err != nil {
+		return err
+	}
+	filter, err := c.filter(c.Object)
+	if err != nil {
+		return err
+	}
+	if len(c.Remove) > 0 {
+		return newUsageError(errors.New("--remove is not supported by update-where; it needs each record's current values"))
+	}
+	data, _, err := c.payload(ctx, recordValueTarget(client, c.Object, ""), c.Data, valueWriteUpdate)
+	if err != nil {
+		return err
+	}
+	if err := validateWriteData(ctx, flags, newRecordValidator(client, c.Object), data, validateUpdate, ""); err != nil {
+		return err
+	}
+	return runBulkWhere(ctx, flags, c.BulkFlags, bulkOp{
+		command: "records update-where", verb: "update", kind: undo.KindRecord,
+		parentKey: "object", parent: c.Object, filter: filter, data: data,
+		undoAction: undo.ActionUpdate, undoKeys: writtenKeys(data, "values"),
+		query: func(ctx context.Context, limit int, offset int) ([]map[string]any, error) {
+			return client.QueryRecords(ctx, c.Object, filter, nil, limit, offset)
+		},
+		apply: func(ctx context.Context, id string) error {
+			_, err := client.UpdateRecord(ctx, c.Object, id, data)
+			return err
+		},
+		describe: describeRecordMatch,
+	})
+}
+
+type RecordsDeleteWhereCmd struct {
+	Object    string `arg:"" name:"object" help:"Object slug or UUID" required:""`
+	BulkFlags `embed:""`
+}
+
+func (c *RecordsDeleteWhereCmd) Run(ctx context.Context, flags *RootFlags) error {
+	client, err := requireClient(flags.Profile)
+	if err != nil {
+		return err
+	}
+	filter, err := c.filter(c.Object)
+	if err != nil {
+		return err
+	}
+	return runBulkWhere(ctx, flags, c.BulkFlags, bulkOp{
+		command: "records delete-where", verb: "delete", kind: undo.KindRecord,
+		parentKey: "object", parent: c.Object, filter: filter,
+		undoAction: undo.ActionDelete,
+		query: func(ctx context.Context, limit int, offset int) ([]map[string]any, error) {
+			return client.QueryRecords(ctx, c.Object, filter, nil, limit, offset)
+		},
+		apply: func(ctx context.Context, id string) error {
+			return client.DeleteRecord(ctx, c.Object, id)
+		},
+		describe: describeRecordMatch,
+	})
+}
+
+type EntriesUpdateWhereCmd struct {
+	List       string `arg:"" name:"list" help:"List slug or UUID" required:""`
+	Data       string `name:"data" help:"Entry data JSON applied to every match; supports '-' or @file.json"`
+	ValueFlags `embed:""`
+	BulkFlags  `embed:""`
+}
+
+func (c *EntriesUpdateWhereCmd) Run(ctx context.Context, flags *RootFlags) error {
+	client, err := requireClient(flags.Profile)
+	if err != nil {
+		return err
+	}
+	filter, err := c.filter(c.List)
+	if err != nil {
+		return err
+	}
+	if len(c.Remove) > 0 {
+		return newUsageError(errors.New("--remove is not supported by update-where; it needs each entry's current values"))
+	}
+	data, _, err := c.payload(ctx, entryValueTarget(client, c.List, ""), c.Data, valueWriteUpdate)
+	if err != nil {
+		return err
+	}
+	if err := validateWriteData(ctx, flags, newEntryValidator(client, c.List), data, validateUpdate, ""); err != nil {
+		return err
+	}
+	return runBulkWhere(ctx, flags, c.BulkFlags, bulkOp{
+		command: "entries update-where", verb: "update", kind: undo.KindEntry,
+		parentKey: "list", parent: c.List, filter: filter, data: data,
+		undoAction: undo.ActionUpdate, undoKeys: writtenKeys(data, "entry_values"),
+		query: func(ctx context.Context, limit int, offset int) ([]map[string]any, error) {
+			return client.QueryEntries(ctx, c.List, filter, nil, limit, offset)
+		},
+		apply: func(ctx context.Context, id string) error {
+			_, err := client.UpdateEntry(ctx, c.List, id, data)
+			return err
+		},
+		describe: describeEntryMatch,
+	})
+}
+
+type EntriesDeleteWhereCmd struct {
+	List      string `arg:"" name:"list" help:"List slug or UUID" required:""`
+	BulkFlags `embed:""`
+}
+
+func (c *EntriesDeleteWhereCmd) Run(ctx context.Context, flags *RootFlags) error {
+	client, err := requireClient(flags.Profile)
+	if err != nil {
+		return err
+	}
+	filter, err := c.filter(c.List)
+	if err != nil {
+		return err
+	}
+	return runBulkWhere(ctx, flags, c.BulkFlags, bulkOp{
+		command: "entries delete-where", verb: "delete", kind: undo.KindEntry,
+		parentKey: "list", parent: c.List, filter: filter,
+		undoAction: undo.ActionDelete,
+		query: func(ctx context.Context, limit int, offset int) ([]map[string]any, error) {
+			return client.QueryEntries(ctx, c.List, filter, nil, limit, offset)
+		},
+		apply: func(ctx context.Context, id string) error {
+			return client.DeleteEntry(ctx, c.List, id)
+		},
+		describe: describeEntryMatch,
+	})
+}
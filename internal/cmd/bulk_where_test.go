@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/failup-ventures/attio-cli/mockserver"
+)
+
+func TestRecordsBulkWhere(t *testing.T) {
+	setupCLIEnv(t)
+	handler, err := mockserver.New(nil)
+	if err != nil {
+		t.Fatalf("new mock server: %v", err)
+	}
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+	t.Setenv("ATTIO_API_KEY", "mock")
+	t.Setenv("ATTIO_BASE_URL", srv.URL)
+
+	run := func(args ...string) string {
+		t.Helper()
+		stdout, stderr, err := captureExecute(t, args)
+		if err != nil {
+			t.Fatalf("%s: %v\nstderr=%s", strings.Join(args, " "), err, stderr)
+		}
+		return stdout
+	}
+	count := func(filter string) int {
+		t.Helper()
+		var out struct {
+			Data []map[string]any `json:"data"`
+		}
+		if err := json.Unmarshal([]byte(run("--json", "records", "query", "companies", "--filter", filter)), &out); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return len(out.Data)
+	}
+
+	for _, name := range []string{"Acme", "Globex", "Initech"} {
+		run("records", "create", "companies", "--data", `{"values":{"name":"`+name+`","description":"old"}}`)
+	}
+	run("records", "create", "companies", "--data", `{"values":{"name":"Umbrella","description":"keep"}}`)
+
+	if _, _, err := captureExecute(t, []string{"records", "update-where", "companies", "--set", "description=new"}); ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected a usage error without a filter, got %v", err)
+	}
+
+	stdout := run("--json", "--dry-run", "records", "update-where", "companies", "--filter", `{"description":"old"}`, "--set", "description=new")
+	if !strings.Contains(stdout, `"matched": 3`) {
+		t.Fatalf("expected dry run to report 3 matches, got %s", stdout)
+	}
+	if got := count(`{"description":"new"}`); got != 0 {
+		t.Fatalf("dry run changed %d records", got)
+	}
+
+	if _, _, err := captureExecute(t, []string{"records", "update-where", "companies", "--filter", `{"description":"old"}`, "--set", "description=new", "--max", "2"}); ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected --max to refuse 3 matches, got %v", err)
+	}
+
+	// Without a terminal to confirm on, a filter-based change needs --yes.
+	if _, stderr, err := captureExecute(t, []string{"records", "update-where", "companies", "--filter", `{"description":"old"}`, "--set", "description=new"}); ExitCode(err) != ExitCodeUsage || !strings.Contains(stderr, "pass --yes") {
+		t.Fatalf("expected update-where without --yes to be refused, got %v\n%s", err, stderr)
+	}
+	if got := count(`{"description":"new"}`); got != 0 {
+		t.Fatalf("update-where without --yes changed %d records", got)
+	}
+
+	var summary struct {
+		Data struct {
+			Matched   int          `json:"matched"`
+			Succeeded int          `json:"succeeded"`
+			Failed    int          `json:"failed"`
+			Results   []bulkResult `json:"results"`
+		} `json:"data"`
+	}
+	stdout = run("--json", "--yes", "records", "update-where", "companies", "--filter", `{"description":"old"}`, "--set", "description=new", "--concurrency", "2")
+	if err := json.Unmarshal([]byte(stdout), &summary); err != nil {
+		t.Fatalf("decode summary: %v\n%s", err, stdout)
+	}
+	if summary.Data.Matched != 3 || summary.Data.Succeeded != 3 || summary.Data.Failed != 0 || len(summary.Data.Results) != 3 {
+		t.Fatalf("unexpected summary %+v", summary.Data)
+	}
+	if got := count(`{"description":"new"}`); got != 3 {
+		t.Fatalf("expected 3 updated records, got %d", got)
+	}
+
+	stdout = run("--yes", "records", "delete-where", "companies", "--where", `description = "new"`)
+	if !strings.Contains(stdout, "Deleted 3 of 3 records in companies") {
+		t.Fatalf("unexpected delete summary %q", stdout)
+	}
+	if got := count(`{}`); got != 1 {
+		t.Fatalf("expected only Umbrella to remain, got %d records", got)
+	}
+
+	stdout = run("--yes", "records", "delete-where", "companies", "--filter", `{"description":"missing"}`)
+	if !strings.Contains(stdout, "No records in companies match") {
+		t.Fatalf("unexpected empty summary %q", stdout)
+	}
+}
+
+func TestRunBulkPoolCollectsErrors(t *testing.T) {
+	ids := []string{"a", "b", "c", "d", "e"}
+	results := runBulkPool(context.Background(), ids, 3, func(_ context.Context, id string) error {
+		if id == "c" {
+			return errors.New("boom")
+		}
+		return nil
+	})
+	if len(results) != len(ids) || countBulkFailures(results) != 1 {
+		t.Fatalf("unexpected results %+v", results)
+	}
+	for i, r := range results {
+		if r.ID != ids[i] {
+			t.Fatalf("results out of order: %+v", results)
+		}
+	}
+	if results[2].Status != "failed" || results[2].Error != "boom" {
+		t.Fatalf("expected c to fail, got %+v", results[2])
+	}
+}
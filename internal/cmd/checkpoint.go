@@ -0,0 +1,326 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/failup-ventures/attio-cli/internal/api"
+	"github.com/failup-ventures/attio-cli/internal/outfmt"
+	"github.com/failup-ventures/attio-cli/internal/ui"
+)
+
+const checkpointVersion = 2
+
+// pageCheckpoint persists pagination progress for --checkpoint so an
+// interrupted --all or --stream run can pick up after the last saved page.
+type pageCheckpoint struct {
+	path    string
+	resumed bool
+	// keepItems appends fetched items to an NDJSON file next to the
+	// checkpoint so a resumed --all run can still print the complete result.
+	// Streamed items are already written.
+	keepItems bool
+	// saved are the items a resumed run read back from that file.
+	saved []map[string]any
+	state checkpointState
+}
+
+type checkpointState struct {
+	Version     int    `json:"version"`
+	Command     string `json:"command"`
+	Fingerprint string `json:"fingerprint"`
+	Offset      int    `json:"offset,omitempty"`
+	Cursor      string `json:"cursor,omitempty"`
+	Pages       int    `json:"pages"`
+	// Items counts the items in the items file that belong to saved pages.
+	Items     int    `json:"items,omitempty"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// openPageCheckpoint loads the checkpoint at path or starts a new one. params
+// identify the query (filter, sorts, page size, ...); resuming with different
+// params is rejected. A nil checkpoint is returned when path is empty.
+func openPageCheckpoint(path string, command string, params map[string]any, keepItems bool) (*pageCheckpoint, error) {
+	if path == "" {
+		return nil, nil
+	}
+	fingerprint, err := checkpointFingerprint(command, params, keepItems)
+	if err != nil {
+		return nil, err
+	}
+	cp := &pageCheckpoint{
+		path:      path,
+		keepItems: keepItems,
+		state:     checkpointState{Version: checkpointVersion, Command: command, Fingerprint: fingerprint},
+	}
+
+	b, err := os.ReadFile(path) //nolint:gosec // user-specified local file path
+	if errors.Is(err, fs.ErrNotExist) {
+		// Items left behind by a run whose checkpoint is gone are stale.
+		if err := os.Remove(cp.itemsPath()); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("remove checkpoint items: %w", err)
+		}
+		return cp, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read checkpoint: %w", err)
+	}
+	var state checkpointState
+	if err := json.Unmarshal(b, &state); err != nil {
+		return nil, newUsageError(fmt.Errorf("checkpoint %s is not valid: %w", path, err))
+	}
+	if state.Version != checkpointVersion || state.Command != command || state.Fingerprint != fingerprint {
+		return nil, newUsageError(fmt.Errorf("checkpoint %s was written for a different %s invocation; remove it or choose another path", path, command))
+	}
+	cp.state = state
+	cp.resumed = true
+	if keepItems {
+		if cp.saved, err = cp.loadItems(); err != nil {
+			return nil, err
+		}
+	}
+	return cp, nil
+}
+
+func (cp *pageCheckpoint) itemsPath() string {
+	return cp.path + ".items.ndjson"
+}
+
+// loadItems reads back the items of the saved pages. Items appended for a
+// page whose checkpoint was never saved are cut off, since that page is
+// fetched again.
+func (cp *pageCheckpoint) loadItems() ([]map[string]any, error) {
+	f, err := os.Open(cp.itemsPath())
+	if errors.Is(err, fs.ErrNotExist) && cp.state.Items == 0 {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read checkpoint items: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	items := make([]map[string]any, 0, cp.state.Items)
+	dec := json.NewDecoder(f)
+	for len(items) < cp.state.Items {
+		var item map[string]any
+		if err := dec.Decode(&item); err != nil {
+			return nil, newUsageError(fmt.Errorf("checkpoint items %s are incomplete: %w", cp.itemsPath(), err))
+		}
+		items = append(items, item)
+	}
+	// The decoder stops right after the last item's closing brace; keep its
+	// newline too.
+	size := dec.InputOffset() + 1
+	if cp.state.Items == 0 {
+		size = 0
+	}
+	if err := os.Truncate(cp.itemsPath(), size); err != nil {
+		return nil, fmt.Errorf("truncate checkpoint items: %w", err)
+	}
+	return items, nil
+}
+
+// appendItems adds a page's items to the items file.
+func (cp *pageCheckpoint) appendItems(items []map[string]any) error {
+	f, err := os.OpenFile(cp.itemsPath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("write checkpoint items: %w", err)
+	}
+	enc := json.NewEncoder(f)
+	for _, item := range items {
+		if err := enc.Encode(item); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("write checkpoint items: %w", err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("write checkpoint items: %w", err)
+	}
+	return nil
+}
+
+func checkpointFingerprint(command string, params map[string]any, keepItems bool) (string, error) {
+	b, err := json.Marshal(map[string]any{"command": command, "params": params, "items": keepItems})
+	if err != nil {
+		return "", fmt.Errorf("fingerprint checkpoint: %w", err)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// notifyContext cancels ctx on Ctrl-C so the in-flight page is abandoned and
+// the checkpoint keeps the last completed page.
+func (cp *pageCheckpoint) notifyContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if cp == nil {
+		return ctx, func() {}
+	}
+	return signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+}
+
+func (cp *pageCheckpoint) items() []map[string]any {
+	if cp == nil {
+		return nil
+	}
+	return cp.saved
+}
+
+func (cp *pageCheckpoint) advance(offset int, cursor string, items []map[string]any) error {
+	cp.state.Offset = offset
+	cp.state.Cursor = cursor
+	cp.state.Pages++
+	if cp.keepItems {
+		if err := cp.appendItems(items); err != nil {
+			return err
+		}
+		cp.state.Items += len(items)
+	}
+	cp.state.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	return cp.save()
+}
+
+// save writes through a temporary file so an interrupted write never leaves a
+// truncated checkpoint behind.
+func (cp *pageCheckpoint) save() error {
+	b, err := json.Marshal(cp.state)
+	if err != nil {
+		return fmt.Errorf("marshal checkpoint: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(cp.path), filepath.Base(cp.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+	if _, err := tmp.Write(append(b, '\n')); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), cp.path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+	return nil
+}
+
+// finish removes the checkpoint once pagination is exhausted. Otherwise the
+// file is kept and the error (or --max-pages stop) explains how to resume.
+func (cp *pageCheckpoint) finish(ctx context.Context, exhausted bool, err error) error {
+	if cp == nil {
+		return err
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			err = fmt.Errorf("interrupted: %w", err)
+		}
+		cp.notice(ctx, "Checkpoint %s holds %d pages; rerun the same command to resume", cp.path, cp.state.Pages)
+		return err
+	}
+	if exhausted {
+		for _, path := range []string{cp.path, cp.itemsPath()} {
+			if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("remove checkpoint: %w", err)
+			}
+		}
+		return nil
+	}
+	cp.notice(ctx, "Stopped at --max-pages; checkpoint %s holds %d pages, rerun to continue", cp.path, cp.state.Pages)
+	return nil
+}
+
+func (cp *pageCheckpoint) notice(ctx context.Context, format string, args ...any) {
+	if u := ui.FromContext(ctx); u != nil && !outfmt.IsPlain(ctx) {
+		u.Err().Printf(format, args...)
+	}
+}
+
+// eachOffsetPageFrom pages from start (an absolute offset), or from the
+// checkpointed offset when resuming, saving progress after every page.
+func eachOffsetPageFrom(ctx context.Context, cp *pageCheckpoint, limit int, maxPages int, start int, fetch func(offset int) ([]map[string]any, error), fn func(items []map[string]any) error) error {
+	if cp != nil && cp.resumed {
+		start = cp.state.Offset
+	}
+	next := start
+	exhausted := false
+	err := api.EachOffsetPage(ctx, limit, maxPages, func(offset int) ([]map[string]any, error) {
+		return fetch(start + offset)
+	}, func(items []map[string]any) error {
+		if err := fn(items); err != nil {
+			return err
+		}
+		next += len(items)
+		exhausted = len(items) < limit
+		if cp == nil {
+			return nil
+		}
+		return cp.advance(next, "", items)
+	})
+	return cp.finish(ctx, exhausted, err)
+}
+
+// eachCursorPageFrom is the cursor-paginated counterpart of eachOffsetPageFrom.
+func eachCursorPageFrom(ctx context.Context, cp *pageCheckpoint, maxPages int, start string, fetch func(cursor string) ([]map[string]any, string, error), fn func(items []map[string]any) error) error {
+	if cp != nil && cp.resumed {
+		start = cp.state.Cursor
+	}
+	next := ""
+	exhausted := false
+	err := api.EachCursorPage(ctx, maxPages, func(cursor string) ([]map[string]any, string, error) {
+		if cursor == "" {
+			cursor = start
+		}
+		items, nextCursor, err := fetch(cursor)
+		next = nextCursor
+		return items, nextCursor, err
+	}, func(items []map[string]any) error {
+		if err := fn(items); err != nil {
+			return err
+		}
+		exhausted = next == ""
+		if cp == nil {
+			return nil
+		}
+		return cp.advance(0, next, items)
+	})
+	return cp.finish(ctx, exhausted, err)
+}
+
+// collectOffsetPages gathers every page for --all, starting with any items a
+// resumed checkpoint already holds.
+func collectOffsetPages(ctx context.Context, cp *pageCheckpoint, limit int, maxPages int, start int, fetch func(offset int) ([]map[string]any, error)) ([]map[string]any, error) {
+	all := append(make([]map[string]any, 0), cp.items()...)
+	err := eachOffsetPageFrom(ctx, cp, limit, maxPages, start, fetch, func(items []map[string]any) error {
+		all = append(all, items...)
+		return nil
+	})
+	return all, err
+}
+
+// collectCursorPages is the cursor-paginated counterpart of collectOffsetPages.
+func collectCursorPages(ctx context.Context, cp *pageCheckpoint, maxPages int, start string, fetch func(cursor string) ([]map[string]any, string, error)) ([]map[string]any, error) {
+	all := append(make([]map[string]any, 0), cp.items()...)
+	err := eachCursorPageFrom(ctx, cp, maxPages, start, fetch, func(items []map[string]any) error {
+		all = append(all, items...)
+		return nil
+	})
+	return all, err
+}
+
+func requireCheckpointPaging(path string, all bool, stream bool) error {
+	if path != "" && !all && !stream {
+		return newUsageError(errors.New("--checkpoint requires --all or --stream"))
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExecuteQueryCheckpointResumes(t *testing.T) {
+	setupCLIEnv(t)
+
+	failAt := 4.0
+	var offsets []float64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		offset, _ := body["offset"].(float64)
+		offsets = append(offsets, offset)
+		if offset == failAt {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]any{"status_code": 400, "message": "boom"})
+			return
+		}
+		items := []map[string]any{{"n": offset}, {"n": offset + 1}}
+		if offset >= 4 {
+			items = items[:1]
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": items})
+	}))
+	defer srv.Close()
+	t.Setenv("ATTIO_API_KEY", "env-key")
+	t.Setenv("ATTIO_BASE_URL", srv.URL)
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	args := []string{"--json", "--results-only", "records", "query", "people", "--all", "--limit", "2", "--checkpoint", path}
+
+	_, stderr, err := captureExecute(t, args)
+	if err == nil || !strings.Contains(stderr, "holds 2 pages") {
+		t.Fatalf("expected failure mentioning the checkpoint, got %v / %q", err, stderr)
+	}
+	var state checkpointState
+	b, _ := os.ReadFile(path)
+	if err := json.Unmarshal(b, &state); err != nil || state.Offset != 4 || state.Items != 4 {
+		t.Fatalf("unexpected checkpoint state %s (%v)", b, err)
+	}
+	// A page appended to the items file whose checkpoint was never saved is
+	// dropped on resume.
+	f, _ := os.OpenFile(path+".items.ndjson", os.O_APPEND|os.O_WRONLY, 0o600)
+	_, _ = f.WriteString(`{"n":99}` + "\n")
+	_ = f.Close()
+
+	_, stderr, err = captureExecute(t, []string{"records", "query", "people", "--all", "--limit", "3", "--checkpoint", path})
+	if ExitCode(err) != ExitCodeUsage || !strings.Contains(stderr, "different records query invocation") {
+		t.Fatalf("expected fingerprint mismatch usage error, got %v / %q", err, stderr)
+	}
+
+	failAt = -1
+	offsets = nil
+	stdout, stderr, err := captureExecute(t, args)
+	if err != nil {
+		t.Fatalf("unexpected error: %v\nstderr=%s", err, stderr)
+	}
+	var items []map[string]any
+	if err := json.Unmarshal([]byte(stdout), &items); err != nil || len(items) != 5 {
+		t.Fatalf("expected all 5 items after resuming, got %s (%v)", stdout, err)
+	}
+	if len(offsets) != 1 || offsets[0] != 4 {
+		t.Fatalf("expected resume to start at offset 4, got %v", offsets)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected checkpoint to be removed after completion, got %v", err)
+	}
+	if _, err := os.Stat(path + ".items.ndjson"); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected checkpoint items to be removed after completion, got %v", err)
+	}
+}
+
+func TestExecuteMeetingsStreamCheckpoint(t *testing.T) {
+	setupCLIEnv(t)
+
+	var cursors []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cursor := r.URL.Query().Get("cursor")
+		cursors = append(cursors, cursor)
+		next := map[string]string{"": "c2", "c2": "c3"}[cursor]
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": []map[string]any{{"title": "after " + cursor}}, "pagination": map[string]any{"next_cursor": next}})
+	}))
+	defer srv.Close()
+	t.Setenv("ATTIO_API_KEY", "env-key")
+	t.Setenv("ATTIO_BASE_URL", srv.URL)
+
+	path := filepath.Join(t.TempDir(), "meetings.json")
+	args := []string{"meetings", "list", "--stream", "--max-pages", "1", "--checkpoint", path}
+	stdout, stderr, err := captureExecute(t, args)
+	if err != nil || stdout != "{\"title\":\"after \"}\n" || !strings.Contains(stderr, "Stopped at --max-pages") {
+		t.Fatalf("unexpected first run: %v / %q / %q", err, stdout, stderr)
+	}
+
+	cursors = nil
+	args[4] = "5"
+	stdout, stderr, err = captureExecute(t, args)
+	if err != nil {
+		t.Fatalf("unexpected error: %v\nstderr=%s", err, stderr)
+	}
+	if stdout != "{\"title\":\"after c2\"}\n{\"title\":\"after c3\"}\n" || strings.Join(cursors, ",") != "c2,c3" {
+		t.Fatalf("expected resume from saved cursor, got %q after %v", stdout, cursors)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected checkpoint to be removed after completion, got %v", err)
+	}
+
+	_, stderr, err = captureExecute(t, []string{"meetings", "list", "--checkpoint", path})
+	if ExitCode(err) != ExitCodeUsage || !strings.Contains(stderr, "requires --all or --stream") {
+		t.Fatalf("expected usage error without --all/--stream, got %v / %q", err, stderr)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"sync"
 
 	"github.com/alecthomas/kong"
+
+	"github.com/failup-ventures/attio-cli/internal/openapi"
 )
 
 type completionFlag struct {
@@ -58,6 +60,12 @@ func completeWords(cword int, words []string) ([]string, error) {
 		current = words[cword]
 	}
 
+	if !strings.HasPrefix(current, "-") {
+		if suggestions, ok := completeAPIArgs(root, words, start, cword, current); ok {
+			return suggestions, nil
+		}
+	}
+
 	suggestions := make([]string, 0)
 	if strings.HasPrefix(current, "-") {
 		suggestions = append(suggestions, matchingFlags(node, current)...)
@@ -264,3 +272,58 @@ func matchingFlags(node *completionNode, prefix string) []string {
 	}
 	return results
 }
+
+// completeAPIArgs completes the method and path arguments of `attio api` from
+// the bundled OpenAPI description. ok is false outside those two positions.
+func completeAPIArgs(root *completionNode, words []string, start int, cword int, current string) ([]string, bool) {
+	apiNode, exists := root.children["api"]
+	if !exists {
+		return nil, false
+	}
+	node := root
+	position := -1
+	method := ""
+	for i := start; i < cword && i < len(words); i++ {
+		word := words[i]
+		if word == "--" {
+			return nil, false
+		}
+		if strings.HasPrefix(word, "-") {
+			flagToken, hasValue := splitFlagToken(word)
+			if spec, ok := node.flags[flagToken]; ok && spec.takesValue && !hasValue {
+				i++
+			}
+			continue
+		}
+		if position < 0 {
+			if word != "api" {
+				return nil, false
+			}
+			node = apiNode
+			position = 0
+			continue
+		}
+		if position == 0 {
+			method = word
+		}
+		position++
+	}
+
+	switch position {
+	case 0:
+		methods := make([]string, 0, len(apiMethods))
+		for _, m := range apiMethods {
+			if strings.HasPrefix(m, strings.ToUpper(current)) {
+				methods = append(methods, m)
+			}
+		}
+		return methods, true
+	case 1:
+		paths, err := openapi.CompletePath(method, current)
+		if err != nil {
+			return nil, false
+		}
+		return paths, true
+	}
+	return nil, false
+}
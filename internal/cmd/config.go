@@ -0,0 +1,579 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/failup-ventures/attio-cli/internal/api"
+	"github.com/failup-ventures/attio-cli/internal/audit"
+	"github.com/failup-ventures/attio-cli/internal/config"
+	"github.com/failup-ventures/attio-cli/internal/outfmt"
+	"github.com/failup-ventures/attio-cli/internal/ui"
+)
+
+const (
+	outputJSON  = "json"
+	outputPlain = "plain"
+	outputTable = "table"
+)
+
+type ConfigCmd struct {
+	Profiles ConfigProfilesCmd `cmd:"" help:"Manage named profiles"`
+	Get      ConfigGetCmd      `cmd:"" help:"Show profile settings"`
+	Set      ConfigSetCmd      `cmd:"" help:"Change a profile setting"`
+}
+
+type ConfigProfilesCmd struct {
+	List   ConfigProfilesListCmd   `cmd:"" help:"List profiles"`
+	Add    ConfigProfilesAddCmd    `cmd:"" help:"Add a profile"`
+	Remove ConfigProfilesRemoveCmd `cmd:"" aliases:"rm" help:"Remove a profile and its stored credentials"`
+	Rename ConfigProfilesRenameCmd `cmd:"" help:"Rename a profile and move its stored credentials"`
+	Use    ConfigProfilesUseCmd    `cmd:"" help:"Set the default profile"`
+	Show   ConfigProfilesShowCmd   `cmd:"" help:"Show a profile's settings and auth status"`
+}
+
+// profileSetting is a key accepted by config get/set. set parses and
+// validates a non-empty value; an empty value clears the setting.
+type profileSetting struct {
+	name string
+	get  func(config.Profile) string
+	set  func(*config.Profile, string) error
+}
+
+// stringSetting is a profileSetting stored as a plain string.
+func stringSetting(name string, field func(*config.Profile) *string, validate func(string) error) profileSetting {
+	return profileSetting{
+		name: name,
+		get:  func(p config.Profile) string { return *field(&p) },
+		set: func(p *config.Profile, v string) error {
+			if v != "" && validate != nil {
+				if err := validate(v); err != nil {
+					return err
+				}
+			}
+			*field(p) = v
+			return nil
+		},
+	}
+}
+
+var profileSettings = []profileSetting{
+	stringSetting("base_url", func(p *config.Profile) *string { return &p.BaseURL }, validateBaseURL),
+	stringSetting("timeout", func(p *config.Profile) *string { return &p.Timeout }, func(v string) error {
+		_, err := parseTimeout(v)
+		return err
+	}),
+	stringSetting("output", func(p *config.Profile) *string { return &p.Output }, oneOf(outputJSON, outputPlain, outputTable)),
+	stringSetting("color", func(p *config.Profile) *string { return &p.Color }, oneOf(colorAuto, "always", colorNever)),
+	stringSetting("rate_limit", func(p *config.Profile) *string { return &p.RateLimit }, func(v string) error {
+		_, err := api.ParseRateLimit(v)
+		return err
+	}),
+	stringSetting("api_key_command", func(p *config.Profile) *string { return &p.APIKeyCommand }, nil),
+	stringSetting("api_key_command_timeout", func(p *config.Profile) *string { return &p.APIKeyCommandTimeout }, func(v string) error {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid duration %q (for example: 10s)", v)
+		}
+		return nil
+	}),
+	{
+		name: "read_only",
+		get:  func(p config.Profile) string { return strconv.FormatBool(p.ReadOnly) },
+		set: func(p *config.Profile, v string) error {
+			b, err := parseSettingBool(v)
+			p.ReadOnly = b
+			return err
+		},
+	},
+	{
+		name: "disable_commands",
+		get:  func(p config.Profile) string { return strings.Join(p.DisableCommands, ",") },
+		set: func(p *config.Profile, v string) error {
+			p.DisableCommands = splitCommaList(v)
+			return nil
+		},
+	},
+	{
+		name: "object_policy",
+		get: func(p config.Profile) string {
+			return formatObjectPolicy(p.ObjectPolicy)
+		},
+		set: func(p *config.Profile, v string) error {
+			policy, err := parseObjectPolicy(v)
+			if err != nil {
+				return err
+			}
+			p.ObjectPolicy = policy
+			return nil
+		},
+	},
+	{
+		name: "undo",
+		get:  func(p config.Profile) string { return strconv.FormatBool(p.Undo) },
+		set: func(p *config.Profile, v string) error {
+			b, err := parseSettingBool(v)
+			p.Undo = b
+			return err
+		},
+	},
+	stringSetting("audit_log", func(p *config.Profile) *string { return &p.AuditLog }, nil),
+	stringSetting("audit_body", func(p *config.Profile) *string { return &p.AuditBody }, oneOf(audit.BodyNone, audit.BodyHash, audit.BodyFull)),
+}
+
+func lookupProfileSetting(key string) (profileSetting, error) {
+	key = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(key)), "-", "_")
+	names := make([]string, 0, len(profileSettings))
+	for _, s := range profileSettings {
+		if s.name == key {
+			return s, nil
+		}
+		names = append(names, s.name)
+	}
+	return profileSetting{}, newUsageError(fmt.Errorf("unknown setting %q (expected one of: %s)", key, strings.Join(names, ", ")))
+}
+
+func validateBaseURL(v string) error {
+	u, err := url.Parse(v)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid base URL %q (expected http(s)://host)", v)
+	}
+	return nil
+}
+
+// parseSettingBool parses a boolean setting; "" clears it to false.
+func parseSettingBool(v string) (bool, error) {
+	if v == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("invalid value %q (expected true|false)", v)
+	}
+	return b, nil
+}
+
+func oneOf(values ...string) func(string) error {
+	return func(v string) error {
+		for _, allowed := range values {
+			if v == allowed {
+				return nil
+			}
+		}
+		return fmt.Errorf("invalid value %q (expected %s)", v, strings.Join(values, "|"))
+	}
+}
+
+type ConfigGetCmd struct {
+	Key string `arg:"" optional:"" name:"key" help:"Setting to show; omit to show all settings"`
+}
+
+func (c *ConfigGetCmd) Run(ctx context.Context, flags *RootFlags) error {
+	profile := config.ResolveProfile(flags.Profile)
+	settings := config.LoadProfile(profile)
+
+	if c.Key != "" {
+		setting, err := lookupProfileSetting(c.Key)
+		if err != nil {
+			return err
+		}
+		value := setting.get(settings)
+		if outfmt.IsJSON(ctx) {
+			return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{"profile": profile, "key": setting.name, "value": value})
+		}
+		_, _ = fmt.Fprintln(os.Stdout, value)
+		return nil
+	}
+
+	values := make(map[string]string, len(profileSettings))
+	for _, setting := range profileSettings {
+		values[setting.name] = setting.get(settings)
+	}
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{"profile": profile, "settings": values})
+	}
+	w, done := tableWriter(ctx)
+	defer done()
+	_, _ = fmt.Fprintln(w, "KEY\tVALUE")
+	for _, setting := range profileSettings {
+		_, _ = fmt.Fprintf(w, "%s\t%s\n", setting.name, values[setting.name])
+	}
+	return nil
+}
+
+type ConfigSetCmd struct {
+	Key   string `arg:"" name:"key" help:"Setting to change: base_url, timeout, output, color, rate_limit, api_key_command, api_key_command_timeout, read_only, disable_commands, object_policy, undo, audit_log, audit_body"`
+	Value string `arg:"" name:"value" help:"New value; pass \"\" to clear the setting"`
+}
+
+func (c *ConfigSetCmd) Run(ctx context.Context, flags *RootFlags) error {
+	profile := config.ResolveProfile(flags.Profile)
+	setting, err := lookupProfileSetting(c.Key)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	settings := cfg.Profiles[profile]
+	if err := setting.set(&settings, strings.TrimSpace(c.Value)); err != nil {
+		return newUsageError(fmt.Errorf("%s: %w", setting.name, err))
+	}
+	value := setting.get(settings)
+	if ok, err := maybeDryRun(ctx, "config set", map[string]any{"profile": profile, "key": setting.name, "value": value}); ok || err != nil {
+		return err
+	}
+
+	cfg.Profiles[profile] = settings
+	if err := config.SaveConfig(cfg); err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{"profile": profile, "key": setting.name, "value": value})
+	}
+	u := ui.FromContext(ctx)
+	if strings.TrimSpace(c.Value) == "" {
+		u.Out().Successf("Cleared %s for profile %q", setting.name, profile)
+		return nil
+	}
+	u.Out().Successf("Set %s for profile %q", setting.name, profile)
+	return nil
+}
+
+// knownProfiles returns every profile in the config file or the keyring,
+// plus the default profile, sorted by name.
+func knownProfiles(cfg config.Config) []string {
+	seen := map[string]bool{cfg.DefaultProfile: true}
+	for name := range cfg.Profiles {
+		seen[name] = true
+	}
+	// The keyring is optional; profiles stored there are listed when it opens.
+	if names, err := config.KeyringProfiles(); err == nil {
+		for _, name := range names {
+			seen[name] = true
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func profileExists(cfg config.Config, name string) bool {
+	if _, ok := cfg.Profiles[name]; ok {
+		return true
+	}
+	names, err := config.KeyringProfiles()
+	if err != nil {
+		return false
+	}
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func validateProfileName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.ContainsAny(name, " \t\r\n") {
+		return "", newUsageError(fmt.Errorf("invalid profile name %q", name))
+	}
+	return name, nil
+}
+
+type ConfigProfilesListCmd struct{}
+
+func (c *ConfigProfilesListCmd) Run(ctx context.Context, _ *RootFlags) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	names := knownProfiles(cfg)
+	if err := maybePreviewResults(ctx, len(names)); err != nil {
+		return err
+	}
+
+	rows := make([]map[string]any, 0, len(names))
+	for _, name := range names {
+		// Listing must not run every profile's api_key_command.
+		source := config.ResolveAuthSource(name)
+		rows = append(rows, map[string]any{
+			"name":        name,
+			"default":     name == cfg.DefaultProfile,
+			"base_url":    config.ResolveBaseURL(name),
+			"resolved":    source != "",
+			"auth_source": string(source),
+		})
+	}
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{"data": rows})
+	}
+
+	w, done := tableWriter(ctx)
+	defer done()
+	_, _ = fmt.Fprintln(w, "NAME\tDEFAULT\tBASE_URL\tAUTH")
+	for _, row := range rows {
+		def := ""
+		if row["default"] == true {
+			def = "*"
+		}
+		source := mapString(row, "auth_source")
+		if source == "" {
+			source = "-"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", row["name"], def, row["base_url"], source)
+	}
+	return nil
+}
+
+type ConfigProfilesAddCmd struct {
+	Name    string `arg:"" name:"name" help:"Profile name"`
+	BaseURL string `name:"base-url" help:"API base URL for the profile"`
+	Use     bool   `name:"use" help:"Also make it the default profile"`
+}
+
+func (c *ConfigProfilesAddCmd) Run(ctx context.Context, _ *RootFlags) error {
+	name, err := validateProfileName(c.Name)
+	if err != nil {
+		return err
+	}
+	baseURL := strings.TrimRight(strings.TrimSpace(c.BaseURL), "/")
+	if baseURL != "" {
+		if err := validateBaseURL(baseURL); err != nil {
+			return newUsageError(fmt.Errorf("--base-url: %w", err))
+		}
+	}
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	if _, ok := cfg.Profiles[name]; ok {
+		return newUsageError(fmt.Errorf("profile %q already exists", name))
+	}
+	if ok, err := maybeDryRun(ctx, "config profiles add", map[string]any{"name": name, "base_url": baseURL, "use": c.Use}); ok || err != nil {
+		return err
+	}
+
+	cfg.Profiles[name] = config.Profile{BaseURL: baseURL}
+	if c.Use {
+		cfg.DefaultProfile = name
+	}
+	if err := config.SaveConfig(cfg); err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{"added": true, "name": name, "default": cfg.DefaultProfile == name})
+	}
+	u := ui.FromContext(ctx)
+	u.Out().Successf("Added profile %q", name)
+	if c.Use {
+		u.Out().Printf("Default profile is now %q", name)
+	}
+	return nil
+}
+
+// profilesSaveConfigFunc saves the config after a profile remove or rename;
+// tests replace it to check that a failed save leaves the credentials alone.
+var profilesSaveConfigFunc = config.SaveConfig
+
+type ConfigProfilesRemoveCmd struct {
+	Name string `arg:"" name:"name" help:"Profile name"`
+}
+
+func (c *ConfigProfilesRemoveCmd) Run(ctx context.Context, _ *RootFlags) error {
+	name, err := validateProfileName(c.Name)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	if !profileExists(cfg, name) {
+		return newUsageError(fmt.Errorf("profile %q not found", name))
+	}
+	if ok, err := maybeDryRun(ctx, "config profiles remove", map[string]any{"name": name}); ok || err != nil {
+		return err
+	}
+
+	delete(cfg.Profiles, name)
+	if cfg.DefaultProfile == name {
+		cfg.DefaultProfile = config.DefaultProfileName
+	}
+	// The config is saved first so a failed save leaves the profile whole.
+	if err := profilesSaveConfigFunc(cfg); err != nil {
+		return err
+	}
+	if _, err := removeCredential(config.RemoveAPIKey, name); err != nil {
+		return err
+	}
+	if _, err := removeCredential(config.RemoveOAuthCredentials, name); err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{"removed": true, "name": name, "default_profile": cfg.DefaultProfile})
+	}
+	u := ui.FromContext(ctx)
+	u.Out().Successf("Removed profile %q", name)
+	return nil
+}
+
+type ConfigProfilesRenameCmd struct {
+	From string `arg:"" name:"from" help:"Current profile name"`
+	To   string `arg:"" name:"to" help:"New profile name"`
+}
+
+func (c *ConfigProfilesRenameCmd) Run(ctx context.Context, _ *RootFlags) error {
+	from, err := validateProfileName(c.From)
+	if err != nil {
+		return err
+	}
+	to, err := validateProfileName(c.To)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	if !profileExists(cfg, from) {
+		return newUsageError(fmt.Errorf("profile %q not found", from))
+	}
+	if from == to {
+		return newUsageError(errors.New("the new profile name is the same as the old one"))
+	}
+	if profileExists(cfg, to) {
+		return newUsageError(fmt.Errorf("profile %q already exists", to))
+	}
+	if ok, err := maybeDryRun(ctx, "config profiles rename", map[string]any{"from": from, "to": to}); ok || err != nil {
+		return err
+	}
+
+	if err := config.MoveKeyringProfile(from, to); err != nil {
+		return fmt.Errorf("move keyring credentials: %w", err)
+	}
+	if settings, ok := cfg.Profiles[from]; ok {
+		cfg.Profiles[to] = settings
+		delete(cfg.Profiles, from)
+	}
+	if cfg.DefaultProfile == from {
+		cfg.DefaultProfile = to
+	}
+	if err := profilesSaveConfigFunc(cfg); err != nil {
+		// The config still names the old profile, so its credentials go back.
+		if moveErr := config.MoveKeyringProfile(to, from); moveErr != nil {
+			return fmt.Errorf("%w; moving the keyring credentials back to %q also failed: %v", err, from, moveErr)
+		}
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{"renamed": true, "from": from, "to": to})
+	}
+	u := ui.FromContext(ctx)
+	u.Out().Successf("Renamed profile %q to %q", from, to)
+	return nil
+}
+
+type ConfigProfilesUseCmd struct {
+	Name string `arg:"" name:"name" help:"Profile name"`
+}
+
+func (c *ConfigProfilesUseCmd) Run(ctx context.Context, _ *RootFlags) error {
+	name, err := validateProfileName(c.Name)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	if !profileExists(cfg, name) && name != config.DefaultProfileName {
+		return newUsageError(fmt.Errorf("profile %q not found (create it with: attio config profiles add %s)", name, name))
+	}
+	if ok, err := maybeDryRun(ctx, "config profiles use", map[string]any{"name": name}); ok || err != nil {
+		return err
+	}
+
+	cfg.DefaultProfile = name
+	if err := config.SaveConfig(cfg); err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{"default_profile": name})
+	}
+	u := ui.FromContext(ctx)
+	u.Out().Successf("Default profile is now %q", name)
+	return nil
+}
+
+type ConfigProfilesShowCmd struct {
+	Name string `arg:"" optional:"" name:"name" help:"Profile name (default: the selected profile)"`
+}
+
+func (c *ConfigProfilesShowCmd) Run(ctx context.Context, flags *RootFlags) error {
+	name := strings.TrimSpace(c.Name)
+	if name == "" {
+		name = config.ResolveProfile(flags.Profile)
+	}
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	if !profileExists(cfg, name) && name != cfg.DefaultProfile {
+		return newUsageError(fmt.Errorf("profile %q not found", name))
+	}
+
+	settings := cfg.Profiles[name]
+	values := make(map[string]string, len(profileSettings))
+	for _, setting := range profileSettings {
+		values[setting.name] = setting.get(settings)
+	}
+	status := config.AuthStatus(name)
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{
+			"name":     name,
+			"default":  name == cfg.DefaultProfile,
+			"settings": values,
+			"auth":     status,
+		})
+	}
+
+	w, done := tableWriter(ctx)
+	defer done()
+	_, _ = fmt.Fprintln(w, "FIELD\tVALUE")
+	_, _ = fmt.Fprintf(w, "name\t%s\n", name)
+	_, _ = fmt.Fprintf(w, "default\t%t\n", name == cfg.DefaultProfile)
+	_, _ = fmt.Fprintf(w, "base_url\t%s\n", status.BaseURL)
+	for _, setting := range profileSettings {
+		if setting.name == "base_url" || values[setting.name] == "" {
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\n", setting.name, values[setting.name])
+	}
+	_, _ = fmt.Fprintf(w, "resolved\t%t\n", status.Resolved)
+	if status.Resolved {
+		_, _ = fmt.Fprintf(w, "auth_source\t%s\n", status.ResolvedSource)
+		_, _ = fmt.Fprintf(w, "api_key\t%s\n", status.MaskedKey)
+	}
+	if status.Error != "" {
+		_, _ = fmt.Fprintf(w, "error\t%s\n", status.Error)
+	}
+	return nil
+}
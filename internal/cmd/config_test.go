@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/failup-ventures/attio-cli/internal/config"
+)
+
+func TestConfigProfilesLifecycle(t *testing.T) {
+	setupCLIEnv(t)
+
+	if _, stderr, err := captureExecute(t, []string{"--profile", "work", "auth", "login", "--api-key", "work-key-1234"}); err != nil {
+		t.Fatalf("auth login: %v stderr=%s", err, stderr)
+	}
+	if _, stderr, err := captureExecute(t, []string{"config", "profiles", "add", "staging", "--base-url", "https://staging.example.com", "--use"}); err != nil {
+		t.Fatalf("profiles add: %v stderr=%s", err, stderr)
+	}
+	if _, _, err := captureExecute(t, []string{"config", "profiles", "add", "staging"}); ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected duplicate add to be a usage error, got %v", err)
+	}
+
+	stdout, stderr, err := captureExecute(t, []string{"--json", "config", "profiles", "list"})
+	if err != nil {
+		t.Fatalf("profiles list: %v stderr=%s", err, stderr)
+	}
+	var listed struct {
+		Data []struct {
+			Name       string `json:"name"`
+			Default    bool   `json:"default"`
+			BaseURL    string `json:"base_url"`
+			AuthSource string `json:"auth_source"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &listed); err != nil {
+		t.Fatalf("decode list: %v\n%s", err, stdout)
+	}
+	if len(listed.Data) != 2 || listed.Data[0].Name != "staging" || !listed.Data[0].Default ||
+		listed.Data[0].BaseURL != "https://staging.example.com" || listed.Data[1].Name != "work" || listed.Data[1].AuthSource != "keyring" {
+		t.Fatalf("unexpected profiles: %+v", listed.Data)
+	}
+	if got := config.ResolveProfile(""); got != "staging" {
+		t.Fatalf("expected staging to be the default profile, got %q", got)
+	}
+
+	if _, stderr, err := captureExecute(t, []string{"config", "profiles", "rename", "work", "prod"}); err != nil {
+		t.Fatalf("rename: %v stderr=%s", err, stderr)
+	}
+	if key, err := config.LoadAPIKey("prod"); err != nil || key != "work-key-1234" {
+		t.Fatalf("expected the key to move to prod, got %q (%v)", key, err)
+	}
+	if _, err := config.LoadAPIKey("work"); err == nil {
+		t.Fatalf("expected the old keyring entry to be removed")
+	}
+	if _, stderr, err := captureExecute(t, []string{"config", "profiles", "rename", "staging", "stage"}); err != nil {
+		t.Fatalf("rename default: %v stderr=%s", err, stderr)
+	}
+	if got := config.ResolveProfile(""); got != "stage" {
+		t.Fatalf("expected the default profile to follow the rename, got %q", got)
+	}
+
+	if _, stderr, err := captureExecute(t, []string{"config", "profiles", "use", "prod"}); err != nil {
+		t.Fatalf("use: %v stderr=%s", err, stderr)
+	}
+	stdout, _, err = captureExecute(t, []string{"config", "profiles", "show"})
+	if err != nil || !strings.Contains(stdout, "prod") || !strings.Contains(stdout, "*********1234") {
+		t.Fatalf("unexpected show output: %v\n%s", err, stdout)
+	}
+	if _, _, err := captureExecute(t, []string{"config", "profiles", "use", "missing"}); ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected unknown profile to be a usage error, got %v", err)
+	}
+
+	if _, stderr, err := captureExecute(t, []string{"config", "profiles", "remove", "prod"}); err != nil {
+		t.Fatalf("remove: %v stderr=%s", err, stderr)
+	}
+	if _, err := config.LoadAPIKey("prod"); err == nil {
+		t.Fatalf("expected remove to delete the keyring entry")
+	}
+	if got := config.ResolveProfile(""); got != config.DefaultProfileName {
+		t.Fatalf("expected the default profile to reset, got %q", got)
+	}
+}
+
+func TestConfigGetSetAppliesProfileDefaults(t *testing.T) {
+	setupCLIEnv(t)
+	t.Setenv("ATTIO_TIMEOUT", "")
+
+	for _, kv := range [][2]string{{"timeout", "45s"}, {"output", "json"}, {"color", "never"}} {
+		if _, stderr, err := captureExecute(t, []string{"config", "set", kv[0], kv[1]}); err != nil {
+			t.Fatalf("config set %s: %v stderr=%s", kv[0], err, stderr)
+		}
+	}
+	if _, _, err := captureExecute(t, []string{"config", "set", "color", "sometimes"}); ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected invalid color to be a usage error, got %v", err)
+	}
+	if _, _, err := captureExecute(t, []string{"config", "set", "favourite", "x"}); ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected unknown key to be a usage error, got %v", err)
+	}
+
+	// output=json makes JSON the default without --json.
+	stdout, stderr, err := captureExecute(t, []string{"config", "get", "timeout"})
+	if err != nil {
+		t.Fatalf("config get: %v stderr=%s", err, stderr)
+	}
+	var got map[string]any
+	if err := json.Unmarshal([]byte(stdout), &got); err != nil || got["value"] != "45s" {
+		t.Fatalf("expected JSON output with 45s, got %v\n%s", err, stdout)
+	}
+	if _, timeout := getClientRuntimeOptions(); timeout != 45*time.Second {
+		t.Fatalf("expected the profile timeout to apply, got %s", timeout)
+	}
+
+	// Flags still win over profile settings.
+	stdout, _, err = captureExecute(t, []string{"--plain", "--timeout", "5s", "config", "get", "timeout"})
+	if err != nil || strings.TrimSpace(stdout) != "45s" {
+		t.Fatalf("expected plain output, got %v %q", err, stdout)
+	}
+	if _, timeout := getClientRuntimeOptions(); timeout != 5*time.Second {
+		t.Fatalf("expected --timeout to win, got %s", timeout)
+	}
+
+	if _, _, err := captureExecute(t, []string{"config", "set", "output", ""}); err != nil {
+		t.Fatalf("clear output: %v", err)
+	}
+	if p := config.LoadProfile(""); p.Output != "" || p.Timeout != "45s" {
+		t.Fatalf("unexpected profile after clearing output: %+v", p)
+	}
+}
+
+func TestConfigProfilesListDoesNotRunKeyCommands(t *testing.T) {
+	setupCLIEnv(t)
+	marker := filepath.Join(t.TempDir(), "ran")
+	cfg := config.DefaultConfig()
+	cfg.Profiles["vault"] = config.Profile{APIKeyCommand: "touch " + marker + " && echo vault-key"}
+	if err := config.SaveConfig(cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	stdout, stderr, err := captureExecute(t, []string{"--json", "config", "profiles", "list"})
+	if err != nil {
+		t.Fatalf("profiles list: %v stderr=%s", err, stderr)
+	}
+	if !strings.Contains(stdout, `"auth_source": "command"`) {
+		t.Fatalf("expected the command source to be reported, got %s", stdout)
+	}
+	if _, err := os.Stat(marker); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected api_key_command not to run, got %v", err)
+	}
+}
+
+func TestConfigProfilesKeepKeyOnSaveFailure(t *testing.T) {
+	setupCLIEnv(t)
+	if _, stderr, err := captureExecute(t, []string{"--profile", "work", "auth", "login", "--api-key", "work-key-1234"}); err != nil {
+		t.Fatalf("auth login: %v stderr=%s", err, stderr)
+	}
+	orig := profilesSaveConfigFunc
+	t.Cleanup(func() { profilesSaveConfigFunc = orig })
+	profilesSaveConfigFunc = func(config.Config) error { return errors.New("disk full") }
+
+	if _, stderr, err := captureExecute(t, []string{"config", "profiles", "rename", "work", "prod"}); err == nil || !strings.Contains(stderr, "disk full") {
+		t.Fatalf("expected the save error, got %v\n%s", err, stderr)
+	}
+	if key, err := config.LoadAPIKey("work"); err != nil || key != "work-key-1234" {
+		t.Fatalf("expected the key to stay with work, got %q (%v)", key, err)
+	}
+	if _, err := config.LoadAPIKey("prod"); err == nil {
+		t.Fatalf("expected no key under prod")
+	}
+
+	if _, stderr, err := captureExecute(t, []string{"config", "profiles", "remove", "work"}); err == nil || !strings.Contains(stderr, "disk full") {
+		t.Fatalf("expected the save error, got %v\n%s", err, stderr)
+	}
+	if key, err := config.LoadAPIKey("work"); err != nil || key != "work-key-1234" {
+		t.Fatalf("expected remove to keep the key when the config cannot be saved, got %q (%v)", key, err)
+	}
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/failup-ventures/attio-cli/internal/ui"
+)
+
+// maxConfirmListed caps how many targets a bulk confirmation lists.
+const maxConfirmListed = 10
+
+var (
+	confirmIsTerminalFunc = func(fd int) bool { return term.IsTerminal(fd) }
+	confirmPromptLineFunc = promptInitLine
+	confirmPromptBoolFunc = promptInitBool
+)
+
+var errConfirmationDeclined = errors.New("aborted: confirmation declined")
+
+// shouldConfirm reports whether a destructive command must prompt. --yes
+// skips the prompt and --no-input without --yes is an error. Without a
+// terminal there is nobody to ask, so the command proceeds as before.
+func shouldConfirm(flags *RootFlags, action string) (bool, error) {
+	if flags.Yes {
+		return false, nil
+	}
+	if flags.NoInput {
+		return false, newUsageError(fmt.Errorf("%s needs confirmation; pass --yes to run it with --no-input", action))
+	}
+	return confirmIsTerminalFunc(int(os.Stdin.Fd())), nil
+}
+
+// confirmDestructive asks before deleting a single resource. describe fetches
+// the resource for a human-readable summary and only runs when prompting.
+func confirmDestructive(ctx context.Context, flags *RootFlags, action string, describe func() (string, error)) error {
+	prompt, err := shouldConfirm(flags, action)
+	if !prompt || err != nil {
+		return err
+	}
+	summary, err := describe()
+	if err != nil {
+		return err
+	}
+	out := promptWriter(ctx)
+	_, _ = fmt.Fprintf(out, "About to %s:\n  %s\n", action, summary)
+	ok, err := confirmPromptBoolFunc(out, "Continue", false)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errConfirmationDeclined
+	}
+	return nil
+}
+
+// confirmBulk asks before a destructive command that affects several
+// resources. The user has to type the number of targets or the object slug.
+func confirmBulk(ctx context.Context, flags *RootFlags, action string, slug string, targets []string) error {
+	prompt, err := shouldConfirm(flags, action)
+	if !prompt || err != nil {
+		return err
+	}
+	out := promptWriter(ctx)
+	count := strconv.Itoa(len(targets))
+	_, _ = fmt.Fprintf(out, "About to %s:\n", action)
+	for i, target := range targets {
+		if i == maxConfirmListed {
+			_, _ = fmt.Fprintf(out, "  ... and %d more\n", len(targets)-maxConfirmListed)
+			break
+		}
+		_, _ = fmt.Fprintf(out, "  %s\n", target)
+	}
+	answer, err := confirmPromptLineFunc(out, fmt.Sprintf("Type %s or %q to confirm", count, slug), "")
+	if err != nil {
+		return err
+	}
+	answer = strings.TrimSpace(answer)
+	if answer != count && (slug == "" || answer != slug) {
+		return errConfirmationDeclined
+	}
+	return nil
+}
+
+// confirmBulkMatches is confirmBulk for targets found by a filter. A filter
+// can match more than the caller meant, so without a terminal to ask it
+// needs --yes instead of proceeding.
+func confirmBulkMatches(ctx context.Context, flags *RootFlags, action string, slug string, targets []string) error {
+	if !flags.Yes && !flags.NoInput && !confirmIsTerminalFunc(int(os.Stdin.Fd())) {
+		return newUsageError(fmt.Errorf("%s needs confirmation; pass --yes to run it without a terminal", action))
+	}
+	return confirmBulk(ctx, flags, action, slug, targets)
+}
+
+func promptWriter(ctx context.Context) io.Writer {
+	if u := ui.FromContext(ctx); u != nil {
+		return u.ErrWriter()
+	}
+	return os.Stderr
+}
+
+// describeResource joins the non-empty parts of a confirmation summary.
+func describeResource(kind string, id string, details ...string) string {
+	parts := []string{kind + " " + id}
+	for _, d := range details {
+		if d = strings.TrimSpace(d); d != "" {
+			parts = append(parts, d)
+		}
+	}
+	return strings.Join(parts, " - ")
+}
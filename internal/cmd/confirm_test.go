@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/failup-ventures/attio-cli/mockserver"
+)
+
+func TestDestructiveCommandsConfirm(t *testing.T) {
+	setupCLIEnv(t)
+	handler, err := mockserver.New(nil)
+	if err != nil {
+		t.Fatalf("new mock server: %v", err)
+	}
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+	t.Setenv("ATTIO_API_KEY", "mock")
+	t.Setenv("ATTIO_BASE_URL", srv.URL)
+
+	origTTY, origBool, origLine := confirmIsTerminalFunc, confirmPromptBoolFunc, confirmPromptLineFunc
+	t.Cleanup(func() {
+		confirmIsTerminalFunc, confirmPromptBoolFunc, confirmPromptLineFunc = origTTY, origBool, origLine
+	})
+	confirmIsTerminalFunc = func(int) bool { return true }
+	var answer string
+	confirmPromptBoolFunc = func(out io.Writer, question string, _ bool) (bool, error) {
+		_, _ = io.WriteString(out, question+"\n")
+		return answer == "y", nil
+	}
+	confirmPromptLineFunc = func(out io.Writer, question string, _ string) (string, error) {
+		_, _ = io.WriteString(out, question+"\n")
+		return answer, nil
+	}
+
+	createPerson := func(name string) string {
+		t.Helper()
+		stdout, stderr, err := captureExecute(t, []string{"--id-only", "records", "create", "people", "--data", `{"values":{"name":"` + name + `"}}`})
+		if err != nil {
+			t.Fatalf("create: %v stderr=%s", err, stderr)
+		}
+		return strings.TrimSpace(stdout)
+	}
+	exists := func(id string) bool {
+		_, _, err := captureExecute(t, []string{"records", "get", "people", id})
+		return err == nil
+	}
+
+	ada := createPerson("Ada Lovelace")
+	_, stderr, err := captureExecute(t, []string{"--no-input", "records", "delete", "people", ada})
+	if ExitCode(err) != ExitCodeUsage || !strings.Contains(stderr, "--yes") {
+		t.Fatalf("expected --no-input without --yes to fail, got %v\n%s", err, stderr)
+	}
+
+	answer = "n"
+	_, stderr, err = captureExecute(t, []string{"records", "delete", "people", ada})
+	if err == nil || !strings.Contains(stderr, "Ada Lovelace") || !exists(ada) {
+		t.Fatalf("expected a declined prompt that names the record, got %v\n%s", err, stderr)
+	}
+	answer = "y"
+	if _, stderr, err := captureExecute(t, []string{"records", "delete", "people", ada}); err != nil || exists(ada) {
+		t.Fatalf("expected the confirmed delete to succeed: %v\n%s", err, stderr)
+	}
+
+	// --yes skips the prompt entirely.
+	answer = "n"
+	grace := createPerson("Grace Hopper")
+	if _, stderr, err := captureExecute(t, []string{"--no-input", "--yes", "records", "delete", "people", grace}); err != nil || exists(grace) {
+		t.Fatalf("expected --yes to delete without prompting: %v\n%s", err, stderr)
+	}
+
+	// Bulk deletes need the count or the object slug typed out.
+	ids := []string{createPerson("Alan Turing"), createPerson("Alan Kay")}
+	bulk := []string{"records", "delete-where", "people", "--where", `name ^= "Alan"`}
+	answer = "1"
+	if _, _, err := captureExecute(t, bulk); err == nil || !exists(ids[0]) {
+		t.Fatalf("expected a wrong count to abort, got %v", err)
+	}
+	answer = "people"
+	stdout, stderr, err := captureExecute(t, bulk)
+	if err != nil || !strings.Contains(stderr, "delete 2 records in people") {
+		t.Fatalf("expected the bulk delete to run: %v\n%s\n%s", err, stdout, stderr)
+	}
+	if exists(ids[0]) || exists(ids[1]) {
+		t.Fatal("expected both records to be deleted")
+	}
+
+	// Delete takes a single ID.
+	if _, _, err := captureExecute(t, []string{"--yes", "records", "delete", "people", ids[0], ids[1]}); ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected several IDs to be rejected, got %v", err)
+	}
+}
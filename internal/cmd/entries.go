@@ -5,24 +5,28 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/failup-ventures/attio-cli/internal/api"
 	"github.com/failup-ventures/attio-cli/internal/outfmt"
+	"github.com/failup-ventures/attio-cli/internal/undo"
 )
 
 type EntriesCmd struct {
-	Create  EntriesCreateCmd  `cmd:"" help:"Create an entry"`
-	Assert  EntriesAssertCmd  `cmd:"" aliases:"upsert" help:"Assert (upsert) a list entry by parent"`
-	Query   EntriesQueryCmd   `cmd:"" help:"Query entries"`
-	Get     EntriesGetCmd     `cmd:"" help:"Get entry"`
-	Update  EntriesUpdateCmd  `cmd:"" help:"Update entry (PATCH append multiselect)"`
-	Replace EntriesReplaceCmd `cmd:"" help:"Replace entry (PUT overwrite multiselect)"`
-	Delete  EntriesDeleteCmd  `cmd:"" help:"Delete entry"`
-	Values  EntriesValuesCmd  `cmd:"" help:"Entry attribute values"`
+	Create      EntriesCreateCmd      `cmd:"" help:"Create an entry"`
+	Assert      EntriesAssertCmd      `cmd:"" aliases:"upsert" help:"Assert (upsert) a list entry by parent"`
+	Query       EntriesQueryCmd       `cmd:"" help:"Query entries"`
+	Get         EntriesGetCmd         `cmd:"" help:"Get entry"`
+	Update      EntriesUpdateCmd      `cmd:"" help:"Update entry (PATCH append multiselect)"`
+	Replace     EntriesReplaceCmd     `cmd:"" help:"Replace entry (PUT overwrite multiselect)"`
+	Delete      EntriesDeleteCmd      `cmd:"" help:"Delete entry"`
+	UpdateWhere EntriesUpdateWhereCmd `cmd:"" name:"update-where" help:"Update every entry matching a filter"`
+	DeleteWhere EntriesDeleteWhereCmd `cmd:"" name:"delete-where" help:"Delete every entry matching a filter"`
+	Values      EntriesValuesCmd      `cmd:"" help:"Entry attribute values"`
+	History     EntriesHistoryCmd     `cmd:"" help:"Show an entry's changes from its historic attribute values"`
 }
 
 type EntriesCreateCmd struct {
-	List string `arg:"" name:"list" help:"List slug or UUID" required:""`
-	Data string `name:"data" help:"Entry data JSON; supports '-' or @file.json" required:""`
+	List       string `arg:"" name:"list" help:"List slug or UUID" required:""`
+	Data       string `name:"data" help:"Entry data JSON; supports '-' or @file.json"`
+	ValueFlags `embed:""`
 }
 
 func (c *EntriesCreateCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -30,10 +34,13 @@ func (c *EntriesCreateCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if err != nil {
 		return err
 	}
-	data, err := readJSONObjectInput(c.Data)
+	data, _, err := c.payload(ctx, entryValueTarget(client, c.List, ""), c.Data, valueWriteCreate)
 	if err != nil {
 		return err
 	}
+	if err := validateWriteData(ctx, flags, newEntryValidator(client, c.List), data, validateCreate, ""); err != nil {
+		return err
+	}
 	if ok, err := maybeDryRun(ctx, "entries create", map[string]any{"list": c.List, "data": data}); ok || err != nil {
 		return err
 	}
@@ -45,8 +52,9 @@ func (c *EntriesCreateCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type EntriesAssertCmd struct {
-	List string `arg:"" name:"list" help:"List slug or UUID" required:""`
-	Data string `name:"data" help:"Entry data JSON; supports '-' or @file.json" required:""`
+	List       string `arg:"" name:"list" help:"List slug or UUID" required:""`
+	Data       string `name:"data" help:"Entry data JSON; supports '-' or @file.json"`
+	ValueFlags `embed:""`
 }
 
 func (c *EntriesAssertCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -54,10 +62,13 @@ func (c *EntriesAssertCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if err != nil {
 		return err
 	}
-	data, err := readJSONObjectInput(c.Data)
+	data, _, err := c.payload(ctx, entryValueTarget(client, c.List, ""), c.Data, valueWriteCreate)
 	if err != nil {
 		return err
 	}
+	if err := validateWriteData(ctx, flags, newEntryValidator(client, c.List), data, validateAssert, ""); err != nil {
+		return err
+	}
 	if ok, err := maybeDryRun(ctx, "entries assert", map[string]any{"list": c.List, "data": data}); ok || err != nil {
 		return err
 	}
@@ -69,13 +80,17 @@ func (c *EntriesAssertCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type EntriesQueryCmd struct {
-	List     string `arg:"" name:"list" help:"List slug or UUID" required:""`
-	Filter   string `name:"filter" help:"Filter JSON object"`
-	Sorts    string `name:"sorts" help:"Sorts JSON array"`
-	Limit    int    `name:"limit" help:"Page size" default:"500"`
-	Offset   int    `name:"offset" help:"Offset for first page" default:"0"`
-	All      bool   `name:"all" help:"Fetch all pages"`
-	MaxPages int    `name:"max-pages" help:"Maximum pages to fetch when --all is set" default:"100"`
+	List        string `arg:"" name:"list" help:"List slug or UUID" required:""`
+	Filter      string `name:"filter" help:"Filter JSON object"`
+	Where       string `name:"where" help:"Filter expression, e.g. 'name ~ \"Ada\" and stage in (\"Won\",\"Lost\")'"`
+	Sorts       string `name:"sorts" help:"Sorts JSON array"`
+	Limit       int    `name:"limit" help:"Page size" default:"500"`
+	Offset      int    `name:"offset" help:"Offset for first page" default:"0"`
+	All         bool   `name:"all" help:"Fetch all pages"`
+	MaxPages    int    `name:"max-pages" help:"Maximum pages to fetch when --all or --stream is set" default:"100"`
+	Stream      bool   `name:"stream" aliases:"ndjson" help:"Stream all pages as NDJSON, one item per line, as each page arrives"`
+	Checkpoint  string `name:"checkpoint" help:"Save progress to this file after each page and resume from it on rerun (with --all or --stream)"`
+	ExpandFlags `embed:""`
 }
 
 func (c *EntriesQueryCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -84,12 +99,9 @@ func (c *EntriesQueryCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 
-	var filter any
-	if c.Filter != "" {
-		filter, err = readJSONValueInput(c.Filter)
-		if err != nil {
-			return err
-		}
+	filter, err := resolveQueryFilter(c.Filter, c.Where, c.List)
+	if err != nil {
+		return err
 	}
 
 	var sorts any
@@ -100,25 +112,49 @@ func (c *EntriesQueryCmd) Run(ctx context.Context, flags *RootFlags) error {
 		}
 	}
 
+	if err := requireCheckpointPaging(c.Checkpoint, c.All, c.Stream); err != nil {
+		return err
+	}
+
+	exp, err := c.expander(ctx, client, "lists", c.List)
+	if err != nil {
+		return err
+	}
+
 	limit := c.Limit
 	if limit <= 0 {
 		limit = 500
 	}
+	// A query changes nothing, so --dry-run only previews the filter a
+	// --where expression compiles to.
+	if c.Where != "" {
+		if ok, err := maybeDryRun(ctx, "entries query", map[string]any{"list": c.List, "filter": filter, "sorts": sorts, "limit": limit, "offset": c.Offset}); ok || err != nil {
+			return err
+		}
+	}
+
+	cp, err := openPageCheckpoint(c.Checkpoint, "entries query", map[string]any{"list": c.List, "filter": filter, "sorts": sorts, "limit": limit, "offset": c.Offset}, !c.Stream)
+	if err != nil {
+		return err
+	}
+	ctx, stop := cp.notifyContext(ctx)
+	defer stop()
+
+	fetch := exp.wrap(ctx, "entry_values", func(offset int) ([]map[string]any, error) {
+		return client.QueryEntries(ctx, c.List, filter, sorts, limit, offset)
+	})
+	if c.Stream {
+		return streamOffsetPages(ctx, cp, limit, c.MaxPages, c.Offset, fetch)
+	}
 
 	var entries []map[string]any
 	if c.All {
-		start := c.Offset
-		entries, err = api.FetchAllOffset(ctx, limit, c.MaxPages, func(offset int) ([]map[string]any, error) {
-			return client.QueryEntries(ctx, c.List, filter, sorts, limit, start+offset)
-		})
-		if err != nil {
-			return err
-		}
+		entries, err = collectOffsetPages(ctx, cp, limit, c.MaxPages, c.Offset, fetch)
 	} else {
-		entries, err = client.QueryEntries(ctx, c.List, filter, sorts, limit, c.Offset)
-		if err != nil {
-			return err
-		}
+		entries, err = fetch(c.Offset)
+	}
+	if err != nil {
+		return err
 	}
 
 	if outfmt.IsJSON(ctx) {
@@ -140,12 +176,13 @@ func (c *EntriesQueryCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return writeOffsetPaginatedJSON(ctx, entries, limit, offset)
 	}
 
-	return writeEntryRows(ctx, entries)
+	return writeEntryRows(ctx, entries, exp.columns()...)
 }
 
 type EntriesGetCmd struct {
-	List    string `arg:"" name:"list" help:"List slug or UUID" required:""`
-	EntryID string `arg:"" name:"entry-id" help:"Entry UUID" required:""`
+	List        string `arg:"" name:"list" help:"List slug or UUID" required:""`
+	EntryID     string `arg:"" name:"entry-id" help:"Entry UUID" required:""`
+	ExpandFlags `embed:""`
 }
 
 func (c *EntriesGetCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -153,17 +190,25 @@ func (c *EntriesGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if err != nil {
 		return err
 	}
+	exp, err := c.expander(ctx, client, "lists", c.List)
+	if err != nil {
+		return err
+	}
 	entry, err := client.GetEntry(ctx, c.List, c.EntryID)
 	if err != nil {
 		return err
 	}
-	return writeSingleEntry(ctx, entry)
+	if err := exp.expand(ctx, []map[string]any{entry}, "entry_values"); err != nil {
+		return err
+	}
+	return writeSingleEntry(ctx, entry, exp.columns()...)
 }
 
 type EntriesUpdateCmd struct {
-	List    string `arg:"" name:"list" help:"List slug or UUID" required:""`
-	EntryID string `arg:"" name:"entry-id" help:"Entry UUID" required:""`
-	Data    string `name:"data" help:"Entry data JSON; supports '-' or @file.json" required:""`
+	List       string `arg:"" name:"list" help:"List slug or UUID" required:""`
+	EntryID    string `arg:"" name:"entry-id" help:"Entry UUID" required:""`
+	Data       string `name:"data" help:"Entry data JSON; supports '-' or @file.json"`
+	ValueFlags `embed:""`
 }
 
 func (c *EntriesUpdateCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -171,17 +216,29 @@ func (c *EntriesUpdateCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if err != nil {
 		return err
 	}
-	data, err := readJSONObjectInput(c.Data)
+	data, replace, err := c.payload(ctx, entryValueTarget(client, c.List, c.EntryID), c.Data, valueWriteUpdate)
 	if err != nil {
 		return err
 	}
-	if ok, err := maybeDryRun(ctx, "entries update", map[string]any{"list": c.List, "entry_id": c.EntryID, "data": data}); ok || err != nil {
+	if err := validateWriteData(ctx, flags, newEntryValidator(client, c.List), data, validateUpdate, c.EntryID); err != nil {
+		return err
+	}
+	if ok, err := maybeDryRun(ctx, "entries update", updatePreview(map[string]any{"list": c.List, "entry_id": c.EntryID, "data": data}, replace)); ok || err != nil {
 		return err
 	}
-	entry, err := client.UpdateEntry(ctx, c.List, c.EntryID, data)
+	snapshot, err := snapshotBefore(ctx, client, flags.Profile, undo.KindEntry, undo.ActionUpdate, c.List, []string{c.EntryID}, writtenKeys(data, "entry_values"))
 	if err != nil {
 		return err
 	}
+	update := client.UpdateEntry
+	if replace {
+		update = client.ReplaceEntry
+	}
+	entry, err := update(ctx, c.List, c.EntryID, data)
+	if err != nil {
+		return err
+	}
+	saveSnapshot(ctx, snapshot, 1)
 	return writeSingleEntry(ctx, entry)
 }
 
@@ -200,13 +257,21 @@ func (c *EntriesReplaceCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if err != nil {
 		return err
 	}
+	if err := validateWriteData(ctx, flags, newEntryValidator(client, c.List), data, validateUpdate, c.EntryID); err != nil {
+		return err
+	}
 	if ok, err := maybeDryRun(ctx, "entries replace", map[string]any{"list": c.List, "entry_id": c.EntryID, "data": data}); ok || err != nil {
 		return err
 	}
+	snapshot, err := snapshotBefore(ctx, client, flags.Profile, undo.KindEntry, undo.ActionReplace, c.List, []string{c.EntryID}, writtenKeys(data, "entry_values"))
+	if err != nil {
+		return err
+	}
 	entry, err := client.ReplaceEntry(ctx, c.List, c.EntryID, data)
 	if err != nil {
 		return err
 	}
+	saveSnapshot(ctx, snapshot, 1)
 	return writeSingleEntry(ctx, entry)
 }
 
@@ -223,9 +288,27 @@ func (c *EntriesDeleteCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if ok, err := maybeDryRun(ctx, "entries delete", map[string]any{"list": c.List, "entry_id": c.EntryID}); ok || err != nil {
 		return err
 	}
+	if err := confirmDestructive(ctx, flags, "delete an entry from "+c.List, func() (string, error) {
+		entry, err := client.GetEntry(ctx, c.List, c.EntryID)
+		if err != nil {
+			return "", err
+		}
+		parent := mapString(entry, "parent_record_id")
+		if parent != "" {
+			parent = "record " + parent
+		}
+		return describeResource("entry", c.EntryID, parent, mapString(entry, "web_url")), nil
+	}); err != nil {
+		return err
+	}
+	snapshot, err := snapshotBefore(ctx, client, flags.Profile, undo.KindEntry, undo.ActionDelete, c.List, []string{c.EntryID}, nil)
+	if err != nil {
+		return err
+	}
 	if err := client.DeleteEntry(ctx, c.List, c.EntryID); err != nil {
 		return err
 	}
+	saveSnapshot(ctx, snapshot, 1)
 
 	if outfmt.IsJSON(ctx) {
 		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{
@@ -238,17 +321,19 @@ func (c *EntriesDeleteCmd) Run(ctx context.Context, flags *RootFlags) error {
 	return nil
 }
 
-func writeSingleEntry(ctx context.Context, entry map[string]any) error {
+func writeSingleEntry(ctx context.Context, entry map[string]any, expanded ...string) error {
 	if ok, err := maybeWriteIDOnly(ctx, entry); ok || err != nil {
 		return err
 	}
 	if outfmt.IsJSON(ctx) {
 		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{"data": entry})
 	}
-	return writeEntryRows(ctx, []map[string]any{entry})
+	return writeEntryRows(ctx, []map[string]any{entry}, expanded...)
 }
 
-func writeEntryRows(ctx context.Context, entries []map[string]any) error {
+// writeEntryRows prints entries, with one column of referenced record names
+// per expanded attribute.
+func writeEntryRows(ctx context.Context, entries []map[string]any, expanded ...string) error {
 	if err := maybePreviewResults(ctx, len(entries)); err != nil {
 		return err
 	}
@@ -258,12 +343,13 @@ func writeEntryRows(ctx context.Context, entries []map[string]any) error {
 
 	w, done := tableWriter(ctx)
 	defer done()
-	_, _ = fmt.Fprintln(w, "ID\tCREATED_AT\tWEB_URL")
+	_, _ = fmt.Fprintln(w, "ID\tCREATED_AT\tWEB_URL"+expandedHeaders(expanded))
 	for _, entry := range entries {
-		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n",
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s%s\n",
 			idString(entry["id"]),
 			mapString(entry, "created_at"),
 			mapString(entry, "web_url"),
+			expandedCells(entry, "entry_values", expanded),
 		)
 	}
 	return nil
@@ -49,6 +49,19 @@ func errorObject(err error) map[string]any {
 		payload["code"] = "auth_required"
 	}
 
+	var policyErr *PolicyError
+	if errors.As(err, &policyErr) {
+		payload["kind"] = "policy"
+		payload["code"] = "blocked_by_policy"
+	}
+
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		payload["kind"] = "validation"
+		payload["code"] = "invalid_data"
+		payload["problems"] = validationErr.Problems
+	}
+
 	if errors.Is(err, keyring.ErrKeyNotFound) {
 		payload["kind"] = "keyring"
 		payload["code"] = "key_not_found"
@@ -71,6 +71,16 @@ func stableExitCode(err error) error {
 		return &ExitError{Code: ExitCodeAuth, Err: err}
 	}
 
+	var policyErr *PolicyError
+	if errors.As(err, &policyErr) {
+		return &ExitError{Code: ExitCodeUsage, Err: err}
+	}
+
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return &ExitError{Code: ExitCodeUsage, Err: err}
+	}
+
 	return &ExitError{Code: ExitCodeGeneric, Err: err}
 }
 
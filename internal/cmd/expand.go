@@ -0,0 +1,325 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/failup-ventures/attio-cli/internal/api"
+)
+
+const (
+	// maxExpandDepth bounds --expand-depth; every level is another round of
+	// lookups.
+	maxExpandDepth = 3
+	// expandConcurrency is how many referenced records are fetched at once.
+	expandConcurrency = 8
+)
+
+// ExpandFlags are the --expand flags of records and entries reads.
+type ExpandFlags struct {
+	Expand      string `name:"expand" help:"Comma-separated record-reference attributes whose referenced records are fetched and inlined, e.g. company,team"`
+	ExpandDepth int    `name:"expand-depth" help:"Also expand the same attributes on the referenced records, up to this many levels" default:"1"`
+}
+
+// expander resolves record references for one command run. Lookups are
+// cached, so a record referenced from several items, pages or levels is
+// fetched once.
+type expander struct {
+	client *api.Client
+	attrs  []string
+	depth  int
+
+	mu    sync.Mutex
+	cache map[string]map[string]any
+}
+
+// expander returns nil when --expand is not given. Each attribute has to be
+// a record reference on the object or list, or, with --expand-depth above 1,
+// on an object reached through the levels before.
+func (f ExpandFlags) expander(ctx context.Context, client *api.Client, target string, identifier string) (*expander, error) {
+	keys := splitCommaList(f.Expand)
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	if f.ExpandDepth < 1 || f.ExpandDepth > maxExpandDepth {
+		return nil, newUsageError(fmt.Errorf("--expand-depth must be between 1 and %d", maxExpandDepth))
+	}
+	attrs, err := resolveExpandAttributes(ctx, client, target, identifier, keys, f.ExpandDepth)
+	if err != nil {
+		return nil, err
+	}
+	return &expander{client: client, attrs: attrs, depth: f.ExpandDepth, cache: map[string]map[string]any{}}, nil
+}
+
+// resolveExpandAttributes returns the slugs of the --expand attributes,
+// walking the objects the expanded references point to level by level.
+func resolveExpandAttributes(ctx context.Context, client *api.Client, target string, identifier string, keys []string, depth int) ([]string, error) {
+	type scope struct{ target, identifier string }
+	slugs := make([]string, len(keys))
+	other := make([]attributeDef, len(keys))
+	var seen []attributeDef
+	visited := map[scope]bool{}
+	scopes := []scope{{target, identifier}}
+	for level := 0; level < depth && len(scopes) > 0; level++ {
+		var next []scope
+		for _, sc := range scopes {
+			if visited[sc] {
+				continue
+			}
+			visited[sc] = true
+			defs, err := listAttributeDefs(ctx, client, sc.target, sc.identifier)
+			if err != nil {
+				return nil, err
+			}
+			seen = append(seen, defs...)
+			for i, key := range keys {
+				def, ok := findAttributeDef(defs, key)
+				if !ok {
+					continue
+				}
+				if def.Type != "record-reference" {
+					other[i] = def
+					continue
+				}
+				if slugs[i] == "" {
+					slugs[i] = def.Slug
+				}
+				for _, object := range def.TargetObjects {
+					next = append(next, scope{"objects", object})
+				}
+			}
+		}
+		scopes = next
+	}
+	for i, key := range keys {
+		switch {
+		case slugs[i] != "":
+		case other[i].Slug != "":
+			return nil, newUsageError(fmt.Errorf("--expand: %q is a %s attribute, not a record reference", other[i].Slug, other[i].Type))
+		default:
+			msg := fmt.Sprintf("--expand: unknown attribute %q", key)
+			if slug := closestSlug(key, seen); slug != "" {
+				msg += fmt.Sprintf("; did you mean %q?", slug)
+			}
+			return nil, newUsageError(errors.New(msg))
+		}
+	}
+	return slugs, nil
+}
+
+// columns names the attributes expanded in table output, nil when off.
+func (e *expander) columns() []string {
+	if e == nil {
+		return nil
+	}
+	return e.attrs
+}
+
+// wrap expands every page fetch returns before it is written or collected.
+func (e *expander) wrap(ctx context.Context, field string, fetch func(offset int) ([]map[string]any, error)) func(offset int) ([]map[string]any, error) {
+	if e == nil {
+		return fetch
+	}
+	return func(offset int) ([]map[string]any, error) {
+		items, err := fetch(offset)
+		if err != nil {
+			return nil, err
+		}
+		return items, e.expand(ctx, items, field)
+	}
+}
+
+// expand inlines the referenced record of each expanded attribute value of
+// items as "target_record". field is "values" for records and
+// "entry_values" for entries.
+func (e *expander) expand(ctx context.Context, items []map[string]any, field string) error {
+	if e == nil {
+		return nil
+	}
+	for level := 0; level < e.depth && len(items) > 0; level++ {
+		refs := e.references(items, field)
+		if err := e.fetch(ctx, refs); err != nil {
+			return err
+		}
+		var next []map[string]any
+		for _, ref := range refs {
+			record := e.cached(ref.key())
+			if record == nil {
+				continue
+			}
+			// Each referenced value gets its own copy, so expanding the next
+			// level cannot create cycles in the output.
+			copied := shallowCopyRecord(record)
+			ref.value["target_record"] = copied
+			next = append(next, copied)
+		}
+		items, field = next, "values"
+	}
+	return nil
+}
+
+// recordRef is one record-reference value to expand.
+type recordRef struct {
+	object string
+	id     string
+	value  map[string]any
+}
+
+func (r recordRef) key() string {
+	return r.object + "/" + r.id
+}
+
+func (e *expander) references(items []map[string]any, field string) []recordRef {
+	var refs []recordRef
+	for _, item := range items {
+		values := mapMap(item, field)
+		for _, attr := range e.attrs {
+			for _, raw := range valueList(values[attr]) {
+				v, ok := raw.(map[string]any)
+				if !ok {
+					continue
+				}
+				object, id := mapString(v, "target_object"), mapString(v, "target_record_id")
+				if object == "" || id == "" {
+					continue
+				}
+				refs = append(refs, recordRef{object: object, id: id, value: v})
+			}
+		}
+	}
+	return refs
+}
+
+// fetch looks up the referenced records that are not cached yet. Records
+// that no longer exist are left unexpanded.
+func (e *expander) fetch(ctx context.Context, refs []recordRef) error {
+	seen := map[string]bool{}
+	var keys []string
+	e.mu.Lock()
+	for _, ref := range refs {
+		key := ref.key()
+		if _, ok := e.cache[key]; ok || seen[key] {
+			continue
+		}
+		seen[key] = true
+		keys = append(keys, key)
+	}
+	e.mu.Unlock()
+
+	errs := map[string]error{}
+	runBulkPool(ctx, keys, expandConcurrency, func(ctx context.Context, key string) error {
+		object, id, _ := strings.Cut(key, "/")
+		record, err := e.client.GetRecord(ctx, object, id)
+		if api.IsNotFound(err) {
+			slog.Debug("expand: referenced record not found", "object", object, "record_id", id)
+			record, err = nil, nil
+		}
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		if err != nil {
+			errs[key] = err
+			return err
+		}
+		e.cache[key] = record
+		return nil
+	})
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := errs[key]; err != nil {
+			return fmt.Errorf("expand %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+func (e *expander) cached(key string) map[string]any {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.cache[key]
+}
+
+// shallowCopyRecord copies a record and its values map, which is all that
+// expanding it changes.
+func shallowCopyRecord(record map[string]any) map[string]any {
+	out := make(map[string]any, len(record))
+	for key, value := range record {
+		out[key] = value
+	}
+	values := mapMap(record, "values")
+	if values == nil {
+		return out
+	}
+	copied := make(map[string]any, len(values))
+	for slug, raw := range values {
+		list, ok := raw.([]any)
+		if !ok {
+			copied[slug] = raw
+			continue
+		}
+		items := make([]any, len(list))
+		for i, item := range list {
+			if m, ok := item.(map[string]any); ok {
+				clone := make(map[string]any, len(m))
+				for k, v := range m {
+					clone[k] = v
+				}
+				item = clone
+			}
+			items[i] = item
+		}
+		copied[slug] = items
+	}
+	out["values"] = copied
+	return out
+}
+
+// expandedHeaders and expandedCells add the expanded attribute columns to a
+// tab-separated table row.
+func expandedHeaders(attrs []string) string {
+	var b strings.Builder
+	for _, attr := range attrs {
+		b.WriteString("\t" + strings.ToUpper(attr))
+	}
+	return b.String()
+}
+
+func expandedCells(item map[string]any, field string, attrs []string) string {
+	var b strings.Builder
+	for _, attr := range attrs {
+		b.WriteString("\t" + expandedNames(item, field, attr))
+	}
+	return b.String()
+}
+
+// expandedNames lists the names of the records an expanded attribute of item
+// refers to, for table output.
+func expandedNames(item map[string]any, field string, attr string) string {
+	var names []string
+	for _, raw := range valueList(mapMap(item, field)[attr]) {
+		v, ok := raw.(map[string]any)
+		if !ok {
+			continue
+		}
+		if name := referencedRecordName(v); name != "" {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// referencedRecordName is the name of an expanded reference's record, or
+// the referenced record ID when it was not expanded.
+func referencedRecordName(v map[string]any) string {
+	if record := mapMap(v, "target_record"); record != nil {
+		if name := recordValueSummary(record, "name", "full_name", "company_name"); name != "" {
+			return name
+		}
+	}
+	return mapString(v, "target_record_id")
+}
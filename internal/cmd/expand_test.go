@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/failup-ventures/attio-cli/mockserver"
+)
+
+func TestRecordsExpand(t *testing.T) {
+	setupCLIEnv(t)
+	handler, err := mockserver.New(nil)
+	if err != nil {
+		t.Fatalf("new mock server: %v", err)
+	}
+	var companyGets atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/v2/objects/companies/records/") {
+			companyGets.Add(1)
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+	t.Setenv("ATTIO_API_KEY", "mock")
+	t.Setenv("ATTIO_BASE_URL", srv.URL)
+
+	run := func(args ...string) string {
+		t.Helper()
+		stdout, stderr, err := captureExecute(t, args)
+		if err != nil {
+			t.Fatalf("%s: %v\nstderr=%s", strings.Join(args, " "), err, stderr)
+		}
+		return stdout
+	}
+
+	founder := strings.TrimSpace(run("--id-only", "records", "create", "people", "--set", "name=Grace Hopper"))
+	acme := strings.TrimSpace(run("--id-only", "records", "create", "companies", "--set", "name=Acme", "--set", "team=people:"+founder))
+	ada := strings.TrimSpace(run("--id-only", "records", "create", "people", "--set", "name=Ada Lovelace", "--set", "company=companies:"+acme))
+	run("records", "create", "people", "--set", "name=Alan Turing", "--set", "company=companies:"+acme)
+
+	var got struct {
+		Data struct {
+			Values struct {
+				Company []struct {
+					TargetRecordID string         `json:"target_record_id"`
+					TargetRecord   map[string]any `json:"target_record"`
+				} `json:"company"`
+			} `json:"values"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(run("--json", "records", "get", "people", ada, "--expand", "company")), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	company := got.Data.Values.Company
+	if len(company) != 1 || idString(company[0].TargetRecord["id"]) != acme {
+		t.Fatalf("expected the company to be inlined, got %+v", company)
+	}
+	if name := recordValueSummary(company[0].TargetRecord, "name"); name != "Acme" {
+		t.Fatalf("expected Acme, got %q", name)
+	}
+	if _, ok := mapMap(company[0].TargetRecord, "values")["team"].([]any)[0].(map[string]any)["target_record"]; ok {
+		t.Fatalf("expected depth 1 not to expand the company's team")
+	}
+
+	// Both people share one company: it is fetched once for the page.
+	companyGets.Store(0)
+	stdout := run("records", "query", "people", "--filter", `{"company":{"target_record_id":"`+acme+`"}}`, "--expand", "company")
+	if n := companyGets.Load(); n != 1 {
+		t.Fatalf("expected 1 company lookup, got %d", n)
+	}
+	if !strings.Contains(stdout, "COMPANY") || strings.Count(stdout, "Acme") != 2 {
+		t.Fatalf("expected a COMPANY column with names, got:\n%s", stdout)
+	}
+
+	// Project default columns keep the expanded column.
+	chdirProject(t, ".attio.yaml", "columns:\n  people: [name]\n")
+	stdout = run("records", "query", "people", "--filter", `{"company":{"target_record_id":"`+acme+`"}}`, "--expand", "company")
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 3 || strings.Join(strings.Fields(lines[0]), " ") != "NAME COMPANY" || !strings.HasSuffix(lines[1], "Acme") {
+		t.Fatalf("expected the project columns plus COMPANY, got:\n%s", stdout)
+	}
+
+	stdout = run("--json", "records", "get", "people", ada, "--expand", "company,team", "--expand-depth", "2")
+	if !strings.Contains(stdout, "Grace Hopper") {
+		t.Fatalf("expected depth 2 to inline the company's team, got:\n%s", stdout)
+	}
+
+	if _, _, err := captureExecute(t, []string{"records", "get", "people", ada, "--expand", "company", "--expand-depth", "9"}); ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected a usage error for --expand-depth 9, got %v", err)
+	}
+	if _, stderr, err := captureExecute(t, []string{"records", "get", "people", ada, "--expand", "compnay"}); ExitCode(err) != ExitCodeUsage || !strings.Contains(stderr, `did you mean "company"?`) {
+		t.Fatalf("expected a suggestion for a misspelt attribute, got %v\n%s", err, stderr)
+	}
+	if _, stderr, err := captureExecute(t, []string{"records", "get", "people", ada, "--expand", "name"}); ExitCode(err) != ExitCodeUsage || !strings.Contains(stderr, "not a record reference") {
+		t.Fatalf("expected a usage error for a non-reference attribute, got %v\n%s", err, stderr)
+	}
+	if _, _, err := captureExecute(t, []string{"records", "get", "people", ada, "--expand", "company,team"}); ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected team to be unknown at depth 1, got %v", err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -18,12 +19,18 @@ import (
 	"github.com/failup-ventures/attio-cli/internal/config"
 	"github.com/failup-ventures/attio-cli/internal/outfmt"
 	"github.com/failup-ventures/attio-cli/internal/ui"
+	"github.com/failup-ventures/attio-cli/internal/where"
 )
 
 var (
 	clientRuntimeMu sync.RWMutex
 	clientUserAgent = "attio-cli/dev"
 	clientTimeout   = 30 * time.Second
+	clientRateLimit string
+
+	// rateLimiters shares one limiter per profile and limit so every client
+	// built in this process draws from the same budget.
+	rateLimiters = map[string]*api.RateLimiter{}
 )
 
 func setClientRuntimeOptions(userAgent string, timeout time.Duration) {
@@ -38,6 +45,39 @@ func setClientRuntimeOptions(userAgent string, timeout time.Duration) {
 	}
 }
 
+func setClientRateLimit(spec string) {
+	clientRuntimeMu.Lock()
+	defer clientRuntimeMu.Unlock()
+	clientRateLimit = strings.TrimSpace(spec)
+}
+
+// sharedRateLimiter resolves the limit for profile (--rate-limit, then the
+// profile's rate_limit, then api.DefaultRateLimit) and returns its limiter.
+func sharedRateLimiter(profile string) (*api.RateLimiter, error) {
+	clientRuntimeMu.Lock()
+	defer clientRuntimeMu.Unlock()
+
+	spec := clientRateLimit
+	if spec == "" {
+		var err error
+		if spec, err = config.ResolveRateLimit(profile); err != nil {
+			return nil, err
+		}
+	}
+	limit, err := api.ParseRateLimit(spec)
+	if err != nil {
+		return nil, fmt.Errorf("profile rate_limit: %w", err)
+	}
+	key := config.ResolveProfile(profile) + "|" + limit.String()
+	if limiter, ok := rateLimiters[key]; ok {
+		return limiter, nil
+	}
+	slog.Debug("rate limit", "profile", config.ResolveProfile(profile), "limit", limit.String())
+	limiter := api.NewRateLimiter(limit)
+	rateLimiters[key] = limiter
+	return limiter, nil
+}
+
 func getClientRuntimeOptions() (string, time.Duration) {
 	clientRuntimeMu.RLock()
 	defer clientRuntimeMu.RUnlock()
@@ -45,15 +85,40 @@ func getClientRuntimeOptions() (string, time.Duration) {
 }
 
 func requireClient(profile string) (*api.Client, error) {
-	apiKey, err := config.ResolveAPIKey(profile)
+	apiKey, source, err := config.ResolveAPIKeyWithSource(profile)
 	if err != nil {
-		return nil, err
+		// Replayed cassettes never reach the API, so they need no key.
+		if !api.ReplayingFromEnv() {
+			return nil, err
+		}
+		apiKey = "replay"
+	}
+	if source == config.AuthSourceOAuth {
+		if apiKey, err = freshOAuthToken(profile); err != nil {
+			return nil, err
+		}
 	}
 	baseURL := config.ResolveBaseURL(profile)
 	client := api.NewClient(apiKey, baseURL)
+	cassette, err := api.CassetteFromEnv()
+	if err != nil {
+		return nil, newUsageError(err)
+	}
+	client.SetCassette(cassette)
 	userAgent, timeout := getClientRuntimeOptions()
 	client.SetUserAgent(userAgent)
 	client.SetTimeout(timeout)
+	limiter, err := sharedRateLimiter(profile)
+	if err != nil {
+		return nil, err
+	}
+	client.SetRateLimiter(limiter)
+	client.SetRequestGuard(getClientPolicy().guard)
+	auditor, err := newAuditor(profile, client)
+	if err != nil {
+		return nil, err
+	}
+	client.SetAuditor(auditor)
 	return client, nil
 }
 
@@ -449,3 +514,26 @@ func idString(v any) string {
 	}
 	return anyString(v)
 }
+
+// resolveQueryFilter returns the filter from --filter JSON or a compiled --where
+// expression. root is the queried object or list, used for path filters.
+func resolveQueryFilter(filterFlag string, whereFlag string, root string) (any, error) {
+	if strings.TrimSpace(whereFlag) == "" {
+		if filterFlag == "" {
+			return nil, nil
+		}
+		return readJSONValueInput(filterFlag)
+	}
+	if filterFlag != "" {
+		return nil, newUsageError(errors.New("use either --filter or --where, not both"))
+	}
+	filter, err := where.ParseAndCompile(whereFlag, root)
+	var parseErr *where.ParseError
+	if errors.As(err, &parseErr) {
+		return nil, newUsageError(fmt.Errorf("invalid --where expression at %w", err))
+	}
+	if err != nil {
+		return nil, newUsageError(fmt.Errorf("invalid --where expression: %w", err))
+	}
+	return filter, nil
+}
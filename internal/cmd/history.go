@@ -0,0 +1,312 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/failup-ventures/attio-cli/internal/api"
+	"github.com/failup-ventures/attio-cli/internal/outfmt"
+	"github.com/failup-ventures/attio-cli/internal/ui"
+)
+
+// historyConcurrency is how many attributes' values are fetched at once.
+const historyConcurrency = 8
+
+// HistoryFlags are shared by records history and entries history.
+type HistoryFlags struct {
+	Attributes string `name:"attributes" help:"Comma-separated attribute slugs to include (default: all)"`
+	Markdown   bool   `name:"markdown" help:"Render the changelog as a markdown table"`
+}
+
+// historyChange is one change to one attribute: the values it had just
+// before Time and the values it had from Time on.
+type historyChange struct {
+	Time      string         `json:"time"`
+	Attribute string         `json:"attribute"`
+	Title     string         `json:"attribute_title,omitempty"`
+	Actor     map[string]any `json:"actor,omitempty"`
+	From      []string       `json:"from"`
+	To        []string       `json:"to"`
+
+	at time.Time
+}
+
+type RecordsHistoryCmd struct {
+	Object       string `arg:"" name:"object" help:"Object slug or UUID" required:""`
+	RecordID     string `arg:"" name:"record-id" help:"Record UUID" required:""`
+	HistoryFlags `embed:""`
+}
+
+func (c *RecordsHistoryCmd) Run(ctx context.Context, flags *RootFlags) error {
+	client, err := requireClient(flags.Profile)
+	if err != nil {
+		return err
+	}
+	return c.run(ctx, client, "objects", c.Object, func(slug string, limit int, offset int) ([]map[string]any, error) {
+		return client.ListRecordAttributeValues(ctx, c.Object, c.RecordID, slug, true, limit, offset)
+	})
+}
+
+type EntriesHistoryCmd struct {
+	List         string `arg:"" name:"list" help:"List slug or UUID" required:""`
+	EntryID      string `arg:"" name:"entry-id" help:"Entry UUID" required:""`
+	HistoryFlags `embed:""`
+}
+
+func (c *EntriesHistoryCmd) Run(ctx context.Context, flags *RootFlags) error {
+	client, err := requireClient(flags.Profile)
+	if err != nil {
+		return err
+	}
+	return c.run(ctx, client, "lists", c.List, func(slug string, limit int, offset int) ([]map[string]any, error) {
+		return client.ListEntryAttributeValues(ctx, c.List, c.EntryID, slug, true, limit, offset)
+	})
+}
+
+// run fetches the historic values of every selected attribute of the object
+// or list and writes them as one changelog, oldest change first.
+func (f HistoryFlags) run(ctx context.Context, client *api.Client, target string, identifier string, list func(slug string, limit int, offset int) ([]map[string]any, error)) error {
+	defs, err := listAttributeDefs(ctx, client, target, identifier)
+	if err != nil {
+		return err
+	}
+	if only := splitCommaList(f.Attributes); len(only) > 0 {
+		selected := make([]attributeDef, 0, len(only))
+		for _, key := range only {
+			def, ok := findAttributeDef(defs, key)
+			if !ok {
+				msg := fmt.Sprintf("--attributes: unknown attribute %q", key)
+				if slug := closestSlug(key, defs); slug != "" {
+					msg += fmt.Sprintf("; did you mean %q?", slug)
+				}
+				return newUsageError(errors.New(msg))
+			}
+			selected = append(selected, def)
+		}
+		defs = selected
+	} else {
+		// Interaction (COMINT) attributes and read-only system or enriched
+		// attributes have no history the API will return.
+		kept := defs[:0]
+		for _, def := range defs {
+			if def.Writable && def.Type != "interaction" {
+				kept = append(kept, def)
+			}
+		}
+		defs = kept
+	}
+
+	var (
+		mu      sync.Mutex
+		changes []historyChange
+		skipped []historySkip
+		errs    = map[string]error{}
+	)
+	slugs := make([]string, 0, len(defs))
+	bySlug := make(map[string]attributeDef, len(defs))
+	for _, def := range defs {
+		slugs = append(slugs, def.Slug)
+		bySlug[def.Slug] = def
+	}
+	runBulkPool(ctx, slugs, historyConcurrency, func(ctx context.Context, slug string) error {
+		var values []map[string]any
+		err := api.EachOffsetPage(ctx, 500, 0, func(offset int) ([]map[string]any, error) {
+			return list(slug, 500, offset)
+		}, func(page []map[string]any) error {
+			values = append(values, page...)
+			return nil
+		})
+		mu.Lock()
+		defer mu.Unlock()
+		var ae *api.AttioError
+		if errors.As(err, &ae) && ae.StatusCode == http.StatusBadRequest {
+			skipped = append(skipped, historySkip{Attribute: slug, Reason: ae.Message})
+			return nil
+		}
+		if err != nil {
+			errs[slug] = err
+			return err
+		}
+		changes = append(changes, attributeChanges(bySlug[slug], values)...)
+		return nil
+	})
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	for _, slug := range slugs {
+		if err := errs[slug]; err != nil {
+			return fmt.Errorf("history of %s: %w", slug, err)
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		if !changes[i].at.Equal(changes[j].at) {
+			return changes[i].at.Before(changes[j].at)
+		}
+		return changes[i].Attribute < changes[j].Attribute
+	})
+	sort.Slice(skipped, func(i, j int) bool { return skipped[i].Attribute < skipped[j].Attribute })
+	return writeHistory(ctx, changes, skipped, f.Markdown)
+}
+
+// historySkip is an attribute whose history the API refused to return, such
+// as an enriched attribute or a list attribute without history.
+type historySkip struct {
+	Attribute string `json:"attribute"`
+	Reason    string `json:"reason"`
+}
+
+// historicValue is an attribute value with its parsed active period. A zero
+// until means the value is still active.
+type historicValue struct {
+	raw   map[string]any
+	from  time.Time
+	until time.Time
+}
+
+func (v historicValue) activeBefore(t time.Time) bool {
+	return v.from.Before(t) && (v.until.IsZero() || !v.until.Before(t))
+}
+
+func (v historicValue) activeAt(t time.Time) bool {
+	return !v.from.After(t) && (v.until.IsZero() || v.until.After(t))
+}
+
+// attributeChanges turns the historic values of one attribute into changes.
+// Every time a value became active or stopped being active is a change; the
+// actor is whoever created the values that became active then.
+func attributeChanges(def attributeDef, raw []map[string]any) []historyChange {
+	values := make([]historicValue, 0, len(raw))
+	times := map[time.Time]string{}
+	for _, v := range raw {
+		hv := historicValue{raw: v, from: parseHistoryTime(mapString(v, "active_from")), until: parseHistoryTime(mapString(v, "active_until"))}
+		if hv.from.IsZero() {
+			continue
+		}
+		values = append(values, hv)
+		times[hv.from] = mapString(v, "active_from")
+		if !hv.until.IsZero() {
+			times[hv.until] = mapString(v, "active_until")
+		}
+	}
+
+	var changes []historyChange
+	for at, stamp := range times {
+		change := historyChange{Time: stamp, Attribute: def.Slug, Title: def.Title, From: []string{}, To: []string{}, at: at}
+		for _, v := range values {
+			text := flattenAttributeValue(def.Type, v.raw)
+			if v.activeBefore(at) {
+				change.From = append(change.From, text)
+			}
+			if v.activeAt(at) {
+				change.To = append(change.To, text)
+			}
+			if change.Actor == nil && v.from.Equal(at) {
+				change.Actor = mapMap(v.raw, "created_by_actor")
+			}
+		}
+		if strings.Join(change.From, "\x00") != strings.Join(change.To, "\x00") {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+func parseHistoryTime(value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+func actorLabel(actor map[string]any) string {
+	typ, id := mapString(actor, "type"), mapString(actor, "id")
+	switch {
+	case typ == "" && id == "":
+		return ""
+	case id == "":
+		return typ
+	case typ == "":
+		return id
+	}
+	return typ + ":" + id
+}
+
+func writeHistory(ctx context.Context, changes []historyChange, skipped []historySkip, markdown bool) error {
+	if err := maybePreviewResults(ctx, len(changes)); err != nil {
+		return err
+	}
+	if outfmt.IsJSON(ctx) {
+		if changes == nil {
+			changes = []historyChange{}
+		}
+		if skipped == nil {
+			skipped = []historySkip{}
+		}
+		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{"data": changes, "skipped": skipped})
+	}
+	if len(skipped) > 0 {
+		names := make([]string, 0, len(skipped))
+		for _, skip := range skipped {
+			names = append(names, skip.Attribute)
+		}
+		msg := "Skipped attributes without history: " + strings.Join(names, ", ")
+		if u := ui.FromContext(ctx); u != nil {
+			u.Err().Printf("%s", msg)
+		} else {
+			_, _ = fmt.Fprintln(os.Stderr, msg)
+		}
+	}
+	if markdown {
+		writeHistoryMarkdown(os.Stdout, changes)
+		return nil
+	}
+
+	w, done := tableWriter(ctx)
+	defer done()
+	_, _ = fmt.Fprintln(w, "TIME\tATTRIBUTE\tACTOR\tFROM\tTO")
+	for _, c := range changes {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			c.Time,
+			c.Attribute,
+			actorLabel(c.Actor),
+			strings.Join(c.From, "; "),
+			strings.Join(c.To, "; "),
+		)
+	}
+	return nil
+}
+
+func writeHistoryMarkdown(out io.Writer, changes []historyChange) {
+	cell := func(s string) string {
+		s = strings.ReplaceAll(s, "|", `\|`)
+		return strings.ReplaceAll(s, "\n", " ")
+	}
+	_, _ = fmt.Fprintln(out, "| Time | Attribute | Actor | From | To |")
+	_, _ = fmt.Fprintln(out, "| --- | --- | --- | --- | --- |")
+	for _, c := range changes {
+		attr := c.Attribute
+		if c.Title != "" {
+			attr = c.Title
+		}
+		_, _ = fmt.Fprintf(out, "| %s | %s | %s | %s | %s |\n",
+			cell(c.Time),
+			cell(attr),
+			cell(actorLabel(c.Actor)),
+			cell(strings.Join(c.From, "; ")),
+			cell(strings.Join(c.To, "; ")),
+		)
+	}
+}
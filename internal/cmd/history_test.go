@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/failup-ventures/attio-cli/mockserver"
+)
+
+func TestRecordsHistory(t *testing.T) {
+	setupCLIEnv(t)
+	handler, err := mockserver.New(nil)
+	if err != nil {
+		t.Fatalf("new mock server: %v", err)
+	}
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+	t.Setenv("ATTIO_API_KEY", "mock")
+	t.Setenv("ATTIO_BASE_URL", srv.URL)
+
+	run := func(args ...string) string {
+		t.Helper()
+		stdout, stderr, err := captureExecute(t, args)
+		if err != nil {
+			t.Fatalf("%s: %v\nstderr=%s", strings.Join(args, " "), err, stderr)
+		}
+		return stdout
+	}
+
+	id := strings.TrimSpace(run("--id-only", "records", "create", "companies", "--set", "name=Acme", "--set", "description=v1"))
+	run("records", "update", "companies", id, "--set", "description=v2")
+	run("records", "update", "companies", id, "--add", "categories=SaaS")
+	run("records", "replace", "companies", id, "--set", "categories=B2B")
+
+	var out struct {
+		Data []struct {
+			Attribute string         `json:"attribute"`
+			Actor     map[string]any `json:"actor"`
+			From      []string       `json:"from"`
+			To        []string       `json:"to"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(run("--json", "records", "history", "companies", id, "--attributes", "description,categories")), &out); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	var got []string
+	for _, c := range out.Data {
+		got = append(got, c.Attribute+":"+strings.Join(c.From, ";")+">"+strings.Join(c.To, ";"))
+		if mapString(c.Actor, "type") == "" {
+			t.Fatalf("expected an actor on %+v", c)
+		}
+	}
+	want := []string{"description:>v1", "description:v1>v2", "categories:>SaaS", "categories:SaaS>B2B"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Fatalf("unexpected changelog\n got %v\nwant %v", got, want)
+	}
+
+	// Without --attributes every attribute is included.
+	if stdout := run("records", "history", "companies", id); !strings.Contains(stdout, "Acme") || !strings.Contains(stdout, "v2") {
+		t.Fatalf("expected name and description changes, got:\n%s", stdout)
+	}
+
+	stdout := run("records", "history", "companies", id, "--attributes", "description", "--markdown")
+	if !strings.Contains(stdout, "| Time | Attribute | Actor | From | To |") || !strings.Contains(stdout, "| v1 | v2 |") {
+		t.Fatalf("unexpected markdown:\n%s", stdout)
+	}
+
+	// The API refuses history for interaction (COMINT) attributes. They are
+	// left out by default and reported as skipped when asked for.
+	run("attributes", "create", "objects", "companies", "--data", `{"api_slug":"last_touch","title":"Last touch","type":"interaction"}`)
+	run("records", "history", "companies", id)
+	var skipped struct {
+		Data    []map[string]any `json:"data"`
+		Skipped []historySkip    `json:"skipped"`
+	}
+	if err := json.Unmarshal([]byte(run("--json", "records", "history", "companies", id, "--attributes", "description,last_touch")), &skipped); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(skipped.Data) != 2 || len(skipped.Skipped) != 1 || skipped.Skipped[0].Attribute != "last_touch" {
+		t.Fatalf("expected last_touch to be skipped, got %+v", skipped)
+	}
+
+	if _, _, err := captureExecute(t, []string{"records", "history", "companies", id, "--attributes", "descripton"}); ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected a usage error for an unknown attribute, got %v", err)
+	}
+}
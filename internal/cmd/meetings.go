@@ -28,7 +28,9 @@ type MeetingsListCmd struct {
 	StartsBefore   string `name:"starts-before" help:"ISO timestamp upper bound for start"`
 	Timezone       string `name:"timezone" help:"IANA timezone"`
 	All            bool   `name:"all" help:"Fetch all pages"`
-	MaxPages       int    `name:"max-pages" help:"Maximum pages when --all is set" default:"100"`
+	MaxPages       int    `name:"max-pages" help:"Maximum pages when --all or --stream is set" default:"100"`
+	Stream         bool   `name:"stream" aliases:"ndjson" help:"Stream all pages as NDJSON, one item per line, as each page arrives"`
+	Checkpoint     string `name:"checkpoint" help:"Save progress to this file after each page and resume from it on rerun (with --all or --stream)"`
 }
 
 func (c *MeetingsListCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -37,12 +39,34 @@ func (c *MeetingsListCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 
+	if err := requireCheckpointPaging(c.Checkpoint, c.All, c.Stream); err != nil {
+		return err
+	}
+	cp, err := openPageCheckpoint(c.Checkpoint, "meetings list", map[string]any{
+		"limit":            c.Limit,
+		"cursor":           c.Cursor,
+		"sort":             c.Sort,
+		"participants":     c.Participants,
+		"linked_object":    c.LinkedObject,
+		"linked_record_id": c.LinkedRecordID,
+		"ends_from":        c.EndsFrom,
+		"starts_before":    c.StartsBefore,
+		"timezone":         c.Timezone,
+	}, !c.Stream)
+	if err != nil {
+		return err
+	}
+	ctx, stop := cp.notifyContext(ctx)
+	defer stop()
+
+	if c.Stream {
+		return streamCursorPages(ctx, cp, c.MaxPages, c.Cursor, func(cursor string) ([]map[string]any, string, error) {
+			return client.ListMeetings(ctx, c.Limit, cursor, c.Sort, c.Participants, c.LinkedObject, c.LinkedRecordID, c.EndsFrom, c.StartsBefore, c.Timezone)
+		})
+	}
+
 	if c.All {
-		startCursor := c.Cursor
-		meetings, err := api.FetchAllCursor(ctx, c.MaxPages, func(cursor string) ([]map[string]any, string, error) {
-			if cursor == "" {
-				cursor = startCursor
-			}
+		meetings, err := collectCursorPages(ctx, cp, c.MaxPages, c.Cursor, func(cursor string) ([]map[string]any, string, error) {
 			return client.ListMeetings(ctx, c.Limit, cursor, c.Sort, c.Participants, c.LinkedObject, c.LinkedRecordID, c.EndsFrom, c.StartsBefore, c.Timezone)
 		})
 		if err != nil {
@@ -217,6 +241,15 @@ func (c *MeetingsRecordingsDeleteCmd) Run(ctx context.Context, flags *RootFlags)
 	if ok, err := maybeDryRun(ctx, "meetings recordings delete", map[string]any{"meeting_id": c.MeetingID, "recording_id": c.CallRecordingID}); ok || err != nil {
 		return err
 	}
+	if err := confirmDestructive(ctx, flags, "delete a call recording", func() (string, error) {
+		recording, err := client.GetCallRecording(ctx, c.MeetingID, c.CallRecordingID)
+		if err != nil {
+			return "", err
+		}
+		return describeResource("call recording", c.CallRecordingID, "meeting "+c.MeetingID, mapString(recording, "status"), mapString(recording, "created_at")), nil
+	}); err != nil {
+		return err
+	}
 	if err := client.DeleteCallRecording(ctx, c.MeetingID, c.CallRecordingID); err != nil {
 		return err
 	}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/failup-ventures/attio-cli/mockserver"
+)
+
+type MockServerCmd struct {
+	Port    int    `name:"port" help:"Port to listen on (0 picks a free port)" default:"8765"`
+	Host    string `name:"host" help:"Interface to bind" default:"127.0.0.1"`
+	Fixture string `name:"fixture" help:"Seed the workspace from a fixture JSON file"`
+}
+
+func (c *MockServerCmd) Run(ctx context.Context) error {
+	var fixture *mockserver.Fixture
+	if c.Fixture != "" {
+		var err error
+		if fixture, err = mockserver.LoadFixture(c.Fixture); err != nil {
+			return newUsageError(err)
+		}
+	}
+	handler, err := mockserver.New(fixture)
+	if err != nil {
+		return newUsageError(err)
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(c.Host, strconv.Itoa(c.Port)))
+	if err != nil {
+		return err
+	}
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+
+	url := "http://" + listener.Addr().String()
+	_, _ = fmt.Fprintf(os.Stderr, "Mock Attio API listening on %s\n", url)
+	_, _ = fmt.Fprintf(os.Stderr, "Point the CLI at it with: export ATTIO_BASE_URL=%s ATTIO_API_KEY=mock\n", url)
+
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- server.Serve(listener) }()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/failup-ventures/attio-cli/mockserver"
+)
+
+func TestMockServerEndToEnd(t *testing.T) {
+	setupCLIEnv(t)
+	handler, err := mockserver.New(nil)
+	if err != nil {
+		t.Fatalf("new mock server: %v", err)
+	}
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+	t.Setenv("ATTIO_API_KEY", "mock")
+	t.Setenv("ATTIO_BASE_URL", srv.URL)
+
+	run := func(args ...string) any {
+		t.Helper()
+		stdout, stderr, err := captureExecute(t, append([]string{"--json"}, args...))
+		if err != nil {
+			t.Fatalf("%s: %v\nstderr=%s", strings.Join(args, " "), err, stderr)
+		}
+		var out struct {
+			Data any `json:"data"`
+		}
+		if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+			t.Fatalf("%s: invalid JSON output: %v\n%s", strings.Join(args, " "), err, stdout)
+		}
+		return out.Data
+	}
+	recordID := func(v any) string {
+		id, _ := v.(map[string]any)["id"].(map[string]any)
+		return id["record_id"].(string)
+	}
+
+	acme := recordID(run("records", "create", "companies", "--data", `{"values":{"name":"Acme","domains":["acme.com"]}}`))
+	recordID(run("records", "create", "companies", "--data", `{"values":{"name":"Globex"}}`))
+	ada := recordID(run("records", "create", "people", "--data", `{"values":{"name":"Ada Lovelace","email_addresses":["ada@acme.com"],"company":"`+acme+`"}}`))
+	for _, name := range []string{"Grace Hopper", "Alan Turing", "Annie Easley"} {
+		run("records", "create", "people", "--data", `{"values":{"name":"`+name+`"}}`)
+	}
+
+	got := run("records", "query", "people", "--where", `name ^= "A"`, "--sorts", `[{"attribute":"name","field":"last_name","direction":"asc"}]`)
+	names := []string{}
+	for _, rec := range got.([]any) {
+		values := rec.(map[string]any)["values"].(map[string]any)
+		names = append(names, values["name"].([]any)[0].(map[string]any)["full_name"].(string))
+	}
+	if strings.Join(names, ",") != "Annie Easley,Ada Lovelace,Alan Turing" {
+		t.Fatalf("unexpected filtered and sorted people: %v", names)
+	}
+
+	got = run("records", "query", "people", "--where", `company -> companies.name = "Acme"`)
+	if rows := got.([]any); len(rows) != 1 || recordID(rows[0]) != ada {
+		t.Fatalf("expected path filter to find Ada, got %v", got)
+	}
+
+	// Offset pagination: four people in pages of three.
+	got = run("records", "query", "people", "--all", "--limit", "3")
+	if len(got.([]any)) != 4 {
+		t.Fatalf("expected --all to collect 4 people, got %d", len(got.([]any)))
+	}
+
+	// Asserting on a unique attribute updates instead of duplicating.
+	run("records", "assert", "people", "--matching-attribute", "email_addresses", "--data", `{"values":{"email_addresses":["ada@acme.com"],"job_title":"Analyst"}}`)
+	got = run("records", "query", "people", "--filter", `{"email_addresses":"ada@acme.com"}`)
+	if rows := got.([]any); len(rows) != 1 || recordID(rows[0]) != ada {
+		t.Fatalf("expected assert to update Ada, got %v", got)
+	}
+
+	// Skip client-side validation so the server's own check is exercised.
+	_, stderr, err := captureExecute(t, []string{"--no-validate", "records", "create", "people", "--data", `{"values":{"email_addresses":["ada@acme.com"]}}`})
+	if err == nil || !strings.Contains(stderr, "Uniqueness") {
+		t.Fatalf("expected a uniqueness error, got %v\nstderr=%s", err, stderr)
+	}
+
+	// Cursor pagination over meetings.
+	for _, uid := range []string{"m1", "m2", "m3"} {
+		run("meetings", "create", "--data", `{"title":"Sync `+uid+`","start":{"datetime":"2026-01-0`+uid[1:]+`T10:00:00Z","timezone":"UTC"},"end":{"datetime":"2026-01-0`+uid[1:]+`T11:00:00Z","timezone":"UTC"},"is_all_day":false,"participants":[],"external_ref":{"ical_uid":"`+uid+`","provider":"google"}}`)
+	}
+	got = run("meetings", "list", "--all", "--limit", "2")
+	if meetings := got.([]any); len(meetings) != 3 || meetings[2].(map[string]any)["title"] != "Sync m3" {
+		t.Fatalf("expected 3 meetings across cursor pages, got %v", got)
+	}
+
+	run("notes", "create", "--parent-object", "people", "--parent-record", ada, "--title", "Intro", "--content", "Met at the conference")
+	got = run("notes", "list", "--parent-object", "people", "--parent-record", ada)
+	if notes := got.([]any); len(notes) != 1 || notes[0].(map[string]any)["title"] != "Intro" {
+		t.Fatalf("expected Ada's note, got %v", got)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/failup-ventures/attio-cli/internal/outfmt"
@@ -154,6 +155,15 @@ func (c *NotesDeleteCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if ok, err := maybeDryRun(ctx, "notes delete", map[string]any{"note_id": c.NoteID}); ok || err != nil {
 		return err
 	}
+	if err := confirmDestructive(ctx, flags, "delete a note", func() (string, error) {
+		note, err := client.GetNote(ctx, c.NoteID)
+		if err != nil {
+			return "", err
+		}
+		return describeResource("note", c.NoteID, strconv.Quote(mapString(note, "title")), mapString(note, "created_at")), nil
+	}); err != nil {
+		return err
+	}
 	if err := client.DeleteNote(ctx, c.NoteID); err != nil {
 		return err
 	}
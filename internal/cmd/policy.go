@@ -0,0 +1,388 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/alecthomas/kong"
+)
+
+const (
+	policyRead  = "read"
+	policyWrite = "write"
+
+	// targetObject and targetList take the write target from the command's
+	// <object> or <list> argument.
+	targetObject = "<object>"
+	targetList   = "<list>"
+)
+
+// writeCommands maps every command that changes data, in Attio or in the
+// local config and keyring, to the target object_policy is checked against.
+var writeCommands = map[string]string{
+	"attributes create":          "attributes",
+	"attributes update":          "attributes",
+	"attributes options create":  "attributes",
+	"attributes options update":  "attributes",
+	"attributes statuses create": "attributes",
+	"attributes statuses update": "attributes",
+	"auth login":                 "auth",
+	"auth logout":                "auth",
+	"comments create":            "comments",
+	"comments delete":            "comments",
+	"config set":                 "config",
+	"config profiles add":        "config",
+	"config profiles remove":     "config",
+	"config profiles rename":     "config",
+	"config profiles use":        "config",
+	"entries assert":             targetList,
+	"entries create":             targetList,
+	"entries delete":             targetList,
+	"entries delete-where":       targetList,
+	"entries replace":            targetList,
+	"entries update":             targetList,
+	"entries update-where":       targetList,
+	"init":                       "config",
+	"lists create":               "lists",
+	"lists update":               "lists",
+	"meetings create":            "meetings",
+	"meetings recordings create": "meetings",
+	"meetings recordings delete": "meetings",
+	"notes create":               "notes",
+	"notes delete":               "notes",
+	"objects create":             "objects",
+	"objects update":             "objects",
+	"records assert":             targetObject,
+	"records create":             targetObject,
+	"records delete":             targetObject,
+	"records delete-where":       targetObject,
+	"records import":             targetObject,
+	"records replace":            targetObject,
+	"records update":             targetObject,
+	"records update-where":       targetObject,
+	"schema apply":               "schema",
+	"tasks create":               "tasks",
+	"tasks delete":               "tasks",
+	"tasks update":               "tasks",
+	"undo":                       "undo",
+	"webhooks create":            "webhooks",
+	"webhooks delete":            "webhooks",
+	"webhooks update":            "webhooks",
+}
+
+// readCommands lists the commands that only read. Every command must be in
+// writeCommands or here; TestEveryCommandIsClassified keeps them complete.
+var readCommands = map[string]bool{
+	// api depends on what it sends, so the request guard checks each call.
+	"api":                      true,
+	"attributes get":           true,
+	"audit log":                true,
+	"attributes list":          true,
+	"attributes options list":  true,
+	"attributes statuses list": true,
+	"auth status":              true,
+	"comments get":             true,
+	"completion":               true,
+	"config get":               true,
+	"config profiles list":     true,
+	"config profiles show":     true,
+	"entries get":              true,
+	"entries history":          true,
+	"entries query":            true,
+	"entries values list":      true,
+	"lists get":                true,
+	"lists list":               true,
+	"meetings get":             true,
+	"meetings list":            true,
+	"meetings recordings get":  true,
+	"meetings recordings list": true,
+	"meetings transcript":      true,
+	"members get":              true,
+	"members list":             true,
+	"mock-server":              true,
+	"notes get":                true,
+	"notes list":               true,
+	"objects get":              true,
+	"objects list":             true,
+	"query":                    true,
+	"records entries list":     true,
+	"records export":           true,
+	"records validate":         true,
+	"records get":              true,
+	"records history":          true,
+	"records query":            true,
+	"records search":           true,
+	"records values list":      true,
+	"schema commands":          true,
+	"schema export":            true,
+	"schema plan":              true,
+	"search":                   true,
+	"self":                     true,
+	"tasks get":                true,
+	"tasks list":               true,
+	"threads get":              true,
+	"threads list":             true,
+	"version":                  true,
+	"webhooks get":             true,
+	"webhooks list":            true,
+	"webhooks listen":          true,
+	"__complete":               true,
+}
+
+// PolicyError reports a command or request blocked by --read-only,
+// --disable-commands or a profile's object_policy.
+type PolicyError struct {
+	Message string
+}
+
+func (e *PolicyError) Error() string {
+	if e == nil {
+		return ""
+	}
+	return e.Message
+}
+
+// writePolicy is the effective read-only mode, command deny-list and
+// per-target overrides.
+type writePolicy struct {
+	ReadOnly bool
+	// ForceReadOnly is read-only mode from --read-only or ATTIO_READ_ONLY,
+	// which object_policy cannot lift.
+	ForceReadOnly   bool
+	DisableCommands []string
+	Objects         map[string]string
+	// resolve looks up the slug and UUID of an object or list, so
+	// object_policy applies whichever of the two a command or path uses.
+	resolve func(kind string, identifier string) (slug string, id string, err error)
+}
+
+// checkWrite reports whether target may be changed. kind is "objects" or
+// "lists" when target is an object or list slug or UUID.
+func (p writePolicy) checkWrite(what string, kind string, target string) error {
+	if p.ForceReadOnly {
+		return &PolicyError{Message: fmt.Sprintf("%s is blocked: read-only mode is on (--read-only or ATTIO_READ_ONLY)", what)}
+	}
+	override, err := p.objectPolicy(kind, target)
+	if err != nil {
+		return &PolicyError{Message: fmt.Sprintf("%s is blocked: cannot check object_policy for %q: %v", what, target, err)}
+	}
+	switch override {
+	case policyWrite:
+		return nil
+	case policyRead:
+		return &PolicyError{Message: fmt.Sprintf("%s is blocked: object_policy makes %q read-only", what, target)}
+	}
+	if p.ReadOnly {
+		return &PolicyError{Message: fmt.Sprintf("%s is blocked: read-only mode is on (profile read_only)", what)}
+	}
+	return nil
+}
+
+// objectPolicy returns the object_policy entry for target, matching an
+// object or list by slug or by UUID.
+func (p writePolicy) objectPolicy(kind string, target string) (string, error) {
+	if override, ok := p.Objects[target]; ok {
+		return override, nil
+	}
+	if p.resolve == nil || target == "" || (kind != "objects" && kind != "lists") {
+		return "", nil
+	}
+	// A lookup is only needed when the command and the policy may name the
+	// target differently.
+	needed := uuidPattern.MatchString(target)
+	for name := range p.Objects {
+		needed = needed || uuidPattern.MatchString(name)
+	}
+	if !needed {
+		return "", nil
+	}
+	slug, id, err := p.resolve(kind, target)
+	if err != nil {
+		return "", err
+	}
+	for _, name := range []string{slug, id} {
+		if override, ok := p.Objects[name]; ok && name != "" {
+			return override, nil
+		}
+	}
+	return "", nil
+}
+
+// policyResolver looks up objects and lists for object_policy, caching the
+// results for the process.
+func policyResolver(profile string) func(kind string, identifier string) (string, string, error) {
+	var mu sync.Mutex
+	type names struct{ slug, id string }
+	cache := map[string]names{}
+	return func(kind string, identifier string) (string, string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		key := kind + "/" + identifier
+		if n, ok := cache[key]; ok {
+			return n.slug, n.id, nil
+		}
+		client, err := requireClient(profile)
+		if err != nil {
+			return "", "", err
+		}
+		var item map[string]any
+		idKey := "object_id"
+		if kind == "lists" {
+			idKey = "list_id"
+			item, err = client.GetList(context.Background(), identifier)
+		} else {
+			item, err = client.GetObject(context.Background(), identifier)
+		}
+		if err != nil {
+			return "", "", err
+		}
+		n := names{slug: mapString(item, "api_slug"), id: mapString(mapMap(item, "id"), idKey)}
+		cache[key] = n
+		return n.slug, n.id, nil
+	}
+}
+
+// enforcePolicy blocks disabled commands, and write commands the policy does
+// not allow, before they run. Dry runs of write commands may proceed because
+// the request guard still blocks anything they would send.
+func enforcePolicy(kctx *kong.Context, policy writePolicy, dryRun bool) error {
+	commandPath := normalizeCommandPath(kctx.Command())
+	if commandPath == "" {
+		return nil
+	}
+	for _, denied := range policy.DisableCommands {
+		denied = normalizeCommandPath(denied)
+		if denied != "" && (commandPath == denied || strings.HasPrefix(commandPath, denied+" ")) {
+			return &PolicyError{Message: fmt.Sprintf("command %q is disabled by --disable-commands or profile disable_commands", commandPath)}
+		}
+	}
+
+	target, ok := writeCommands[commandPath]
+	if !ok || dryRun {
+		return nil
+	}
+	kind := ""
+	switch target {
+	case targetObject:
+		kind, target = "objects", positionalValue(kctx, "object")
+	case targetList:
+		kind, target = "lists", positionalValue(kctx, "list")
+	}
+	return policy.checkWrite(fmt.Sprintf("%q on %q", commandPath, target), kind, target)
+}
+
+func positionalValue(kctx *kong.Context, name string) string {
+	for _, p := range kctx.Path {
+		if p.Positional != nil && p.Positional.Name == name {
+			return strings.TrimSpace(p.Positional.Target.String())
+		}
+	}
+	return ""
+}
+
+// guard vetoes API requests that would change data the policy protects.
+func (p writePolicy) guard(method string, path string) error {
+	if isReadRequest(method, path) {
+		return nil
+	}
+	target, kind := requestTarget(path)
+	return p.checkWrite(fmt.Sprintf("%s %s", method, stripQuery(path)), kind, target)
+}
+
+// readPOSTSuffixes are endpoints that take a POST body but only read.
+var readPOSTSuffixes = []string{"/records/query", "/entries/query", "/records/search"}
+
+func isReadRequest(method string, path string) bool {
+	switch strings.ToUpper(method) {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	case http.MethodPost:
+		path = strings.TrimRight(stripQuery(path), "/")
+		for _, suffix := range readPOSTSuffixes {
+			if strings.HasSuffix(path, suffix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// requestTarget names what a request changes: the object of a records path,
+// the list of an entries path, "attributes" for attribute paths, and
+// otherwise the resource (tasks, notes, webhooks, ...). kind is "objects" or
+// "lists" for records and entries paths.
+func requestTarget(path string) (target string, kind string) {
+	segments := strings.Split(strings.Trim(stripQuery(path), "/"), "/")
+	if len(segments) > 0 && segments[0] == "v2" {
+		segments = segments[1:]
+	}
+	if len(segments) == 0 {
+		return "", ""
+	}
+	for _, s := range segments {
+		if s == "attributes" {
+			return "attributes", ""
+		}
+	}
+	if len(segments) >= 3 {
+		switch {
+		case segments[0] == "objects" && segments[2] == "records":
+			return segments[1], "objects"
+		case segments[0] == "lists" && segments[2] == "entries":
+			return segments[1], "lists"
+		}
+	}
+	return segments[0], ""
+}
+
+func stripQuery(path string) string {
+	path, _, _ = strings.Cut(path, "?")
+	return path
+}
+
+func parseObjectPolicy(raw string) (map[string]string, error) {
+	items := splitCommaList(raw)
+	if len(items) == 0 {
+		return nil, nil
+	}
+	policy := make(map[string]string, len(items))
+	for _, item := range items {
+		target, mode, ok := strings.Cut(item, "=")
+		target, mode = strings.TrimSpace(target), strings.ToLower(strings.TrimSpace(mode))
+		if !ok || target == "" || (mode != policyRead && mode != policyWrite) {
+			return nil, fmt.Errorf("invalid entry %q (expected <target>=read|write)", item)
+		}
+		policy[target] = mode
+	}
+	return policy, nil
+}
+
+func formatObjectPolicy(policy map[string]string) string {
+	items := make([]string, 0, len(policy))
+	for target, mode := range policy {
+		items = append(items, target+"="+mode)
+	}
+	sort.Strings(items)
+	return strings.Join(items, ",")
+}
+
+var (
+	clientPolicyMu sync.RWMutex
+	clientPolicy   writePolicy
+)
+
+func setClientPolicy(policy writePolicy) {
+	clientPolicyMu.Lock()
+	defer clientPolicyMu.Unlock()
+	clientPolicy = policy
+}
+
+func getClientPolicy() writePolicy {
+	clientPolicyMu.RLock()
+	defer clientPolicyMu.RUnlock()
+	return clientPolicy
+}
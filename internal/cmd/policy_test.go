@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/failup-ventures/attio-cli/internal/config"
+	"github.com/failup-ventures/attio-cli/mockserver"
+)
+
+func TestEveryCommandIsClassified(t *testing.T) {
+	parser, _, err := newParser()
+	if err != nil {
+		t.Fatalf("new parser: %v", err)
+	}
+	commands := map[string]struct{}{}
+	collectCommandPaths(parser.Model.Node, nil, commands)
+
+	var unclassified []string
+	for path := range commands {
+		_, write := writeCommands[path]
+		if write == readCommands[path] {
+			unclassified = append(unclassified, path)
+		}
+	}
+	for path := range writeCommands {
+		if _, ok := commands[path]; !ok {
+			unclassified = append(unclassified, "stale write entry: "+path)
+		}
+	}
+	for path := range readCommands {
+		if _, ok := commands[path]; !ok && path != "__complete" {
+			unclassified = append(unclassified, "stale read entry: "+path)
+		}
+	}
+	sort.Strings(unclassified)
+	if len(unclassified) > 0 {
+		t.Fatalf("commands must be in exactly one of writeCommands or readCommands:\n%s", strings.Join(unclassified, "\n"))
+	}
+}
+
+func TestRequestTargetAndReadRequests(t *testing.T) {
+	t.Parallel()
+
+	targets := map[string]string{
+		"/v2/objects/companies/records":                "companies",
+		"/v2/objects/people/records/rec-1?x=1":         "people",
+		"/v2/lists/pipeline/entries/ent-1":             "pipeline",
+		"/v2/objects/people/attributes/name":           "attributes",
+		"/v2/lists/pipeline/attributes/stage/statuses": "attributes",
+		"/v2/tasks/task-1":                             "tasks",
+		"/v2/objects":                                  "objects",
+	}
+	for path, want := range targets {
+		if got, _ := requestTarget(path); got != want {
+			t.Errorf("requestTarget(%q) = %q, want %q", path, got, want)
+		}
+	}
+	if !isReadRequest("POST", "/v2/objects/people/records/query") || !isReadRequest("POST", "/v2/objects/records/search") ||
+		isReadRequest("POST", "/v2/objects/people/records") || isReadRequest("DELETE", "/v2/tasks/t1") {
+		t.Fatal("unexpected read/write classification")
+	}
+}
+
+func TestReadOnlyAndObjectPolicy(t *testing.T) {
+	setupCLIEnv(t)
+	handler, err := mockserver.New(nil)
+	if err != nil {
+		t.Fatalf("new mock server: %v", err)
+	}
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+	t.Setenv("ATTIO_API_KEY", "mock")
+	t.Setenv("ATTIO_BASE_URL", srv.URL)
+
+	createPerson := []string{"records", "create", "people", "--data", `{"values":{"name":"Ada Lovelace"}}`}
+	blocked := func(args ...string) {
+		t.Helper()
+		_, stderr, err := captureExecute(t, append([]string{"--json"}, args...))
+		if ExitCode(err) != ExitCodeUsage || !strings.Contains(stderr, `"kind": "policy"`) {
+			t.Fatalf("%s: expected a policy error, got %v\n%s", strings.Join(args, " "), err, stderr)
+		}
+	}
+	allowed := func(args ...string) {
+		t.Helper()
+		if _, stderr, err := captureExecute(t, args); err != nil {
+			t.Fatalf("%s: %v\n%s", strings.Join(args, " "), err, stderr)
+		}
+	}
+
+	blocked(append([]string{"--read-only"}, createPerson...)...)
+	allowed(append([]string{"--read-only", "--dry-run"}, createPerson...)...)
+	allowed("--read-only", "records", "query", "people")
+	// The raw api command is only stopped by the request guard.
+	blocked("--read-only", "api", "POST", "/v2/objects/people/records", "--raw-field", `data={"values":{}}`)
+	allowed("--read-only", "api", "POST", "/v2/objects/people/records/query")
+	blocked("--read-only", "config", "set", "read_only", "false")
+	// undo writes whatever the snapshot holds, so read-only mode blocks it
+	// before any restore is tried.
+	blocked("--read-only", "undo")
+
+	if err := config.SaveConfig(config.Config{Profiles: map[string]config.Profile{"default": {
+		ReadOnly:        true,
+		DisableCommands: []string{"webhooks"},
+		ObjectPolicy:    map[string]string{"tasks": policyWrite},
+	}}}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	blocked(createPerson...)
+	blocked("webhooks", "list")
+	allowed("tasks", "create", "--content", "Follow up", "--deadline", "2030-01-01T00:00:00Z")
+	// object_policy only lifts the profile's read_only, never the flag or
+	// environment variable.
+	blocked("--read-only", "tasks", "create", "--content", "Follow up", "--deadline", "2030-01-01T00:00:00Z")
+	t.Setenv("ATTIO_READ_ONLY", "true")
+	blocked("tasks", "create", "--content", "Follow up", "--deadline", "2030-01-01T00:00:00Z")
+	t.Setenv("ATTIO_READ_ONLY", "false")
+
+	stdout, _, err := captureExecute(t, []string{"--json", "auth", "status"})
+	if err != nil {
+		t.Fatalf("auth status: %v", err)
+	}
+	var status config.Status
+	_ = json.Unmarshal([]byte(stdout), &status)
+	found := map[string]string{}
+	for _, s := range status.Settings {
+		found[s.Setting] = s.Value
+	}
+	if found["read_only"] != "true" || found["disable_commands"] != "webhooks" || found["object_policy"] != "tasks=write" {
+		t.Fatalf("expected policy settings in auth status, got %+v", status.Settings)
+	}
+
+	// Without read-only mode, object_policy can still protect one object.
+	if err := config.SaveConfig(config.Config{Profiles: map[string]config.Profile{"default": {
+		ObjectPolicy: map[string]string{"companies": policyRead},
+	}}}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	allowed(createPerson...)
+	blocked("records", "create", "companies", "--data", `{"values":{"name":"Acme"}}`)
+	// UUIDs name the same object as its slug, in commands and in raw paths.
+	var object struct {
+		Data struct {
+			ID struct {
+				ObjectID string `json:"object_id"`
+			} `json:"id"`
+		} `json:"data"`
+	}
+	stdout, _, err = captureExecute(t, []string{"--json", "objects", "get", "companies"})
+	if err != nil || json.Unmarshal([]byte(stdout), &object) != nil || object.Data.ID.ObjectID == "" {
+		t.Fatalf("objects get companies: %v\n%s", err, stdout)
+	}
+	companies := object.Data.ID.ObjectID
+	blocked("records", "create", companies, "--data", `{"values":{"name":"Acme"}}`)
+	blocked("api", "POST", "/v2/objects/"+companies+"/records", "--raw-field", `data={"values":{"name":"Acme"}}`)
+	if err := config.SaveConfig(config.Config{Profiles: map[string]config.Profile{"default": {
+		ObjectPolicy: map[string]string{companies: policyRead},
+	}}}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	blocked("records", "create", "companies", "--data", `{"values":{"name":"Acme"}}`)
+	allowed(createPerson...)
+	blocked("--disable-commands", "records create", "records", "create", "people", "--data", `{"values":{}}`)
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestExecuteQueryWhereCompilesFilter(t *testing.T) {
+	setupCLIEnv(t)
+
+	filters := map[string]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/lists/deals/entries/query" && r.URL.Path != "/v2/objects/people/records/query" {
+			http.NotFound(w, r)
+			return
+		}
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		b, _ := json.Marshal(body["filter"])
+		filters[r.URL.Path] = string(b)
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": []map[string]any{}})
+	}))
+	defer srv.Close()
+	t.Setenv("ATTIO_API_KEY", "env-key")
+	t.Setenv("ATTIO_BASE_URL", srv.URL)
+
+	_, stderr, err := captureExecute(t, []string{"--json", "entries", "query", "deals", "--where", `stage in ("Won","Lost") and value >= 1000`})
+	if err != nil {
+		t.Fatalf("unexpected error: %v\nstderr=%s", err, stderr)
+	}
+	want := `{"$and":[{"stage":{"$in":["Won","Lost"]}},{"value":{"$gte":1000}}]}`
+	if got := filters["/v2/lists/deals/entries/query"]; got != want {
+		t.Fatalf("unexpected entries filter: %s", got)
+	}
+
+	_, stderr, err = captureExecute(t, []string{"--json", "records", "query", "people", "--where", `name ~ "Ada" or job_title ^= "CTO"`})
+	if err != nil {
+		t.Fatalf("unexpected error: %v\nstderr=%s", err, stderr)
+	}
+	want = `{"$or":[{"name":{"$contains":"Ada"}},{"job_title":{"$starts_with":"CTO"}}]}`
+	if got := filters["/v2/objects/people/records/query"]; got != want {
+		t.Fatalf("unexpected records filter: %s", got)
+	}
+}
+
+func TestExecuteRecordsQueryWhereDryRunAndErrors(t *testing.T) {
+	setupCLIEnv(t)
+
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+	t.Setenv("ATTIO_API_KEY", "env-key")
+	t.Setenv("ATTIO_BASE_URL", srv.URL)
+
+	stdout, stderr, err := captureExecute(t, []string{"--json", "--dry-run", "records", "query", "people", "--where", `company -> companies.name ~ "Acme"`})
+	if err != nil {
+		t.Fatalf("unexpected error: %v\nstderr=%s", err, stderr)
+	}
+	if hits != 0 {
+		t.Fatalf("expected no network calls in dry-run, got %d", hits)
+	}
+	if !strings.Contains(stdout, `"action": "records query"`) || !strings.Contains(stdout, `"companies",`) || !strings.Contains(stdout, `"$contains": "Acme"`) {
+		t.Fatalf("unexpected dry-run output: %s", stdout)
+	}
+
+	// Without --where there is nothing to preview and the query runs.
+	_, _, _ = captureExecute(t, []string{"--json", "--dry-run", "records", "query", "people", "--filter", `{}`})
+	if hits != 1 {
+		t.Fatalf("expected --dry-run without --where to run the query, got %d requests", hits)
+	}
+
+	_, stderr, err = captureExecute(t, []string{"records", "query", "people", "--where", `name ~`})
+	if ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected usage error, got %v", err)
+	}
+	if !strings.Contains(stderr, "invalid --where expression at column 7") {
+		t.Fatalf("expected column in error, got %q", stderr)
+	}
+
+	_, _, err = captureExecute(t, []string{"records", "query", "people", "--where", `a = 1`, "--filter", `{}`})
+	if ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected usage error for --filter with --where, got %v", err)
+	}
+}
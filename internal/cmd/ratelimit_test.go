@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/failup-ventures/attio-cli/internal/api"
+)
+
+// restoreRateLimits puts the process-wide --rate-limit and shared limiters
+// back after the test.
+func restoreRateLimits(t *testing.T) {
+	t.Helper()
+	clientRuntimeMu.Lock()
+	spec, limiters := clientRateLimit, rateLimiters
+	rateLimiters = map[string]*api.RateLimiter{}
+	clientRuntimeMu.Unlock()
+	t.Cleanup(func() {
+		clientRuntimeMu.Lock()
+		defer clientRuntimeMu.Unlock()
+		clientRateLimit, rateLimiters = spec, limiters
+	})
+}
+
+func TestRateLimitFlagAndProfileConfig(t *testing.T) {
+	setupCLIEnv(t)
+	restoreRateLimits(t)
+	t.Setenv("ATTIO_API_KEY", "env-key")
+
+	_, stderr, err := captureExecute(t, []string{"--rate-limit", "fast", "self"})
+	if ExitCode(err) != ExitCodeUsage || !strings.Contains(stderr, "--rate-limit") {
+		t.Fatalf("expected usage error for invalid --rate-limit, got %v / %q", err, stderr)
+	}
+
+	if err := os.WriteFile(os.Getenv("ATTIO_CONFIG_PATH"), []byte(`{"profiles":{"bulk":{"rate_limit":"read=5:10,write=2"}}}`), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	setClientRateLimit("")
+	limiter, err := sharedRateLimiter("bulk")
+	if err != nil || limiter.Limit().String() != "read=5:10,write=2:2" {
+		t.Fatalf("expected profile rate_limit, got %v (%v)", limiter, err)
+	}
+	again, _ := sharedRateLimiter("bulk")
+	if again != limiter {
+		t.Fatalf("expected clients of one profile to share a limiter")
+	}
+
+	// A broken config file is reported, not read as "no limit".
+	if err := os.WriteFile(os.Getenv("ATTIO_CONFIG_PATH"), []byte(`{"profiles":`), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if _, err := sharedRateLimiter("other"); err == nil || !strings.Contains(err.Error(), "parse config") {
+		t.Fatalf("expected the config parse error, got %v", err)
+	}
+
+	setClientRateLimit("off")
+	limiter, err = sharedRateLimiter("bulk")
+	if err != nil || limiter.Limit().String() != "read=off,write=off" {
+		t.Fatalf("expected --rate-limit to override the profile, got %v (%v)", limiter, err)
+	}
+}
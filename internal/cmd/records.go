@@ -4,27 +4,35 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 
-	"github.com/failup-ventures/attio-cli/internal/api"
 	"github.com/failup-ventures/attio-cli/internal/outfmt"
+	"github.com/failup-ventures/attio-cli/internal/undo"
 )
 
 type RecordsCmd struct {
-	Create  RecordsCreateCmd  `cmd:"" help:"Create a record"`
-	Assert  RecordsAssertCmd  `cmd:"" aliases:"upsert" help:"Assert (upsert) a record"`
-	Query   RecordsQueryCmd   `cmd:"" help:"Query records"`
-	Search  RecordsSearchCmd  `cmd:"" help:"Search records (Beta)"`
-	Get     RecordsGetCmd     `cmd:"" help:"Get record"`
-	Update  RecordsUpdateCmd  `cmd:"" help:"Update record (PATCH append multiselect)"`
-	Replace RecordsReplaceCmd `cmd:"" help:"Replace record (PUT overwrite multiselect)"`
-	Delete  RecordsDeleteCmd  `cmd:"" help:"Delete record"`
-	Values  RecordsValuesCmd  `cmd:"" help:"Record attribute values"`
-	Entries RecordsEntriesCmd `cmd:"" help:"List entries for a record"`
+	Create      RecordsCreateCmd      `cmd:"" help:"Create a record"`
+	Assert      RecordsAssertCmd      `cmd:"" aliases:"upsert" help:"Assert (upsert) a record"`
+	Query       RecordsQueryCmd       `cmd:"" help:"Query records"`
+	Search      RecordsSearchCmd      `cmd:"" help:"Search records (Beta)"`
+	Get         RecordsGetCmd         `cmd:"" help:"Get record"`
+	Update      RecordsUpdateCmd      `cmd:"" help:"Update record (PATCH append multiselect)"`
+	Replace     RecordsReplaceCmd     `cmd:"" help:"Replace record (PUT overwrite multiselect)"`
+	Delete      RecordsDeleteCmd      `cmd:"" help:"Delete record"`
+	UpdateWhere RecordsUpdateWhereCmd `cmd:"" name:"update-where" help:"Update every record matching a filter"`
+	DeleteWhere RecordsDeleteWhereCmd `cmd:"" name:"delete-where" help:"Delete every record matching a filter"`
+	Values      RecordsValuesCmd      `cmd:"" help:"Record attribute values"`
+	History     RecordsHistoryCmd     `cmd:"" help:"Show a record's changes from its historic attribute values"`
+	Entries     RecordsEntriesCmd     `cmd:"" help:"List entries for a record"`
+	Import      RecordsImportCmd      `cmd:"" help:"Import records from a CSV file"`
+	Export      RecordsExportCmd      `cmd:"" help:"Export records as CSV, TSV or NDJSON"`
+	Validate    RecordsValidateCmd    `cmd:"" help:"Check record data against the object's attributes without writing"`
 }
 
 type RecordsCreateCmd struct {
-	Object string `arg:"" name:"object" help:"Object slug or UUID" required:""`
-	Data   string `name:"data" help:"Record data JSON; supports '-' or @file.json" required:""`
+	Object     string `arg:"" name:"object" help:"Object slug or UUID" required:""`
+	Data       string `name:"data" help:"Record data JSON; supports '-' or @file.json"`
+	ValueFlags `embed:""`
 }
 
 func (c *RecordsCreateCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -32,10 +40,13 @@ func (c *RecordsCreateCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if err != nil {
 		return err
 	}
-	data, err := readJSONObjectInput(c.Data)
+	data, _, err := c.payload(ctx, recordValueTarget(client, c.Object, ""), c.Data, valueWriteCreate)
 	if err != nil {
 		return err
 	}
+	if err := validateWriteData(ctx, flags, newRecordValidator(client, c.Object), data, validateCreate, ""); err != nil {
+		return err
+	}
 	if ok, err := maybeDryRun(ctx, "records create", map[string]any{"object": c.Object, "data": data}); ok || err != nil {
 		return err
 	}
@@ -43,13 +54,14 @@ func (c *RecordsCreateCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if err != nil {
 		return err
 	}
-	return writeSingleRecord(ctx, record)
+	return writeSingleRecord(ctx, c.Object, record)
 }
 
 type RecordsAssertCmd struct {
 	Object            string `arg:"" name:"object" help:"Object slug or UUID" required:""`
 	MatchingAttribute string `name:"matching-attribute" help:"Matching attribute slug or UUID" required:""`
-	Data              string `name:"data" help:"Record data JSON; supports '-' or @file.json" required:""`
+	Data              string `name:"data" help:"Record data JSON; supports '-' or @file.json"`
+	ValueFlags        `embed:""`
 }
 
 func (c *RecordsAssertCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -57,10 +69,13 @@ func (c *RecordsAssertCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if err != nil {
 		return err
 	}
-	data, err := readJSONObjectInput(c.Data)
+	data, _, err := c.payload(ctx, recordValueTarget(client, c.Object, ""), c.Data, valueWriteCreate)
 	if err != nil {
 		return err
 	}
+	if err := validateWriteData(ctx, flags, newRecordValidator(client, c.Object), data, validateAssert, ""); err != nil {
+		return err
+	}
 	if ok, err := maybeDryRun(ctx, "records assert", map[string]any{"object": c.Object, "matching_attribute": c.MatchingAttribute, "data": data}); ok || err != nil {
 		return err
 	}
@@ -68,17 +83,21 @@ func (c *RecordsAssertCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if err != nil {
 		return err
 	}
-	return writeSingleRecord(ctx, record)
+	return writeSingleRecord(ctx, c.Object, record)
 }
 
 type RecordsQueryCmd struct {
-	Object   string `arg:"" name:"object" help:"Object slug or UUID" required:""`
-	Filter   string `name:"filter" help:"Filter JSON object"`
-	Sorts    string `name:"sorts" help:"Sorts JSON array"`
-	Limit    int    `name:"limit" help:"Page size" default:"500"`
-	Offset   int    `name:"offset" help:"Offset for first page" default:"0"`
-	All      bool   `name:"all" help:"Fetch all pages"`
-	MaxPages int    `name:"max-pages" help:"Maximum pages to fetch when --all is set" default:"100"`
+	Object      string `arg:"" name:"object" help:"Object slug or UUID" required:""`
+	Filter      string `name:"filter" help:"Filter JSON object"`
+	Where       string `name:"where" help:"Filter expression, e.g. 'name ~ \"Ada\" and stage in (\"Won\",\"Lost\")'"`
+	Sorts       string `name:"sorts" help:"Sorts JSON array"`
+	Limit       int    `name:"limit" help:"Page size" default:"500"`
+	Offset      int    `name:"offset" help:"Offset for first page" default:"0"`
+	All         bool   `name:"all" help:"Fetch all pages"`
+	MaxPages    int    `name:"max-pages" help:"Maximum pages to fetch when --all or --stream is set" default:"100"`
+	Stream      bool   `name:"stream" aliases:"ndjson" help:"Stream all pages as NDJSON, one item per line, as each page arrives"`
+	Checkpoint  string `name:"checkpoint" help:"Save progress to this file after each page and resume from it on rerun (with --all or --stream)"`
+	ExpandFlags `embed:""`
 }
 
 func (c *RecordsQueryCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -87,12 +106,9 @@ func (c *RecordsQueryCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 
-	var filter any
-	if c.Filter != "" {
-		filter, err = readJSONValueInput(c.Filter)
-		if err != nil {
-			return err
-		}
+	filter, err := resolveQueryFilter(c.Filter, c.Where, c.Object)
+	if err != nil {
+		return err
 	}
 
 	var sorts any
@@ -103,25 +119,49 @@ func (c *RecordsQueryCmd) Run(ctx context.Context, flags *RootFlags) error {
 		}
 	}
 
+	if err := requireCheckpointPaging(c.Checkpoint, c.All, c.Stream); err != nil {
+		return err
+	}
+
+	exp, err := c.expander(ctx, client, "objects", c.Object)
+	if err != nil {
+		return err
+	}
+
 	limit := c.Limit
 	if limit <= 0 {
 		limit = 500
 	}
+	// A query changes nothing, so --dry-run only previews the filter a
+	// --where expression compiles to.
+	if c.Where != "" {
+		if ok, err := maybeDryRun(ctx, "records query", map[string]any{"object": c.Object, "filter": filter, "sorts": sorts, "limit": limit, "offset": c.Offset}); ok || err != nil {
+			return err
+		}
+	}
+
+	cp, err := openPageCheckpoint(c.Checkpoint, "records query", map[string]any{"object": c.Object, "filter": filter, "sorts": sorts, "limit": limit, "offset": c.Offset}, !c.Stream)
+	if err != nil {
+		return err
+	}
+	ctx, stop := cp.notifyContext(ctx)
+	defer stop()
+
+	fetch := exp.wrap(ctx, "values", func(offset int) ([]map[string]any, error) {
+		return client.QueryRecords(ctx, c.Object, filter, sorts, limit, offset)
+	})
+	if c.Stream {
+		return streamOffsetPages(ctx, cp, limit, c.MaxPages, c.Offset, fetch)
+	}
 
 	var records []map[string]any
 	if c.All {
-		start := c.Offset
-		records, err = api.FetchAllOffset(ctx, limit, c.MaxPages, func(offset int) ([]map[string]any, error) {
-			return client.QueryRecords(ctx, c.Object, filter, sorts, limit, start+offset)
-		})
-		if err != nil {
-			return err
-		}
+		records, err = collectOffsetPages(ctx, cp, limit, c.MaxPages, c.Offset, fetch)
 	} else {
-		records, err = client.QueryRecords(ctx, c.Object, filter, sorts, limit, c.Offset)
-		if err != nil {
-			return err
-		}
+		records, err = fetch(c.Offset)
+	}
+	if err != nil {
+		return err
 	}
 
 	if outfmt.IsJSON(ctx) {
@@ -142,7 +182,7 @@ func (c *RecordsQueryCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return writeOffsetPaginatedJSON(ctx, records, limit, offset)
 	}
 
-	return writeRecordRows(ctx, records)
+	return writeRecordRows(ctx, c.Object, records, exp.columns()...)
 }
 
 type RecordsSearchCmd struct {
@@ -175,12 +215,13 @@ func (c *RecordsSearchCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if err != nil {
 		return err
 	}
-	return writeRecordRows(ctx, records)
+	return writeRecordRows(ctx, "", records)
 }
 
 type RecordsGetCmd struct {
-	Object   string `arg:"" name:"object" help:"Object slug or UUID" required:""`
-	RecordID string `arg:"" name:"record-id" help:"Record UUID" required:""`
+	Object      string `arg:"" name:"object" help:"Object slug or UUID" required:""`
+	RecordID    string `arg:"" name:"record-id" help:"Record UUID" required:""`
+	ExpandFlags `embed:""`
 }
 
 func (c *RecordsGetCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -188,17 +229,25 @@ func (c *RecordsGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if err != nil {
 		return err
 	}
+	exp, err := c.expander(ctx, client, "objects", c.Object)
+	if err != nil {
+		return err
+	}
 	record, err := client.GetRecord(ctx, c.Object, c.RecordID)
 	if err != nil {
 		return err
 	}
-	return writeSingleRecord(ctx, record)
+	if err := exp.expand(ctx, []map[string]any{record}, "values"); err != nil {
+		return err
+	}
+	return writeSingleRecord(ctx, c.Object, record, exp.columns()...)
 }
 
 type RecordsUpdateCmd struct {
-	Object   string `arg:"" name:"object" help:"Object slug or UUID" required:""`
-	RecordID string `arg:"" name:"record-id" help:"Record UUID" required:""`
-	Data     string `name:"data" help:"Record data JSON; supports '-' or @file.json" required:""`
+	Object     string `arg:"" name:"object" help:"Object slug or UUID" required:""`
+	RecordID   string `arg:"" name:"record-id" help:"Record UUID" required:""`
+	Data       string `name:"data" help:"Record data JSON; supports '-' or @file.json"`
+	ValueFlags `embed:""`
 }
 
 func (c *RecordsUpdateCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -206,24 +255,37 @@ func (c *RecordsUpdateCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if err != nil {
 		return err
 	}
-	data, err := readJSONObjectInput(c.Data)
+	data, replace, err := c.payload(ctx, recordValueTarget(client, c.Object, c.RecordID), c.Data, valueWriteUpdate)
 	if err != nil {
 		return err
 	}
-	if ok, err := maybeDryRun(ctx, "records update", map[string]any{"object": c.Object, "record_id": c.RecordID, "data": data}); ok || err != nil {
+	if err := validateWriteData(ctx, flags, newRecordValidator(client, c.Object), data, validateUpdate, c.RecordID); err != nil {
+		return err
+	}
+	if ok, err := maybeDryRun(ctx, "records update", updatePreview(map[string]any{"object": c.Object, "record_id": c.RecordID, "data": data}, replace)); ok || err != nil {
+		return err
+	}
+	snapshot, err := snapshotBefore(ctx, client, flags.Profile, undo.KindRecord, undo.ActionUpdate, c.Object, []string{c.RecordID}, writtenKeys(data, "values"))
+	if err != nil {
 		return err
 	}
-	record, err := client.UpdateRecord(ctx, c.Object, c.RecordID, data)
+	update := client.UpdateRecord
+	if replace {
+		update = client.ReplaceRecord
+	}
+	record, err := update(ctx, c.Object, c.RecordID, data)
 	if err != nil {
 		return err
 	}
-	return writeSingleRecord(ctx, record)
+	saveSnapshot(ctx, snapshot, 1)
+	return writeSingleRecord(ctx, c.Object, record)
 }
 
 type RecordsReplaceCmd struct {
-	Object   string `arg:"" name:"object" help:"Object slug or UUID" required:""`
-	RecordID string `arg:"" name:"record-id" help:"Record UUID" required:""`
-	Data     string `name:"data" help:"Record data JSON; supports '-' or @file.json" required:""`
+	Object     string `arg:"" name:"object" help:"Object slug or UUID" required:""`
+	RecordID   string `arg:"" name:"record-id" help:"Record UUID" required:""`
+	Data       string `name:"data" help:"Record data JSON; supports '-' or @file.json"`
+	ValueFlags `embed:""`
 }
 
 func (c *RecordsReplaceCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -231,18 +293,26 @@ func (c *RecordsReplaceCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if err != nil {
 		return err
 	}
-	data, err := readJSONObjectInput(c.Data)
+	data, _, err := c.payload(ctx, recordValueTarget(client, c.Object, c.RecordID), c.Data, valueWriteReplace)
 	if err != nil {
 		return err
 	}
+	if err := validateWriteData(ctx, flags, newRecordValidator(client, c.Object), data, validateUpdate, c.RecordID); err != nil {
+		return err
+	}
 	if ok, err := maybeDryRun(ctx, "records replace", map[string]any{"object": c.Object, "record_id": c.RecordID, "data": data}); ok || err != nil {
 		return err
 	}
+	snapshot, err := snapshotBefore(ctx, client, flags.Profile, undo.KindRecord, undo.ActionReplace, c.Object, []string{c.RecordID}, writtenKeys(data, "values"))
+	if err != nil {
+		return err
+	}
 	record, err := client.ReplaceRecord(ctx, c.Object, c.RecordID, data)
 	if err != nil {
 		return err
 	}
-	return writeSingleRecord(ctx, record)
+	saveSnapshot(ctx, snapshot, 1)
+	return writeSingleRecord(ctx, c.Object, record)
 }
 
 type RecordsDeleteCmd struct {
@@ -258,9 +328,27 @@ func (c *RecordsDeleteCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if ok, err := maybeDryRun(ctx, "records delete", map[string]any{"object": c.Object, "record_id": c.RecordID}); ok || err != nil {
 		return err
 	}
+	if err := confirmDestructive(ctx, flags, "delete a record from "+c.Object, func() (string, error) {
+		record, err := client.GetRecord(ctx, c.Object, c.RecordID)
+		if err != nil {
+			return "", err
+		}
+		return describeResource("record", c.RecordID,
+			recordValueSummary(record, "name", "full_name", "company_name"),
+			recordValueSummary(record, "email_addresses", "email_address"),
+			mapString(record, "web_url"),
+		), nil
+	}); err != nil {
+		return err
+	}
+	snapshot, err := snapshotBefore(ctx, client, flags.Profile, undo.KindRecord, undo.ActionDelete, c.Object, []string{c.RecordID}, nil)
+	if err != nil {
+		return err
+	}
 	if err := client.DeleteRecord(ctx, c.Object, c.RecordID); err != nil {
 		return err
 	}
+	saveSnapshot(ctx, snapshot, 1)
 
 	if outfmt.IsJSON(ctx) {
 		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{
@@ -273,17 +361,20 @@ func (c *RecordsDeleteCmd) Run(ctx context.Context, flags *RootFlags) error {
 	return nil
 }
 
-func writeSingleRecord(ctx context.Context, record map[string]any) error {
+func writeSingleRecord(ctx context.Context, object string, record map[string]any, expanded ...string) error {
 	if ok, err := maybeWriteIDOnly(ctx, record); ok || err != nil {
 		return err
 	}
 	if outfmt.IsJSON(ctx) {
 		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{"data": record})
 	}
-	return writeRecordRows(ctx, []map[string]any{record})
+	return writeRecordRows(ctx, object, []map[string]any{record}, expanded...)
 }
 
-func writeRecordRows(ctx context.Context, records []map[string]any) error {
+// writeRecordRows prints records, using the project's default columns for
+// object in table and plain output when it has any. The built-in columns are
+// followed by one column of referenced record names per expanded attribute.
+func writeRecordRows(ctx context.Context, object string, records []map[string]any, expanded ...string) error {
 	if err := maybePreviewResults(ctx, len(records)); err != nil {
 		return err
 	}
@@ -293,16 +384,33 @@ func writeRecordRows(ctx context.Context, records []map[string]any) error {
 
 	w, done := tableWriter(ctx)
 	defer done()
-	_, _ = fmt.Fprintln(w, "ID\tNAME\tEMAIL\tCREATED_AT\tWEB_URL")
+	if names := defaultColumns(ctx, object); len(names) > 0 {
+		columns := tableColumns(names)
+		headers := make([]string, 0, len(columns))
+		for _, col := range columns {
+			headers = append(headers, strings.ToUpper(col.Header))
+		}
+		_, _ = fmt.Fprintln(w, strings.Join(headers, "\t")+expandedHeaders(expanded))
+		for _, record := range records {
+			row := make([]string, 0, len(columns))
+			for _, col := range columns {
+				row = append(row, col.value(record))
+			}
+			_, _ = fmt.Fprintln(w, strings.Join(row, "\t")+expandedCells(record, "values", expanded))
+		}
+		return nil
+	}
+	_, _ = fmt.Fprintln(w, "ID\tNAME\tEMAIL\tCREATED_AT\tWEB_URL"+expandedHeaders(expanded))
 	for _, record := range records {
 		name := recordValueSummary(record, "name", "full_name", "company_name")
 		email := recordValueSummary(record, "email_addresses", "email_address")
-		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s%s\n",
 			idString(record["id"]),
 			name,
 			email,
 			mapString(record, "created_at"),
 			mapString(record, "web_url"),
+			expandedCells(record, "values", expanded),
 		)
 	}
 	return nil
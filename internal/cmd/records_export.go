@@ -0,0 +1,302 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+type RecordsExportCmd struct {
+	Object   string `arg:"" name:"object" help:"Object slug or UUID" required:""`
+	Format   string `name:"format" help:"Output format: csv|tsv|ndjson" default:"csv" enum:"csv,tsv,ndjson"`
+	Columns  string `name:"columns" help:"Comma-separated columns in output order (attribute slugs, slug.field, record_id, created_at, web_url)"`
+	Filter   string `name:"filter" help:"Filter JSON object"`
+	Sorts    string `name:"sorts" help:"Sorts JSON array"`
+	Output   string `name:"output" short:"o" help:"Write to file instead of stdout"`
+	Limit    int    `name:"limit" help:"Page size" default:"500"`
+	MaxPages int    `name:"max-pages" help:"Maximum pages to fetch (0 = no limit)" default:"0"`
+}
+
+// exportColumn is one output column: a record field or an attribute value,
+// optionally narrowed to a single field of the value ("name.first_name").
+type exportColumn struct {
+	Header    string
+	Attribute string
+	Field     string
+	Type      string
+}
+
+func (c *RecordsExportCmd) Run(ctx context.Context, flags *RootFlags) error {
+	client, err := requireClient(flags.Profile)
+	if err != nil {
+		return err
+	}
+
+	var filter any
+	if c.Filter != "" {
+		filter, err = readJSONValueInput(c.Filter)
+		if err != nil {
+			return err
+		}
+	}
+	var sorts any
+	if c.Sorts != "" {
+		sorts, err = readJSONValueInput(c.Sorts)
+		if err != nil {
+			return err
+		}
+	}
+
+	defs, err := listAttributeDefs(ctx, client, "objects", c.Object)
+	if err != nil {
+		return err
+	}
+	requested := splitCommaList(c.Columns)
+	if len(requested) == 0 {
+		requested = defaultColumns(ctx, c.Object)
+	}
+	columns, err := buildExportColumns(requested, defs)
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if strings.TrimSpace(c.Output) != "" {
+		path, err := expandPath(c.Output)
+		if err != nil {
+			return err
+		}
+		f, err := os.Create(path) //nolint:gosec // user-specified local file path
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		out = f
+	}
+
+	w := newRecordExportWriter(out, c.Format, columns)
+	if err := w.WriteHeader(); err != nil {
+		return err
+	}
+
+	limit := c.Limit
+	if limit <= 0 {
+		limit = 500
+	}
+	total := 0
+	offset := 0
+	for page := 0; c.MaxPages <= 0 || page < c.MaxPages; page++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		records, err := client.QueryRecords(ctx, c.Object, filter, sorts, limit, offset)
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			if err := w.WriteRecord(record); err != nil {
+				return err
+			}
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		total += len(records)
+		if len(records) < limit {
+			break
+		}
+		offset += len(records)
+	}
+
+	return maybeFailEmpty(ctx, total)
+}
+
+func buildExportColumns(requested []string, defs []attributeDef) ([]exportColumn, error) {
+	bySlug := make(map[string]attributeDef, len(defs))
+	for _, def := range defs {
+		bySlug[def.Slug] = def
+	}
+
+	if len(requested) == 0 {
+		requested = []string{"record_id"}
+		for _, def := range defs {
+			requested = append(requested, def.Slug)
+		}
+	}
+
+	columns := make([]exportColumn, 0, len(requested))
+	for _, name := range requested {
+		switch name {
+		case "record_id", "created_at", "web_url":
+			columns = append(columns, exportColumn{Header: name, Field: name})
+			continue
+		}
+		slug, field, _ := strings.Cut(name, ".")
+		def, ok := bySlug[slug]
+		if !ok {
+			return nil, newUsageError(fmt.Errorf("unknown column %q", name))
+		}
+		columns = append(columns, exportColumn{Header: name, Attribute: slug, Field: field, Type: def.Type})
+	}
+	return columns, nil
+}
+
+// tableColumns parses column names without the object's attribute
+// definitions; value types are then read from each value's attribute_type.
+func tableColumns(names []string) []exportColumn {
+	columns := make([]exportColumn, 0, len(names))
+	for _, name := range names {
+		switch name {
+		case "record_id", "created_at", "web_url":
+			columns = append(columns, exportColumn{Header: name, Field: name})
+			continue
+		}
+		slug, field, _ := strings.Cut(name, ".")
+		columns = append(columns, exportColumn{Header: name, Attribute: slug, Field: field})
+	}
+	return columns
+}
+
+func (col exportColumn) value(record map[string]any) string {
+	switch {
+	case col.Attribute == "" && col.Field == "record_id":
+		return idString(record["id"])
+	case col.Attribute == "":
+		return mapString(record, col.Field)
+	}
+
+	items, _ := mapMap(record, "values")[col.Attribute].([]any)
+	parts := make([]string, 0, len(items))
+	for _, item := range items {
+		m, _ := item.(map[string]any)
+		if m == nil {
+			continue
+		}
+		var s string
+		if col.Field != "" {
+			s = anyString(m[col.Field])
+		} else {
+			s = flattenAttributeValue(col.Type, m)
+		}
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, ";")
+}
+
+// flattenAttributeValue reduces one Attio output value to a scalar string.
+func flattenAttributeValue(attrType string, value map[string]any) string {
+	if attrType == "" {
+		attrType = mapString(value, "attribute_type")
+	}
+	switch attrType {
+	case "currency":
+		return mapString(value, "currency_value")
+	case "status":
+		return mapString(mapMap(value, "status"), "title")
+	case "select":
+		return mapString(mapMap(value, "option"), "title")
+	case "record-reference":
+		return referencedRecordName(value)
+	case "actor-reference":
+		return mapString(value, "referenced_actor_id")
+	case "domain":
+		return mapString(value, "domain")
+	case "email-address":
+		return mapString(value, "email_address")
+	case "phone-number":
+		if s := mapString(value, "phone_number"); s != "" {
+			return s
+		}
+		return mapString(value, "original_phone_number")
+	case "personal-name":
+		return mapString(value, "full_name")
+	case "interaction":
+		return mapString(value, "interacted_at")
+	case "location":
+		parts := make([]string, 0, 6)
+		for _, key := range []string{"line_1", "line_2", "locality", "region", "postcode", "country_code"} {
+			if s := mapString(value, key); s != "" {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, ", ")
+	default:
+		return mapString(value, "value")
+	}
+}
+
+type recordExportWriter struct {
+	columns []exportColumn
+	out     io.Writer
+	csv     *csv.Writer
+}
+
+func newRecordExportWriter(out io.Writer, format string, columns []exportColumn) *recordExportWriter {
+	w := &recordExportWriter{columns: columns, out: out}
+	if format != "ndjson" {
+		w.csv = csv.NewWriter(out)
+		if format == "tsv" {
+			w.csv.Comma = '\t'
+		}
+	}
+	return w
+}
+
+func (w *recordExportWriter) WriteHeader() error {
+	if w.csv == nil {
+		return nil
+	}
+	headers := make([]string, 0, len(w.columns))
+	for _, col := range w.columns {
+		headers = append(headers, col.Header)
+	}
+	return w.csv.Write(headers)
+}
+
+func (w *recordExportWriter) WriteRecord(record map[string]any) error {
+	if w.csv == nil {
+		return w.writeNDJSON(record)
+	}
+	row := make([]string, 0, len(w.columns))
+	for _, col := range w.columns {
+		row = append(row, col.value(record))
+	}
+	return w.csv.Write(row)
+}
+
+// writeNDJSON encodes one object per line, keeping keys in column order.
+func (w *recordExportWriter) writeNDJSON(record map[string]any) error {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, col := range w.columns {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(col.Header)
+		value, _ := json.Marshal(col.value(record))
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteString("}\n")
+	_, err := w.out.Write(buf.Bytes())
+	return err
+}
+
+func (w *recordExportWriter) Flush() error {
+	if w.csv == nil {
+		return nil
+	}
+	w.csv.Flush()
+	if err := w.csv.Error(); err != nil {
+		return fmt.Errorf("write export: %w", err)
+	}
+	return nil
+}
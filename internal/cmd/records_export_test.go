@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newRecordsExportServer(t *testing.T, offsets *[]int) *httptest.Server {
+	t.Helper()
+	pages := map[int][]map[string]any{
+		0: {
+			{
+				"id": map[string]any{"record_id": "r1"},
+				"values": map[string]any{
+					"name":            []any{map[string]any{"full_name": "Ada Lovelace", "first_name": "Ada", "attribute_type": "personal-name"}},
+					"email_addresses": []any{map[string]any{"email_address": "ada@example.com"}, map[string]any{"email_address": "ada@work.com"}},
+					"stage":           []any{map[string]any{"status": map[string]any{"title": "Won"}}},
+					"arr":             []any{map[string]any{"currency_value": 1200.5, "currency_code": "USD"}},
+				},
+			},
+			{
+				"id":     map[string]any{"record_id": "r2"},
+				"values": map[string]any{"name": []any{map[string]any{"full_name": "Grace, \"Amazing\" Hopper"}}},
+			},
+		},
+		2: {
+			{
+				"id":     map[string]any{"record_id": "r3"},
+				"values": map[string]any{"company": []any{map[string]any{"target_object": "companies", "target_record_id": "c1"}}},
+			},
+		},
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v2/objects/people/attributes":
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": []map[string]any{
+				{"api_slug": "name", "type": "personal-name"},
+				{"api_slug": "email_addresses", "type": "email-address", "is_multiselect": true},
+				{"api_slug": "stage", "type": "status"},
+				{"api_slug": "arr", "type": "currency"},
+				{"api_slug": "company", "type": "record-reference"},
+			}})
+		case r.Method == http.MethodPost && r.URL.Path == "/v2/objects/people/records/query":
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			offset, _ := intFromAnyValue(body["offset"])
+			*offsets = append(*offsets, offset)
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": pages[offset]})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestExecuteRecordsExportCSVStreamsPages(t *testing.T) {
+	setupCLIEnv(t)
+
+	var offsets []int
+	srv := newRecordsExportServer(t, &offsets)
+	defer srv.Close()
+	t.Setenv("ATTIO_API_KEY", "env-key")
+	t.Setenv("ATTIO_BASE_URL", srv.URL)
+
+	stdout, stderr, err := captureExecute(t, []string{"records", "export", "people", "--limit", "2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v\nstderr=%s", err, stderr)
+	}
+	if len(offsets) != 2 || offsets[1] != 2 {
+		t.Fatalf("expected two pages, got offsets %v", offsets)
+	}
+	want := strings.Join([]string{
+		"record_id,name,email_addresses,stage,arr,company",
+		"r1,Ada Lovelace,ada@example.com;ada@work.com,Won,1200.5,",
+		`r2,"Grace, ""Amazing"" Hopper",,,,`,
+		"r3,,,,,c1",
+		"",
+	}, "\n")
+	if stdout != want {
+		t.Fatalf("unexpected csv:\n%s\nwant:\n%s", stdout, want)
+	}
+}
+
+func TestExecuteRecordsExportNDJSONColumnOrder(t *testing.T) {
+	setupCLIEnv(t)
+
+	var offsets []int
+	srv := newRecordsExportServer(t, &offsets)
+	defer srv.Close()
+	t.Setenv("ATTIO_API_KEY", "env-key")
+	t.Setenv("ATTIO_BASE_URL", srv.URL)
+
+	stdout, stderr, err := captureExecute(t, []string{"records", "export", "people", "--format", "ndjson", "--columns", "stage,name.first_name,record_id", "--limit", "2", "--max-pages", "1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v\nstderr=%s", err, stderr)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines with --max-pages 1, got %d: %s", len(lines), stdout)
+	}
+	if lines[0] != `{"stage":"Won","name.first_name":"Ada","record_id":"r1"}` {
+		t.Fatalf("unexpected first line: %s", lines[0])
+	}
+
+	_, _, err = captureExecute(t, []string{"records", "export", "people", "--columns", "nope"})
+	if ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected usage error for unknown column, got %v", err)
+	}
+}
@@ -0,0 +1,353 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/failup-ventures/attio-cli/internal/outfmt"
+	"github.com/failup-ventures/attio-cli/internal/ui"
+)
+
+type RecordsImportCmd struct {
+	Object            string `arg:"" name:"object" help:"Object slug or UUID" required:""`
+	File              string `name:"file" help:"CSV file to import ('-' for stdin)" required:""`
+	Mapping           string `name:"mapping" help:"JSON file mapping CSV columns to attribute slugs (empty slug skips a column)"`
+	MatchingAttribute string `name:"matching-attribute" help:"Assert (upsert) rows on this attribute instead of creating"`
+	Rejects           string `name:"rejects" help:"Path for rejected rows CSV (default: <file>.rejected.csv)"`
+	Delimiter         string `name:"delimiter" help:"Field delimiter" default:","`
+}
+
+type importRowResult struct {
+	Row      int    `json:"row"`
+	Status   string `json:"status"`
+	RecordID string `json:"record_id,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+func (c *RecordsImportCmd) Run(ctx context.Context, flags *RootFlags) error {
+	client, err := requireClient(flags.Profile)
+	if err != nil {
+		return err
+	}
+
+	header, rows, err := readImportCSV(c.File, c.Delimiter)
+	if err != nil {
+		return err
+	}
+
+	overrides, err := readImportMapping(c.Mapping)
+	if err != nil {
+		return err
+	}
+
+	defs, err := listAttributeDefs(ctx, client, "objects", c.Object)
+	if err != nil {
+		return err
+	}
+	columns, unmapped := mapImportColumns(header, defs, overrides)
+	if len(columns) == 0 {
+		return newUsageError(errors.New("no CSV columns matched an attribute; pass --mapping"))
+	}
+	if len(unmapped) > 0 && !outfmt.IsJSON(ctx) {
+		if u := ui.FromContext(ctx); u != nil {
+			u.Err().Printf("Ignoring unmapped columns: %s", strings.Join(unmapped, ", "))
+		}
+	}
+
+	bySlug := make(map[string]attributeDef, len(defs))
+	for _, def := range defs {
+		bySlug[def.Slug] = def
+	}
+
+	type pendingRow struct {
+		row  int
+		data map[string]any
+	}
+	pending := make([]pendingRow, 0, len(rows))
+	results := make([]importRowResult, 0, len(rows))
+	rejected := make([][]string, 0)
+	for i, cells := range rows {
+		rowNum := i + 2
+		data, err := importRowPayload(cells, columns, bySlug)
+		if err != nil {
+			results = append(results, importRowResult{Row: rowNum, Status: "rejected", Error: err.Error()})
+			rejected = append(rejected, append(append([]string(nil), cells...), err.Error()))
+			continue
+		}
+		pending = append(pending, pendingRow{row: rowNum, data: data})
+	}
+
+	if isDryRun(ctx) {
+		preview := make([]map[string]any, 0, len(pending))
+		for _, p := range pending {
+			preview = append(preview, map[string]any{"row": p.row, "data": p.data})
+		}
+		_, err := maybeDryRun(ctx, "records import", map[string]any{
+			"object":             c.Object,
+			"matching_attribute": c.MatchingAttribute,
+			"rows":               preview,
+			"rejected":           results,
+			"unmapped_columns":   unmapped,
+		})
+		return err
+	}
+
+	for _, p := range pending {
+		var record map[string]any
+		if c.MatchingAttribute != "" {
+			record, err = client.AssertRecord(ctx, c.Object, c.MatchingAttribute, p.data)
+		} else {
+			record, err = client.CreateRecord(ctx, c.Object, p.data)
+		}
+		if err != nil {
+			results = append(results, importRowResult{Row: p.row, Status: "failed", Error: err.Error()})
+			rejected = append(rejected, append(append([]string(nil), rows[p.row-2]...), err.Error()))
+			continue
+		}
+		results = append(results, importRowResult{Row: p.row, Status: "ok", RecordID: idString(record["id"])})
+	}
+	sortImportResults(results)
+
+	rejectsPath := ""
+	if len(rejected) > 0 {
+		rejectsPath = c.rejectsPath()
+		if err := writeImportRejects(rejectsPath, header, rejected, c.Delimiter); err != nil {
+			return err
+		}
+	}
+
+	if err := writeImportResults(ctx, results, unmapped, rejectsPath); err != nil {
+		return err
+	}
+	if len(rejected) > 0 {
+		return &ExitError{Code: ExitCodeGeneric, Err: fmt.Errorf("%d of %d rows were not imported (see %s)", len(rejected), len(rows), rejectsPath)}
+	}
+	return nil
+}
+
+func (c *RecordsImportCmd) rejectsPath() string {
+	if strings.TrimSpace(c.Rejects) != "" {
+		if p, err := expandPath(c.Rejects); err == nil {
+			return p
+		}
+		return c.Rejects
+	}
+	if c.File == "-" {
+		return "import.rejected.csv"
+	}
+	return strings.TrimSuffix(c.File, filepath.Ext(c.File)) + ".rejected.csv"
+}
+
+func readImportCSV(path string, delimiter string) ([]string, [][]string, error) {
+	var r io.Reader
+	if strings.TrimSpace(path) == "-" {
+		r = os.Stdin
+	} else {
+		expanded, err := expandPath(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		f, err := os.Open(expanded) //nolint:gosec // user-specified local file path
+		if err != nil {
+			return nil, nil, err
+		}
+		defer func() { _ = f.Close() }()
+		r = f
+	}
+
+	reader := csv.NewReader(r)
+	sep, err := csvDelimiter(delimiter)
+	if err != nil {
+		return nil, nil, err
+	}
+	reader.Comma = sep
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, nil, newUsageError(fmt.Errorf("invalid CSV: %w", err))
+	}
+	if len(records) == 0 {
+		return nil, nil, newUsageError(errors.New("CSV file is empty"))
+	}
+	header := records[0]
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+	return header, records[1:], nil
+}
+
+func csvDelimiter(value string) (rune, error) {
+	switch value {
+	case "", ",":
+		return ',', nil
+	case `\t`, "\t", "tab":
+		return '\t', nil
+	}
+	runes := []rune(value)
+	if len(runes) != 1 {
+		return 0, newUsageError(fmt.Errorf("--delimiter must be a single character"))
+	}
+	return runes[0], nil
+}
+
+func readImportMapping(path string) (map[string]string, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, nil
+	}
+	expanded, err := expandPath(path)
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(expanded) //nolint:gosec // user-specified local file path
+	if err != nil {
+		return nil, err
+	}
+	var mapping map[string]string
+	if err := json.Unmarshal(b, &mapping); err != nil {
+		return nil, newUsageError(fmt.Errorf("invalid --mapping file: %w", err))
+	}
+	return mapping, nil
+}
+
+// mapImportColumns returns column index -> attribute slug. Explicit mapping
+// entries win; other headers are matched against attribute slugs and titles.
+func mapImportColumns(header []string, defs []attributeDef, overrides map[string]string) (map[int]string, []string) {
+	bySlug := map[string]string{}
+	byTitle := map[string]string{}
+	for _, def := range defs {
+		bySlug[normalizeColumnName(def.Slug)] = def.Slug
+		byTitle[normalizeColumnName(def.Title)] = def.Slug
+	}
+
+	columns := map[int]string{}
+	unmapped := make([]string, 0)
+	for i, name := range header {
+		if slug, ok := overrides[name]; ok {
+			if slug = strings.TrimSpace(slug); slug != "" {
+				columns[i] = slug
+			}
+			continue
+		}
+		key := normalizeColumnName(name)
+		if slug, ok := bySlug[key]; ok {
+			columns[i] = slug
+			continue
+		}
+		if slug, ok := byTitle[key]; ok {
+			columns[i] = slug
+			continue
+		}
+		unmapped = append(unmapped, name)
+	}
+	return columns, unmapped
+}
+
+func normalizeColumnName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(name)
+}
+
+func importRowPayload(cells []string, columns map[int]string, bySlug map[string]attributeDef) (map[string]any, error) {
+	values := map[string]any{}
+	for i, cell := range cells {
+		slug, ok := columns[i]
+		if !ok || strings.TrimSpace(cell) == "" {
+			continue
+		}
+		def, ok := bySlug[slug]
+		if !ok {
+			return nil, fmt.Errorf("unknown attribute %q", slug)
+		}
+		converted, err := attributeValuesFromString(def, cell)
+		if err != nil {
+			return nil, err
+		}
+		if existing, ok := values[slug].([]any); ok {
+			converted = append(existing, converted...)
+		}
+		values[slug] = converted
+	}
+	if len(values) == 0 {
+		return nil, errors.New("row has no values")
+	}
+	return map[string]any{"values": values}, nil
+}
+
+func sortImportResults(results []importRowResult) {
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Row < results[j].Row
+	})
+}
+
+func writeImportRejects(path string, header []string, rows [][]string, delimiter string) error {
+	f, err := os.Create(path) //nolint:gosec // user-specified local file path
+	if err != nil {
+		return fmt.Errorf("write rejects: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	w := csv.NewWriter(f)
+	if sep, err := csvDelimiter(delimiter); err == nil {
+		w.Comma = sep
+	}
+	_ = w.Write(append(append([]string(nil), header...), "error"))
+	for _, row := range rows {
+		_ = w.Write(row)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("write rejects: %w", err)
+	}
+	return nil
+}
+
+func writeImportResults(ctx context.Context, results []importRowResult, unmapped []string, rejectsPath string) error {
+	counts := map[string]int{"ok": 0, "failed": 0, "rejected": 0}
+	for _, r := range results {
+		counts[r.Status]++
+	}
+	summary := map[string]any{
+		"total":    len(results),
+		"ok":       counts["ok"],
+		"failed":   counts["failed"],
+		"rejected": counts["rejected"],
+	}
+
+	if outfmt.IsJSON(ctx) {
+		payload := map[string]any{
+			"data":    results,
+			"summary": summary,
+		}
+		if len(unmapped) > 0 {
+			payload["unmapped_columns"] = unmapped
+		}
+		if rejectsPath != "" {
+			payload["rejects_file"] = rejectsPath
+		}
+		return outfmt.WriteJSON(ctx, os.Stdout, payload)
+	}
+
+	w, done := tableWriter(ctx)
+	_, _ = fmt.Fprintln(w, "ROW\tSTATUS\tRECORD_ID\tERROR")
+	for _, r := range results {
+		_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", r.Row, r.Status, r.RecordID, r.Error)
+	}
+	done()
+
+	if u := ui.FromContext(ctx); u != nil && !outfmt.IsPlain(ctx) {
+		u.Err().Printf("Imported %d of %d rows (%d failed, %d rejected)", counts["ok"], len(results), counts["failed"], counts["rejected"])
+		if rejectsPath != "" {
+			u.Err().Printf("Rejected rows written to %s", rejectsPath)
+		}
+	}
+	return nil
+}
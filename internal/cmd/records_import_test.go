@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func importTestAttributes() []map[string]any {
+	return []map[string]any{
+		{"api_slug": "name", "title": "Name", "type": "personal-name"},
+		{"api_slug": "email_addresses", "title": "Email addresses", "type": "email-address", "is_multiselect": true},
+		{"api_slug": "employees", "title": "Employees", "type": "number"},
+		{"api_slug": "stage", "title": "Stage", "type": "status"},
+	}
+}
+
+func TestExecuteRecordsImportCreatesRowsAndWritesRejects(t *testing.T) {
+	setupCLIEnv(t)
+
+	var created []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v2/objects/people/attributes":
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": importTestAttributes()})
+		case r.Method == http.MethodPost && r.URL.Path == "/v2/objects/people/records":
+			body := decodeDataEnvelope(t, r)
+			created = append(created, body)
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"id": map[string]any{"record_id": "rec-1"}}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	t.Setenv("ATTIO_API_KEY", "env-key")
+	t.Setenv("ATTIO_BASE_URL", srv.URL)
+
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "leads.csv")
+	content := "Name,Email Addresses,Employees,Ignored\n" +
+		"Ada Lovelace,ada@example.com;ada@work.com,12,x\n" +
+		"Bad Row,bad@example.com,lots,y\n"
+	if err := os.WriteFile(csvPath, []byte(content), 0o600); err != nil {
+		t.Fatalf("write csv: %v", err)
+	}
+
+	stdout, stderr, err := captureExecute(t, []string{"--json", "records", "import", "people", "--file", csvPath})
+	if err == nil {
+		t.Fatalf("expected error for rejected row; stdout=%s", stdout)
+	}
+	if ExitCode(err) != ExitCodeGeneric {
+		t.Fatalf("expected generic exit code, got %d (stderr=%s)", ExitCode(err), stderr)
+	}
+
+	var payload struct {
+		Data            []importRowResult `json:"data"`
+		Summary         map[string]int    `json:"summary"`
+		UnmappedColumns []string          `json:"unmapped_columns"`
+		RejectsFile     string            `json:"rejects_file"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &payload); err != nil {
+		t.Fatalf("unmarshal stdout: %v\n%s", err, stdout)
+	}
+	if payload.Summary["ok"] != 1 || payload.Summary["rejected"] != 1 {
+		t.Fatalf("unexpected summary: %#v", payload.Summary)
+	}
+	if len(payload.Data) != 2 || payload.Data[0].RecordID != "rec-1" || payload.Data[1].Row != 3 {
+		t.Fatalf("unexpected rows: %#v", payload.Data)
+	}
+	if len(payload.UnmappedColumns) != 1 || payload.UnmappedColumns[0] != "Ignored" {
+		t.Fatalf("unexpected unmapped columns: %#v", payload.UnmappedColumns)
+	}
+
+	if len(created) != 1 {
+		t.Fatalf("expected one create call, got %d", len(created))
+	}
+	values := created[0]["values"].(map[string]any)
+	name := values["name"].([]any)[0].(map[string]any)
+	if name["first_name"] != "Ada" || name["last_name"] != "Lovelace" {
+		t.Fatalf("unexpected name value: %#v", name)
+	}
+	if emails := values["email_addresses"].([]any); len(emails) != 2 {
+		t.Fatalf("expected multiselect emails split, got %#v", emails)
+	}
+
+	rejects, err := os.ReadFile(filepath.Join(dir, "leads.rejected.csv"))
+	if err != nil {
+		t.Fatalf("read rejects: %v", err)
+	}
+	if !strings.Contains(string(rejects), "Bad Row") || !strings.Contains(string(rejects), "invalid number") {
+		t.Fatalf("unexpected rejects file: %s", rejects)
+	}
+}
+
+func TestExecuteRecordsImportDryRunUsesMappingAndAssert(t *testing.T) {
+	setupCLIEnv(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && r.URL.Path == "/v2/objects/people/attributes" {
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": importTestAttributes()})
+			return
+		}
+		t.Fatalf("unexpected request in dry-run: %s %s", r.Method, r.URL.Path)
+	}))
+	defer srv.Close()
+
+	t.Setenv("ATTIO_API_KEY", "env-key")
+	t.Setenv("ATTIO_BASE_URL", srv.URL)
+
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "leads.csv")
+	mappingPath := filepath.Join(dir, "mapping.json")
+	if err := os.WriteFile(csvPath, []byte("Mail,Pipeline\nada@example.com,Won\n"), 0o600); err != nil {
+		t.Fatalf("write csv: %v", err)
+	}
+	if err := os.WriteFile(mappingPath, []byte(`{"Mail":"email_addresses","Pipeline":"stage"}`), 0o600); err != nil {
+		t.Fatalf("write mapping: %v", err)
+	}
+
+	stdout, stderr, err := captureExecute(t, []string{"--json", "--dry-run", "records", "import", "people", "--file", csvPath, "--mapping", mappingPath, "--matching-attribute", "email_addresses"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v\nstderr=%s", err, stderr)
+	}
+	if !strings.Contains(stdout, `"dry_run": true`) || !strings.Contains(stdout, `"status": "Won"`) || !strings.Contains(stdout, `"matching_attribute": "email_addresses"`) {
+		t.Fatalf("unexpected dry-run output: %s", stdout)
+	}
+}
+
+func TestAttributeValueFromString(t *testing.T) {
+	cases := []struct {
+		def  attributeDef
+		raw  string
+		want string
+	}{
+		{attributeDef{Slug: "n", Type: "number"}, "1,250.5", `{"value":1250.5}`},
+		{attributeDef{Slug: "c", Type: "checkbox"}, "yes", `{"value":true}`},
+		{attributeDef{Slug: "m", Type: "currency", CurrencyCode: "USD"}, "USD 99", `{"currency_code":"USD","currency_value":99}`},
+		{attributeDef{Slug: "p", Type: "personal-name"}, "Lovelace, Ada", `{"first_name":"Ada","full_name":"Ada Lovelace","last_name":"Lovelace"}`},
+		{attributeDef{Slug: "o", Type: "actor-reference"}, "a@b.com", `{"workspace_member_email_address":"a@b.com"}`},
+		{attributeDef{Slug: "r", Type: "record-reference"}, "companies:acme.com", `{"domains":[{"domain":"acme.com"}],"target_object":"companies"}`},
+	}
+	for _, tc := range cases {
+		got, err := attributeValueFromString(tc.def, tc.raw)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.def.Slug, err)
+		}
+		b, _ := json.Marshal(got)
+		if string(b) != tc.want {
+			t.Fatalf("%s: got %s want %s", tc.def.Slug, b, tc.want)
+		}
+	}
+
+	if _, err := attributeValueFromString(attributeDef{Slug: "m", Type: "currency", CurrencyCode: "USD"}, "EUR 5"); err == nil {
+		t.Fatalf("expected currency mismatch error")
+	}
+	if _, err := attributeValueFromString(attributeDef{Slug: "r", Type: "record-reference"}, "acme.com"); err == nil {
+		t.Fatalf("expected target object error")
+	}
+	if _, err := attributeValueFromString(attributeDef{Slug: "l", Type: "location"}, "Berlin"); err == nil {
+		t.Fatalf("expected unsupported type error")
+	}
+}
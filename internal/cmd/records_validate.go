@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/failup-ventures/attio-cli/internal/outfmt"
+)
+
+type RecordsValidateCmd struct {
+	Object   string `arg:"" name:"object" help:"Object slug or UUID" required:""`
+	Data     string `name:"data" help:"Record data JSON; supports '-' or @file.json" required:""`
+	Mode     string `name:"mode" help:"Check the data as for records create, update (also replace) or assert" enum:"create,update,assert" default:"create"`
+	RecordID string `name:"record-id" help:"Record the data would update, ignored by uniqueness checks"`
+}
+
+func (c *RecordsValidateCmd) Run(ctx context.Context, flags *RootFlags) error {
+	client, err := requireClient(flags.Profile)
+	if err != nil {
+		return err
+	}
+	data, err := readJSONObjectInput(c.Data)
+	if err != nil {
+		return err
+	}
+	problems, err := newRecordValidator(client, c.Object).withCache(flags.Profile).Validate(ctx, data, validationMode(c.Mode), c.RecordID)
+	if err != nil {
+		return err
+	}
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{"data": map[string]any{
+			"object": c.Object,
+			"mode":   c.Mode,
+			"valid":  true,
+		}})
+	}
+	_, _ = fmt.Fprintf(os.Stdout, "Valid %s data for %s\n", c.Mode, c.Object)
+	return nil
+}
@@ -9,6 +9,8 @@ import (
 
 	"github.com/alecthomas/kong"
 
+	"github.com/failup-ventures/attio-cli/internal/api"
+	"github.com/failup-ventures/attio-cli/internal/config"
 	"github.com/failup-ventures/attio-cli/internal/errfmt"
 	"github.com/failup-ventures/attio-cli/internal/outfmt"
 	"github.com/failup-ventures/attio-cli/internal/ui"
@@ -29,6 +31,7 @@ type CLI struct {
 
 	Init       InitCmd               `cmd:"" help:"Run first-time setup and API key verification"`
 	Auth       AuthCmd               `cmd:"" help:"Manage authentication"`
+	Config     ConfigCmd             `cmd:"" help:"Manage profiles and profile settings"`
 	Self       SelfCmd               `cmd:"" name:"self" aliases:"whoami,me" help:"Show current token info"`
 	Objects    ObjectsCmd            `cmd:"" help:"Manage objects"`
 	Records    RecordsCmd            `cmd:"" help:"Manage records"`
@@ -42,10 +45,14 @@ type CLI struct {
 	Meetings   MeetingsCmd           `cmd:"" help:"Manage meetings"`
 	Attributes AttributesCmd         `cmd:"" aliases:"attrs" help:"Manage attributes"`
 	Members    MembersCmd            `cmd:"" help:"Manage workspace members"`
+	API        APICmd                `cmd:"" name:"api" help:"Make an authenticated request to any Attio API endpoint"`
+	MockServer MockServerCmd         `cmd:"" name:"mock-server" help:"Run an in-memory Attio API for local development"`
 	VersionCmd VersionCmd            `cmd:"" name:"version" help:"Print version"`
 	Completion CompletionCmd         `cmd:"" help:"Generate shell completion scripts"`
 	Complete   CompletionInternalCmd `cmd:"" name:"__complete" hidden:"" help:"Internal completion helper"`
-	Schema     SchemaCmd             `cmd:"" help:"Print command schema"`
+	Schema     SchemaGroupCmd        `cmd:"" help:"Print command schema or manage workspace schema"`
+	Audit      AuditCmd              `cmd:"" help:"Inspect the local audit log of changes made through the CLI"`
+	Undo       UndoCmd               `cmd:"" help:"Revert the last records/entries update, replace or delete (needs the undo setting)"`
 }
 
 func Execute(args []string) error {
@@ -61,9 +68,12 @@ func Execute(args []string) error {
 		return err
 	}
 
-	if shouldAutoJSON(cli.RootFlags) {
-		cli.JSON = true
+	project, err := config.LoadProjectConfig()
+	if err != nil {
+		emitCLIError(err, cli.JSON, nil)
+		return err
 	}
+	settings, policy := resolveSettings(kctx, &cli.RootFlags, project)
 
 	mode, err := outfmt.FromFlags(cli.JSON, cli.Plain)
 	if err != nil {
@@ -78,7 +88,18 @@ func Execute(args []string) error {
 		emitCLIError(err, mode.JSON, nil)
 		return err
 	}
+	if cli.RateLimit != "" {
+		if _, err := api.ParseRateLimit(cli.RateLimit); err != nil {
+			err = newUsageError(fmt.Errorf("--rate-limit: %w", err))
+			emitCLIError(err, mode.JSON, nil)
+			return err
+		}
+	}
 	setClientRuntimeOptions("attio-cli/"+Version, timeout)
+	setClientRateLimit(cli.RateLimit)
+	policy.resolve = policyResolver(cli.Profile)
+	setClientPolicy(policy)
+	setCommandPath(normalizeCommandPath(kctx.Command()))
 
 	logLevel := slog.LevelWarn
 	if cli.Verbose {
@@ -96,6 +117,8 @@ func Execute(args []string) error {
 		DryRun:    cli.DryRun,
 		FailEmpty: cli.FailEmpty,
 		IDOnly:    cli.IDOnly,
+		Settings:  settings,
+		Columns:   projectColumns(project),
 	})
 
 	uiColor := cli.Color
@@ -121,6 +144,11 @@ func Execute(args []string) error {
 		emitCLIError(err, mode.JSON, u)
 		return err
 	}
+	if err := enforcePolicy(kctx, policy, cli.DryRun); err != nil {
+		err = stableExitCode(err)
+		emitCLIError(err, mode.JSON, u)
+		return err
+	}
 
 	err = kctx.Run()
 	if err == nil {
@@ -141,7 +169,6 @@ func newParser() (*kong.Kong, *CLI, error) {
 		kong.UsageOnError(),
 		kong.ConfigureHelp(kong.HelpOptions{Compact: true}),
 		kong.Vars{
-			"color":   colorAuto,
 			"version": buildVersionString(),
 		},
 	)
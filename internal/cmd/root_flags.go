@@ -1,17 +1,22 @@
 package cmd
 
 type RootFlags struct {
-	Profile        string `help:"Named profile to use" default:"default"`
-	JSON           bool   `help:"Output JSON to stdout" short:"j"`
-	Plain          bool   `help:"Output stable TSV for piping" short:"p"`
-	IDOnly         bool   `name:"id-only" help:"Output only the primary resource ID when available"`
-	ResultsOnly    bool   `name:"results-only" help:"In JSON mode, emit only the data array"`
-	Select         string `name:"select" help:"In JSON mode, project comma-separated fields"`
-	DryRun         bool   `name:"dry-run" aliases:"noop,preview,dryrun" help:"Do not make changes; print intended action and exit successfully"`
-	FailEmpty      bool   `name:"fail-empty" help:"Exit code 3 when a list/query command returns no results"`
-	EnableCommands string `name:"enable-commands" env:"ATTIO_ENABLE_COMMANDS" help:"Comma-separated command allowlist (supports top-level or full command path)"`
-	Timeout        string `name:"timeout" env:"ATTIO_TIMEOUT" default:"30s" help:"HTTP request timeout (for example: 30s, 2m)"`
-	Color          string `help:"Color output: auto|always|never" default:"${color}"`
-	Verbose        bool   `help:"Enable debug logging" short:"v"`
-	NoInput        bool   `name:"no-input" help:"Never prompt; fail instead"`
+	Profile         string `help:"Named profile to use (default: default_profile from config, else default)"`
+	JSON            bool   `help:"Output JSON to stdout" short:"j"`
+	Plain           bool   `help:"Output stable TSV for piping" short:"p"`
+	IDOnly          bool   `name:"id-only" help:"Output only the primary resource ID when available"`
+	ResultsOnly     bool   `name:"results-only" help:"In JSON mode, emit only the data array"`
+	Select          string `name:"select" help:"In JSON mode, project comma-separated fields"`
+	DryRun          bool   `name:"dry-run" aliases:"noop,preview,dryrun" help:"Do not make changes; print intended action and exit successfully"`
+	FailEmpty       bool   `name:"fail-empty" help:"Exit code 3 when a list/query command returns no results"`
+	EnableCommands  string `name:"enable-commands" env:"ATTIO_ENABLE_COMMANDS" help:"Comma-separated command allowlist (supports top-level or full command path)"`
+	DisableCommands string `name:"disable-commands" env:"ATTIO_DISABLE_COMMANDS" help:"Comma-separated command deny-list, added to the profile's disable_commands"`
+	ReadOnly        bool   `name:"read-only" env:"ATTIO_READ_ONLY" help:"Block every command and API request that would change data (also set by profile read_only)"`
+	Timeout         string `name:"timeout" env:"ATTIO_TIMEOUT" help:"HTTP request timeout, for example 30s or 2m (default: profile timeout, else 30s)"`
+	RateLimit       string `name:"rate-limit" env:"ATTIO_RATE_LIMIT" help:"Client-side rate limit: <rps>[:<burst>], read=<rps>[:<burst>],write=<rps>[:<burst>], or off (default: profile rate_limit, else 100 reads/25 writes per second)"`
+	Color           string `help:"Color output: auto|always|never (default: profile color, else auto)"`
+	Verbose         bool   `help:"Enable debug logging" short:"v"`
+	NoInput         bool   `name:"no-input" help:"Never prompt; fail instead"`
+	Yes             bool   `name:"yes" short:"y" help:"Skip confirmation prompts for destructive commands"`
+	NoValidate      bool   `name:"no-validate" env:"ATTIO_NO_VALIDATE" help:"Skip checking records and entries --data against the workspace schema before writing"`
 }
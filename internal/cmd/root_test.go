@@ -17,6 +17,7 @@ func setupCLIEnv(t *testing.T) {
 	tmp := t.TempDir()
 	t.Setenv("HOME", tmp)
 	t.Setenv("ATTIO_CONFIG_PATH", filepath.Join(tmp, "config.json"))
+	t.Setenv("ATTIO_CACHE_DIR", filepath.Join(tmp, "cache"))
 	t.Setenv("ATTIO_API_KEY", "")
 	t.Setenv("ATTIO_BASE_URL", "")
 }
@@ -141,3 +142,33 @@ func TestExecuteSelfAuthMissing(t *testing.T) {
 		t.Fatalf("expected auth error message, got %q", stderr)
 	}
 }
+
+func TestExecuteSelfCassette(t *testing.T) {
+	setupCLIEnv(t)
+	dir := t.TempDir()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"active":true,"workspace_name":"Test WS","workspace_slug":"test-ws"}`))
+	}))
+	t.Setenv("ATTIO_API_KEY", "env-key")
+	t.Setenv("ATTIO_BASE_URL", srv.URL)
+	t.Setenv("ATTIO_HTTP_RECORD", dir)
+	if _, stderr, err := captureExecute(t, []string{"--json", "self"}); err != nil {
+		t.Fatalf("record: %v\nstderr=%s", err, stderr)
+	}
+	srv.Close()
+
+	t.Setenv("ATTIO_API_KEY", "")
+	t.Setenv("ATTIO_HTTP_RECORD", "")
+	t.Setenv("ATTIO_HTTP_REPLAY", dir)
+	stdout, stderr, err := captureExecute(t, []string{"--json", "self"})
+	if err != nil || !strings.Contains(stdout, `"workspace_name": "Test WS"`) {
+		t.Fatalf("expected a replayed self, got %v\nstdout=%s\nstderr=%s", err, stdout, stderr)
+	}
+
+	t.Setenv("ATTIO_HTTP_MATCH", "method,url")
+	_, stderr, err = captureExecute(t, []string{"self"})
+	if ExitCode(err) != ExitCodeUsage || !strings.Contains(stderr, `unknown match key "url"`) {
+		t.Fatalf("expected a usage error for an unknown match key, got %v\n%s", err, stderr)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/failup-ventures/attio-cli/internal/config"
 	"github.com/failup-ventures/attio-cli/internal/outfmt"
 	"github.com/failup-ventures/attio-cli/internal/ui"
 )
@@ -14,6 +15,10 @@ type RuntimeOptions struct {
 	DryRun    bool
 	FailEmpty bool
 	IDOnly    bool
+	// Settings records where output, timeout and similar settings came from.
+	Settings []config.SettingSource
+	// Columns are the project's default record columns, keyed by object.
+	Columns map[string][]string
 }
 
 type runtimeOptionsKey struct{}
@@ -10,6 +10,13 @@ import (
 	"github.com/failup-ventures/attio-cli/internal/outfmt"
 )
 
+type SchemaGroupCmd struct {
+	Commands SchemaCmd       `cmd:"" default:"1" help:"Print command schema (default)"`
+	Export   SchemaExportCmd `cmd:"" help:"Export workspace objects, lists and attributes as YAML or JSON"`
+	Plan     SchemaPlanCmd   `cmd:"" help:"Show the changes needed to match a schema file"`
+	Apply    SchemaApplyCmd  `cmd:"" help:"Create and update attributes, options and statuses to match a schema file"`
+}
+
 type SchemaCmd struct {
 }
 
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/failup-ventures/attio-cli/internal/config"
+)
+
+// schemaCache keeps the attribute, select option and status listings of one
+// object or list on disk, so that consecutive writes validate without
+// listing the schema again. Uniqueness lookups are never cached.
+type schemaCache struct {
+	path    string
+	ttl     time.Duration
+	entries map[string]schemaCacheEntry
+}
+
+type schemaCacheEntry struct {
+	FetchedAt time.Time        `json:"fetched_at"`
+	Data      []map[string]any `json:"data"`
+}
+
+// openSchemaCache returns the cache of target/identifier for profile, or nil
+// when it is turned off or cannot be used. The base URL is part of the key,
+// so a profile pointed at another workspace starts with an empty cache.
+func openSchemaCache(profile string, target string, identifier string) *schemaCache {
+	ttl, err := config.ResolveSchemaCacheTTL()
+	if err != nil {
+		slog.Debug("schema cache off", "error", err)
+		return nil
+	}
+	if ttl == 0 {
+		return nil
+	}
+	dir, err := config.CacheDir()
+	if err != nil {
+		slog.Debug("schema cache off", "error", err)
+		return nil
+	}
+	profile = config.ResolveProfile(profile)
+	sum := sha256.Sum256([]byte(profile + "\x00" + config.ResolveBaseURL(profile) + "\x00" + target + "\x00" + identifier))
+	c := &schemaCache{
+		path:    filepath.Join(dir, "schema", hex.EncodeToString(sum[:16])+".json"),
+		ttl:     ttl,
+		entries: map[string]schemaCacheEntry{},
+	}
+	b, err := os.ReadFile(c.path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			slog.Debug("read schema cache", "path", c.path, "error", err)
+		}
+		return c
+	}
+	if err := json.Unmarshal(b, &c.entries); err != nil {
+		slog.Debug("ignoring corrupt schema cache", "path", c.path, "error", err)
+		c.entries = map[string]schemaCacheEntry{}
+	}
+	return c
+}
+
+// get returns the cached listing for key if it is younger than the TTL.
+func (c *schemaCache) get(key string) ([]map[string]any, bool) {
+	if c == nil {
+		return nil, false
+	}
+	entry, ok := c.entries[key]
+	if !ok || time.Since(entry.FetchedAt) > c.ttl {
+		return nil, false
+	}
+	return entry.Data, true
+}
+
+// put stores a listing. Failing to write the file only costs a lookup on
+// the next run.
+func (c *schemaCache) put(key string, data []map[string]any) {
+	if c == nil {
+		return
+	}
+	c.entries[key] = schemaCacheEntry{FetchedAt: time.Now().UTC(), Data: data}
+	for k, entry := range c.entries {
+		if time.Since(entry.FetchedAt) > c.ttl {
+			delete(c.entries, k)
+		}
+	}
+	b, err := json.Marshal(c.entries)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(c.path), 0o700)
+	}
+	if err == nil {
+		err = writeFileAtomic(c.path, b)
+	}
+	if err != nil {
+		slog.Debug("write schema cache", "path", c.path, "error", err)
+	}
+}
+
+// drop forgets every listing, after a cached schema turned up problems that
+// a fresh one might not.
+func (c *schemaCache) drop() {
+	if c == nil {
+		return
+	}
+	c.entries = map[string]schemaCacheEntry{}
+	if err := os.Remove(c.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		slog.Debug("remove schema cache", "path", c.path, "error", err)
+	}
+}
+
+// writeFileAtomic replaces path through a temporary file in the same
+// directory, so concurrent runs never read a half-written file.
+func writeFileAtomic(path string, b []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
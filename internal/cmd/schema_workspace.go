@@ -0,0 +1,896 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/failup-ventures/attio-cli/internal/api"
+	"github.com/failup-ventures/attio-cli/internal/outfmt"
+	"github.com/failup-ventures/attio-cli/internal/ui"
+)
+
+const workspaceSchemaVersion = 1
+
+// workspaceSchema is the declarative schema file read and written by
+// `schema export`, `schema plan` and `schema apply`.
+type workspaceSchema struct {
+	Version int            `json:"version" yaml:"version"`
+	Objects []schemaObject `json:"objects,omitempty" yaml:"objects,omitempty"`
+	Lists   []schemaList   `json:"lists,omitempty" yaml:"lists,omitempty"`
+}
+
+type schemaObject struct {
+	APISlug      string            `json:"api_slug" yaml:"api_slug"`
+	SingularNoun string            `json:"singular_noun,omitempty" yaml:"singular_noun,omitempty"`
+	PluralNoun   string            `json:"plural_noun,omitempty" yaml:"plural_noun,omitempty"`
+	Attributes   []schemaAttribute `json:"attributes,omitempty" yaml:"attributes,omitempty"`
+}
+
+type schemaList struct {
+	APISlug         string            `json:"api_slug" yaml:"api_slug"`
+	Name            string            `json:"name,omitempty" yaml:"name,omitempty"`
+	ParentObject    string            `json:"parent_object" yaml:"parent_object"`
+	WorkspaceAccess string            `json:"workspace_access,omitempty" yaml:"workspace_access,omitempty"`
+	Attributes      []schemaAttribute `json:"attributes,omitempty" yaml:"attributes,omitempty"`
+}
+
+type schemaAttribute struct {
+	APISlug       string         `json:"api_slug" yaml:"api_slug"`
+	Title         string         `json:"title" yaml:"title"`
+	Type          string         `json:"type" yaml:"type"`
+	Description   string         `json:"description,omitempty" yaml:"description,omitempty"`
+	IsRequired    bool           `json:"is_required,omitempty" yaml:"is_required,omitempty"`
+	IsUnique      bool           `json:"is_unique,omitempty" yaml:"is_unique,omitempty"`
+	IsMultiselect bool           `json:"is_multiselect,omitempty" yaml:"is_multiselect,omitempty"`
+	Config        map[string]any `json:"config,omitempty" yaml:"config,omitempty"`
+	IsArchived    bool           `json:"is_archived,omitempty" yaml:"is_archived,omitempty"`
+	Options       []schemaOption `json:"options,omitempty" yaml:"options,omitempty"`
+	Statuses      []schemaStatus `json:"statuses,omitempty" yaml:"statuses,omitempty"`
+
+	// system marks attributes Attio manages itself; they are never created
+	// or changed by a plan.
+	system bool
+}
+
+type schemaOption struct {
+	Title      string `json:"title" yaml:"title"`
+	IsArchived bool   `json:"is_archived,omitempty" yaml:"is_archived,omitempty"`
+}
+
+type schemaStatus struct {
+	Title              string `json:"title" yaml:"title"`
+	IsArchived         bool   `json:"is_archived,omitempty" yaml:"is_archived,omitempty"`
+	CelebrationEnabled bool   `json:"celebration_enabled,omitempty" yaml:"celebration_enabled,omitempty"`
+	TargetTimeInStatus string `json:"target_time_in_status,omitempty" yaml:"target_time_in_status,omitempty"`
+}
+
+// schemaOperation is one step of a plan. Target/Identifier/Attribute address
+// the API resource; Item is the option or status being changed.
+type schemaOperation struct {
+	Action     string         `json:"action"`
+	Kind       string         `json:"kind"`
+	Address    string         `json:"address"`
+	Target     string         `json:"target,omitempty"`
+	Identifier string         `json:"identifier,omitempty"`
+	Attribute  string         `json:"attribute,omitempty"`
+	Item       string         `json:"item,omitempty"`
+	Changes    []string       `json:"changes,omitempty"`
+	Data       map[string]any `json:"data"`
+}
+
+type schemaPlan struct {
+	Operations []schemaOperation `json:"operations"`
+	Warnings   []string          `json:"warnings,omitempty"`
+}
+
+func (p *schemaPlan) summary() map[string]int {
+	counts := map[string]int{"create": 0, "update": 0}
+	for _, op := range p.Operations {
+		counts[op.Action]++
+	}
+	return counts
+}
+
+type SchemaExportCmd struct {
+	Output  string `name:"output" short:"o" help:"Write to file instead of stdout"`
+	Format  string `name:"format" help:"File format: yaml|json (default: from --output extension, else yaml)" enum:",yaml,json" default:""`
+	Objects string `name:"objects" help:"Comma-separated object slugs to export (default: all)"`
+	Lists   string `name:"lists" help:"Comma-separated list slugs to export (default: all)"`
+}
+
+func (c *SchemaExportCmd) Run(ctx context.Context, flags *RootFlags) error {
+	client, err := requireClient(flags.Profile)
+	if err != nil {
+		return err
+	}
+	schema, err := exportWorkspaceSchema(ctx, client, splitCommaList(c.Objects), splitCommaList(c.Lists))
+	if err != nil {
+		return err
+	}
+
+	format := c.Format
+	if format == "" {
+		format = "yaml"
+		if strings.EqualFold(filepath.Ext(c.Output), ".json") || (c.Output == "" && outfmt.IsJSON(ctx)) {
+			format = "json"
+		}
+	}
+	b, err := encodeWorkspaceSchema(schema, format)
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(c.Output) == "" {
+		_, err := os.Stdout.Write(b)
+		return err
+	}
+	path, err := expandPath(c.Output)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, b, 0o644); err != nil { //nolint:gosec // user-specified local file path
+		return err
+	}
+	if u := ui.FromContext(ctx); u != nil && !outfmt.IsJSON(ctx) {
+		u.Err().Printf("Exported %d objects and %d lists to %s", len(schema.Objects), len(schema.Lists), path)
+	}
+	return nil
+}
+
+type SchemaPlanCmd struct {
+	File string `name:"file" short:"f" help:"Schema file (YAML or JSON); '-' reads stdin" required:""`
+}
+
+func (c *SchemaPlanCmd) Run(ctx context.Context, flags *RootFlags) error {
+	desired, err := readWorkspaceSchemaFile(c.File)
+	if err != nil {
+		return err
+	}
+	client, err := requireClient(flags.Profile)
+	if err != nil {
+		return err
+	}
+	plan, err := planWorkspaceSchema(ctx, client, desired)
+	if err != nil {
+		return err
+	}
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{
+			"data":     plan.Operations,
+			"summary":  plan.summary(),
+			"warnings": plan.Warnings,
+		})
+	}
+	writeSchemaPlan(os.Stdout, plan)
+	return nil
+}
+
+type SchemaApplyCmd struct {
+	File string `name:"file" short:"f" help:"Schema file (YAML or JSON); '-' reads stdin" required:""`
+}
+
+func (c *SchemaApplyCmd) Run(ctx context.Context, flags *RootFlags) error {
+	desired, err := readWorkspaceSchemaFile(c.File)
+	if err != nil {
+		return err
+	}
+	client, err := requireClient(flags.Profile)
+	if err != nil {
+		return err
+	}
+	plan, err := planWorkspaceSchema(ctx, client, desired)
+	if err != nil {
+		return err
+	}
+
+	if isDryRun(ctx) && !outfmt.IsJSON(ctx) {
+		_, _ = fmt.Fprintln(os.Stdout, "[dry-run] schema apply")
+		writeSchemaPlan(os.Stdout, plan)
+		return nil
+	}
+	if ok, err := maybeDryRun(ctx, "schema apply", map[string]any{"operations": plan.Operations, "summary": plan.summary(), "warnings": plan.Warnings}); ok || err != nil {
+		return err
+	}
+
+	u := ui.FromContext(ctx)
+	applied := make([]schemaOperation, 0, len(plan.Operations))
+	for _, op := range plan.Operations {
+		if err := applySchemaOperation(ctx, client, op); err != nil {
+			return fmt.Errorf("%s %s: %w (%d of %d changes applied)", op.Action, op.Address, err, len(applied), len(plan.Operations))
+		}
+		applied = append(applied, op)
+		if u != nil && !outfmt.IsJSON(ctx) {
+			u.Out().Printf("%s %s", schemaActionSymbol(op.Action), op.Address)
+		}
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{
+			"data":     applied,
+			"summary":  plan.summary(),
+			"warnings": plan.Warnings,
+		})
+	}
+	for _, warning := range plan.Warnings {
+		if u != nil {
+			u.Err().Printf("Warning: %s", warning)
+		}
+	}
+	if u != nil {
+		counts := plan.summary()
+		u.Out().Printf("Apply complete: %d created, %d updated.", counts["create"], counts["update"])
+	}
+	return nil
+}
+
+func exportWorkspaceSchema(ctx context.Context, client *api.Client, objectSlugs []string, listSlugs []string) (*workspaceSchema, error) {
+	objects, err := client.ListObjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	objectSlugByID := objectSlugsByID(objects)
+	schema := &workspaceSchema{Version: workspaceSchemaVersion}
+
+	wantObject := stringSet(objectSlugs)
+	for _, object := range objects {
+		slug := mapString(object, "api_slug")
+		if slug == "" || (len(wantObject) > 0 && !wantObject[slug]) {
+			continue
+		}
+		attrs, err := fetchSchemaAttributes(ctx, client, "objects", slug, objectSlugByID, false)
+		if err != nil {
+			return nil, err
+		}
+		schema.Objects = append(schema.Objects, schemaObject{
+			APISlug:      slug,
+			SingularNoun: mapString(object, "singular_noun"),
+			PluralNoun:   mapString(object, "plural_noun"),
+			Attributes:   attrs,
+		})
+	}
+
+	lists, err := client.ListLists(ctx)
+	if err != nil {
+		return nil, err
+	}
+	wantList := stringSet(listSlugs)
+	for _, list := range lists {
+		slug := mapString(list, "api_slug")
+		if slug == "" || (len(wantList) > 0 && !wantList[slug]) {
+			continue
+		}
+		attrs, err := fetchSchemaAttributes(ctx, client, "lists", slug, objectSlugByID, false)
+		if err != nil {
+			return nil, err
+		}
+		schema.Lists = append(schema.Lists, schemaList{
+			APISlug:         slug,
+			Name:            mapString(list, "name"),
+			ParentObject:    listParentObject(list, objectSlugByID),
+			WorkspaceAccess: mapString(list, "workspace_access"),
+			Attributes:      attrs,
+		})
+	}
+
+	sort.Slice(schema.Objects, func(i, j int) bool { return schema.Objects[i].APISlug < schema.Objects[j].APISlug })
+	sort.Slice(schema.Lists, func(i, j int) bool { return schema.Lists[i].APISlug < schema.Lists[j].APISlug })
+	return schema, nil
+}
+
+// fetchSchemaAttributes returns the non-system, non-archived attributes of an
+// object or list, including select options and statuses. With showArchived,
+// used to plan against the workspace, archived attributes, options and
+// statuses are included and marked as archived, and system attributes are
+// included and marked as system.
+func fetchSchemaAttributes(ctx context.Context, client *api.Client, target string, identifier string, objectSlugByID map[string]string, showArchived bool) ([]schemaAttribute, error) {
+	raw, err := client.ListAttributes(ctx, target, identifier, showArchived, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	attrs := make([]schemaAttribute, 0, len(raw))
+	for _, m := range raw {
+		system := isTruthy(m["is_system_attribute"])
+		if !showArchived && (system || isTruthy(m["is_archived"])) {
+			continue
+		}
+		def := attributeDefFromMap(m)
+		if def.Slug == "" {
+			continue
+		}
+		attr := schemaAttribute{
+			APISlug:       def.Slug,
+			Title:         def.Title,
+			Type:          def.Type,
+			Description:   mapString(m, "description"),
+			IsRequired:    def.Required,
+			IsUnique:      def.Unique,
+			IsMultiselect: def.Multiselect,
+			Config:        schemaAttributeConfig(def, mapMap(m, "config"), objectSlugByID),
+			IsArchived:    isTruthy(m["is_archived"]),
+			system:        system,
+		}
+		if system {
+			attrs = append(attrs, attr)
+			continue
+		}
+		switch def.Type {
+		case "select":
+			options, err := client.ListSelectOptions(ctx, target, identifier, def.Slug, showArchived)
+			if err != nil {
+				return nil, err
+			}
+			for _, option := range options {
+				attr.Options = append(attr.Options, schemaOption{Title: mapString(option, "title"), IsArchived: isTruthy(option["is_archived"])})
+			}
+		case "status":
+			statuses, err := client.ListStatuses(ctx, target, identifier, def.Slug, showArchived)
+			if err != nil {
+				return nil, err
+			}
+			for _, status := range statuses {
+				attr.Statuses = append(attr.Statuses, schemaStatusFromMap(status))
+			}
+		}
+		attrs = append(attrs, attr)
+	}
+	sort.Slice(attrs, func(i, j int) bool { return attrs[i].APISlug < attrs[j].APISlug })
+	return attrs, nil
+}
+
+// schemaAttributeConfig keeps the parts of an attribute config that can be
+// set on create, with record reference object IDs replaced by slugs.
+func schemaAttributeConfig(def attributeDef, config map[string]any, objectSlugByID map[string]string) map[string]any {
+	switch def.Type {
+	case "currency":
+		currency := mapMap(config, "currency")
+		out := map[string]any{}
+		if code := mapString(currency, "default_currency_code"); code != "" {
+			out["default_currency_code"] = code
+		}
+		if display := mapString(currency, "display_type"); display != "" {
+			out["display_type"] = display
+		}
+		if len(out) == 0 {
+			return nil
+		}
+		return map[string]any{"currency": out}
+	case "record-reference":
+		allowed := make([]any, 0, len(def.TargetObjects))
+		for _, id := range def.TargetObjects {
+			if slug := objectSlugByID[id]; slug != "" {
+				id = slug
+			}
+			allowed = append(allowed, id)
+		}
+		if len(allowed) == 0 {
+			return nil
+		}
+		return map[string]any{"record_reference": map[string]any{"allowed_objects": allowed}}
+	}
+	return nil
+}
+
+func schemaStatusFromMap(m map[string]any) schemaStatus {
+	status := schemaStatus{
+		Title:              mapString(m, "title"),
+		IsArchived:         isTruthy(m["is_archived"]),
+		CelebrationEnabled: isTruthy(m["celebration_enabled"]),
+	}
+	if target, ok := m["target_time_in_status"].(string); ok {
+		status.TargetTimeInStatus = target
+	}
+	return status
+}
+
+func planWorkspaceSchema(ctx context.Context, client *api.Client, desired *workspaceSchema) (*schemaPlan, error) {
+	plan := &schemaPlan{Operations: []schemaOperation{}}
+
+	objects, err := client.ListObjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	objectSlugByID := objectSlugsByID(objects)
+	existingObjects := make(map[string]map[string]any, len(objects))
+	for _, object := range objects {
+		existingObjects[mapString(object, "api_slug")] = object
+	}
+
+	var attributeOps []schemaOperation
+	for _, want := range desired.Objects {
+		address := "objects." + want.APISlug
+		current, exists := existingObjects[want.APISlug]
+		if !exists {
+			plan.Operations = append(plan.Operations, schemaOperation{
+				Action:  "create",
+				Kind:    "object",
+				Address: address,
+				Data: map[string]any{
+					"api_slug":      want.APISlug,
+					"singular_noun": want.SingularNoun,
+					"plural_noun":   want.PluralNoun,
+				},
+			})
+		} else {
+			changes, data := diffSchemaFields(
+				[]string{"singular_noun", "plural_noun"},
+				[]string{mapString(current, "singular_noun"), mapString(current, "plural_noun")},
+				[]string{want.SingularNoun, want.PluralNoun},
+			)
+			if len(data) > 0 {
+				plan.Operations = append(plan.Operations, schemaOperation{
+					Action:     "update",
+					Kind:       "object",
+					Address:    address,
+					Identifier: want.APISlug,
+					Changes:    changes,
+					Data:       data,
+				})
+			}
+		}
+		ops, err := planSchemaAttributes(ctx, client, plan, "objects", want.APISlug, address, exists, want.Attributes, objectSlugByID)
+		if err != nil {
+			return nil, err
+		}
+		attributeOps = append(attributeOps, ops...)
+	}
+
+	if len(desired.Lists) > 0 {
+		lists, err := client.ListLists(ctx)
+		if err != nil {
+			return nil, err
+		}
+		existingLists := make(map[string]map[string]any, len(lists))
+		for _, list := range lists {
+			existingLists[mapString(list, "api_slug")] = list
+		}
+		for _, want := range desired.Lists {
+			address := "lists." + want.APISlug
+			current, exists := existingLists[want.APISlug]
+			if !exists {
+				access := want.WorkspaceAccess
+				if access == "" {
+					access = "full-access"
+				}
+				plan.Operations = append(plan.Operations, schemaOperation{
+					Action:  "create",
+					Kind:    "list",
+					Address: address,
+					Data: map[string]any{
+						"api_slug":                want.APISlug,
+						"name":                    want.Name,
+						"parent_object":           want.ParentObject,
+						"workspace_access":        access,
+						"workspace_member_access": []any{},
+					},
+				})
+			} else {
+				if parent := listParentObject(current, objectSlugByID); want.ParentObject != "" && parent != "" && parent != want.ParentObject {
+					plan.Warnings = append(plan.Warnings, fmt.Sprintf("%s: parent object is %q in the workspace but %q in the file; lists cannot change parent object", address, parent, want.ParentObject))
+				}
+				changes, data := diffSchemaFields(
+					[]string{"name", "workspace_access"},
+					[]string{mapString(current, "name"), mapString(current, "workspace_access")},
+					[]string{want.Name, want.WorkspaceAccess},
+				)
+				if len(data) > 0 {
+					plan.Operations = append(plan.Operations, schemaOperation{
+						Action:     "update",
+						Kind:       "list",
+						Address:    address,
+						Identifier: want.APISlug,
+						Changes:    changes,
+						Data:       data,
+					})
+				}
+			}
+			ops, err := planSchemaAttributes(ctx, client, plan, "lists", want.APISlug, address, exists, want.Attributes, objectSlugByID)
+			if err != nil {
+				return nil, err
+			}
+			attributeOps = append(attributeOps, ops...)
+		}
+	}
+
+	// Objects and lists first so attributes can reference them.
+	plan.Operations = append(plan.Operations, attributeOps...)
+	return plan, nil
+}
+
+func planSchemaAttributes(ctx context.Context, client *api.Client, plan *schemaPlan, target string, identifier string, parentAddress string, parentExists bool, wanted []schemaAttribute, objectSlugByID map[string]string) ([]schemaOperation, error) {
+	current := map[string]schemaAttribute{}
+	if parentExists {
+		// Archived attributes, options and statuses still hold their slugs
+		// and titles, so a file that lists one unarchives it instead of
+		// creating a duplicate.
+		attrs, err := fetchSchemaAttributes(ctx, client, target, identifier, objectSlugByID, true)
+		if err != nil {
+			return nil, err
+		}
+		for _, attr := range attrs {
+			current[attr.APISlug] = attr
+		}
+	}
+
+	var ops []schemaOperation
+	for _, want := range wanted {
+		address := parentAddress + ".attributes." + want.APISlug
+		have, exists := current[want.APISlug]
+		if exists && have.system {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("%s: system attributes are managed by Attio and cannot be created or changed", address))
+			continue
+		}
+		if !exists && want.IsArchived {
+			continue
+		}
+		if !exists {
+			ops = append(ops, schemaOperation{
+				Action:     "create",
+				Kind:       "attribute",
+				Address:    address,
+				Target:     target,
+				Identifier: identifier,
+				Changes:    []string{"type: " + want.Type},
+				Data:       schemaAttributeCreateData(want),
+			})
+			have = schemaAttribute{APISlug: want.APISlug, Type: want.Type}
+		} else {
+			if have.Type != want.Type || have.IsMultiselect != want.IsMultiselect {
+				plan.Warnings = append(plan.Warnings, fmt.Sprintf("%s: type %s in the workspace differs from %s in the file; attribute types cannot be changed", address, describeAttributeType(have), describeAttributeType(want)))
+			}
+			changes, data := diffSchemaFields(
+				[]string{"title", "description"},
+				[]string{have.Title, have.Description},
+				[]string{want.Title, want.Description},
+			)
+			if have.IsRequired != want.IsRequired {
+				changes = append(changes, fmt.Sprintf("is_required: %t -> %t", have.IsRequired, want.IsRequired))
+				data["is_required"] = want.IsRequired
+			}
+			if have.IsUnique != want.IsUnique {
+				changes = append(changes, fmt.Sprintf("is_unique: %t -> %t", have.IsUnique, want.IsUnique))
+				data["is_unique"] = want.IsUnique
+			}
+			if have.IsArchived != want.IsArchived {
+				changes = append(changes, fmt.Sprintf("is_archived: %t -> %t", have.IsArchived, want.IsArchived))
+				data["is_archived"] = want.IsArchived
+			}
+			if len(data) > 0 {
+				ops = append(ops, schemaOperation{
+					Action:     "update",
+					Kind:       "attribute",
+					Address:    address,
+					Target:     target,
+					Identifier: identifier,
+					Attribute:  want.APISlug,
+					Changes:    changes,
+					Data:       data,
+				})
+			}
+		}
+
+		existingOptions := make(map[string]schemaOption, len(have.Options))
+		for _, option := range have.Options {
+			existingOptions[option.Title] = option
+		}
+		for _, option := range want.Options {
+			optionAddress := address + ".options." + quoteSchemaKey(option.Title)
+			if cur, ok := existingOptions[option.Title]; ok {
+				if cur.IsArchived != option.IsArchived {
+					ops = append(ops, schemaOperation{Action: "update", Kind: "option", Address: optionAddress, Target: target, Identifier: identifier, Attribute: want.APISlug, Item: option.Title, Changes: []string{fmt.Sprintf("is_archived: %t -> %t", cur.IsArchived, option.IsArchived)}, Data: map[string]any{"is_archived": option.IsArchived}})
+				}
+				continue
+			}
+			if option.IsArchived {
+				continue
+			}
+			ops = append(ops, schemaOperation{Action: "create", Kind: "option", Address: optionAddress, Target: target, Identifier: identifier, Attribute: want.APISlug, Data: map[string]any{"title": option.Title}})
+		}
+
+		existingStatuses := make(map[string]schemaStatus, len(have.Statuses))
+		for _, status := range have.Statuses {
+			existingStatuses[status.Title] = status
+		}
+		for _, status := range want.Statuses {
+			statusAddress := address + ".statuses." + quoteSchemaKey(status.Title)
+			cur, ok := existingStatuses[status.Title]
+			if !ok {
+				if status.IsArchived {
+					continue
+				}
+				ops = append(ops, schemaOperation{Action: "create", Kind: "status", Address: statusAddress, Target: target, Identifier: identifier, Attribute: want.APISlug, Data: schemaStatusData(status)})
+				continue
+			}
+			var changes []string
+			data := map[string]any{}
+			if cur.IsArchived != status.IsArchived {
+				changes = append(changes, fmt.Sprintf("is_archived: %t -> %t", cur.IsArchived, status.IsArchived))
+				data["is_archived"] = status.IsArchived
+			}
+			if cur.CelebrationEnabled != status.CelebrationEnabled {
+				changes = append(changes, fmt.Sprintf("celebration_enabled: %t -> %t", cur.CelebrationEnabled, status.CelebrationEnabled))
+				data["celebration_enabled"] = status.CelebrationEnabled
+			}
+			if cur.TargetTimeInStatus != status.TargetTimeInStatus {
+				changes = append(changes, fmt.Sprintf("target_time_in_status: %q -> %q", cur.TargetTimeInStatus, status.TargetTimeInStatus))
+				data["target_time_in_status"] = nullableString(status.TargetTimeInStatus)
+			}
+			if len(data) > 0 {
+				ops = append(ops, schemaOperation{Action: "update", Kind: "status", Address: statusAddress, Target: target, Identifier: identifier, Attribute: want.APISlug, Item: status.Title, Changes: changes, Data: data})
+			}
+		}
+	}
+	return ops, nil
+}
+
+func schemaAttributeCreateData(attr schemaAttribute) map[string]any {
+	config := attr.Config
+	if config == nil {
+		config = map[string]any{}
+	}
+	return map[string]any{
+		"api_slug":       attr.APISlug,
+		"title":          attr.Title,
+		"type":           attr.Type,
+		"description":    nullableString(attr.Description),
+		"is_required":    attr.IsRequired,
+		"is_unique":      attr.IsUnique,
+		"is_multiselect": attr.IsMultiselect,
+		"config":         config,
+	}
+}
+
+func schemaStatusData(status schemaStatus) map[string]any {
+	data := map[string]any{
+		"title":               status.Title,
+		"celebration_enabled": status.CelebrationEnabled,
+	}
+	if status.TargetTimeInStatus != "" {
+		data["target_time_in_status"] = status.TargetTimeInStatus
+	}
+	return data
+}
+
+func applySchemaOperation(ctx context.Context, client *api.Client, op schemaOperation) error {
+	var err error
+	switch op.Kind + " " + op.Action {
+	case "object create":
+		_, err = client.CreateObject(ctx, op.Data)
+	case "object update":
+		_, err = client.UpdateObject(ctx, op.Identifier, op.Data)
+	case "list create":
+		_, err = client.CreateList(ctx, op.Data)
+	case "list update":
+		_, err = client.UpdateList(ctx, op.Identifier, op.Data)
+	case "attribute create":
+		_, err = client.CreateAttribute(ctx, op.Target, op.Identifier, op.Data)
+	case "attribute update":
+		_, err = client.UpdateAttribute(ctx, op.Target, op.Identifier, op.Attribute, op.Data)
+	case "option create":
+		_, err = client.CreateSelectOption(ctx, op.Target, op.Identifier, op.Attribute, op.Data)
+	case "option update":
+		_, err = client.UpdateSelectOption(ctx, op.Target, op.Identifier, op.Attribute, op.Item, op.Data)
+	case "status create":
+		_, err = client.CreateStatus(ctx, op.Target, op.Identifier, op.Attribute, op.Data)
+	case "status update":
+		_, err = client.UpdateStatus(ctx, op.Target, op.Identifier, op.Attribute, op.Item, op.Data)
+	default:
+		err = fmt.Errorf("unsupported operation %s %s", op.Action, op.Kind)
+	}
+	return err
+}
+
+// writeSchemaPlan renders a plan for review, one line per operation followed
+// by its field changes.
+func writeSchemaPlan(w io.Writer, plan *schemaPlan) {
+	if len(plan.Operations) == 0 {
+		_, _ = fmt.Fprintln(w, "No changes. The workspace matches the schema file.")
+	}
+	for _, op := range plan.Operations {
+		_, _ = fmt.Fprintf(w, "  %s %s %s\n", schemaActionSymbol(op.Action), op.Kind, op.Address)
+		for _, change := range op.Changes {
+			_, _ = fmt.Fprintf(w, "      %s\n", change)
+		}
+	}
+	for _, warning := range plan.Warnings {
+		_, _ = fmt.Fprintf(w, "  ! %s\n", warning)
+	}
+	if len(plan.Operations) > 0 {
+		counts := plan.summary()
+		_, _ = fmt.Fprintf(w, "\nPlan: %d to create, %d to update.\n", counts["create"], counts["update"])
+	}
+}
+
+func schemaActionSymbol(action string) string {
+	if action == "create" {
+		return "+"
+	}
+	return "~"
+}
+
+func readWorkspaceSchemaFile(path string) (*workspaceSchema, error) {
+	path = strings.TrimSpace(path)
+	var (
+		raw []byte
+		err error
+	)
+	if path == "-" {
+		raw, err = io.ReadAll(os.Stdin)
+	} else {
+		path, err = expandPath(path)
+		if err != nil {
+			return nil, err
+		}
+		raw, err = os.ReadFile(path) //nolint:gosec // user-specified local file path
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// YAML is a superset of JSON, so one decoder handles both formats.
+	var schema workspaceSchema
+	dec := yaml.NewDecoder(bytes.NewReader(raw))
+	dec.KnownFields(true)
+	if err := dec.Decode(&schema); err != nil && !errors.Is(err, io.EOF) {
+		return nil, newUsageError(fmt.Errorf("invalid schema file: %w", err))
+	}
+	if schema.Version != 0 && schema.Version != workspaceSchemaVersion {
+		return nil, newUsageError(fmt.Errorf("unsupported schema file version %d", schema.Version))
+	}
+	if err := validateWorkspaceSchema(&schema); err != nil {
+		return nil, newUsageError(err)
+	}
+	return &schema, nil
+}
+
+func validateWorkspaceSchema(schema *workspaceSchema) error {
+	seen := map[string]bool{}
+	check := func(address string) error {
+		if seen[address] {
+			return fmt.Errorf("duplicate entry %s", address)
+		}
+		seen[address] = true
+		return nil
+	}
+	checkAttrs := func(parent string, attrs []schemaAttribute) error {
+		for _, attr := range attrs {
+			if attr.APISlug == "" || attr.Type == "" {
+				return fmt.Errorf("%s: attributes need api_slug and type", parent)
+			}
+			if err := check(parent + ".attributes." + attr.APISlug); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, object := range schema.Objects {
+		if object.APISlug == "" {
+			return errors.New("objects need an api_slug")
+		}
+		if err := check("objects." + object.APISlug); err != nil {
+			return err
+		}
+		if err := checkAttrs("objects."+object.APISlug, object.Attributes); err != nil {
+			return err
+		}
+	}
+	for _, list := range schema.Lists {
+		if list.APISlug == "" || list.ParentObject == "" {
+			return errors.New("lists need an api_slug and parent_object")
+		}
+		if err := check("lists." + list.APISlug); err != nil {
+			return err
+		}
+		if err := checkAttrs("lists."+list.APISlug, list.Attributes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func encodeWorkspaceSchema(schema *workspaceSchema, format string) ([]byte, error) {
+	if format == "json" {
+		b, err := json.MarshalIndent(schema, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(b, '\n'), nil
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(schema); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// diffSchemaFields compares current and desired values field by field. Empty
+// desired values are treated as "not managed" and never produce a change.
+func diffSchemaFields(fields []string, current []string, desired []string) ([]string, map[string]any) {
+	var changes []string
+	data := map[string]any{}
+	for i, field := range fields {
+		if desired[i] == "" || desired[i] == current[i] {
+			continue
+		}
+		changes = append(changes, fmt.Sprintf("%s: %q -> %q", field, current[i], desired[i]))
+		data[field] = desired[i]
+	}
+	return changes, data
+}
+
+func objectSlugsByID(objects []map[string]any) map[string]string {
+	out := make(map[string]string, len(objects))
+	for _, object := range objects {
+		if id := mapString(mapMap(object, "id"), "object_id"); id != "" {
+			out[id] = mapString(object, "api_slug")
+		}
+	}
+	return out
+}
+
+// listParentObject accepts the parent_object shapes returned by the API: a
+// slug, a list of slugs, or an object reference.
+func listParentObject(list map[string]any, objectSlugByID map[string]string) string {
+	switch v := list["parent_object"].(type) {
+	case string:
+		return v
+	case []any:
+		if len(v) > 0 {
+			return anyString(v[0])
+		}
+	case map[string]any:
+		if slug := mapString(v, "api_slug"); slug != "" {
+			return slug
+		}
+		return objectSlugByID[mapString(v, "object_id")]
+	}
+	return ""
+}
+
+func describeAttributeType(attr schemaAttribute) string {
+	if attr.IsMultiselect {
+		return attr.Type + " (multiselect)"
+	}
+	return attr.Type
+}
+
+func quoteSchemaKey(key string) string {
+	if strings.ContainsAny(key, " .\"") {
+		return fmt.Sprintf("%q", key)
+	}
+	return key
+}
+
+func nullableString(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func stringSet(values []string) map[string]bool {
+	out := make(map[string]bool, len(values))
+	for _, v := range values {
+		out[v] = true
+	}
+	return out
+}
+
+func isTruthy(v any) bool {
+	b, _ := v.(bool)
+	return b
+}
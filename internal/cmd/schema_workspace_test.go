@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func newSchemaTestServer(t *testing.T) (*httptest.Server, func() []string) {
+	t.Helper()
+	var (
+		mu     sync.Mutex
+		writes []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			b, _ := json.Marshal(body["data"])
+			mu.Lock()
+			writes = append(writes, r.Method+" "+r.URL.EscapedPath()+" "+string(b))
+			mu.Unlock()
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{}})
+			return
+		}
+		var data []map[string]any
+		switch r.URL.Path {
+		case "/v2/objects":
+			data = []map[string]any{
+				{"id": map[string]any{"object_id": "obj-people"}, "api_slug": "people", "singular_noun": "Person", "plural_noun": "People"},
+				{"id": map[string]any{"object_id": "obj-companies"}, "api_slug": "companies", "singular_noun": "Company", "plural_noun": "Companies"},
+			}
+		case "/v2/lists":
+			data = []map[string]any{
+				{"api_slug": "deals", "name": "Deals", "parent_object": []any{"companies"}, "workspace_access": "full-access"},
+			}
+		case "/v2/objects/people/attributes":
+			data = []map[string]any{
+				{"api_slug": "name", "title": "Name", "type": "personal-name", "is_system_attribute": true},
+				{"api_slug": "tier", "title": "Tier", "type": "select", "description": "Customer tier"},
+				{"api_slug": "employer", "title": "Employer", "type": "record-reference", "config": map[string]any{"record_reference": map[string]any{"allowed_object_ids": []any{"obj-companies"}}}},
+			}
+			if r.URL.Query().Get("show_archived") == "true" {
+				data = append(data, map[string]any{"api_slug": "nickname", "title": "Nickname", "type": "text", "is_archived": true})
+			}
+		case "/v2/objects/people/attributes/tier/options":
+			data = []map[string]any{{"title": "Gold"}, {"title": "Silver"}}
+			if r.URL.Query().Get("show_archived") == "true" {
+				data = append(data, map[string]any{"title": "Bronze", "is_archived": true})
+			}
+		case "/v2/objects/companies/attributes":
+			data = []map[string]any{}
+		case "/v2/lists/deals/attributes":
+			data = []map[string]any{
+				{"api_slug": "stage", "title": "Stage", "type": "status", "is_required": true},
+			}
+		case "/v2/lists/deals/attributes/stage/statuses":
+			data = []map[string]any{
+				{"title": "Lead", "celebration_enabled": false, "target_time_in_status": nil},
+				{"title": "Won", "celebration_enabled": true, "target_time_in_status": nil},
+			}
+			if r.URL.Query().Get("show_archived") == "true" {
+				data = append(data, map[string]any{"title": "Churned", "is_archived": true, "celebration_enabled": false, "target_time_in_status": nil})
+			}
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": data})
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), writes...)
+	}
+}
+
+func TestExecuteSchemaExport(t *testing.T) {
+	setupCLIEnv(t)
+	srv, _ := newSchemaTestServer(t)
+	t.Setenv("ATTIO_API_KEY", "env-key")
+	t.Setenv("ATTIO_BASE_URL", srv.URL)
+
+	stdout, stderr, err := captureExecute(t, []string{"schema", "export"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v\nstderr=%s", err, stderr)
+	}
+	for _, want := range []string{
+		"version: 1\n",
+		"- api_slug: people\n",
+		"      - api_slug: employer\n",
+		"              - companies\n",
+		"        options:\n          - title: Gold\n",
+		"parent_object: companies\n",
+		"        statuses:\n          - title: Lead\n          - title: Won\n            celebration_enabled: true\n",
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Fatalf("expected %q in export:\n%s", want, stdout)
+		}
+	}
+	if strings.Contains(stdout, "personal-name") {
+		t.Fatalf("system attributes should not be exported:\n%s", stdout)
+	}
+
+	out := filepath.Join(t.TempDir(), "schema.json")
+	if _, stderr, err := captureExecute(t, []string{"schema", "export", "--objects", "people", "--lists", "none", "-o", out}); err != nil {
+		t.Fatalf("unexpected error: %v\nstderr=%s", err, stderr)
+	}
+	b, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read export: %v", err)
+	}
+	var schema workspaceSchema
+	if err := json.Unmarshal(b, &schema); err != nil {
+		t.Fatalf("export must be JSON for .json output: %v\n%s", err, b)
+	}
+	if len(schema.Objects) != 1 || schema.Objects[0].APISlug != "people" || len(schema.Lists) != 0 {
+		t.Fatalf("unexpected filtered export: %+v", schema)
+	}
+}
+
+const schemaTestFile = `
+version: 1
+objects:
+  - api_slug: people
+    attributes:
+      - api_slug: name
+        title: Name
+        type: personal-name
+      - api_slug: nickname
+        title: Nickname
+        type: text
+      - api_slug: tier
+        title: Customer tier
+        type: select
+        options:
+          - title: Bronze
+          - title: Gold
+          - title: Platinum
+          - title: Silver
+            is_archived: true
+      - api_slug: linkedin
+        title: LinkedIn
+        type: text
+  - api_slug: projects
+    singular_noun: Project
+    plural_noun: Projects
+    attributes:
+      - api_slug: budget
+        title: Budget
+        type: currency
+        config:
+          currency:
+            default_currency_code: EUR
+lists:
+  - api_slug: deals
+    parent_object: companies
+    attributes:
+      - api_slug: stage
+        title: Stage
+        type: status
+        is_required: true
+        statuses:
+          - title: Won
+          - title: Lost
+          - title: Churned
+`
+
+func TestExecuteSchemaPlanAndApply(t *testing.T) {
+	setupCLIEnv(t)
+	srv, writes := newSchemaTestServer(t)
+	t.Setenv("ATTIO_API_KEY", "env-key")
+	t.Setenv("ATTIO_BASE_URL", srv.URL)
+
+	file := filepath.Join(t.TempDir(), "schema.yaml")
+	if err := os.WriteFile(file, []byte(schemaTestFile), 0o600); err != nil {
+		t.Fatalf("write schema file: %v", err)
+	}
+
+	stdout, stderr, err := captureExecute(t, []string{"schema", "plan", "-f", file})
+	if err != nil {
+		t.Fatalf("unexpected error: %v\nstderr=%s", err, stderr)
+	}
+	for _, want := range []string{
+		"  + object objects.projects\n",
+		"  ~ attribute objects.people.attributes.tier\n      title: \"Tier\" -> \"Customer tier\"\n",
+		"  + option objects.people.attributes.tier.options.Platinum\n",
+		"  ~ option objects.people.attributes.tier.options.Bronze\n      is_archived: true -> false\n",
+		"  ~ option objects.people.attributes.tier.options.Silver\n      is_archived: false -> true\n",
+		"  + attribute objects.people.attributes.linkedin\n",
+		"  + attribute objects.projects.attributes.budget\n",
+		"  ~ status lists.deals.attributes.stage.statuses.Won\n      celebration_enabled: true -> false\n",
+		"  + status lists.deals.attributes.stage.statuses.Lost\n",
+		"  ~ status lists.deals.attributes.stage.statuses.Churned\n      is_archived: true -> false\n",
+		"  ~ attribute objects.people.attributes.nickname\n      is_archived: true -> false\n",
+		"  ! objects.people.attributes.name: system attributes are managed by Attio and cannot be created or changed\n",
+		"Plan: 5 to create, 6 to update.",
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Fatalf("expected %q in plan:\n%s", want, stdout)
+		}
+	}
+	if strings.Index(stdout, "object objects.projects") > strings.Index(stdout, "attribute objects.people") {
+		t.Fatalf("objects must be created before attributes:\n%s", stdout)
+	}
+
+	stdout, stderr, err = captureExecute(t, []string{"--json", "--dry-run", "schema", "apply", "-f", file})
+	if err != nil {
+		t.Fatalf("unexpected error: %v\nstderr=%s", err, stderr)
+	}
+	if len(writes()) != 0 {
+		t.Fatalf("dry-run must not write, got %v", writes())
+	}
+	if !strings.Contains(stdout, `"action": "schema apply"`) || !strings.Contains(stdout, `"create": 5`) {
+		t.Fatalf("unexpected dry-run output: %s", stdout)
+	}
+
+	if _, stderr, err = captureExecute(t, []string{"schema", "apply", "-f", file}); err != nil {
+		t.Fatalf("unexpected error: %v\nstderr=%s", err, stderr)
+	}
+	got := writes()
+	want := []string{
+		`POST /v2/objects {"api_slug":"projects","plural_noun":"Projects","singular_noun":"Project"}`,
+		`PATCH /v2/objects/people/attributes/nickname {"is_archived":false}`,
+		`PATCH /v2/objects/people/attributes/tier {"title":"Customer tier"}`,
+		`PATCH /v2/objects/people/attributes/tier/options/Bronze {"is_archived":false}`,
+		`POST /v2/objects/people/attributes/tier/options {"title":"Platinum"}`,
+		`PATCH /v2/objects/people/attributes/tier/options/Silver {"is_archived":true}`,
+		`POST /v2/objects/people/attributes {"api_slug":"linkedin","config":{},"description":null,"is_multiselect":false,"is_required":false,"is_unique":false,"title":"LinkedIn","type":"text"}`,
+		`POST /v2/objects/projects/attributes {"api_slug":"budget","config":{"currency":{"default_currency_code":"EUR"}},"description":null,"is_multiselect":false,"is_required":false,"is_unique":false,"title":"Budget","type":"currency"}`,
+		`PATCH /v2/lists/deals/attributes/stage/statuses/Won {"celebration_enabled":false}`,
+		`POST /v2/lists/deals/attributes/stage/statuses {"celebration_enabled":false,"title":"Lost"}`,
+		`PATCH /v2/lists/deals/attributes/stage/statuses/Churned {"is_archived":false}`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected writes:\n%s", strings.Join(got, "\n"))
+	}
+}
+
+func TestExecuteSchemaPlanRejectsInvalidFile(t *testing.T) {
+	setupCLIEnv(t)
+	t.Setenv("ATTIO_API_KEY", "env-key")
+
+	file := filepath.Join(t.TempDir(), "schema.yaml")
+	if err := os.WriteFile(file, []byte("objects:\n  - api_slug: people\n    colour: red\n"), 0o600); err != nil {
+		t.Fatalf("write schema file: %v", err)
+	}
+	_, _, err := captureExecute(t, []string{"schema", "plan", "-f", file})
+	if ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected usage error for unknown field, got %v", err)
+	}
+}
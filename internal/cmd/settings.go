@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/failup-ventures/attio-cli/internal/config"
+)
+
+const (
+	defaultTimeout = "30s"
+
+	sourceFlag    = "flag"
+	sourceDefault = "default"
+)
+
+// resolveSettings fills the profile, output mode, timeout, color and command
+// allowlist that were not given as flags or environment variables from the
+// project config and then the profile's settings in the global config. It
+// returns where each effective setting came from and the write policy.
+func resolveSettings(kctx *kong.Context, flags *RootFlags, project *config.ProjectConfig) ([]config.SettingSource, writePolicy) {
+	explicit := map[string]bool{}
+	for _, p := range kctx.Path {
+		if p.Flag != nil {
+			explicit[p.Flag.Name] = true
+		}
+	}
+	if project == nil {
+		project = &config.ProjectConfig{}
+	}
+	var sources []config.SettingSource
+	add := func(setting string, value string, source string) {
+		sources = append(sources, config.SettingSource{Setting: setting, Value: value, Source: source})
+	}
+
+	configFile := "config.json"
+	if path, err := config.ConfigPath(); err == nil {
+		configFile = path
+	}
+	switch {
+	case explicit["profile"]:
+		add("profile", flags.Profile, sourceFlag)
+	case project.Profile != "":
+		flags.Profile = project.Profile
+		add("profile", flags.Profile, project.Path)
+	default:
+		flags.Profile = config.ResolveProfile("")
+		if flags.Profile == config.DefaultProfileName {
+			add("profile", flags.Profile, sourceDefault)
+		} else {
+			add("profile", flags.Profile, configFile)
+		}
+	}
+	profile := config.LoadProfile(flags.Profile)
+	profileSource := fmt.Sprintf("%s (profile %s)", configFile, flags.Profile)
+
+	switch {
+	case flags.JSON:
+		add("output", outputJSON, sourceFlag)
+	case flags.Plain:
+		add("output", outputPlain, sourceFlag)
+	case shouldAutoJSON(*flags):
+		flags.JSON = true
+		add("output", outputJSON, "env ATTIO_AUTO_JSON")
+	case project.Output != "":
+		setOutputMode(flags, project.Output)
+		add("output", project.Output, project.Path)
+	case strings.TrimSpace(profile.Output) != "":
+		output := strings.ToLower(strings.TrimSpace(profile.Output))
+		setOutputMode(flags, output)
+		add("output", output, profileSource)
+	default:
+		add("output", outputTable, sourceDefault)
+	}
+
+	switch {
+	case explicit["timeout"]:
+		add("timeout", flags.Timeout, sourceFlag)
+	case strings.TrimSpace(flags.Timeout) != "":
+		add("timeout", flags.Timeout, "env ATTIO_TIMEOUT")
+	case project.Timeout != "":
+		flags.Timeout = project.Timeout
+		add("timeout", flags.Timeout, project.Path)
+	case strings.TrimSpace(profile.Timeout) != "":
+		flags.Timeout = strings.TrimSpace(profile.Timeout)
+		add("timeout", flags.Timeout, profileSource)
+	default:
+		flags.Timeout = defaultTimeout
+		add("timeout", flags.Timeout, sourceDefault)
+	}
+
+	switch {
+	case strings.TrimSpace(flags.Color) != "":
+		add("color", flags.Color, sourceFlag)
+	case strings.TrimSpace(profile.Color) != "":
+		flags.Color = strings.TrimSpace(profile.Color)
+		add("color", flags.Color, profileSource)
+	default:
+		flags.Color = colorAuto
+		add("color", flags.Color, sourceDefault)
+	}
+
+	switch {
+	case explicit["enable-commands"]:
+		add("enable_commands", flags.EnableCommands, sourceFlag)
+	case strings.TrimSpace(flags.EnableCommands) != "":
+		add("enable_commands", flags.EnableCommands, "env ATTIO_ENABLE_COMMANDS")
+	case len(project.EnableCommands) > 0:
+		flags.EnableCommands = strings.Join(project.EnableCommands, ",")
+		add("enable_commands", flags.EnableCommands, project.Path)
+	}
+
+	// Policy settings only ever add restrictions: flags and the profile are
+	// combined rather than one overriding the other.
+	policy := writePolicy{Objects: profile.ObjectPolicy}
+	switch {
+	case explicit["read-only"]:
+		policy.ForceReadOnly = true
+		add("read_only", "true", sourceFlag)
+	case flags.ReadOnly:
+		policy.ForceReadOnly = true
+		add("read_only", "true", "env ATTIO_READ_ONLY")
+	case profile.ReadOnly:
+		flags.ReadOnly = true
+		add("read_only", "true", profileSource)
+	}
+	policy.ReadOnly = flags.ReadOnly
+	if denied := splitCommaList(flags.DisableCommands); len(denied) > 0 {
+		source := sourceFlag
+		if !explicit["disable-commands"] {
+			source = "env ATTIO_DISABLE_COMMANDS"
+		}
+		add("disable_commands", strings.Join(denied, ","), source)
+		policy.DisableCommands = append(policy.DisableCommands, denied...)
+	}
+	if len(profile.DisableCommands) > 0 {
+		add("disable_commands", strings.Join(profile.DisableCommands, ","), profileSource)
+		policy.DisableCommands = append(policy.DisableCommands, profile.DisableCommands...)
+	}
+	if len(profile.ObjectPolicy) > 0 {
+		add("object_policy", formatObjectPolicy(profile.ObjectPolicy), profileSource)
+	}
+
+	objects := make([]string, 0, len(project.Columns))
+	for object := range project.Columns {
+		objects = append(objects, object)
+	}
+	sort.Strings(objects)
+	for _, object := range objects {
+		add("columns."+object, strings.Join(project.Columns[object], ","), project.Path)
+	}
+	return sources, policy
+}
+
+func setOutputMode(flags *RootFlags, output string) {
+	switch output {
+	case outputJSON:
+		flags.JSON = true
+	case outputPlain:
+		flags.Plain = true
+	}
+}
+
+func projectColumns(project *config.ProjectConfig) map[string][]string {
+	if project == nil {
+		return nil
+	}
+	return project.Columns
+}
+
+// defaultColumns returns the project's default columns for object, if any.
+func defaultColumns(ctx context.Context, object string) []string {
+	return runtimeOptionsFromContext(ctx).Columns[strings.TrimSpace(object)]
+}
+
+func settingSources(ctx context.Context) []config.SettingSource {
+	return runtimeOptionsFromContext(ctx).Settings
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/failup-ventures/attio-cli/internal/config"
+	"github.com/failup-ventures/attio-cli/mockserver"
+)
+
+// chdirProject writes a project config file into a new directory tree and
+// changes into a subdirectory of it.
+func chdirProject(t *testing.T, name string, content string) string {
+	t.Helper()
+	root := t.TempDir()
+	sub := filepath.Join(root, "src")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	path := filepath.Join(root, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write project config: %v", err)
+	}
+	wd, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+	if err := os.Chdir(sub); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	found, err := config.FindProjectConfig(".")
+	if err != nil {
+		t.Fatalf("find project config: %v", err)
+	}
+	return found
+}
+
+func TestProjectConfigSettingsAndSources(t *testing.T) {
+	setupCLIEnv(t)
+	t.Setenv("ATTIO_TIMEOUT", "")
+	t.Setenv("ATTIO_ENABLE_COMMANDS", "")
+	t.Setenv("ATTIO_API_KEY", "env-key")
+	if err := config.SaveConfig(config.Config{Profiles: map[string]config.Profile{
+		"staging": {Timeout: "10s", Color: "never", Output: "plain"},
+	}}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	projectPath := chdirProject(t, ".attio.json", `{"profile":"staging","output":"json","timeout":"20s","enable_commands":["auth","records"],"columns":{"people":["name"]}}`)
+
+	sources := func(args ...string) map[string]config.SettingSource {
+		t.Helper()
+		stdout, stderr, err := captureExecute(t, append(args, "auth", "status"))
+		if err != nil {
+			t.Fatalf("auth status: %v stderr=%s", err, stderr)
+		}
+		var status config.Status
+		if err := json.Unmarshal([]byte(stdout), &status); err != nil {
+			t.Fatalf("expected JSON from the project output setting: %v\n%s", err, stdout)
+		}
+		out := map[string]config.SettingSource{}
+		for _, s := range status.Settings {
+			out[s.Setting] = s
+		}
+		if status.Profile != out["profile"].Value {
+			t.Fatalf("status profile %q does not match setting %+v", status.Profile, out["profile"])
+		}
+		return out
+	}
+
+	got := sources()
+	want := map[string][2]string{
+		"profile":         {"staging", projectPath},
+		"output":          {"json", projectPath},
+		"timeout":         {"20s", projectPath},
+		"color":           {"never", "(profile staging)"},
+		"enable_commands": {"auth,records", projectPath},
+		"columns.people":  {"name", projectPath},
+	}
+	for setting, w := range want {
+		if got[setting].Value != w[0] || !strings.Contains(got[setting].Source, w[1]) {
+			t.Fatalf("%s: expected %q from %q, got %+v", setting, w[0], w[1], got[setting])
+		}
+	}
+
+	t.Setenv("ATTIO_TIMEOUT", "15s")
+	got = sources("--profile", "default", "--enable-commands", "auth")
+	if got["timeout"].Value != "15s" || got["timeout"].Source != "env ATTIO_TIMEOUT" ||
+		got["profile"].Value != "default" || got["profile"].Source != sourceFlag ||
+		got["enable_commands"].Source != sourceFlag || got["color"].Source != sourceDefault {
+		t.Fatalf("expected flags and env to win: %+v", got)
+	}
+
+	// The project's allowlist applies.
+	if _, _, err := captureExecute(t, []string{"objects", "list"}); ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected objects to be blocked by the project allowlist, got %v", err)
+	}
+
+	stdout, _, err := captureExecute(t, []string{"--plain", "auth", "status"})
+	if err != nil || !strings.Contains(stdout, "SETTING") || !strings.Contains(stdout, projectPath) {
+		t.Fatalf("expected setting sources in text output: %v\n%s", err, stdout)
+	}
+}
+
+func TestProjectConfigDefaultColumns(t *testing.T) {
+	setupCLIEnv(t)
+	handler, err := mockserver.New(nil)
+	if err != nil {
+		t.Fatalf("new mock server: %v", err)
+	}
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+	t.Setenv("ATTIO_API_KEY", "mock")
+	t.Setenv("ATTIO_BASE_URL", srv.URL)
+	chdirProject(t, ".attio.yaml", "columns:\n  people: [name, email_addresses, name.last_name]\n")
+
+	if _, stderr, err := captureExecute(t, []string{"records", "create", "people", "--data", `{"values":{"name":"Ada Lovelace","email_addresses":["ada@example.com"]}}`}); err != nil {
+		t.Fatalf("create: %v stderr=%s", err, stderr)
+	}
+	stdout, stderr, err := captureExecute(t, []string{"records", "query", "people"})
+	if err != nil {
+		t.Fatalf("query: %v stderr=%s", err, stderr)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 2 || strings.Join(strings.Fields(lines[0]), " ") != "NAME EMAIL_ADDRESSES NAME.LAST_NAME" ||
+		strings.Join(strings.Fields(lines[1]), " ") != "Ada Lovelace ada@example.com Lovelace" {
+		t.Fatalf("unexpected table:\n%s", stdout)
+	}
+
+	stdout, stderr, err = captureExecute(t, []string{"records", "export", "people"})
+	if err != nil {
+		t.Fatalf("export: %v stderr=%s", err, stderr)
+	}
+	if !strings.HasPrefix(stdout, "name,email_addresses,name.last_name\nAda Lovelace,ada@example.com,Lovelace\n") {
+		t.Fatalf("expected export to use the project columns:\n%s", stdout)
+	}
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"os"
+
+	"github.com/failup-ventures/attio-cli/internal/outfmt"
+)
+
+// streamOffsetPages writes every item as an NDJSON line, flushing after each
+// page so output appears while later pages are still being fetched. fetch
+// receives absolute offsets beginning at start.
+func streamOffsetPages(ctx context.Context, cp *pageCheckpoint, limit int, maxPages int, start int, fetch func(offset int) ([]map[string]any, error)) error {
+	w := bufio.NewWriter(os.Stdout)
+	total := 0
+	err := eachOffsetPageFrom(ctx, cp, limit, maxPages, start, fetch, func(items []map[string]any) error {
+		total += len(items)
+		return writeNDJSONPage(ctx, w, items)
+	})
+	if err != nil {
+		return err
+	}
+	return maybeFailEmpty(ctx, total)
+}
+
+// streamCursorPages is the cursor-paginated counterpart of streamOffsetPages.
+func streamCursorPages(ctx context.Context, cp *pageCheckpoint, maxPages int, start string, fetch func(cursor string) ([]map[string]any, string, error)) error {
+	w := bufio.NewWriter(os.Stdout)
+	total := 0
+	err := eachCursorPageFrom(ctx, cp, maxPages, start, fetch, func(items []map[string]any) error {
+		total += len(items)
+		return writeNDJSONPage(ctx, w, items)
+	})
+	if err != nil {
+		return err
+	}
+	return maybeFailEmpty(ctx, total)
+}
+
+func writeNDJSONPage(ctx context.Context, w *bufio.Writer, items []map[string]any) error {
+	for _, item := range items {
+		if err := outfmt.WriteNDJSON(ctx, w, item); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestExecuteQueryStreamNDJSON(t *testing.T) {
+	setupCLIEnv(t)
+
+	pages := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/objects/people/records/query":
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			pages++
+			var data []map[string]any
+			switch body["offset"] {
+			case nil:
+				data = []map[string]any{{"id": map[string]any{"record_id": "r1"}}, {"id": map[string]any{"record_id": "r2"}}}
+			case float64(2):
+				data = []map[string]any{{"id": map[string]any{"record_id": "r3"}}}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": data})
+		case "/v2/lists/empty/entries/query":
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": []any{}})
+		case "/v2/meetings":
+			if r.URL.Query().Get("cursor") == "" {
+				_ = json.NewEncoder(w).Encode(map[string]any{"data": []map[string]any{{"title": "Kickoff"}}, "pagination": map[string]any{"next_cursor": "c2"}})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": []map[string]any{{"title": "Review"}}, "pagination": map[string]any{"next_cursor": nil}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	t.Setenv("ATTIO_API_KEY", "env-key")
+	t.Setenv("ATTIO_BASE_URL", srv.URL)
+
+	stdout, stderr, err := captureExecute(t, []string{"records", "query", "people", "--stream", "--limit", "2", "--select", "id.record_id"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v\nstderr=%s", err, stderr)
+	}
+	want := "{\"id.record_id\":\"r1\"}\n{\"id.record_id\":\"r2\"}\n{\"id.record_id\":\"r3\"}\n"
+	if stdout != want || pages != 2 {
+		t.Fatalf("unexpected stream output after %d pages:\n%s", pages, stdout)
+	}
+
+	pages = 0
+	stdout, _, err = captureExecute(t, []string{"--json", "records", "query", "people", "--ndjson", "--limit", "2", "--max-pages", "1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pages != 1 || stdout != "{\"id\":{\"record_id\":\"r1\"}}\n{\"id\":{\"record_id\":\"r2\"}}\n" {
+		t.Fatalf("expected --max-pages to cap streaming, got %d pages:\n%s", pages, stdout)
+	}
+
+	stdout, _, err = captureExecute(t, []string{"meetings", "list", "--stream", "--select", "title"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stdout != "{\"title\":\"Kickoff\"}\n{\"title\":\"Review\"}\n" {
+		t.Fatalf("unexpected meetings stream:\n%s", stdout)
+	}
+
+	stdout, _, err = captureExecute(t, []string{"--fail-empty", "entries", "query", "empty", "--stream"})
+	if ExitCode(err) != ExitCodeNoResult || stdout != "" {
+		t.Fatalf("expected --fail-empty exit code with no output, got %v / %q", err, stdout)
+	}
+}
@@ -0,0 +1,369 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/failup-ventures/attio-cli/internal/api"
+	"github.com/failup-ventures/attio-cli/internal/config"
+	"github.com/failup-ventures/attio-cli/internal/outfmt"
+	"github.com/failup-ventures/attio-cli/internal/ui"
+	"github.com/failup-ventures/attio-cli/internal/undo"
+)
+
+type UndoCmd struct {
+	OperationID string `arg:"" optional:"" name:"operation-id" help:"Operation to undo (default: the most recent one for the profile)"`
+	List        bool   `name:"list" help:"List the operations that can be undone"`
+}
+
+// undoResult reports what happened to one snapshotted record or entry.
+type undoResult struct {
+	Kind     string `json:"kind"`
+	Parent   string `json:"parent"`
+	ID       string `json:"id"`
+	NewID    string `json:"new_id,omitempty"`
+	Restored bool   `json:"restored"`
+	Message  string `json:"message"`
+}
+
+func (c *UndoCmd) Run(ctx context.Context, flags *RootFlags) error {
+	store, err := undoStore()
+	if err != nil {
+		return err
+	}
+	profile := config.ResolveProfile(flags.Profile)
+	if c.List {
+		ops, err := store.List(profile)
+		if err != nil {
+			return err
+		}
+		return writeUndoOperations(ctx, ops)
+	}
+
+	var op *undo.Operation
+	if id := strings.TrimSpace(c.OperationID); id != "" {
+		if op, err = store.Load(id); errors.Is(err, undo.ErrNotFound) {
+			return newUsageError(fmt.Errorf("no undo snapshot %q (see attio undo --list)", id))
+		} else if err != nil {
+			return err
+		}
+		if op.Profile != profile {
+			return newUsageError(fmt.Errorf("operation %s was made with profile %q; pass --profile %s", op.ID, op.Profile, op.Profile))
+		}
+	} else {
+		ops, err := store.List(profile)
+		if err != nil {
+			return err
+		}
+		if len(ops) == 0 {
+			return newUsageError(fmt.Errorf("nothing to undo for profile %q; turn on snapshots with: attio config set undo true", profile))
+		}
+		op = &ops[0]
+	}
+
+	if ok, err := maybeDryRun(ctx, "undo", op); ok || err != nil {
+		return err
+	}
+	client, err := requireClient(flags.Profile)
+	if err != nil {
+		return err
+	}
+
+	results := make([]undoResult, 0, len(op.Items))
+	var remaining []undo.Item
+	for _, item := range op.Items {
+		result, err := restoreItem(ctx, client, item)
+		if err != nil {
+			result.Message = err.Error()
+			remaining = append(remaining, item)
+		}
+		results = append(results, result)
+	}
+	if len(remaining) == 0 {
+		err = store.Remove(op.ID)
+	} else {
+		op.Items = remaining
+		err = store.Save(op)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := writeUndoResults(ctx, op, results); err != nil {
+		return err
+	}
+	if len(remaining) > 0 {
+		return fmt.Errorf("%d of %d items could not be restored; run attio undo %s to retry them", len(remaining), len(results), op.ID)
+	}
+	return nil
+}
+
+func undoStore() (undo.Store, error) {
+	dir, err := config.StateDir()
+	if err != nil {
+		return undo.Store{}, err
+	}
+	return undo.NewStore(filepath.Join(dir, "undo")), nil
+}
+
+// snapshotBefore fetches the records or entries a command is about to
+// change so the change can be undone. It returns nil when undo is off.
+func snapshotBefore(ctx context.Context, client *api.Client, profile string, kind string, action string, parent string, ids []string, keys []string) (*undo.Operation, error) {
+	if !config.ResolveUndo(profile) {
+		return nil, nil
+	}
+	items := make([]map[string]any, 0, len(ids))
+	for _, id := range ids {
+		var before map[string]any
+		var err error
+		if kind == undo.KindEntry {
+			before, err = client.GetEntry(ctx, parent, id)
+		} else {
+			before, err = client.GetRecord(ctx, parent, id)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("snapshot %s %s for undo: %w", kind, id, err)
+		}
+		items = append(items, before)
+	}
+	return snapshotFetched(profile, kind, action, parent, items, keys), nil
+}
+
+// snapshotFetched is snapshotBefore for records or entries the command has
+// already fetched, such as the matches of a bulk command.
+func snapshotFetched(profile string, kind string, action string, parent string, items []map[string]any, keys []string) *undo.Operation {
+	if !config.ResolveUndo(profile) {
+		return nil
+	}
+	op := &undo.Operation{Profile: config.ResolveProfile(profile), Command: currentCommandPath()}
+	for _, before := range items {
+		op.Items = append(op.Items, undo.Item{Kind: kind, Action: action, Parent: parent, ID: idString(before["id"]), Keys: keys, Before: before})
+	}
+	return op
+}
+
+// saveSnapshot stores the first n items of op once they have been changed.
+// A snapshot that cannot be saved only logs a warning, since the change has
+// already happened.
+func saveSnapshot(ctx context.Context, op *undo.Operation, n int) {
+	if op == nil || n == 0 {
+		return
+	}
+	op.Items = op.Items[:n]
+	store, err := undoStore()
+	if err == nil {
+		err = store.Save(op)
+	}
+	if err != nil {
+		slog.Warn("undo snapshot", "error", err)
+		return
+	}
+	msg := fmt.Sprintf("Saved undo snapshot %s; revert with: attio undo %s", op.ID, op.ID)
+	if u := ui.FromContext(ctx); u != nil {
+		u.Err().Printf("%s", msg)
+		return
+	}
+	_, _ = fmt.Fprintln(os.Stderr, msg)
+}
+
+// writtenKeys lists the attributes in a records or entries write payload.
+func writtenKeys(data map[string]any, field string) []string {
+	values := mapMap(data, field)
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func restoreItem(ctx context.Context, client *api.Client, item undo.Item) (undoResult, error) {
+	result := undoResult{Kind: item.Kind, Parent: item.Parent, ID: item.ID}
+	target, field := "objects", "values"
+	if item.Kind == undo.KindEntry {
+		target, field = "lists", "entry_values"
+	}
+	before := mapMap(item.Before, field)
+
+	if item.Action != undo.ActionDelete {
+		values, err := restoredKeyValues(ctx, client, target, item, before)
+		if err != nil {
+			return result, err
+		}
+		payload := map[string]any{field: values}
+		if item.Kind == undo.KindEntry {
+			_, err = client.ReplaceEntry(ctx, item.Parent, item.ID, payload)
+		} else {
+			_, err = client.ReplaceRecord(ctx, item.Parent, item.ID, payload)
+		}
+		if err != nil {
+			return result, err
+		}
+		result.Restored = true
+		result.Message = fmt.Sprintf("restored %d %s", len(values), pluralWord(len(values), "attribute", "attributes"))
+		return result, nil
+	}
+
+	// A deleted record or entry cannot come back under its old ID, so it is
+	// recreated from its writable values.
+	defs, err := listAttributeDefs(ctx, client, target, item.Parent)
+	if err != nil {
+		return result, err
+	}
+	values := map[string]any{}
+	for _, def := range defs {
+		if !def.Writable {
+			continue
+		}
+		if vals := writableValues(before[def.Slug]); len(vals) > 0 {
+			values[def.Slug] = vals
+		}
+	}
+	var created map[string]any
+	if item.Kind == undo.KindEntry {
+		created, err = client.CreateEntry(ctx, item.Parent, map[string]any{
+			"parent_record_id": mapString(item.Before, "parent_record_id"),
+			"parent_object":    mapString(item.Before, "parent_object"),
+			"entry_values":     values,
+		})
+	} else {
+		created, err = client.CreateRecord(ctx, item.Parent, map[string]any{"values": values})
+	}
+	if err != nil {
+		return result, err
+	}
+	result.Restored = true
+	result.NewID = idString(created["id"])
+	if item.Kind == undo.KindEntry {
+		result.Message = fmt.Sprintf("recreated as entry %s; the entry ID %s could not be restored", result.NewID, item.ID)
+	} else {
+		result.Message = fmt.Sprintf("recreated as record %s; the record ID %s, system attributes, and the record's notes, tasks, comments and list entries could not be restored", result.NewID, item.ID)
+	}
+	return result, nil
+}
+
+// restoredKeyValues builds a replace payload that writes back the values the
+// snapshotted attributes had. Attributes that were empty are cleared.
+func restoredKeyValues(ctx context.Context, client *api.Client, target string, item undo.Item, before map[string]any) (map[string]any, error) {
+	var slugs map[string]string
+	values := make(map[string]any, len(item.Keys))
+	for _, key := range item.Keys {
+		slug := key
+		if _, ok := before[key]; !ok && uuidPattern.MatchString(key) {
+			if slugs == nil {
+				defs, err := listAttributeDefs(ctx, client, target, item.Parent)
+				if err != nil {
+					return nil, err
+				}
+				slugs = make(map[string]string, len(defs))
+				for _, def := range defs {
+					slugs[def.ID] = def.Slug
+				}
+			}
+			slug = slugs[key]
+		}
+		values[key] = writableValues(before[slug])
+	}
+	return values, nil
+}
+
+// valueMetadata are the keys Attio adds to every returned attribute value.
+var valueMetadata = map[string]bool{"active_from": true, "active_until": true, "created_by_actor": true, "attribute_type": true}
+
+// writableValues converts attribute values as Attio returns them into values
+// it accepts on create and replace.
+func writableValues(raw any) []any {
+	items, _ := raw.([]any)
+	out := make([]any, 0, len(items))
+	for _, item := range items {
+		v, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		if w := writableValue(v); w != nil {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+func writableValue(v map[string]any) any {
+	switch mapString(v, "attribute_type") {
+	case "select":
+		return mapString(mapMap(mapMap(v, "option"), "id"), "option_id")
+	case "status":
+		return mapString(mapMap(mapMap(v, "status"), "id"), "status_id")
+	case "email-address":
+		return mapString(v, "email_address")
+	case "domain":
+		return mapString(v, "domain")
+	case "phone-number":
+		return map[string]any{"original_phone_number": mapString(v, "original_phone_number"), "country_code": v["country_code"]}
+	case "interaction":
+		return nil
+	}
+	if value, ok := v["value"]; ok {
+		return value
+	}
+	out := make(map[string]any, len(v))
+	for key, value := range v {
+		if !valueMetadata[key] {
+			out[key] = value
+		}
+	}
+	return out
+}
+
+func pluralWord(n int, singular string, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
+
+func writeUndoOperations(ctx context.Context, ops []undo.Operation) error {
+	if err := maybeFailEmpty(ctx, len(ops)); err != nil {
+		return err
+	}
+	if outfmt.IsJSON(ctx) {
+		if ops == nil {
+			ops = []undo.Operation{}
+		}
+		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{"data": ops})
+	}
+	w, done := tableWriter(ctx)
+	defer done()
+	_, _ = fmt.Fprintln(w, "ID\tTIME\tCOMMAND\tTARGETS")
+	for _, op := range ops {
+		targets := make([]string, 0, len(op.Items))
+		for _, item := range op.Items {
+			targets = append(targets, item.Parent+"/"+item.ID)
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", op.ID, op.Time.Local().Format(time.RFC3339), op.Command, strings.Join(targets, ","))
+	}
+	return nil
+}
+
+func writeUndoResults(ctx context.Context, op *undo.Operation, results []undoResult) error {
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{"data": map[string]any{
+			"operation_id": op.ID,
+			"command":      op.Command,
+			"results":      results,
+		}})
+	}
+	w, done := tableWriter(ctx)
+	defer done()
+	_, _ = fmt.Fprintln(w, "KIND\tPARENT\tID\tRESTORED\tMESSAGE")
+	for _, r := range results {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\n", r.Kind, r.Parent, r.ID, r.Restored, r.Message)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/failup-ventures/attio-cli/mockserver"
+)
+
+func TestUndoRestoresRecords(t *testing.T) {
+	setupCLIEnv(t)
+	handler, err := mockserver.New(nil)
+	if err != nil {
+		t.Fatalf("new mock server: %v", err)
+	}
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+	t.Setenv("ATTIO_API_KEY", "mock")
+	t.Setenv("ATTIO_BASE_URL", srv.URL)
+	t.Setenv("ATTIO_STATE_DIR", t.TempDir())
+
+	run := func(args ...string) string {
+		t.Helper()
+		stdout, stderr, err := captureExecute(t, args)
+		if err != nil {
+			t.Fatalf("%s: %v\nstderr=%s", strings.Join(args, " "), err, stderr)
+		}
+		return stdout
+	}
+	company := func(id string) (string, []string) {
+		t.Helper()
+		var out struct {
+			Data struct {
+				Values map[string][]map[string]any `json:"values"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal([]byte(run("--json", "records", "get", "companies", id)), &out); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		var categories []string
+		for _, v := range out.Data.Values["categories"] {
+			categories = append(categories, mapString(mapMap(v, "option"), "title"))
+		}
+		sort.Strings(categories)
+		description := ""
+		if d := out.Data.Values["description"]; len(d) > 0 {
+			description, _ = d[0]["value"].(string)
+		}
+		return description, categories
+	}
+
+	id := strings.TrimSpace(run("--id-only", "records", "create", "companies", "--data",
+		`{"values":{"name":"Acme","domains":["acme.com"],"categories":["B2B","SaaS"]}}`))
+
+	// Snapshots are opt-in.
+	run("records", "update", "companies", id, "--data", `{"values":{"description":"first"}}`)
+	if _, _, err := captureExecute(t, []string{"undo"}); ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected nothing to undo without the undo setting, got %v", err)
+	}
+	run("config", "set", "undo", "true")
+
+	_, stderr, err := captureExecute(t, []string{"records", "replace", "companies", id, "--data", `{"values":{"categories":["B2C"],"description":"second"}}`})
+	if err != nil || !strings.Contains(stderr, "attio undo ") {
+		t.Fatalf("expected a saved snapshot hint, got %v\n%s", err, stderr)
+	}
+	if desc, cats := company(id); desc != "second" || strings.Join(cats, ",") != "B2C" {
+		t.Fatalf("unexpected state after replace: %q %v", desc, cats)
+	}
+	run("undo")
+	if desc, cats := company(id); desc != "first" || strings.Join(cats, ",") != "B2B,SaaS" {
+		t.Fatalf("expected undo to restore the multiselect, got %q %v", desc, cats)
+	}
+
+	// Payloads may key attributes by UUID instead of slug.
+	var attr struct {
+		Data struct {
+			ID struct {
+				AttributeID string `json:"attribute_id"`
+			} `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(run("--json", "attributes", "get", "objects", "companies", "description")), &attr); err != nil || attr.Data.ID.AttributeID == "" {
+		t.Fatalf("decode attribute: %v", err)
+	}
+	run("records", "update", "companies", id, "--data", `{"values":{"`+attr.Data.ID.AttributeID+`":"third"}}`)
+	if desc, _ := company(id); desc != "third" {
+		t.Fatalf("expected the UUID-keyed update to apply, got %q", desc)
+	}
+	run("undo")
+	if desc, _ := company(id); desc != "first" {
+		t.Fatalf("expected undo of a UUID-keyed update to restore the value, got %q", desc)
+	}
+
+	run("--yes", "records", "delete", "companies", id)
+	listed := run("undo", "--list")
+	if !strings.Contains(listed, "records delete") || !strings.Contains(listed, "companies/"+id) {
+		t.Fatalf("unexpected undo list:\n%s", listed)
+	}
+	var undone struct {
+		Data struct {
+			Results []undoResult `json:"results"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(run("--json", "undo")), &undone); err != nil {
+		t.Fatalf("decode undo: %v", err)
+	}
+	if len(undone.Data.Results) != 1 || !undone.Data.Results[0].Restored || undone.Data.Results[0].NewID == "" ||
+		!strings.Contains(undone.Data.Results[0].Message, "could not be restored") {
+		t.Fatalf("unexpected undo result: %+v", undone.Data.Results)
+	}
+	if desc, cats := company(undone.Data.Results[0].NewID); desc != "first" || strings.Join(cats, ",") != "B2B,SaaS" {
+		t.Fatalf("expected the recreated record to keep its values, got %q %v", desc, cats)
+	}
+	if _, _, err := captureExecute(t, []string{"undo"}); ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected the snapshot to be used up, got %v", err)
+	}
+}
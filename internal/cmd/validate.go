@@ -0,0 +1,568 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/failup-ventures/attio-cli/internal/api"
+)
+
+// ValidationError lists every problem found in a --data payload before it
+// was sent.
+type ValidationError struct {
+	Problems []ValidationProblem
+}
+
+// ValidationProblem is one invalid part of a payload, located by a JSON path
+// such as $.values.email_addresses[1].
+type ValidationProblem struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (e *ValidationError) Error() string {
+	if e == nil {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "invalid data (%d %s):", len(e.Problems), pluralWord(len(e.Problems), "problem", "problems"))
+	for _, p := range e.Problems {
+		fmt.Fprintf(&b, "\n  %s: %s", p.Path, p.Message)
+	}
+	return b.String()
+}
+
+// validationMode selects which constraints apply: creates need every required
+// attribute, updates only check what they set, and asserts only check what
+// they set and skip uniqueness, since they may update an existing record
+// matched on a unique attribute.
+type validationMode string
+
+const (
+	validateCreate validationMode = "create"
+	validateUpdate validationMode = "update"
+	validateAssert validationMode = "assert"
+)
+
+// dataValidator checks record or entry payloads against the attributes of one
+// object or list. The schema and select options are loaded once and reused,
+// so bulk commands validate every payload with a single lookup, and with a
+// cache they are also reused across runs.
+type dataValidator struct {
+	client     *api.Client
+	target     string
+	identifier string
+	field      string
+	noun       string
+
+	defs    []attributeDef
+	byKey   map[string]attributeDef
+	options map[string][]map[string]any
+
+	cache *schemaCache
+	// fromCache is set once any listing in use came from the cache.
+	fromCache bool
+}
+
+func newRecordValidator(client *api.Client, object string) *dataValidator {
+	return &dataValidator{client: client, target: "objects", identifier: object, field: "values", noun: "record"}
+}
+
+func newEntryValidator(client *api.Client, list string) *dataValidator {
+	return &dataValidator{client: client, target: "lists", identifier: list, field: "entry_values", noun: "entry"}
+}
+
+// withCache makes the validator reuse the profile's cached listings.
+func (v *dataValidator) withCache(profile string) *dataValidator {
+	v.cache = openSchemaCache(profile, v.target, v.identifier)
+	return v
+}
+
+func (v *dataValidator) load(ctx context.Context) error {
+	if v.byKey != nil {
+		return nil
+	}
+	attrs, ok := v.cache.get("attributes")
+	if ok {
+		v.fromCache = true
+	} else {
+		var err error
+		if attrs, err = v.client.ListAttributes(ctx, v.target, v.identifier, false, 0, 0); err != nil {
+			return err
+		}
+		v.cache.put("attributes", attrs)
+	}
+	defs := attributeDefsFromList(attrs)
+	v.defs = defs
+	v.byKey = make(map[string]attributeDef, 2*len(defs))
+	v.options = map[string][]map[string]any{}
+	for _, def := range defs {
+		v.byKey[def.Slug] = def
+		if def.ID != "" {
+			v.byKey[def.ID] = def
+		}
+	}
+	return nil
+}
+
+// Validate returns the problems in data. id is the record or entry being
+// updated, which uniqueness checks ignore. An error means the schema could
+// not be loaded.
+func (v *dataValidator) Validate(ctx context.Context, data map[string]any, mode validationMode, id string) ([]ValidationProblem, error) {
+	problems, err := v.validate(ctx, data, mode, id)
+	if err == nil && len(problems) > 0 && v.fromCache {
+		// The cached schema may predate an attribute or option data uses.
+		v.cache.drop()
+		v.byKey, v.fromCache = nil, false
+		problems, err = v.validate(ctx, data, mode, id)
+	}
+	return problems, err
+}
+
+func (v *dataValidator) validate(ctx context.Context, data map[string]any, mode validationMode, id string) ([]ValidationProblem, error) {
+	if err := v.load(ctx); err != nil {
+		return nil, err
+	}
+	var problems []ValidationProblem
+	add := func(path string, format string, args ...any) {
+		problems = append(problems, ValidationProblem{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	root := "$." + v.field
+	raw, present := data[v.field]
+	values, ok := raw.(map[string]any)
+	switch {
+	case !present && v.target == "objects":
+		add("$", "missing %q; attribute values go under {\"values\": {...}}", v.field)
+	case present && !ok:
+		add(root, "expected an object of attribute slugs to values")
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	given := map[string]string{}
+	for _, key := range keys {
+		path := root + jsonPathKey(key)
+		def, ok := v.byKey[key]
+		if !ok {
+			msg := fmt.Sprintf("unknown attribute %q on %s", key, v.identifier)
+			if slug := closestSlug(key, v.defs); slug != "" {
+				msg += fmt.Sprintf("; did you mean %q?", slug)
+			}
+			add(path, "%s", msg)
+			continue
+		}
+		if other, dup := given[def.ID]; dup {
+			add(path, "attribute %q is also set as %q", def.Slug, other)
+			continue
+		}
+		given[def.ID] = key
+		if !def.Writable {
+			add(path, "attribute %q is read-only", def.Slug)
+			continue
+		}
+		if def.Required && isEmptyValue(values[key]) {
+			add(path, "required attribute %q cannot be empty", def.Slug)
+			continue
+		}
+		problems = append(problems, v.checkValues(ctx, def, values[key], path)...)
+	}
+
+	if mode == validateCreate {
+		for _, def := range v.defs {
+			if _, ok := given[def.ID]; !ok && def.Required && def.Writable {
+				add(root+jsonPathKey(def.Slug), "required attribute %q is missing", def.Slug)
+			}
+		}
+	}
+	if mode != validateAssert {
+		for _, key := range keys {
+			if def, ok := v.byKey[key]; ok && def.Unique && def.Writable {
+				problems = append(problems, v.checkUnique(ctx, def, values[key], root+jsonPathKey(key), id)...)
+			}
+		}
+	}
+	return problems, nil
+}
+
+func (v *dataValidator) checkValues(ctx context.Context, def attributeDef, raw any, path string) []ValidationProblem {
+	items, isList := raw.([]any)
+	if !isList {
+		items = []any{raw}
+	}
+	var problems []ValidationProblem
+	if len(items) > 1 && !def.Multiselect {
+		problems = append(problems, ValidationProblem{Path: path, Message: fmt.Sprintf("attribute %q takes a single value, got %d (it is not multiselect)", def.Slug, len(items))})
+	}
+	for i, item := range items {
+		itemPath := path
+		if isList {
+			itemPath = fmt.Sprintf("%s[%d]", path, i)
+		}
+		sub, msg := checkValueShape(def, item)
+		if msg == "" && (def.Type == "select" || def.Type == "status") {
+			msg = v.checkOption(ctx, def, optionRef(def, item))
+		}
+		if msg != "" {
+			problems = append(problems, ValidationProblem{Path: itemPath + sub, Message: msg})
+		}
+	}
+	return problems
+}
+
+// valueProperty is the property that holds the value in the object form of
+// each attribute type, e.g. {"email_address": "ada@example.com"}.
+var valueProperty = map[string]string{
+	"currency":      "currency_value",
+	"domain":        "domain",
+	"email-address": "email_address",
+	"phone-number":  "original_phone_number",
+	"select":        "option",
+	"status":        "status",
+}
+
+// checkValueShape checks one input value against the forms Attio accepts for
+// the attribute type. It returns the path below the value that is wrong, if
+// any, and a message; an empty message means the value is fine.
+func checkValueShape(def attributeDef, item any) (string, string) {
+	obj, isObj := item.(map[string]any)
+	prop := "value"
+	if p, ok := valueProperty[def.Type]; ok {
+		prop = p
+	}
+	scalar, sub := item, ""
+	if isObj {
+		scalar, sub = obj[prop], "."+prop
+	}
+	expected := func(what string) (string, string) {
+		return sub, fmt.Sprintf("expected %s or {%q: ...} for %s attribute %q, got %s", what, prop, def.Type, def.Slug, jsonKind(scalar))
+	}
+
+	switch def.Type {
+	case "text":
+		if _, ok := scalar.(string); !ok {
+			return expected("a string")
+		}
+	case "select", "status", "phone-number":
+		if s, ok := scalar.(string); !ok || strings.TrimSpace(s) == "" {
+			return expected("a non-empty string")
+		}
+	case "number":
+		if _, ok := numberValue(scalar); !ok {
+			return expected("a number")
+		}
+	case "rating":
+		n, ok := numberValue(scalar)
+		if !ok || n != math.Trunc(n) || n < 0 || n > 5 {
+			return expected("a whole number from 0 to 5")
+		}
+	case "checkbox":
+		if _, ok := scalar.(bool); !ok {
+			return expected("true or false")
+		}
+	case "currency":
+		if _, ok := numberValue(scalar); !ok {
+			return expected("a number")
+		}
+		if code, ok := obj["currency_code"]; ok {
+			s, _ := code.(string)
+			switch {
+			case len(s) != 3 || strings.ToUpper(s) != s:
+				return ".currency_code", fmt.Sprintf("expected a 3-letter ISO 4217 currency code, got %s", jsonKind(code))
+			case def.CurrencyCode != "" && s != def.CurrencyCode:
+				return ".currency_code", fmt.Sprintf("currency %s does not match attribute currency %s", s, def.CurrencyCode)
+			}
+		}
+	case "date":
+		s, ok := scalar.(string)
+		if !ok || len(s) < 10 {
+			return expected("a YYYY-MM-DD date")
+		}
+		if _, err := time.Parse("2006-01-02", s[:10]); err != nil {
+			return expected("a YYYY-MM-DD date")
+		}
+	case "timestamp":
+		s, _ := scalar.(string)
+		if _, err := time.Parse(time.RFC3339Nano, s); err != nil {
+			if _, err := time.Parse("2006-01-02", s); err != nil {
+				return expected("an RFC 3339 timestamp")
+			}
+		}
+	case "domain":
+		if s, ok := scalar.(string); !ok || !strings.Contains(s, ".") || strings.ContainsAny(s, " @") {
+			return expected("a domain such as example.com")
+		}
+	case "email-address":
+		s, _ := scalar.(string)
+		if local, domain, ok := strings.Cut(s, "@"); !ok || local == "" || !strings.Contains(domain, ".") {
+			return expected("an email address")
+		}
+	case "personal-name":
+		if s, ok := item.(string); ok && strings.TrimSpace(s) != "" {
+			return "", ""
+		}
+		for _, key := range []string{"first_name", "last_name", "full_name"} {
+			if s, ok := obj[key].(string); ok && s != "" {
+				return "", ""
+			}
+		}
+		return "", fmt.Sprintf("expected a name string or an object with first_name, last_name or full_name for attribute %q", def.Slug)
+	case "record-reference":
+		if s, ok := item.(string); ok {
+			if !uuidPattern.MatchString(s) {
+				return "", fmt.Sprintf("expected a record ID or {\"target_object\": ..., \"target_record_id\": ...} for attribute %q, got %q", def.Slug, s)
+			}
+			return "", ""
+		}
+		if !isObj {
+			return "", fmt.Sprintf("expected a record reference object for attribute %q, got %s", def.Slug, jsonKind(item))
+		}
+		if s, _ := obj["target_object"].(string); s == "" {
+			return ".target_object", "missing the object of the referenced record"
+		}
+		if id, ok := obj["target_record_id"]; ok {
+			if s, _ := id.(string); !uuidPattern.MatchString(s) {
+				return ".target_record_id", fmt.Sprintf("expected a record ID, got %s", jsonKind(id))
+			}
+		} else if len(obj) < 2 {
+			return "", "expected target_record_id or a matching attribute such as domains or email_addresses"
+		}
+	case "actor-reference":
+		if s, ok := item.(string); ok && s != "" {
+			return "", ""
+		}
+		if _, ok := obj["referenced_actor_id"].(string); ok {
+			return "", ""
+		}
+		if _, ok := obj["workspace_member_email_address"].(string); ok {
+			return "", ""
+		}
+		return "", fmt.Sprintf("expected a workspace member ID, referenced_actor_id or workspace_member_email_address for attribute %q", def.Slug)
+	}
+	return "", ""
+}
+
+func optionRef(def attributeDef, item any) string {
+	if obj, ok := item.(map[string]any); ok {
+		return mapString(obj, valueProperty[def.Type])
+	}
+	s, _ := item.(string)
+	return s
+}
+
+// checkOption reports a select option or status that does not exist or is
+// archived. Options that cannot be listed are not checked.
+func (v *dataValidator) checkOption(ctx context.Context, def attributeDef, ref string) string {
+	options, ok := v.options[def.ID]
+	if !ok {
+		key := def.Type + "/" + def.ID
+		if options, ok = v.cache.get(key); ok {
+			v.fromCache = true
+		} else {
+			var err error
+			if def.Type == "status" {
+				options, err = v.client.ListStatuses(ctx, v.target, v.identifier, def.Slug, true)
+			} else {
+				options, err = v.client.ListSelectOptions(ctx, v.target, v.identifier, def.Slug, true)
+			}
+			if err != nil {
+				slog.Debug("skipping option validation", "attribute", def.Slug, "error", err)
+			} else {
+				v.cache.put(key, options)
+			}
+		}
+		v.options[def.ID] = options
+	}
+	if len(options) == 0 {
+		return ""
+	}
+
+	kind, idKey := "option", "option_id"
+	if def.Type == "status" {
+		kind, idKey = "status", "status_id"
+	}
+	var titles []string
+	suggestion := ""
+	for _, opt := range options {
+		title := mapString(opt, "title")
+		if title == ref || mapString(mapMap(opt, "id"), idKey) == ref {
+			if archived, _ := opt["is_archived"].(bool); archived {
+				return fmt.Sprintf("%s %q of attribute %q is archived", kind, title, def.Slug)
+			}
+			return ""
+		}
+		if archived, _ := opt["is_archived"].(bool); !archived {
+			titles = append(titles, fmt.Sprintf("%q", title))
+			if strings.EqualFold(title, ref) {
+				suggestion = title
+			}
+		}
+	}
+	if suggestion != "" {
+		return fmt.Sprintf("unknown %s %q for attribute %q; did you mean %q?", kind, ref, def.Slug, suggestion)
+	}
+	if len(titles) > 10 {
+		titles = append(titles[:10], "...")
+	}
+	return fmt.Sprintf("unknown %s %q for attribute %q; expected one of %s", kind, ref, def.Slug, strings.Join(titles, ", "))
+}
+
+// checkUnique looks up records or entries that already have the values given
+// for a unique attribute.
+func (v *dataValidator) checkUnique(ctx context.Context, def attributeDef, raw any, path string, id string) []ValidationProblem {
+	items, isList := raw.([]any)
+	if !isList {
+		items = []any{raw}
+	}
+	var problems []ValidationProblem
+	for i, item := range items {
+		value := item
+		if obj, ok := item.(map[string]any); ok {
+			prop := valueProperty[def.Type]
+			if prop == "" {
+				prop = "value"
+			}
+			value = obj[prop]
+		}
+		if s, ok := value.(string); !ok || s == "" {
+			if _, ok := value.(float64); !ok {
+				continue
+			}
+		}
+		filter := map[string]any{def.Slug: value}
+		var matches []map[string]any
+		var err error
+		if v.target == "lists" {
+			matches, err = v.client.QueryEntries(ctx, v.identifier, filter, nil, 2, 0)
+		} else {
+			matches, err = v.client.QueryRecords(ctx, v.identifier, filter, nil, 2, 0)
+		}
+		if err != nil {
+			slog.Debug("skipping uniqueness validation", "attribute", def.Slug, "error", err)
+			return problems
+		}
+		for _, match := range matches {
+			if other := idString(match["id"]); other != "" && other != id {
+				itemPath := path
+				if isList {
+					itemPath = fmt.Sprintf("%s[%d]", path, i)
+				}
+				problems = append(problems, ValidationProblem{Path: itemPath, Message: fmt.Sprintf("%s is already used by %s %s; attribute %q is unique", jsonKind(value), v.noun, other, def.Slug)})
+				break
+			}
+		}
+	}
+	return problems
+}
+
+// validateWriteData checks a --data payload before it is written, unless
+// --no-validate is set. When the schema cannot be loaded the check is skipped
+// and the API reports any problems itself.
+func validateWriteData(ctx context.Context, flags *RootFlags, v *dataValidator, data map[string]any, mode validationMode, id string) error {
+	if flags.NoValidate {
+		return nil
+	}
+	problems, err := v.withCache(flags.Profile).Validate(ctx, data, mode, id)
+	if err != nil {
+		slog.Debug("skipping schema validation", "target", v.identifier, "error", err)
+		return nil
+	}
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+func isEmptyValue(raw any) bool {
+	switch t := raw.(type) {
+	case nil:
+		return true
+	case []any:
+		return len(t) == 0
+	case string:
+		return strings.TrimSpace(t) == ""
+	}
+	return false
+}
+
+// numberValue returns v as a float64. Decoded JSON holds float64, but values
+// built from --set flags may hold Go integers.
+func numberValue(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+func jsonKind(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return fmt.Sprintf("%q", t)
+	case float64, bool:
+		return fmt.Sprint(t)
+	case []any:
+		return "an array"
+	case map[string]any:
+		return "an object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+var jsonPathIdent = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func jsonPathKey(key string) string {
+	if jsonPathIdent.MatchString(key) {
+		return "." + key
+	}
+	return fmt.Sprintf("[%q]", key)
+}
+
+// closestSlug suggests the attribute slug nearest to key, if any is close
+// enough to be a typo.
+func closestSlug(key string, defs []attributeDef) string {
+	key = strings.ToLower(key)
+	best, bestDist := "", len(key)/3+2
+	for _, def := range defs {
+		if d := editDistance(key, strings.ToLower(def.Slug)); d < bestDist {
+			best, bestDist = def.Slug, d
+		}
+	}
+	return best
+}
+
+func editDistance(a string, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/failup-ventures/attio-cli/mockserver"
+)
+
+func TestValidateRecordData(t *testing.T) {
+	setupCLIEnv(t)
+	handler, err := mockserver.New(nil)
+	if err != nil {
+		t.Fatalf("new mock server: %v", err)
+	}
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+	t.Setenv("ATTIO_API_KEY", "mock")
+	t.Setenv("ATTIO_BASE_URL", srv.URL)
+
+	problems := func(args ...string) map[string]string {
+		t.Helper()
+		_, stderr, err := captureExecute(t, append([]string{"--json"}, args...))
+		if ExitCode(err) != ExitCodeUsage {
+			t.Fatalf("%s: expected a validation error, got %v\n%s", strings.Join(args, " "), err, stderr)
+		}
+		var out struct {
+			Error struct {
+				Kind     string              `json:"kind"`
+				Problems []ValidationProblem `json:"problems"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal([]byte(stderr), &out); err != nil || out.Error.Kind != "validation" {
+			t.Fatalf("unexpected error output: %v\n%s", err, stderr)
+		}
+		byPath := map[string]string{}
+		for _, p := range out.Error.Problems {
+			byPath[p.Path] = p.Message
+		}
+		return byPath
+	}
+	expect := func(got map[string]string, want map[string]string) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Fatalf("expected %d problems, got %v", len(want), got)
+		}
+		for path, fragment := range want {
+			if !strings.Contains(got[path], fragment) {
+				t.Fatalf("expected %s to mention %q, got %v", path, fragment, got)
+			}
+		}
+	}
+
+	expect(problems("records", "validate", "companies", "--data",
+		`{"values":{"nmae":"Acme","domains":"acme","categories":["B2B","saas","Nope"],"description":5,"team":"ada"}}`), map[string]string{
+		"$.values.nmae":          `did you mean "name"?`,
+		"$.values.domains":       "expected a domain",
+		"$.values.categories[1]": `did you mean "SaaS"?`,
+		"$.values.categories[2]": `expected one of "B2B", "B2C", "SaaS"`,
+		"$.values.description":   "expected a string",
+		"$.values.team":          "expected a record ID",
+	})
+	expect(problems("records", "validate", "deals", "--data",
+		`{"values":{"value":{"currency_value":"10","currency_code":"EUR"},"owner":7}}`), map[string]string{
+		"$.values.name":                 `required attribute "name" is missing`,
+		"$.values.stage":                `required attribute "stage" is missing`,
+		"$.values.value.currency_value": "expected a number",
+		"$.values.owner":                "workspace member",
+	})
+	expect(problems("records", "validate", "deals", "--mode", "update", "--data",
+		`{"values":{"stage":"Won","name":""}}`), map[string]string{
+		"$.values.stage": `unknown status "Won"`,
+		"$.values.name":  "cannot be empty",
+	})
+
+	// An assert may update an existing record, so it does not need every
+	// required attribute.
+	stdout, stderr, err := captureExecute(t, []string{"--json", "records", "validate", "deals", "--mode", "assert", "--data", `{"values":{"name":"Big deal"}}`})
+	if err != nil || !strings.Contains(stdout, `"valid": true`) {
+		t.Fatalf("expected assert data without stage to be valid, got %v\n%s\n%s", err, stdout, stderr)
+	}
+
+	// Writes are checked before anything is sent.
+	expect(problems("records", "create", "people", "--data", `{"values":{"job_title":["CTO","CEO"]}}`), map[string]string{
+		"$.values.job_title": "takes a single value, got 2",
+	})
+	stdout, stderr, err = captureExecute(t, []string{"--id-only", "records", "create", "companies", "--data", `{"values":{"name":"Acme","domains":["acme.com"],"categories":["SaaS"]}}`})
+	if err != nil {
+		t.Fatalf("create: %v\n%s", err, stderr)
+	}
+	acme := strings.TrimSpace(stdout)
+	expect(problems("records", "create", "companies", "--data", `{"values":{"domains":["acme.com"]}}`), map[string]string{
+		"$.values.domains[0]": "already used by record " + acme,
+	})
+	if _, stderr, err := captureExecute(t, []string{"records", "update", "companies", acme, "--data", `{"values":{"domains":["acme.com"],"stage":"x"}}`}); ExitCode(err) != ExitCodeUsage || strings.Contains(stderr, "already used") {
+		t.Fatalf("expected only the unknown attribute to be reported for the record's own domain, got %v\n%s", err, stderr)
+	}
+
+	stdout, stderr, err = captureExecute(t, []string{"--json", "records", "validate", "companies", "--mode", "update", "--record-id", acme, "--data", `{"values":{"domains":[{"domain":"acme.com"}]}}`})
+	if err != nil || !strings.Contains(stdout, `"valid": true`) {
+		t.Fatalf("expected valid data, got %v\n%s\n%s", err, stdout, stderr)
+	}
+
+	// Values built from --set pass the same checks as decoded JSON.
+	if _, stderr, err := captureExecute(t, []string{"attributes", "create", "objects", "companies", "--data", `{"api_slug":"fit","title":"Fit","type":"rating"}`}); err != nil {
+		t.Fatalf("create attribute: %v\n%s", err, stderr)
+	}
+	if _, stderr, err := captureExecute(t, []string{"records", "update", "companies", acme, "--set", "fit=4"}); err != nil {
+		t.Fatalf("expected --set rating=4 to validate, got %v\n%s", err, stderr)
+	}
+	expect(problems("records", "update", "companies", acme, "--data", `{"values":{"fit":4.5}}`), map[string]string{
+		"$.values.fit": "a whole number from 0 to 5",
+	})
+
+	// --no-validate leaves the checks to the API.
+	_, stderr, err = captureExecute(t, []string{"--json", "--no-validate", "records", "create", "companies", "--data", `{"values":{"nmae":"Acme"}}`})
+	if err == nil || !strings.Contains(stderr, `"kind": "api"`) {
+		t.Fatalf("expected the API to reject the payload, got %v\n%s", err, stderr)
+	}
+}
+
+func TestValidateSchemaCache(t *testing.T) {
+	setupCLIEnv(t)
+	handler, err := mockserver.New(nil)
+	if err != nil {
+		t.Fatalf("new mock server: %v", err)
+	}
+	var listings atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && r.URL.Path == "/v2/objects/companies/attributes" {
+			listings.Add(1)
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+	t.Setenv("ATTIO_API_KEY", "mock")
+	t.Setenv("ATTIO_BASE_URL", srv.URL)
+
+	validate := func(data string) {
+		t.Helper()
+		if _, stderr, err := captureExecute(t, []string{"records", "validate", "companies", "--mode", "update", "--data", data}); err != nil {
+			t.Fatalf("validate %s: %v\n%s", data, err, stderr)
+		}
+	}
+
+	validate(`{"values":{"categories":["SaaS"]}}`)
+	validate(`{"values":{"description":"x"}}`)
+	if n := listings.Load(); n != 1 {
+		t.Fatalf("expected the attributes to be listed once, got %d", n)
+	}
+
+	// An attribute created after the listing was cached is found by listing
+	// again instead of being reported as unknown.
+	if _, stderr, err := captureExecute(t, []string{"attributes", "create", "objects", "companies", "--data", `{"api_slug":"tier","title":"Tier","type":"text"}`}); err != nil {
+		t.Fatalf("create attribute: %v\n%s", err, stderr)
+	}
+	validate(`{"values":{"tier":"gold"}}`)
+	if n := listings.Load(); n != 2 {
+		t.Fatalf("expected a stale cache to be listed again, got %d listings", n)
+	}
+
+	t.Setenv("ATTIO_SCHEMA_CACHE_TTL", "0")
+	validate(`{"values":{"tier":"gold"}}`)
+	if n := listings.Load(); n != 3 {
+		t.Fatalf("expected ATTIO_SCHEMA_CACHE_TTL=0 to skip the cache, got %d listings", n)
+	}
+}
@@ -0,0 +1,334 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/failup-ventures/attio-cli/internal/api"
+)
+
+// ValueFlags are the --set, --add and --remove flags of records and entries
+// writes. Each takes attr=value, where the value is a plain string converted
+// to the attribute's type.
+type ValueFlags struct {
+	Set    []string `name:"set" sep:"none" placeholder:"ATTR=VALUE" help:"Set an attribute from a plain value (repeatable; multiselect values split on ';', empty clears)"`
+	Add    []string `name:"add" sep:"none" placeholder:"ATTR=VALUE" help:"Add a value to a multiselect attribute (repeatable)"`
+	Remove []string `name:"remove" sep:"none" placeholder:"ATTR=VALUE" help:"Remove a value from a multiselect attribute (repeatable; needs an existing record or entry). An update with --remove is sent as a replace (PUT) that keeps the other multiselect values"`
+}
+
+// updatePreview is the --dry-run payload of an update, noting when --remove
+// turns it into a replace.
+func updatePreview(preview map[string]any, replace bool) map[string]any {
+	preview["method"] = "PATCH"
+	if replace {
+		preview["method"] = "PUT"
+		preview["note"] = "--remove sends the update as a replace (PUT) with the current values of each multiselect attribute in the payload"
+	}
+	return preview
+}
+
+// valueWrite is how a command writes the values it sends.
+type valueWrite int
+
+const (
+	// valueWriteCreate covers create and assert: there are no current values.
+	valueWriteCreate valueWrite = iota
+	// valueWriteUpdate appends to multiselect attributes (PATCH).
+	valueWriteUpdate
+	// valueWriteReplace overwrites multiselect attributes (PUT).
+	valueWriteReplace
+)
+
+func (f ValueFlags) empty() bool {
+	return len(f.Set) == 0 && len(f.Add) == 0 && len(f.Remove) == 0
+}
+
+// valueTarget is the object or list, and the record or entry, that a write
+// with value flags goes to.
+type valueTarget struct {
+	client     *api.Client
+	target     string
+	identifier string
+	id         string
+}
+
+func recordValueTarget(client *api.Client, object string, recordID string) valueTarget {
+	return valueTarget{client: client, target: "objects", identifier: object, id: recordID}
+}
+
+func entryValueTarget(client *api.Client, list string, entryID string) valueTarget {
+	return valueTarget{client: client, target: "lists", identifier: list, id: entryID}
+}
+
+func (t valueTarget) field() string {
+	if t.target == "lists" {
+		return "entry_values"
+	}
+	return "values"
+}
+
+func (t valueTarget) noun() string {
+	if t.target == "lists" {
+		return "entry"
+	}
+	return "record"
+}
+
+func (t valueTarget) current(ctx context.Context) (map[string]any, error) {
+	var (
+		item map[string]any
+		err  error
+	)
+	if t.target == "lists" {
+		item, err = t.client.GetEntry(ctx, t.identifier, t.id)
+	} else {
+		item, err = t.client.GetRecord(ctx, t.identifier, t.id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return mapMap(item, t.field()), nil
+}
+
+type valueFlag struct {
+	def attributeDef
+	raw string
+}
+
+// payload reads --data and overlays the value flags on its values, the way
+// notes and tasks overlay named flags. An update that removes a multiselect
+// value cannot be sent as a PATCH, which only appends, so payload then
+// returns replace=true with every multiselect attribute in the payload
+// expanded to its full new list.
+func (f ValueFlags) payload(ctx context.Context, t valueTarget, dataInput string, mode valueWrite) (map[string]any, bool, error) {
+	data := map[string]any{}
+	if strings.TrimSpace(dataInput) != "" {
+		parsed, err := readJSONObjectInput(dataInput)
+		if err != nil {
+			return nil, false, err
+		}
+		data = parsed
+	} else if f.empty() {
+		return nil, false, newUsageError(errors.New("pass --data or at least one of --set, --add and --remove"))
+	}
+	if f.empty() {
+		return data, false, nil
+	}
+	if len(f.Remove) > 0 && mode == valueWriteCreate {
+		return nil, false, newUsageError(errors.New("--remove needs an existing record or entry; use it with update or replace"))
+	}
+
+	defs, err := listAttributeDefs(ctx, t.client, t.target, t.identifier)
+	if err != nil {
+		return nil, false, err
+	}
+	sets, err := parseValueFlags("--set", f.Set, defs, false)
+	if err != nil {
+		return nil, false, err
+	}
+	adds, err := parseValueFlags("--add", f.Add, defs, true)
+	if err != nil {
+		return nil, false, err
+	}
+	removes, err := parseValueFlags("--remove", f.Remove, defs, true)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, flag := range append(append([]valueFlag{}, adds...), removes...) {
+		for _, set := range sets {
+			if set.def.Slug == flag.def.Slug {
+				return nil, false, newUsageError(fmt.Errorf("%s is given to --set and to --add or --remove; use one or the other", flag.def.Slug))
+			}
+		}
+	}
+
+	values := mapMap(data, t.field())
+	if values == nil {
+		values = map[string]any{}
+	}
+	data[t.field()] = values
+	for _, set := range sets {
+		converted, err := attributeValuesFromString(set.def, set.raw)
+		if err != nil {
+			return nil, false, newUsageError(fmt.Errorf("--set %s", err))
+		}
+		values[set.def.Slug] = converted
+	}
+	for _, add := range adds {
+		converted, err := attributeValuesFromString(add.def, add.raw)
+		if err != nil {
+			return nil, false, newUsageError(fmt.Errorf("--add %s", err))
+		}
+		values[add.def.Slug] = append(valueList(values[add.def.Slug]), converted...)
+	}
+
+	replace := len(removes) > 0 && mode == valueWriteUpdate
+	if len(removes) == 0 && !(mode == valueWriteReplace && len(adds) > 0) {
+		return data, false, nil
+	}
+
+	// The rest needs the values the record or entry has now.
+	current, err := t.current(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	matched := make([]bool, len(removes))
+	changed := map[string]bool{}
+	for _, flag := range append(append([]valueFlag{}, adds...), removes...) {
+		changed[flag.def.Slug] = true
+	}
+	for _, def := range defs {
+		if !def.Multiselect {
+			continue
+		}
+		if _, ok := values[def.Slug]; !ok && !changed[def.Slug] {
+			continue
+		}
+		if !changed[def.Slug] && !replace {
+			continue
+		}
+		var kept []any
+		for _, item := range valueList(current[def.Slug]) {
+			v, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+			if i := removedValue(def, v, removes); i >= 0 {
+				matched[i] = true
+				continue
+			}
+			if w := writableValue(v); w != nil {
+				kept = append(kept, w)
+			}
+		}
+		values[def.Slug] = append(kept, valueList(values[def.Slug])...)
+	}
+	for i, remove := range removes {
+		if !matched[i] {
+			return nil, false, newUsageError(unmatchedRemoveError(remove, t.noun()))
+		}
+	}
+	return data, replace, nil
+}
+
+// unmatchedRemoveError explains a --remove value that is not among the
+// current values, which would otherwise be dropped without notice.
+func unmatchedRemoveError(remove valueFlag, noun string) error {
+	msg := fmt.Sprintf("--remove %s=%s: the %s has no such value", remove.def.Slug, remove.raw, noun)
+	if remove.def.Type == "record-reference" {
+		ref := strings.TrimSpace(remove.raw)
+		if _, id, ok := strings.Cut(ref, ":"); ok {
+			ref = id
+		}
+		if !uuidPattern.MatchString(ref) {
+			msg += "; record references are removed by record ID"
+		}
+	}
+	return errors.New(msg)
+}
+
+// parseValueFlags resolves the attribute of each attr=value flag.
+// Multiselect-only flags reject other attributes.
+func parseValueFlags(name string, flags []string, defs []attributeDef, multiselectOnly bool) ([]valueFlag, error) {
+	out := make([]valueFlag, 0, len(flags))
+	for _, flag := range flags {
+		key, raw, ok := strings.Cut(flag, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, newUsageError(fmt.Errorf("%s %q: expected attr=value", name, flag))
+		}
+		def, found := findAttributeDef(defs, key)
+		if !found {
+			msg := fmt.Sprintf("%s: unknown attribute %q", name, key)
+			if slug := closestSlug(key, defs); slug != "" {
+				msg += fmt.Sprintf("; did you mean %q?", slug)
+			}
+			return nil, newUsageError(errors.New(msg))
+		}
+		if !def.Writable {
+			return nil, newUsageError(fmt.Errorf("%s: attribute %q is read-only", name, def.Slug))
+		}
+		if multiselectOnly && !def.Multiselect {
+			return nil, newUsageError(fmt.Errorf("%s: attribute %q is not multiselect; use --set", name, def.Slug))
+		}
+		out = append(out, valueFlag{def: def, raw: raw})
+	}
+	return out, nil
+}
+
+func findAttributeDef(defs []attributeDef, key string) (attributeDef, bool) {
+	for _, def := range defs {
+		if def.Slug == key || def.ID == key {
+			return def, true
+		}
+	}
+	for _, def := range defs {
+		if strings.EqualFold(def.Slug, key) || strings.EqualFold(def.Title, key) {
+			return def, true
+		}
+	}
+	return attributeDef{}, false
+}
+
+// removedValue returns the index of the --remove flag that matches a current
+// value, as Attio returns it, or -1 when none does.
+func removedValue(def attributeDef, v map[string]any, removes []valueFlag) int {
+	for i, remove := range removes {
+		if remove.def.Slug != def.Slug {
+			continue
+		}
+		ref := strings.TrimSpace(remove.raw)
+		switch def.Type {
+		case "select":
+			opt := mapMap(v, "option")
+			if mapString(opt, "title") == ref || mapString(mapMap(opt, "id"), "option_id") == ref {
+				return i
+			}
+		case "status":
+			status := mapMap(v, "status")
+			if mapString(status, "title") == ref || mapString(mapMap(status, "id"), "status_id") == ref {
+				return i
+			}
+		case "email-address":
+			if strings.EqualFold(mapString(v, "email_address"), ref) {
+				return i
+			}
+		case "domain":
+			if strings.EqualFold(mapString(v, "domain"), ref) {
+				return i
+			}
+		case "phone-number":
+			if mapString(v, "original_phone_number") == ref || mapString(v, "phone_number") == ref {
+				return i
+			}
+		case "record-reference":
+			if _, id, ok := strings.Cut(ref, ":"); ok {
+				ref = id
+			}
+			if mapString(v, "target_record_id") == ref {
+				return i
+			}
+		case "actor-reference":
+			if mapString(v, "referenced_actor_id") == ref {
+				return i
+			}
+		default:
+			if anyString(v["value"]) == ref {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func valueList(raw any) []any {
+	switch t := raw.(type) {
+	case nil:
+		return nil
+	case []any:
+		return t
+	}
+	return []any{raw}
+}
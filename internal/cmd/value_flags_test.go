@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/failup-ventures/attio-cli/mockserver"
+)
+
+func TestValueFlags(t *testing.T) {
+	setupCLIEnv(t)
+	handler, err := mockserver.New(nil)
+	if err != nil {
+		t.Fatalf("new mock server: %v", err)
+	}
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+	t.Setenv("ATTIO_API_KEY", "mock")
+	t.Setenv("ATTIO_BASE_URL", srv.URL)
+
+	run := func(args ...string) string {
+		t.Helper()
+		stdout, stderr, err := captureExecute(t, args)
+		if err != nil {
+			t.Fatalf("%s: %v\nstderr=%s", strings.Join(args, " "), err, stderr)
+		}
+		return strings.TrimSpace(stdout)
+	}
+	// values returns an attribute's current values as sorted strings, using
+	// the property each value is identified by.
+	values := func(object, id, slug, prop string) string {
+		t.Helper()
+		var out struct {
+			Data struct {
+				Values map[string][]map[string]any `json:"values"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal([]byte(run("--json", "records", "get", object, id)), &out); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		var got []string
+		for _, v := range out.Data.Values[slug] {
+			if prop == "option" {
+				got = append(got, mapString(mapMap(v, "option"), "title"))
+				continue
+			}
+			got = append(got, anyString(v[prop]))
+		}
+		sort.Strings(got)
+		return strings.Join(got, ",")
+	}
+
+	acme := run("--id-only", "records", "create", "companies", "--data", `{"values":{"name":"Placeholder","description":"Widgets"}}`,
+		"--set", "name=Acme", "--set", "domains=acme.com;acme.io", "--set", "categories=B2B;SaaS")
+	if got := values("companies", acme, "name", "value") + "|" + values("companies", acme, "description", "value"); got != "Acme|Widgets" {
+		t.Fatalf("expected --set to overlay --data, got %s", got)
+	}
+	if got := values("companies", acme, "domains", "domain"); got != "acme.com,acme.io" {
+		t.Fatalf("unexpected domains %s", got)
+	}
+
+	run("records", "update", "companies", acme, "--add", "categories=B2C")
+	if got := values("companies", acme, "categories", "option"); got != "B2B,B2C,SaaS" {
+		t.Fatalf("expected --add to append, got %s", got)
+	}
+	run("records", "update", "companies", acme, "--remove", "categories=B2B", "--add", "domains=acme.dev")
+	if got := values("companies", acme, "categories", "option"); got != "B2C,SaaS" {
+		t.Fatalf("expected --remove to drop B2B, got %s", got)
+	}
+	if got := values("companies", acme, "domains", "domain"); got != "acme.com,acme.dev,acme.io" {
+		t.Fatalf("expected other multiselect values to be kept, got %s", got)
+	}
+	if stdout := run("--json", "--dry-run", "records", "update", "companies", acme, "--remove", "categories=B2C"); !strings.Contains(stdout, `"method": "PUT"`) || !strings.Contains(stdout, "replace") {
+		t.Fatalf("expected the dry run to note the replace, got %s", stdout)
+	}
+	if stdout := run("--json", "--dry-run", "records", "update", "companies", acme, "--add", "categories=B2B"); !strings.Contains(stdout, `"method": "PATCH"`) {
+		t.Fatalf("expected a plain update to be a PATCH, got %s", stdout)
+	}
+	run("records", "replace", "companies", acme, "--add", "categories=B2B")
+	if got := values("companies", acme, "categories", "option"); got != "B2B,B2C,SaaS" {
+		t.Fatalf("expected --add on replace to keep current values, got %s", got)
+	}
+
+	ada := run("--id-only", "records", "create", "people", "--set", "name=Lovelace, Ada", "--set", "email_addresses=ada@acme.com", "--set", "company="+acme)
+	if got := values("people", ada, "name", "full_name") + "|" + values("people", ada, "company", "target_record_id"); got != "Ada Lovelace|"+acme {
+		t.Fatalf("unexpected person values %s", got)
+	}
+	deal := run("--id-only", "records", "create", "deals", "--set", "name=Big deal", "--set", "stage=Lead", "--set", "value=USD 1,200")
+	if got := values("deals", deal, "value", "currency_value") + " " + values("deals", deal, "value", "currency_code"); got != "1200 USD" {
+		t.Fatalf("unexpected deal value %s", got)
+	}
+
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"records", "create", "companies"}, "pass --data or"},
+		{[]string{"records", "create", "companies", "--remove", "categories=B2B"}, "needs an existing record"},
+		{[]string{"records", "update", "people", ada, "--add", "job_title=CTO"}, "not multiselect"},
+		{[]string{"records", "update", "people", ada, "--set", "job_titel=CTO"}, `did you mean "job_title"?`},
+		{[]string{"records", "update", "companies", acme, "--set", "categories=B2B", "--remove", "categories=SaaS"}, "use one or the other"},
+		{[]string{"records", "update", "companies", acme, "--remove", "categories=Enterprise"}, "the record has no such value"},
+		{[]string{"records", "update", "companies", acme, "--remove", "team=people:ada@acme.com"}, "removed by record ID"},
+	} {
+		_, stderr, err := captureExecute(t, tc.args)
+		if ExitCode(err) != ExitCodeUsage || !strings.Contains(stderr, tc.want) {
+			t.Fatalf("%s: expected a usage error mentioning %q, got %v\n%s", strings.Join(tc.args, " "), tc.want, err, stderr)
+		}
+	}
+}
@@ -16,6 +16,7 @@ type WebhooksCmd struct {
 	Get    WebhooksGetCmd    `cmd:"" help:"Get webhook"`
 	Update WebhooksUpdateCmd `cmd:"" help:"Update webhook"`
 	Delete WebhooksDeleteCmd `cmd:"" help:"Delete webhook"`
+	Listen WebhooksListenCmd `cmd:"" help:"Run a local receiver that verifies and prints webhook events"`
 }
 
 type WebhooksListCmd struct {
@@ -121,6 +122,15 @@ func (c *WebhooksDeleteCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if ok, err := maybeDryRun(ctx, "webhooks delete", map[string]any{"webhook_id": c.WebhookID}); ok || err != nil {
 		return err
 	}
+	if err := confirmDestructive(ctx, flags, "delete a webhook", func() (string, error) {
+		webhook, err := client.GetWebhook(ctx, c.WebhookID)
+		if err != nil {
+			return "", err
+		}
+		return describeResource("webhook", c.WebhookID, mapString(webhook, "target_url"), mapString(webhook, "status")), nil
+	}); err != nil {
+		return err
+	}
 	if err := client.DeleteWebhook(ctx, c.WebhookID); err != nil {
 		return err
 	}
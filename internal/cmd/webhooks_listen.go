@@ -0,0 +1,300 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/failup-ventures/attio-cli/internal/outfmt"
+)
+
+const maxWebhookBodyBytes = 5 << 20
+
+// webhookSignatureHeaders are checked in order; Attio sends both.
+var webhookSignatureHeaders = []string{"Attio-Signature", "X-Attio-Signature"}
+
+type WebhooksListenCmd struct {
+	Port      int    `name:"port" help:"Port to listen on (0 picks a free port)" default:"8080"`
+	Host      string `name:"host" help:"Interface to bind" default:"127.0.0.1"`
+	Path      string `name:"path" help:"Only accept deliveries on this path (default: any path)"`
+	Secret    string `name:"secret" help:"Webhook signing secret" env:"ATTIO_WEBHOOK_SECRET"`
+	NoVerify  bool   `name:"no-verify" help:"Accept deliveries without checking the signature"`
+	Exec      string `name:"exec" help:"Run a command for each event with the event JSON on stdin"`
+	Forward   string `name:"forward" help:"Relay verified deliveries to this URL"`
+	MaxEvents int    `name:"max-events" help:"Exit after this many events (0 = run until interrupted)" default:"0"`
+}
+
+func (c *WebhooksListenCmd) Run(ctx context.Context) error {
+	if c.Secret == "" && !c.NoVerify {
+		return newUsageError(errors.New("webhooks listen requires --secret (or ATTIO_WEBHOOK_SECRET); pass --no-verify to skip signature checks"))
+	}
+	if c.Forward != "" && !strings.HasPrefix(c.Forward, "http://") && !strings.HasPrefix(c.Forward, "https://") {
+		return newUsageError(fmt.Errorf("--forward must be an http(s) URL, got %q", c.Forward))
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(c.Host, strconv.Itoa(c.Port)))
+	if err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	handler := &webhookListener{
+		secret:    c.Secret,
+		verify:    !c.NoVerify,
+		path:      c.Path,
+		exec:      c.Exec,
+		forward:   c.Forward,
+		ndjson:    outfmt.IsJSON(ctx),
+		out:       os.Stdout,
+		logOut:    os.Stderr,
+		maxEvents: c.MaxEvents,
+		done:      done,
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+
+	_, _ = fmt.Fprintf(os.Stderr, "Listening for webhooks on http://%s%s\n", listener.Addr().String(), c.Path)
+	if !handler.verify {
+		_, _ = fmt.Fprintln(os.Stderr, "Signature verification is disabled")
+	}
+
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- server.Serve(listener) }()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	case <-done:
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// webhookListener verifies and dispatches webhook deliveries. Deliveries are
+// handled one at a time so output, --exec and --forward see events in order.
+type webhookListener struct {
+	secret    string
+	verify    bool
+	path      string
+	exec      string
+	forward   string
+	ndjson    bool
+	out       io.Writer
+	logOut    io.Writer
+	maxEvents int
+	done      chan struct{}
+	client    *http.Client
+
+	mu       sync.Mutex
+	events   int
+	doneOnce sync.Once
+}
+
+func (l *webhookListener) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if l.path != "" && r.URL.Path != l.path {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBodyBytes+1))
+	if err != nil {
+		http.Error(w, "read body", http.StatusBadRequest)
+		return
+	}
+	if len(body) > maxWebhookBodyBytes {
+		http.Error(w, "body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if l.verify {
+		if err := verifyWebhookSignature(l.secret, body, r.Header); err != nil {
+			l.logf("Rejected delivery from %s: %v", r.RemoteAddr, err)
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+	}
+	events, err := splitWebhookEvents(body)
+	if err != nil {
+		l.logf("Rejected delivery from %s: %v", r.RemoteAddr, err)
+		http.Error(w, "invalid JSON body", http.StatusBadRequest)
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.forward != "" {
+		l.forwardDelivery(r, body)
+	}
+	for _, event := range events {
+		if l.maxEvents > 0 && l.events >= l.maxEvents {
+			break
+		}
+		l.events++
+		l.writeEvent(event)
+		if l.exec != "" {
+			l.runExec(r.Context(), event)
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+	if l.maxEvents > 0 && l.events >= l.maxEvents && l.done != nil {
+		l.doneOnce.Do(func() { close(l.done) })
+	}
+}
+
+func (l *webhookListener) writeEvent(event []byte) {
+	if l.ndjson {
+		_, _ = l.out.Write(append(event, '\n'))
+		return
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, event, "", "  "); err != nil {
+		buf.Reset()
+		buf.Write(event)
+	}
+	buf.WriteByte('\n')
+	_, _ = l.out.Write(buf.Bytes())
+}
+
+func (l *webhookListener) runExec(ctx context.Context, event []byte) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", l.exec) //nolint:gosec // user-specified handler command
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", l.exec) //nolint:gosec // user-specified handler command
+	}
+	cmd.Stdin = bytes.NewReader(append(event, '\n'))
+	// Handler output goes to stderr so stdout stays a clean event stream.
+	cmd.Stdout = l.logOut
+	cmd.Stderr = l.logOut
+	var meta struct {
+		EventType string `json:"event_type"`
+	}
+	_ = json.Unmarshal(event, &meta)
+	cmd.Env = append(os.Environ(), "ATTIO_EVENT_TYPE="+meta.EventType)
+	if err := cmd.Run(); err != nil {
+		l.logf("--exec %q failed: %v", l.exec, err)
+	}
+}
+
+func (l *webhookListener) forwardDelivery(r *http.Request, body []byte) {
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodPost, l.forward, bytes.NewReader(body))
+	if err != nil {
+		l.logf("--forward: %v", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for _, header := range webhookSignatureHeaders {
+		if v := r.Header.Get(header); v != "" {
+			req.Header.Set(header, v)
+		}
+	}
+	resp, err := l.client.Do(req)
+	if err != nil {
+		l.logf("--forward to %s failed: %v", l.forward, err)
+		return
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		l.logf("--forward to %s returned %s", l.forward, resp.Status)
+		return
+	}
+	slog.Debug("forwarded webhook delivery", "url", l.forward, "status", resp.StatusCode)
+}
+
+func (l *webhookListener) logf(format string, args ...any) {
+	_, _ = fmt.Fprintf(l.logOut, format+"\n", args...)
+}
+
+// verifyWebhookSignature checks the hex HMAC-SHA256 of body against the
+// signature header.
+func verifyWebhookSignature(secret string, body []byte, header http.Header) error {
+	var signature string
+	for _, name := range webhookSignatureHeaders {
+		if v := strings.TrimSpace(header.Get(name)); v != "" {
+			signature = v
+			break
+		}
+	}
+	if signature == "" {
+		return errors.New("missing Attio-Signature header")
+	}
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil {
+		return errors.New("malformed signature")
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+// splitWebhookEvents returns each entry of the delivery's "events" array as
+// compact JSON, with the delivery's webhook_id added. Deliveries without an
+// events array are returned whole.
+func splitWebhookEvents(body []byte) ([][]byte, error) {
+	var delivery struct {
+		WebhookID string            `json:"webhook_id"`
+		Events    []json.RawMessage `json:"events"`
+	}
+	if err := json.Unmarshal(body, &delivery); err != nil {
+		return nil, err
+	}
+	if delivery.Events == nil {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, body); err != nil {
+			return nil, err
+		}
+		return [][]byte{buf.Bytes()}, nil
+	}
+
+	out := make([][]byte, 0, len(delivery.Events))
+	for _, raw := range delivery.Events {
+		var event map[string]any
+		if err := json.Unmarshal(raw, &event); err != nil {
+			return nil, err
+		}
+		if _, ok := event["webhook_id"]; !ok && delivery.WebhookID != "" {
+			event["webhook_id"] = delivery.WebhookID
+		}
+		b, err := json.Marshal(event)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, b)
+	}
+	return out, nil
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func signWebhookBody(secret string, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestWebhookListenerVerifiesAndDispatches(t *testing.T) {
+	var forwarded []string
+	app := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		forwarded = append(forwarded, r.Header.Get("Attio-Signature")+" "+string(b))
+	}))
+	defer app.Close()
+
+	var out, logs bytes.Buffer
+	listener := &webhookListener{
+		secret:  "shh",
+		verify:  true,
+		path:    "/hook",
+		forward: app.URL,
+		ndjson:  true,
+		out:     &out,
+		logOut:  &logs,
+		client:  app.Client(),
+	}
+	execOut := filepath.Join(t.TempDir(), "events.log")
+	if runtime.GOOS != "windows" {
+		listener.exec = `printf '%s ' "$ATTIO_EVENT_TYPE" >> ` + execOut + ` && cat >> ` + execOut
+	}
+	srv := httptest.NewServer(listener)
+	defer srv.Close()
+
+	body := `{"webhook_id":"wh_1","events":[{"event_type":"record.created","id":{"record_id":"r1"}},{"event_type":"record.updated","id":{"record_id":"r2"}}]}`
+	post := func(path string, signature string) int {
+		req, _ := http.NewRequest(http.MethodPost, srv.URL+path, strings.NewReader(body))
+		if signature != "" {
+			req.Header.Set("Attio-Signature", signature)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("post: %v", err)
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if code := post("/hook", "deadbeef"); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for bad signature, got %d", code)
+	}
+	if code := post("/hook", ""); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for missing signature, got %d", code)
+	}
+	if code := post("/other", signWebhookBody("shh", body)); code != http.StatusNotFound {
+		t.Fatalf("expected 404 for other path, got %d", code)
+	}
+	if out.Len() != 0 || len(forwarded) != 0 {
+		t.Fatalf("rejected deliveries must not be dispatched: out=%q forwarded=%v", out.String(), forwarded)
+	}
+	if !strings.Contains(logs.String(), "signature mismatch") {
+		t.Fatalf("expected rejection to be logged, got %q", logs.String())
+	}
+
+	signature := signWebhookBody("shh", body)
+	if code := post("/hook", signature); code != http.StatusOK {
+		t.Fatalf("expected 200 for valid signature, got %d", code)
+	}
+	want := `{"event_type":"record.created","id":{"record_id":"r1"},"webhook_id":"wh_1"}` + "\n" +
+		`{"event_type":"record.updated","id":{"record_id":"r2"},"webhook_id":"wh_1"}` + "\n"
+	if out.String() != want {
+		t.Fatalf("unexpected NDJSON output:\n%s", out.String())
+	}
+	if len(forwarded) != 1 || forwarded[0] != signature+" "+body {
+		t.Fatalf("expected original delivery to be forwarded once, got %v", forwarded)
+	}
+	if runtime.GOOS != "windows" {
+		b, err := os.ReadFile(execOut)
+		if err != nil {
+			t.Fatalf("read exec output: %v", err)
+		}
+		if !strings.Contains(string(b), `record.created {"event_type":"record.created"`) || !strings.Contains(string(b), `record.updated {"event_type":"record.updated"`) {
+			t.Fatalf("expected --exec to receive each event, got %q", string(b))
+		}
+	}
+}
+
+func TestExecuteWebhooksListenRequiresSecret(t *testing.T) {
+	setupCLIEnv(t)
+	t.Setenv("ATTIO_WEBHOOK_SECRET", "")
+
+	_, stderr, err := captureExecute(t, []string{"webhooks", "listen", "--port", "0"})
+	if ExitCode(err) != ExitCodeUsage {
+		t.Fatalf("expected usage error, got %v", err)
+	}
+	if !strings.Contains(stderr, "--no-verify") {
+		t.Fatalf("expected hint about --no-verify, got %q", stderr)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/99designs/keyring"
 )
@@ -13,7 +14,9 @@ type AuthSource string
 
 const (
 	AuthSourceEnv     AuthSource = "env"
+	AuthSourceCommand AuthSource = "command"
 	AuthSourceKeyring AuthSource = "keyring"
+	AuthSourceOAuth   AuthSource = "oauth"
 	AuthSourceConfig  AuthSource = "config"
 )
 
@@ -32,11 +35,19 @@ type Status struct {
 	Profile        string     `json:"profile"`
 	BaseURL        string     `json:"base_url"`
 	HasEnv         bool       `json:"has_env"`
+	HasCommand     bool       `json:"has_command"`
 	HasKeyring     bool       `json:"has_keyring"`
+	HasOAuth       bool       `json:"has_oauth"`
 	HasConfig      bool       `json:"has_config"`
 	Resolved       bool       `json:"resolved"`
 	ResolvedSource AuthSource `json:"resolved_source,omitempty"`
 	MaskedKey      string     `json:"masked_key,omitempty"`
+	TokenType      string     `json:"token_type,omitempty"`
+	ExpiresAt      string     `json:"expires_at,omitempty"`
+	Scopes         []string   `json:"scopes,omitempty"`
+	Error          string     `json:"error,omitempty"`
+	// Settings lists the effective CLI settings and where each came from.
+	Settings []SettingSource `json:"settings,omitempty"`
 }
 
 func ResolveAPIKey(profile string) (string, error) {
@@ -51,14 +62,31 @@ func ResolveAPIKeyWithSource(profile string) (string, AuthSource, error) {
 		return key, AuthSourceEnv, nil
 	}
 
+	// A configured helper is authoritative: its failure is not masked by
+	// falling back to keys stored elsewhere.
+	cfg, cfgErr := LoadConfig()
+	if cfgErr == nil {
+		if p := cfg.Profiles[profile]; strings.TrimSpace(p.APIKeyCommand) != "" {
+			key, err := runAPIKeyCommand(profile, p)
+			if err != nil {
+				return "", "", err
+			}
+			return key, AuthSourceCommand, nil
+		}
+	}
+
 	if key, err := LoadAPIKey(profile); err == nil && key != "" {
 		return key, AuthSourceKeyring, nil
 	} else if err != nil && !errors.Is(err, keyring.ErrKeyNotFound) {
 		// Continue to config file fallback instead of hard-failing if keyring is unavailable.
 	}
 
-	cfg, err := LoadConfig()
-	if err == nil {
+	// The access token may have expired; requireClient refreshes it.
+	if creds, err := LoadOAuthCredentials(profile); err == nil && creds.AccessToken != "" {
+		return creds.AccessToken, AuthSourceOAuth, nil
+	}
+
+	if cfgErr == nil {
 		if p, ok := cfg.Profiles[profile]; ok {
 			if key := strings.TrimSpace(p.APIKey); key != "" {
 				return key, AuthSourceConfig, nil
@@ -67,8 +95,34 @@ func ResolveAPIKeyWithSource(profile string) (string, AuthSource, error) {
 	}
 
 	return "", "", &AuthRequiredError{
-		Message: fmt.Sprintf("No API key found for profile %q. Set ATTIO_API_KEY or run: attio auth login --api-key <key>", profile),
+		Message: fmt.Sprintf("No API key found for profile %q. Set ATTIO_API_KEY or run: attio auth login --api-key <key> (or --oauth)", profile),
+	}
+}
+
+// ResolveAuthSource reports where ResolveAPIKeyWithSource would take the key
+// from, or "" when there is none, without running api_key_command. A
+// command source is reported whether or not the helper would succeed.
+func ResolveAuthSource(profile string) AuthSource {
+	profile = ResolveProfile(profile)
+
+	if strings.TrimSpace(os.Getenv("ATTIO_API_KEY")) != "" {
+		return AuthSourceEnv
+	}
+	cfg, cfgErr := LoadConfig()
+	p := cfg.Profiles[profile]
+	if cfgErr == nil && strings.TrimSpace(p.APIKeyCommand) != "" {
+		return AuthSourceCommand
+	}
+	if key, err := LoadAPIKey(profile); err == nil && key != "" {
+		return AuthSourceKeyring
+	}
+	if creds, err := LoadOAuthCredentials(profile); err == nil && creds.AccessToken != "" {
+		return AuthSourceOAuth
 	}
+	if cfgErr == nil && strings.TrimSpace(p.APIKey) != "" {
+		return AuthSourceConfig
+	}
+	return ""
 }
 
 func ResolveBaseURL(profile string) string {
@@ -104,19 +158,33 @@ func AuthStatus(profile string) Status {
 		status.HasKeyring = true
 	}
 
+	creds, err := LoadOAuthCredentials(profile)
+	status.HasOAuth = err == nil && creds.AccessToken != ""
+
 	cfg, err := LoadConfig()
 	if err == nil {
 		if p, ok := cfg.Profiles[profile]; ok {
 			if strings.TrimSpace(p.APIKey) != "" {
 				status.HasConfig = true
 			}
+			status.HasCommand = strings.TrimSpace(p.APIKeyCommand) != ""
 		}
 	}
 
-	if key, source, err := ResolveAPIKeyWithSource(profile); err == nil {
+	key, source, err := ResolveAPIKeyWithSource(profile)
+	if err == nil {
 		status.Resolved = true
 		status.ResolvedSource = source
 		status.MaskedKey = maskKey(key)
+		if source == AuthSourceOAuth {
+			status.TokenType = creds.TokenType
+			if !creds.Expiry.IsZero() {
+				status.ExpiresAt = creds.Expiry.UTC().Format(time.RFC3339)
+			}
+			status.Scopes = strings.Fields(creds.Scope)
+		}
+	} else if status.HasCommand {
+		status.Error = err.Error()
 	}
 
 	return status
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 )
 
 const (
@@ -15,8 +17,32 @@ const (
 )
 
 type Profile struct {
-	APIKey  string `json:"api_key,omitempty"`
-	BaseURL string `json:"base_url,omitempty"`
+	APIKey    string `json:"api_key,omitempty"`
+	BaseURL   string `json:"base_url,omitempty"`
+	RateLimit string `json:"rate_limit,omitempty"`
+	// Timeout, Output and Color are defaults for --timeout, --json/--plain
+	// and --color when those flags are not given.
+	Timeout string `json:"timeout,omitempty"`
+	Output  string `json:"output,omitempty"`
+	Color   string `json:"color,omitempty"`
+	// APIKeyCommand is run through the shell to print the API key, e.g.
+	// "op read op://Eng/attio/key".
+	APIKeyCommand        string `json:"api_key_command,omitempty"`
+	APIKeyCommandTimeout string `json:"api_key_command_timeout,omitempty"`
+	// ReadOnly blocks every command and request that would change data.
+	ReadOnly bool `json:"read_only,omitempty"`
+	// DisableCommands lists command paths that may not run, e.g. "webhooks".
+	DisableCommands []string `json:"disable_commands,omitempty"`
+	// ObjectPolicy overrides ReadOnly per object slug, list slug or resource
+	// ("tasks", "notes", ...): "write" allows changes, "read" blocks them.
+	ObjectPolicy map[string]string `json:"object_policy,omitempty"`
+	// AuditLog is the audit log path, or "off". AuditBody chooses how request
+	// bodies are captured: none, hash or full (redacted).
+	AuditLog  string `json:"audit_log,omitempty"`
+	AuditBody string `json:"audit_body,omitempty"`
+	// Undo saves the prior state of records and entries before updates,
+	// replaces and deletes so that attio undo can restore it.
+	Undo bool `json:"undo,omitempty"`
 }
 
 type Config struct {
@@ -100,6 +126,125 @@ func ResolveProfile(profile string) string {
 	return DefaultProfileName
 }
 
+// LoadProfile returns the settings stored for profile, or the zero Profile
+// when it has none.
+func LoadProfile(profile string) Profile {
+	profile = ResolveProfile(profile)
+	cfg, err := LoadConfig()
+	if err != nil {
+		return Profile{}
+	}
+	return cfg.Profiles[profile]
+}
+
+// ResolveRateLimit returns the profile's rate_limit setting, or "" when unset.
+// A config file that cannot be read is an error rather than no limit.
+func ResolveRateLimit(profile string) (string, error) {
+	profile = ResolveProfile(profile)
+	cfg, err := LoadConfig()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(cfg.Profiles[profile].RateLimit), nil
+}
+
+// AuditLogOff disables the audit log when used as its path.
+const AuditLogOff = "off"
+
+// ResolveAuditLog returns the audit log path and body capture mode for
+// profile. ATTIO_AUDIT_LOG and ATTIO_AUDIT_BODY override the profile's
+// audit_log and audit_body. The default path is audit.jsonl next to the
+// config file; the path is "" when the log is turned off.
+func ResolveAuditLog(profile string) (string, string, error) {
+	p := LoadProfile(profile)
+	path := strings.TrimSpace(os.Getenv("ATTIO_AUDIT_LOG"))
+	if path == "" {
+		path = strings.TrimSpace(p.AuditLog)
+	}
+	mode := strings.TrimSpace(os.Getenv("ATTIO_AUDIT_BODY"))
+	if mode == "" {
+		mode = strings.TrimSpace(p.AuditBody)
+	}
+	switch {
+	case strings.EqualFold(path, AuditLogOff):
+		return "", mode, nil
+	case path == "":
+		cfgPath, err := configPath()
+		if err != nil {
+			return "", mode, err
+		}
+		return filepath.Join(filepath.Dir(cfgPath), "audit.jsonl"), mode, nil
+	case strings.HasPrefix(path, "~"):
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", mode, fmt.Errorf("resolve home dir: %w", err)
+		}
+		path = filepath.Join(home, strings.TrimPrefix(path, "~"))
+	}
+	return path, mode, nil
+}
+
+// ResolveUndo reports whether undo snapshots are on for profile.
+// ATTIO_UNDO overrides the profile's undo setting.
+func ResolveUndo(profile string) bool {
+	if v := strings.TrimSpace(os.Getenv("ATTIO_UNDO")); v != "" {
+		on, err := strconv.ParseBool(v)
+		return err == nil && on
+	}
+	return LoadProfile(profile).Undo
+}
+
+// StateDir is where local state such as undo snapshots is kept:
+// ATTIO_STATE_DIR, else $XDG_STATE_HOME/attio-cli, else
+// ~/.local/state/attio-cli.
+func StateDir() (string, error) {
+	if dir := strings.TrimSpace(os.Getenv("ATTIO_STATE_DIR")); dir != "" {
+		return dir, nil
+	}
+	if dir := strings.TrimSpace(os.Getenv("XDG_STATE_HOME")); dir != "" {
+		return filepath.Join(dir, "attio-cli"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve home dir: %w", err)
+	}
+	return filepath.Join(home, ".local", "state", "attio-cli"), nil
+}
+
+// CacheDir is where cached API metadata is kept: ATTIO_CACHE_DIR, else
+// attio-cli in the user cache directory.
+func CacheDir() (string, error) {
+	if dir := strings.TrimSpace(os.Getenv("ATTIO_CACHE_DIR")); dir != "" {
+		return dir, nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve user cache dir: %w", err)
+	}
+	return filepath.Join(dir, "attio-cli"), nil
+}
+
+// DefaultSchemaCacheTTL is how long attribute and option listings used to
+// validate payloads are reused.
+const DefaultSchemaCacheTTL = 5 * time.Minute
+
+// ResolveSchemaCacheTTL returns ATTIO_SCHEMA_CACHE_TTL, else
+// DefaultSchemaCacheTTL. Zero turns the cache off.
+func ResolveSchemaCacheTTL() (time.Duration, error) {
+	v := strings.TrimSpace(os.Getenv("ATTIO_SCHEMA_CACHE_TTL"))
+	if v == "" {
+		return DefaultSchemaCacheTTL, nil
+	}
+	if v == "0" {
+		return 0, nil
+	}
+	ttl, err := time.ParseDuration(v)
+	if err != nil || ttl < 0 {
+		return 0, fmt.Errorf("invalid ATTIO_SCHEMA_CACHE_TTL %q (expected a duration such as 10m, or 0)", v)
+	}
+	return ttl, nil
+}
+
 func configPath() (string, error) {
 	if override := strings.TrimSpace(os.Getenv("ATTIO_CONFIG_PATH")); override != "" {
 		if strings.HasPrefix(override, "~") {
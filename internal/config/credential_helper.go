@@ -0,0 +1,90 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultAPIKeyCommandTimeout bounds an api_key_command that does not set
+// api_key_command_timeout.
+const DefaultAPIKeyCommandTimeout = 30 * time.Second
+
+type helperResult struct {
+	key string
+	err error
+}
+
+// helperCache keeps each command's result for the life of the process so a
+// helper that prompts (e.g. for a 1Password unlock) only runs once.
+var (
+	helperMu    sync.Mutex
+	helperCache = map[string]helperResult{}
+)
+
+// runAPIKeyCommand runs the profile's api_key_command and returns the key it
+// prints. Failures are AuthRequiredErrors that include the helper's stderr.
+func runAPIKeyCommand(profile string, p Profile) (string, error) {
+	command := strings.TrimSpace(p.APIKeyCommand)
+	timeout := DefaultAPIKeyCommandTimeout
+	if v := strings.TrimSpace(p.APIKeyCommandTimeout); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return "", &AuthRequiredError{
+				Message: fmt.Sprintf("invalid api_key_command_timeout %q for profile %q: use a duration such as 10s", v, profile),
+			}
+		}
+		timeout = d
+	}
+
+	helperMu.Lock()
+	defer helperMu.Unlock()
+	if res, ok := helperCache[command]; ok {
+		return res.key, res.err
+	}
+	key, err := execAPIKeyCommand(command, timeout)
+	if err != nil {
+		err = &AuthRequiredError{Message: fmt.Sprintf("api_key_command for profile %q failed: %v", profile, err)}
+	}
+	helperCache[command] = helperResult{key: key, err: err}
+	return key, err
+}
+
+func execAPIKeyCommand(command string, timeout time.Duration) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	var stdout, stderr bytes.Buffer
+	// Stdin stays unset: the CLI's own stdin may carry a --data - payload.
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// Don't wait on children of the shell that still hold the output pipes.
+	cmd.WaitDelay = time.Second
+	err := cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return "", fmt.Errorf("timed out after %s", timeout)
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	key := strings.TrimSpace(stdout.String())
+	if key == "" {
+		return "", errors.New("command printed no API key")
+	}
+	return key, nil
+}
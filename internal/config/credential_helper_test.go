@@ -0,0 +1,117 @@
+package config
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func saveHelperProfile(t *testing.T, p Profile) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("helper commands use sh")
+	}
+	if err := SaveConfig(Config{DefaultProfile: "default", Profiles: map[string]Profile{"default": p}}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+}
+
+func TestResolveAPIKeyFromCommandIsCached(t *testing.T) {
+	tmp := setupConfigEnv(t)
+	counter := filepath.Join(filepath.Dir(tmp), "runs")
+	if err := StoreAPIKey("default", "keyring-key"); err != nil {
+		t.Fatalf("store keyring key: %v", err)
+	}
+	saveHelperProfile(t, Profile{APIKey: "config-key", APIKeyCommand: "echo run >> " + counter + "; echo '  helper-key  '"})
+
+	for i := 0; i < 2; i++ {
+		key, source, err := ResolveAPIKeyWithSource("default")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if key != "helper-key" || source != AuthSourceCommand {
+			t.Fatalf("expected command source, got key=%q source=%q", key, source)
+		}
+	}
+	b, err := os.ReadFile(counter)
+	if err != nil {
+		t.Fatalf("read counter: %v", err)
+	}
+	if runs := strings.Count(string(b), "run"); runs != 1 {
+		t.Fatalf("expected the helper to run once, ran %d times", runs)
+	}
+
+	status := AuthStatus("default")
+	if !status.HasCommand || status.ResolvedSource != AuthSourceCommand || status.MaskedKey != "******-key" {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+}
+
+func TestResolveAPIKeyCommandFailureIncludesStderr(t *testing.T) {
+	_ = setupConfigEnv(t)
+	if err := StoreAPIKey("default", "keyring-key"); err != nil {
+		t.Fatalf("store keyring key: %v", err)
+	}
+	saveHelperProfile(t, Profile{APIKeyCommand: "echo 'vault is sealed' >&2; exit 3"})
+
+	_, _, err := ResolveAPIKeyWithSource("default")
+	var authErr *AuthRequiredError
+	if !errors.As(err, &authErr) || !strings.Contains(err.Error(), "vault is sealed") || !strings.Contains(err.Error(), "exit status 3") {
+		t.Fatalf("expected AuthRequiredError with stderr, got %v", err)
+	}
+	status := AuthStatus("default")
+	if status.Resolved || !strings.Contains(status.Error, "vault is sealed") {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+}
+
+func TestResolveAPIKeyCommandTimeoutAndEmptyOutput(t *testing.T) {
+	_ = setupConfigEnv(t)
+	saveHelperProfile(t, Profile{APIKeyCommand: "sleep 5 # timeout test", APIKeyCommandTimeout: "100ms"})
+	_, _, err := ResolveAPIKeyWithSource("default")
+	if err == nil || !strings.Contains(err.Error(), "timed out after 100ms") {
+		t.Fatalf("expected timeout, got %v", err)
+	}
+
+	saveHelperProfile(t, Profile{APIKeyCommand: "true # empty output test"})
+	_, _, err = ResolveAPIKeyWithSource("default")
+	if err == nil || !strings.Contains(err.Error(), "printed no API key") {
+		t.Fatalf("expected empty output error, got %v", err)
+	}
+
+	saveHelperProfile(t, Profile{APIKeyCommand: "echo key", APIKeyCommandTimeout: "soon"})
+	_, _, err = ResolveAPIKeyWithSource("default")
+	if err == nil || !strings.Contains(err.Error(), "invalid api_key_command_timeout") {
+		t.Fatalf("expected invalid timeout error, got %v", err)
+	}
+}
+
+func TestResolveAPIKeyCommandLeavesStdinAlone(t *testing.T) {
+	_ = setupConfigEnv(t)
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe: %v", err)
+	}
+	origStdin := os.Stdin
+	os.Stdin = r
+	t.Cleanup(func() {
+		os.Stdin = origStdin
+		_ = r.Close()
+	})
+	_, _ = w.WriteString("{\"values\":{}}\n")
+	_ = w.Close()
+
+	saveHelperProfile(t, Profile{APIKeyCommand: "if read -r line; then echo stdin-key; else echo helper-key; fi"})
+	key, _, err := ResolveAPIKeyWithSource("default")
+	if err != nil || key != "helper-key" {
+		t.Fatalf("expected the helper not to read stdin, got key=%q err=%v", key, err)
+	}
+	rest, _ := io.ReadAll(os.Stdin)
+	if string(rest) != "{\"values\":{}}\n" {
+		t.Fatalf("expected the piped payload to be left for the command, got %q", rest)
+	}
+}
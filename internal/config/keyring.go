@@ -1,9 +1,12 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/99designs/keyring"
@@ -12,6 +15,11 @@ import (
 
 const keyringServiceName = "attio-cli"
 
+const (
+	apiKeyKeyringPrefix = "attio-api-key:"
+	oauthKeyringPrefix  = "attio-oauth:"
+)
+
 func StoreAPIKey(profile string, apiKey string) error {
 	ring, err := openKeyring()
 	if err != nil {
@@ -40,7 +48,68 @@ func RemoveAPIKey(profile string) error {
 	if err != nil {
 		return fmt.Errorf("open keyring: %w", err)
 	}
-	return ring.Remove(keyringKey(profile))
+	return removeItem(ring, keyringKey(profile))
+}
+
+// removeItem removes key, reporting a missing item as keyring.ErrKeyNotFound
+// on every backend (the file backend returns a not-exist error instead).
+func removeItem(ring keyring.Keyring, key string) error {
+	err := ring.Remove(key)
+	if errors.Is(err, fs.ErrNotExist) {
+		return keyring.ErrKeyNotFound
+	}
+	return err
+}
+
+// KeyringProfiles returns the profiles with an API key or OAuth tokens in the
+// keyring, sorted by name.
+func KeyringProfiles() ([]string, error) {
+	ring, err := openKeyring()
+	if err != nil {
+		return nil, fmt.Errorf("open keyring: %w", err)
+	}
+	keys, err := ring.Keys()
+	if err != nil {
+		return nil, fmt.Errorf("list keyring: %w", err)
+	}
+	seen := map[string]bool{}
+	var profiles []string
+	for _, key := range keys {
+		for _, prefix := range []string{apiKeyKeyringPrefix, oauthKeyringPrefix} {
+			if name, ok := strings.CutPrefix(key, prefix); ok && name != "" && !seen[name] {
+				seen[name] = true
+				profiles = append(profiles, name)
+			}
+		}
+	}
+	sort.Strings(profiles)
+	return profiles, nil
+}
+
+// MoveKeyringProfile moves the API key and OAuth tokens stored for from to
+// to. Credentials already stored for to are overwritten.
+func MoveKeyringProfile(from string, to string) error {
+	ring, err := openKeyring()
+	if err != nil {
+		return fmt.Errorf("open keyring: %w", err)
+	}
+	for _, key := range []func(string) string{keyringKey, oauthKeyringKey} {
+		item, err := ring.Get(key(from))
+		if errors.Is(err, keyring.ErrKeyNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		item.Key = key(to)
+		if err := ring.Set(item); err != nil {
+			return err
+		}
+		if err := removeItem(ring, key(from)); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func openKeyring() (keyring.Keyring, error) {
@@ -87,5 +156,5 @@ func allowedBackends() []keyring.BackendType {
 
 func keyringKey(profile string) string {
 	profile = ResolveProfile(profile)
-	return "attio-api-key:" + profile
+	return apiKeyKeyringPrefix + profile
 }
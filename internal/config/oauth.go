@@ -0,0 +1,59 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/99designs/keyring"
+
+	"github.com/failup-ventures/attio-cli/internal/oauth"
+)
+
+// OAuthCredentials is an OAuth login stored in the keyring for a profile,
+// with the client details needed to refresh it.
+type OAuthCredentials struct {
+	oauth.Token
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret,omitempty"`
+	TokenURL     string `json:"token_url"`
+}
+
+func StoreOAuthCredentials(profile string, creds OAuthCredentials) error {
+	ring, err := openKeyring()
+	if err != nil {
+		return fmt.Errorf("open keyring: %w", err)
+	}
+	b, err := json.Marshal(creds)
+	if err != nil {
+		return fmt.Errorf("encode oauth credentials: %w", err)
+	}
+	return ring.Set(keyring.Item{Key: oauthKeyringKey(profile), Data: b})
+}
+
+func LoadOAuthCredentials(profile string) (*OAuthCredentials, error) {
+	ring, err := openKeyring()
+	if err != nil {
+		return nil, fmt.Errorf("open keyring: %w", err)
+	}
+	item, err := ring.Get(oauthKeyringKey(profile))
+	if err != nil {
+		return nil, err
+	}
+	var creds OAuthCredentials
+	if err := json.Unmarshal(item.Data, &creds); err != nil {
+		return nil, fmt.Errorf("decode oauth credentials: %w", err)
+	}
+	return &creds, nil
+}
+
+func RemoveOAuthCredentials(profile string) error {
+	ring, err := openKeyring()
+	if err != nil {
+		return fmt.Errorf("open keyring: %w", err)
+	}
+	return removeItem(ring, oauthKeyringKey(profile))
+}
+
+func oauthKeyringKey(profile string) string {
+	return oauthKeyringPrefix + ResolveProfile(profile)
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectFileNames are the project config files looked for, in this order,
+// in the working directory and then in each parent directory.
+var ProjectFileNames = []string{".attio.json", ".attio.yaml", ".attio.yml"}
+
+// ProjectConfig pins settings for a directory tree. Its settings rank below
+// flags and environment variables and above the global config file.
+type ProjectConfig struct {
+	Profile        string   `yaml:"profile"`
+	Output         string   `yaml:"output"`
+	Timeout        string   `yaml:"timeout"`
+	EnableCommands []string `yaml:"enable_commands"`
+	// Columns maps an object slug or ID to the columns shown for its records.
+	Columns map[string][]string `yaml:"columns"`
+
+	// Path is the file the settings were read from.
+	Path string `yaml:"-"`
+}
+
+// FindProjectConfig returns the nearest project config file at or above dir,
+// or "" when there is none.
+func FindProjectConfig(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		for _, name := range ProjectFileNames {
+			path := filepath.Join(dir, name)
+			info, err := os.Stat(path)
+			if err == nil && !info.IsDir() {
+				return path, nil
+			}
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return "", fmt.Errorf("check project config: %w", err)
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// LoadProjectConfig reads the project config that applies to the working
+// directory. It returns nil when there is none.
+func LoadProjectConfig() (*ProjectConfig, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("resolve working directory: %w", err)
+	}
+	path, err := FindProjectConfig(wd)
+	if err != nil || path == "" {
+		return nil, err
+	}
+	b, err := os.ReadFile(path) //nolint:gosec // discovered project config file
+	if err != nil {
+		return nil, fmt.Errorf("read project config: %w", err)
+	}
+
+	// YAML is a superset of JSON, so one decoder handles both formats.
+	var project ProjectConfig
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err := dec.Decode(&project); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parse project config %s: %w", path, err)
+	}
+	project.Path = path
+	project.Profile = strings.TrimSpace(project.Profile)
+	project.Output = strings.ToLower(strings.TrimSpace(project.Output))
+	project.Timeout = strings.TrimSpace(project.Timeout)
+	switch project.Output {
+	case "", "json", "plain", "table":
+	default:
+		return nil, fmt.Errorf("project config %s: invalid output %q (expected json|plain|table)", path, project.Output)
+	}
+	return &project, nil
+}
+
+// ConfigPath returns the path of the global config file.
+func ConfigPath() (string, error) {
+	return configPath()
+}
+
+// SettingSource records where the effective value of a setting came from.
+type SettingSource struct {
+	Setting string `json:"setting"`
+	Value   string `json:"value"`
+	Source  string `json:"source"`
+}
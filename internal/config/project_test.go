@@ -0,0 +1,66 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFindAndLoadProjectConfig(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if path, err := FindProjectConfig(nested); err != nil || path != "" {
+		t.Fatalf("expected no project config, got %q (%v)", path, err)
+	}
+
+	yamlPath := filepath.Join(root, ".attio.yaml")
+	content := "profile: staging\noutput: JSON\ntimeout: 1m\nenable_commands: [records, objects]\ncolumns:\n  people: [record_id, name, email_addresses]\n"
+	if err := os.WriteFile(yamlPath, []byte(content), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	// The nearest file wins, and .attio.json is preferred within a directory.
+	jsonPath := filepath.Join(root, "a", ".attio.json")
+	if err := os.WriteFile(jsonPath, []byte(`{"profile":"prod"}`), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if path, _ := FindProjectConfig(nested); path != jsonPath {
+		t.Fatalf("expected %s, got %s", jsonPath, path)
+	}
+	if err := os.Remove(jsonPath); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+
+	wd, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+	if err := os.Chdir(nested); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	project, err := LoadProjectConfig()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if filepath.Base(project.Path) != ".attio.yaml" {
+		t.Fatalf("unexpected path %q", project.Path)
+	}
+	if project.Profile != "staging" || project.Output != "json" || project.Timeout != "1m" ||
+		strings.Join(project.EnableCommands, ",") != "records,objects" || len(project.Columns["people"]) != 3 {
+		t.Fatalf("unexpected project config: %+v", project)
+	}
+
+	if err := os.WriteFile(yamlPath, []byte("profle: typo\n"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := LoadProjectConfig(); err == nil || !strings.Contains(err.Error(), "profle") {
+		t.Fatalf("expected unknown field error, got %v", err)
+	}
+	if err := os.WriteFile(yamlPath, []byte("output: xml\n"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := LoadProjectConfig(); err == nil || !strings.Contains(err.Error(), "invalid output") {
+		t.Fatalf("expected invalid output error, got %v", err)
+	}
+}
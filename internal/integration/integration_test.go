@@ -19,11 +19,17 @@ func integrationClient(t *testing.T) *api.Client {
 	t.Helper()
 
 	apiKey := strings.TrimSpace(os.Getenv("ATTIO_API_KEY"))
-	if apiKey == "" {
+	if apiKey == "" && !api.ReplayingFromEnv() {
 		t.Skip("ATTIO_API_KEY not set")
 	}
 	baseURL := strings.TrimSpace(os.Getenv("ATTIO_BASE_URL"))
-	return api.NewClient(apiKey, baseURL)
+	client := api.NewClient(apiKey, baseURL)
+	cassette, err := api.CassetteFromEnv()
+	if err != nil {
+		t.Fatalf("cassette: %v", err)
+	}
+	client.SetCassette(cassette)
+	return client
 }
 
 func integrationSkipEnabled(resource string) bool {
@@ -0,0 +1,622 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const refPrefix = "#/components/schemas/"
+
+// namePlaceholder stands for the type name in docs written before the name
+// is final.
+const namePlaceholder = "{name}"
+
+// roots are the top-level models in output order. Each comes from a
+// component schema or from the data field of a GET response.
+var roots = []struct {
+	name      string
+	component string
+	path      string
+	doc       string
+}{
+	{name: "Object", component: "object", doc: "Object is a workspace object such as people, companies or a custom object."},
+	{name: "List", component: "list", doc: "List is a workspace list. Its entries point at records of the parent object."},
+	{name: "Attribute", component: "attribute", doc: "Attribute describes one attribute of an object or list."},
+	{name: "SelectOption", component: "select-option", doc: "SelectOption is one option of a select attribute."},
+	{name: "Status", component: "status", doc: "Status is one status of a status attribute."},
+	{name: "Record", path: "/v2/objects/{object}/records/{record_id}", doc: "Record is a record of an object. Values are keyed by attribute slug."},
+	{name: "Entry", path: "/v2/lists/{list}/entries/{entry_id}", doc: "Entry is a record's entry in a list. EntryValues hold the list's own attributes."},
+	{name: "Note", component: "note", doc: "Note is a note attached to a record."},
+	{name: "Task", component: "task", doc: "Task is a task, optionally linked to records and assigned to members."},
+	{name: "Thread", component: "thread", doc: "Thread is a comment thread on a record or entry."},
+	{name: "Comment", component: "comment", doc: "Comment is one comment in a thread."},
+	{name: "Meeting", component: "meeting", doc: "Meeting is a calendar meeting."},
+	{name: "CallRecording", path: "/v2/meetings/{meeting_id}/call_recordings/{call_recording_id}", doc: "CallRecording is a recording of a meeting."},
+	{name: "Webhook", path: "/v2/webhooks/{webhook_id}", doc: "Webhook is a webhook subscription. The signing secret is only returned on create."},
+	{name: "WorkspaceMember", component: "workspace-member", doc: "WorkspaceMember is a member of the workspace."},
+}
+
+// renames give shared nested types a better name than the parent and field
+// they were first seen in.
+var renames = map[string]rename{
+	"ListCreatedByActor":          {"Actor", "Actor identifies who performed an action: a workspace member, API token, app or the system."},
+	"RecordValue":                 {"AttributeValue", "AttributeValue is one value of a record or entry attribute. AttributeType says which variant field is set."},
+	"AttributeDefaultValue":       {"DefaultValue", "DefaultValue is the value an attribute takes when none is given. Type says which variant field is set."},
+	"TaskAssignee":                {"ActorReference", "ActorReference points at a workspace member or other actor."},
+	"MeetingStart":                {"MeetingTime", "MeetingTime is a datetime with timezone, or a date for all-day meetings."},
+	"WebhookSubscriptionFilterOr": {"WebhookFilterCondition", "WebhookFilterCondition compares one event field with a value."},
+}
+
+// refAliases map component schemas onto types generated elsewhere. Static
+// default values use output-value, which is AttributeValue without the
+// history fields.
+var refAliases = map[string]string{
+	"output-value": "AttributeValue",
+}
+
+type rename struct {
+	name string
+	doc  string
+}
+
+type field struct {
+	name      string
+	typ       string
+	json      string
+	omitempty bool
+	doc       string
+}
+
+type variant struct {
+	value string
+	field string
+	typ   string
+}
+
+type decl struct {
+	name   string
+	doc    string
+	fields []field
+	// tag and variants are set for discriminated unions.
+	tag      *field
+	variants []variant
+}
+
+type generator struct {
+	doc       *document
+	rootNames map[string]string
+	decls     map[string]*decl
+	bySig     map[string]string
+	building  map[string]bool
+	usesJSON  bool
+	err       error
+}
+
+func generate(spec []byte) ([]byte, error) {
+	var doc document
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return nil, fmt.Errorf("parse openapi.json: %w", err)
+	}
+	g := &generator{
+		doc:       &doc,
+		rootNames: map[string]string{},
+		decls:     map[string]*decl{},
+		bySig:     map[string]string{},
+		building:  map[string]bool{},
+	}
+	for _, root := range roots {
+		var s *schema
+		if root.component != "" {
+			s = doc.Components.Schemas[root.component]
+			g.rootNames[refPrefix+root.component] = root.name
+		} else {
+			var err error
+			if s, err = doc.responseData(root.path); err != nil {
+				return nil, err
+			}
+		}
+		if s == nil {
+			return nil, fmt.Errorf("schema %s is not in the spec", root.component)
+		}
+		g.building[root.name] = true
+		g.structType(s, root.name, root.doc, true)
+	}
+	if g.err != nil {
+		return nil, g.err
+	}
+	return g.render()
+}
+
+func (g *generator) fail(format string, args ...any) string {
+	if g.err == nil {
+		g.err = fmt.Errorf(format, args...)
+	}
+	return "any"
+}
+
+func (g *generator) refType(ref string) string {
+	if alias, ok := refAliases[strings.TrimPrefix(ref, refPrefix)]; ok {
+		return alias
+	}
+	name, ok := g.rootNames[ref]
+	if !ok {
+		name = camel(strings.TrimPrefix(ref, refPrefix))
+	}
+	if g.decls[name] != nil || g.building[name] {
+		return name
+	}
+	s := g.doc.Components.Schemas[strings.TrimPrefix(ref, refPrefix)]
+	if s == nil {
+		return g.fail("unknown schema %s", ref)
+	}
+	g.building[name] = true
+	defer delete(g.building, name)
+	doc := fmt.Sprintf("%s is the %s schema.", name, strings.TrimPrefix(ref, refPrefix))
+	for _, root := range roots {
+		if root.name == name {
+			doc = root.doc
+		}
+	}
+	return g.structType(s, name, doc, true)
+}
+
+// typeOf maps a schema to a Go type, declaring named types for nested
+// objects. hint names the type when a new one is needed.
+func (g *generator) typeOf(s *schema, hint string, doc string) string {
+	if s == nil {
+		return "any"
+	}
+	if s.Ref != "" {
+		return g.refType(s.Ref)
+	}
+	alternatives := s.OneOf
+	if len(alternatives) == 0 {
+		alternatives = s.AnyOf
+	}
+	if len(alternatives) > 0 {
+		nonNull := make([]*schema, 0, len(alternatives))
+		nullable := false
+		for _, alt := range alternatives {
+			if types, null := alt.Type.split(); null && len(types) == 0 {
+				nullable = true
+				continue
+			}
+			nonNull = append(nonNull, alt)
+		}
+		return pointer(g.alternativesType(nonNull, hint, doc), nullable)
+	}
+
+	types, nullable := s.Type.split()
+	nullable = nullable || s.Nullable
+	var typ string
+	switch {
+	case len(types) > 1:
+		typ = "any"
+	case len(s.Properties) > 0:
+		typ = g.structType(s, hint, doc, false)
+	case len(types) == 0:
+		typ = "any"
+	default:
+		switch types[0] {
+		case "string":
+			typ = "string"
+		case "integer":
+			typ = "int64"
+		case "number":
+			typ = "float64"
+		case "boolean":
+			typ = "bool"
+		case "array":
+			typ = "[]" + g.typeOf(s.Items, singular(hint), strings.Replace(doc, " is the ", " is one of the ", 1))
+		case "object":
+			if values := s.mapValues(); values != nil {
+				typ = "map[string]" + g.typeOf(values, hint, doc)
+			} else {
+				typ = "map[string]any"
+			}
+		default:
+			typ = "any"
+		}
+	}
+	return pointer(typ, nullable)
+}
+
+// alternativesType handles oneOf/anyOf: a discriminated union when every
+// alternative has a distinct constant for the same property, a merged struct
+// for other objects, and the shared type (or raw JSON) otherwise.
+func (g *generator) alternativesType(alts []*schema, hint string, doc string) string {
+	if len(alts) == 1 {
+		return g.typeOf(alts[0], hint, doc)
+	}
+	objects := true
+	for _, alt := range alts {
+		if alt.Ref != "" || len(alt.Properties) == 0 {
+			objects = false
+		}
+	}
+	if objects {
+		if tag := discriminator(alts); tag != "" {
+			return g.unionType(alts, tag, hint, doc)
+		}
+		merged := &schema{Description: doc}
+		for _, alt := range alts {
+			for _, prop := range alt.Properties {
+				if merged.property(prop.name) == nil {
+					merged.Properties = append(merged.Properties, prop)
+				}
+			}
+		}
+		return g.structType(merged, hint, doc, false)
+	}
+	first := ""
+	for i, alt := range alts {
+		typ := g.typeOf(alt, hint, doc)
+		if i == 0 {
+			first = typ
+		} else if typ != first {
+			g.usesJSON = true
+			return "json.RawMessage"
+		}
+	}
+	return first
+}
+
+func discriminator(alts []*schema) string {
+	for _, candidate := range alts[0].Properties {
+		seen := map[string]bool{}
+		for _, alt := range alts {
+			prop := alt.property(candidate.name)
+			if prop == nil || len(prop.Enum) != 1 {
+				break
+			}
+			value, ok := prop.Enum[0].(string)
+			if !ok || seen[value] {
+				break
+			}
+			seen[value] = true
+		}
+		if len(seen) == len(alts) {
+			return candidate.name
+		}
+	}
+	return ""
+}
+
+func (g *generator) fields(s *schema, parent string, skip func(string) bool) []field {
+	out := make([]field, 0, len(s.Properties))
+	for _, prop := range s.Properties {
+		if skip != nil && skip(prop.name) {
+			continue
+		}
+		name := camel(prop.name)
+		out = append(out, field{
+			name:      name,
+			typ:       g.typeOf(prop.schema, parent+name, fmt.Sprintf("%s is the %s of %s.", namePlaceholder, prop.name, article(parent))),
+			json:      prop.name,
+			omitempty: !s.isRequired(prop.name),
+			doc:       fieldDoc(prop.schema),
+		})
+	}
+	return out
+}
+
+func (g *generator) structType(s *schema, name string, doc string, fixed bool) string {
+	d := &decl{name: name, doc: doc, fields: g.fields(s, name, nil)}
+	return g.declare(d, fixed)
+}
+
+func (g *generator) unionType(alts []*schema, tag string, hint string, doc string) string {
+	// A property is shared when every alternative has it with the same shape.
+	common := func(prop string) bool {
+		shape := ""
+		for i, alt := range alts {
+			s := alt.property(prop)
+			if s == nil || (i > 0 && schemaShape(s) != shape) {
+				return false
+			}
+			shape = schemaShape(s)
+		}
+		return true
+	}
+	name := hint
+	if r, ok := renames[hint]; ok {
+		name = r.name
+	}
+	suffix := lastWord(name)
+	variants := make([]*decl, 0, len(alts))
+	distinct := false
+	for _, alt := range alts {
+		value := alt.property(tag).Enum[0].(string)
+		variantName := camel(value) + suffix
+		v := &decl{
+			name:   variantName,
+			doc:    fmt.Sprintf("%s holds the fields of %s with %s %s.", variantName, article(name), tag, strconvQuote(value)),
+			fields: g.fields(alt, variantName, func(prop string) bool { return common(prop) || prop == tag }),
+		}
+		distinct = distinct || len(v.fields) > 0
+		variants = append(variants, v)
+	}
+	if !distinct {
+		return g.structType(alts[0], hint, doc, false)
+	}
+
+	d := &decl{name: hint, doc: doc}
+	d.fields = g.fields(alts[0], name, func(prop string) bool { return prop == tag || !common(prop) })
+	d.tag = &field{name: camel(tag), typ: "string", json: tag, doc: fieldDoc(alts[0].property(tag))}
+	for i, v := range variants {
+		value := alts[i].property(tag).Enum[0].(string)
+		d.variants = append(d.variants, variant{value: value, field: camel(value), typ: "*" + g.declare(v, true)})
+	}
+	g.usesJSON = true
+	return g.declare(d, false)
+}
+
+// schemaShape summarises a schema without its descriptions and examples.
+func schemaShape(s *schema) string {
+	if s == nil {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s%v%v%v{", s.Ref, s.Type, s.Nullable, s.Enum)
+	for _, prop := range s.Properties {
+		fmt.Fprintf(&b, "%s:%s,", prop.name, schemaShape(prop.schema))
+	}
+	fmt.Fprintf(&b, "}[%s]", schemaShape(s.Items))
+	for _, alt := range append(append([]*schema{}, s.OneOf...), s.AnyOf...) {
+		fmt.Fprintf(&b, "|%s", schemaShape(alt))
+	}
+	if values := s.mapValues(); values != nil {
+		fmt.Fprintf(&b, "map:%s", schemaShape(values))
+	}
+	return b.String()
+}
+
+// declare registers d, reusing an existing type with the same shape. Fixed
+// names are kept as given; others go through renames.
+func (g *generator) declare(d *decl, fixed bool) string {
+	sig := signature(d)
+	if !fixed {
+		if existing, ok := g.bySig[sig]; ok {
+			return existing
+		}
+		if r, ok := renames[d.name]; ok {
+			d.name, d.doc = r.name, r.doc
+		}
+	}
+	d.doc = strings.ReplaceAll(d.doc, namePlaceholder, d.name)
+	if existing := g.decls[d.name]; existing != nil {
+		if signature(existing) == sig {
+			return d.name
+		}
+		return g.fail("two different schemas both map to %s; add a rename", d.name)
+	}
+	g.decls[d.name] = d
+	if _, ok := g.bySig[sig]; !ok {
+		g.bySig[sig] = d.name
+	}
+	return d.name
+}
+
+func signature(d *decl) string {
+	var b strings.Builder
+	for _, f := range d.fields {
+		fmt.Fprintf(&b, "%s %s %s %v;", f.name, f.typ, f.json, f.omitempty)
+	}
+	if d.tag != nil {
+		fmt.Fprintf(&b, "tag %s;", d.tag.json)
+		for _, v := range d.variants {
+			fmt.Fprintf(&b, "%s %s;", v.value, v.typ)
+		}
+	}
+	return b.String()
+}
+
+func (g *generator) render() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("// Code generated by modelgen from openapi.json. DO NOT EDIT.\n\npackage attio\n\n")
+	if g.usesJSON {
+		b.WriteString("import \"encoding/json\"\n\n")
+	}
+
+	written := map[string]bool{}
+	names := make([]string, 0, len(g.decls))
+	for _, root := range roots {
+		names = append(names, root.name)
+		written[root.name] = true
+	}
+	rest := make([]string, 0, len(g.decls))
+	for name := range g.decls {
+		if !written[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	names = append(names, rest...)
+
+	unions := false
+	for _, name := range names {
+		d := g.decls[name]
+		writeComment(&b, "", d.doc)
+		fmt.Fprintf(&b, "type %s struct {\n", d.name)
+		for _, f := range d.fields {
+			writeField(&b, f)
+		}
+		if d.tag != nil {
+			writeField(&b, *d.tag)
+			b.WriteString("\n")
+			for _, v := range d.variants {
+				fmt.Fprintf(&b, "\t%s %s `json:\"-\"`\n", v.field, v.typ)
+			}
+		}
+		b.WriteString("}\n\n")
+		if d.tag != nil {
+			unions = true
+			renderUnionMethods(&b, d)
+		}
+	}
+	if unions {
+		b.WriteString(mergeJSONSource)
+	}
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w\n%s", err, b.String())
+	}
+	return src, nil
+}
+
+func renderUnionMethods(b *bytes.Buffer, d *decl) {
+	fmt.Fprintf(b, "// UnmarshalJSON decodes the shared fields and the variant named by %s.\n", d.tag.name)
+	fmt.Fprintf(b, "func (v *%s) UnmarshalJSON(data []byte) error {\n", d.name)
+	fmt.Fprintf(b, "\ttype plain %s\n\tif err := json.Unmarshal(data, (*plain)(v)); err != nil {\n\t\treturn err\n\t}\n", d.name)
+	fmt.Fprintf(b, "\tswitch v.%s {\n", d.tag.name)
+	for _, variant := range d.variants {
+		fmt.Fprintf(b, "\tcase %s:\n\t\tv.%s = new(%s)\n\t\treturn json.Unmarshal(data, v.%s)\n",
+			strconvQuote(variant.value), variant.field, strings.TrimPrefix(variant.typ, "*"), variant.field)
+	}
+	b.WriteString("\t}\n\treturn nil\n}\n\n")
+
+	fmt.Fprintf(b, "// MarshalJSON writes the shared fields and the set variant as one object.\n")
+	fmt.Fprintf(b, "func (v %s) MarshalJSON() ([]byte, error) {\n", d.name)
+	fmt.Fprintf(b, "\ttype plain %s\n\tvar variant any\n\tswitch {\n", d.name)
+	for _, variant := range d.variants {
+		fmt.Fprintf(b, "\tcase v.%s != nil:\n\t\tvariant = v.%s\n", variant.field, variant.field)
+	}
+	b.WriteString("\t}\n\treturn mergeJSON(plain(v), variant)\n}\n\n")
+}
+
+const mergeJSONSource = `// mergeJSON marshals base and extra and joins them into a single object.
+func mergeJSON(base any, extra any) ([]byte, error) {
+	out, err := json.Marshal(base)
+	if err != nil || extra == nil {
+		return out, err
+	}
+	more, err := json.Marshal(extra)
+	if err != nil {
+		return nil, err
+	}
+	if len(more) <= 2 {
+		return out, nil
+	}
+	if len(out) <= 2 {
+		return more, nil
+	}
+	return append(append(out[:len(out)-1], ','), more[1:]...), nil
+}
+`
+
+func writeField(b *bytes.Buffer, f field) {
+	writeComment(b, "\t", f.doc)
+	tag := f.json
+	if f.omitempty {
+		tag += ",omitempty"
+	}
+	fmt.Fprintf(b, "\t%s %s `json:%s`\n", f.name, f.typ, strconvQuote(tag))
+}
+
+func writeComment(b *bytes.Buffer, indent string, text string) {
+	if text == "" {
+		return
+	}
+	line := indent + "//"
+	for _, word := range strings.Fields(text) {
+		if len(line)+1+len(word) > 80 && line != indent+"//" {
+			b.WriteString(line + "\n")
+			line = indent + "//"
+		}
+		line += " " + word
+	}
+	b.WriteString(line + "\n")
+}
+
+var markdownLink = regexp.MustCompile(`\[([^\]]+)\]\([^)]*\)`)
+
+func fieldDoc(s *schema) string {
+	if s == nil {
+		return ""
+	}
+	doc := firstSentence(s.Description)
+	if n := len(s.Enum); n > 1 && n <= 8 {
+		values := make([]string, 0, n)
+		for _, v := range s.Enum {
+			values = append(values, fmt.Sprintf("%q", v))
+		}
+		doc = strings.TrimSpace(doc + " One of " + strings.Join(values[:n-1], ", ") + " or " + values[n-1] + ".")
+	}
+	return doc
+}
+
+func firstSentence(text string) string {
+	text = strings.Join(strings.Fields(markdownLink.ReplaceAllString(text, "$1")), " ")
+	if i := strings.Index(text, ". "); i >= 0 {
+		text = text[:i+1]
+	}
+	return text
+}
+
+var initialisms = map[string]bool{"id": true, "url": true, "api": true, "uuid": true}
+
+// camel converts snake, kebab and $-prefixed names to exported Go names.
+func camel(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var b strings.Builder
+	for _, word := range words {
+		if initialisms[strings.ToLower(word)] {
+			b.WriteString(strings.ToUpper(word))
+			continue
+		}
+		if strings.ToLower(word) == "ids" {
+			b.WriteString("IDs")
+			continue
+		}
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return b.String()
+}
+
+func singular(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies"):
+		return strings.TrimSuffix(name, "ies") + "y"
+	case strings.HasSuffix(name, "ss"):
+		return name
+	default:
+		return strings.TrimSuffix(name, "s")
+	}
+}
+
+func lastWord(name string) string {
+	for i := len(name) - 1; i > 0; i-- {
+		if unicode.IsUpper(rune(name[i])) {
+			return name[i:]
+		}
+	}
+	return name
+}
+
+func article(name string) string {
+	if name != "" && strings.ContainsRune("AEIOU", rune(name[0])) {
+		return "an " + name
+	}
+	return "a " + name
+}
+
+func pointer(typ string, nullable bool) string {
+	if !nullable || typ == "any" || strings.HasPrefix(typ, "*") || strings.HasPrefix(typ, "[]") ||
+		strings.HasPrefix(typ, "map[") || typ == "json.RawMessage" {
+		return typ
+	}
+	return "*" + typ
+}
+
+func strconvQuote(s string) string {
+	return fmt.Sprintf("%q", s)
+}
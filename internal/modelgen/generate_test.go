@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	attiocli "github.com/failup-ventures/attio-cli"
+)
+
+func TestModelsUpToDate(t *testing.T) {
+	t.Parallel()
+
+	want, err := generate(attiocli.OpenAPISpec)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	got, err := os.ReadFile("../../attio/models_gen.go")
+	if err != nil {
+		t.Fatalf("read models: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatal("attio/models_gen.go is stale; run go generate ./attio")
+	}
+}
+
+func TestNames(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"record_id":          "RecordID",
+		"web_url":            "WebURL",
+		"api_slug":           "APISlug",
+		"allowed_object_ids": "AllowedObjectIDs",
+		"email-address":      "EmailAddress",
+		"$or":                "Or",
+		"line_1":             "Line1",
+	}
+	for in, want := range cases {
+		if got := camel(in); got != want {
+			t.Errorf("camel(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if got := singular("RecordEntries"); got != "RecordEntry" {
+		t.Errorf("singular = %q", got)
+	}
+	if got := singular("ListWorkspaceMemberAccess"); got != "ListWorkspaceMemberAccess" {
+		t.Errorf("singular = %q", got)
+	}
+}
@@ -0,0 +1,26 @@
+// Command modelgen generates the typed models in package attio from the
+// bundled openapi.json. Run it through go generate in the attio directory.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	attiocli "github.com/failup-ventures/attio-cli"
+)
+
+func main() {
+	out := flag.String("o", "models_gen.go", "output file")
+	flag.Parse()
+
+	src, err := generate(attiocli.OpenAPISpec)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "modelgen:", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "modelgen:", err)
+		os.Exit(1)
+	}
+}
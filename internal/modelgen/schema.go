@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// document is the part of openapi.json the generator reads.
+type document struct {
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas map[string]*schema `json:"schemas"`
+	} `json:"components"`
+}
+
+type operation struct {
+	Responses map[string]struct {
+		Content map[string]struct {
+			Schema *schema `json:"schema"`
+		} `json:"content"`
+	} `json:"responses"`
+}
+
+// responseData returns the schema of the data field in the 200 response of
+// GET path.
+func (d *document) responseData(path string) (*schema, error) {
+	raw, ok := d.Paths[path]["get"]
+	if !ok {
+		return nil, fmt.Errorf("GET %s is not in the spec", path)
+	}
+	var op operation
+	if err := json.Unmarshal(raw, &op); err != nil {
+		return nil, fmt.Errorf("GET %s: %w", path, err)
+	}
+	body := op.Responses["200"].Content["application/json"].Schema
+	if body == nil {
+		return nil, fmt.Errorf("GET %s has no JSON response", path)
+	}
+	for _, prop := range body.Properties {
+		if prop.name == "data" {
+			return prop.schema, nil
+		}
+	}
+	return nil, fmt.Errorf("GET %s response has no data field", path)
+}
+
+type schema struct {
+	Ref                  string          `json:"$ref"`
+	Type                 typeSet         `json:"type"`
+	Nullable             bool            `json:"nullable"`
+	Description          string          `json:"description"`
+	Enum                 []any           `json:"enum"`
+	Properties           properties      `json:"properties"`
+	Required             []string        `json:"required"`
+	AdditionalProperties json.RawMessage `json:"additionalProperties"`
+	Items                *schema         `json:"items"`
+	OneOf                []*schema       `json:"oneOf"`
+	AnyOf                []*schema       `json:"anyOf"`
+}
+
+// mapValues returns the schema of additionalProperties when it is a schema
+// rather than a boolean.
+func (s *schema) mapValues() *schema {
+	raw := bytes.TrimSpace(s.AdditionalProperties)
+	if len(raw) == 0 || raw[0] != '{' {
+		return nil
+	}
+	var out schema
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil
+	}
+	return &out
+}
+
+func (s *schema) property(name string) *schema {
+	for _, prop := range s.Properties {
+		if prop.name == name {
+			return prop.schema
+		}
+	}
+	return nil
+}
+
+func (s *schema) isRequired(name string) bool {
+	for _, r := range s.Required {
+		if r == name {
+			return true
+		}
+	}
+	return false
+}
+
+// typeSet accepts both "type": "string" and "type": ["string", "null"].
+type typeSet []string
+
+func (t *typeSet) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*t = typeSet{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*t = many
+	return nil
+}
+
+// split returns the non-null types and whether null is allowed.
+func (t typeSet) split() ([]string, bool) {
+	out := make([]string, 0, len(t))
+	nullable := false
+	for _, typ := range t {
+		if typ == "null" {
+			nullable = true
+			continue
+		}
+		out = append(out, typ)
+	}
+	return out, nullable
+}
+
+type property struct {
+	name   string
+	schema *schema
+}
+
+// properties keeps the order fields appear in the spec so generated structs
+// read like the API reference.
+type properties []property
+
+func (p *properties) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return fmt.Errorf("properties: expected an object")
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		var s schema
+		if err := dec.Decode(&s); err != nil {
+			return fmt.Errorf("property %v: %w", tok, err)
+		}
+		*p = append(*p, property{name: tok.(string), schema: &s})
+	}
+	_, err := dec.Token()
+	return err
+}
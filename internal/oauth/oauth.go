@@ -0,0 +1,246 @@
+// Package oauth implements the OAuth 2.0 authorization-code flow with PKCE
+// used by auth login --oauth, and refreshing the tokens it returns.
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultAuthorizeURL = "https://app.attio.com/authorize"
+	DefaultTokenURL     = "https://app.attio.com/oauth/token"
+	CallbackPath        = "/callback"
+)
+
+// expiryLeeway refreshes tokens shortly before they expire so a request
+// started with a valid token does not fail halfway.
+const expiryLeeway = time.Minute
+
+// Config describes an OAuth client.
+type Config struct {
+	ClientID     string
+	ClientSecret string
+	AuthorizeURL string
+	TokenURL     string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// Token is an access token and what is needed to refresh it.
+type Token struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	TokenType    string    `json:"token_type,omitempty"`
+	Scope        string    `json:"scope,omitempty"`
+	Expiry       time.Time `json:"expiry,omitempty"`
+}
+
+// Expired reports whether the token expires within a minute of now. Tokens
+// without an expiry never expire.
+func (t Token) Expired(now time.Time) bool {
+	return !t.Expiry.IsZero() && !now.Add(expiryLeeway).Before(t.Expiry)
+}
+
+// Error is an error response from the token endpoint.
+type Error struct {
+	StatusCode  int
+	Code        string
+	Description string
+}
+
+func (e *Error) Error() string {
+	if e.Description != "" {
+		return fmt.Sprintf("oauth token request failed (%d %s): %s", e.StatusCode, e.Code, e.Description)
+	}
+	return fmt.Sprintf("oauth token request failed (%d %s)", e.StatusCode, e.Code)
+}
+
+// NewVerifier returns a random PKCE code verifier and its S256 challenge.
+func NewVerifier() (verifier string, challenge string, err error) {
+	verifier, err = randomString(32)
+	if err != nil {
+		return "", "", err
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	return verifier, base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+// NewState returns a random value to tie the callback to this login.
+func NewState() (string, error) {
+	return randomString(16)
+}
+
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate random value: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// AuthCodeURL returns the URL the user opens to approve access.
+func (c Config) AuthCodeURL(state string, challenge string) string {
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", c.ClientID)
+	query.Set("redirect_uri", c.RedirectURL)
+	query.Set("state", state)
+	query.Set("code_challenge", challenge)
+	query.Set("code_challenge_method", "S256")
+	if len(c.Scopes) > 0 {
+		query.Set("scope", strings.Join(c.Scopes, " "))
+	}
+	sep := "?"
+	if strings.Contains(c.AuthorizeURL, "?") {
+		sep = "&"
+	}
+	return c.AuthorizeURL + sep + query.Encode()
+}
+
+// Exchange trades an authorization code for a token.
+func (c Config) Exchange(ctx context.Context, httpClient *http.Client, code string, verifier string) (*Token, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", c.RedirectURL)
+	form.Set("code_verifier", verifier)
+	return c.token(ctx, httpClient, form)
+}
+
+// Refresh trades a refresh token for a new token. The refresh token is kept
+// when the server does not rotate it.
+func (c Config) Refresh(ctx context.Context, httpClient *http.Client, refreshToken string) (*Token, error) {
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", refreshToken)
+	tok, err := c.token(ctx, httpClient, form)
+	if err != nil {
+		return nil, err
+	}
+	if tok.RefreshToken == "" {
+		tok.RefreshToken = refreshToken
+	}
+	return tok, nil
+}
+
+func (c Config) token(ctx context.Context, httpClient *http.Client, form url.Values) (*Token, error) {
+	form.Set("client_id", c.ClientID)
+	if c.ClientSecret != "" {
+		form.Set("client_secret", c.ClientSecret)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("read token response: %w", err)
+	}
+
+	var out struct {
+		AccessToken      string `json:"access_token"`
+		RefreshToken     string `json:"refresh_token"`
+		TokenType        string `json:"token_type"`
+		Scope            string `json:"scope"`
+		ExpiresIn        int64  `json:"expires_in"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil && resp.StatusCode < 400 {
+		return nil, fmt.Errorf("decode token response: %w", err)
+	}
+	if resp.StatusCode >= 400 || out.Error != "" {
+		code := out.Error
+		if code == "" {
+			code = http.StatusText(resp.StatusCode)
+		}
+		return nil, &Error{StatusCode: resp.StatusCode, Code: code, Description: out.ErrorDescription}
+	}
+	if out.AccessToken == "" {
+		return nil, errors.New("token response has no access_token")
+	}
+	tok := &Token{
+		AccessToken:  out.AccessToken,
+		RefreshToken: out.RefreshToken,
+		TokenType:    out.TokenType,
+		Scope:        out.Scope,
+	}
+	if out.ExpiresIn > 0 {
+		tok.Expiry = time.Now().Add(time.Duration(out.ExpiresIn) * time.Second).UTC()
+	}
+	return tok, nil
+}
+
+// WaitForCode serves the redirect on listener until one callback arrives
+// and returns its authorization code. The callback must carry state.
+func WaitForCode(ctx context.Context, listener net.Listener, state string) (string, error) {
+	type result struct {
+		code string
+		err  error
+	}
+	results := make(chan result, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc(CallbackPath, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		var res result
+		switch {
+		case query.Get("state") != state:
+			res.err = errors.New("callback state does not match; try logging in again")
+		case query.Get("error") != "":
+			res.err = fmt.Errorf("authorization denied: %s %s", query.Get("error"), query.Get("error_description"))
+		case query.Get("code") == "":
+			res.err = errors.New("callback has no authorization code")
+		default:
+			res.code = query.Get("code")
+		}
+		message := "Logged in to Attio. You can close this window."
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if res.err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			message = "Login failed: " + res.err.Error()
+		}
+		_, _ = fmt.Fprintf(w, "<!doctype html><p>%s</p>\n", html.EscapeString(message))
+		select {
+		case results <- res:
+		default:
+		}
+	})
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() { _ = server.Serve(listener) }()
+	defer func() {
+		// Shutdown lets the callback page finish rendering.
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	select {
+	case res := <-results:
+		return res.code, res.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
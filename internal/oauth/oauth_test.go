@@ -0,0 +1,125 @@
+package oauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestVerifierAndAuthCodeURL(t *testing.T) {
+	t.Parallel()
+
+	verifier, challenge, err := NewVerifier()
+	if err != nil {
+		t.Fatalf("new verifier: %v", err)
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	if challenge != base64.RawURLEncoding.EncodeToString(sum[:]) || len(verifier) < 43 {
+		t.Fatalf("challenge does not match verifier %q", verifier)
+	}
+
+	cfg := Config{ClientID: "app", AuthorizeURL: "https://example.com/authorize", RedirectURL: "http://127.0.0.1:1/callback", Scopes: []string{"record:read", "note:read"}}
+	u, err := url.Parse(cfg.AuthCodeURL("st", challenge))
+	if err != nil {
+		t.Fatalf("parse url: %v", err)
+	}
+	q := u.Query()
+	if q.Get("response_type") != "code" || q.Get("client_id") != "app" || q.Get("state") != "st" ||
+		q.Get("code_challenge") != challenge || q.Get("code_challenge_method") != "S256" || q.Get("scope") != "record:read note:read" {
+		t.Fatalf("unexpected authorization query: %v", q)
+	}
+}
+
+func TestExchangeAndRefresh(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		w.Header().Set("Content-Type", "application/json")
+		switch r.PostForm.Get("grant_type") {
+		case "authorization_code":
+			if r.PostForm.Get("code") != "the-code" || r.PostForm.Get("code_verifier") != "the-verifier" || r.PostForm.Get("client_secret") != "shh" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"invalid_request"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"access_token":"a1","refresh_token":"r1","token_type":"Bearer","expires_in":3600,"scope":"record:read"}`))
+		case "refresh_token":
+			if r.PostForm.Get("refresh_token") != "r1" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"invalid_grant","error_description":"refresh token revoked"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"access_token":"a2","token_type":"Bearer","expires_in":60}`))
+		}
+	}))
+	defer srv.Close()
+
+	cfg := Config{ClientID: "app", ClientSecret: "shh", TokenURL: srv.URL}
+	tok, err := cfg.Exchange(context.Background(), nil, "the-code", "the-verifier")
+	if err != nil {
+		t.Fatalf("exchange: %v", err)
+	}
+	if tok.AccessToken != "a1" || tok.RefreshToken != "r1" || tok.Scope != "record:read" || tok.Expired(time.Now()) {
+		t.Fatalf("unexpected token: %+v", tok)
+	}
+
+	tok, err = cfg.Refresh(context.Background(), nil, "r1")
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if tok.AccessToken != "a2" || tok.RefreshToken != "r1" {
+		t.Fatalf("expected the refresh token to be kept, got %+v", tok)
+	}
+	if !tok.Expired(time.Now()) {
+		t.Fatalf("expected a token expiring within the leeway to count as expired")
+	}
+
+	_, err = cfg.Refresh(context.Background(), nil, "revoked")
+	var oauthErr *Error
+	if !errors.As(err, &oauthErr) || oauthErr.Code != "invalid_grant" || oauthErr.Description != "refresh token revoked" {
+		t.Fatalf("expected invalid_grant, got %v", err)
+	}
+}
+
+func TestWaitForCodeChecksState(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	callback := "http://" + listener.Addr().String() + CallbackPath
+	go func() {
+		resp, err := http.Get(callback + "?code=c&state=wrong")
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+	}()
+	if _, err := WaitForCode(context.Background(), listener, "expected"); err == nil {
+		t.Fatal("expected a state mismatch error")
+	}
+
+	listener, err = net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	callback = "http://" + listener.Addr().String() + CallbackPath
+	go func() {
+		resp, err := http.Get(callback + "?code=c&state=expected")
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+	}()
+	code, err := WaitForCode(context.Background(), listener, "expected")
+	if err != nil || code != "c" {
+		t.Fatalf("expected code c, got %q (%v)", code, err)
+	}
+}
@@ -0,0 +1,211 @@
+// Package openapi indexes the bundled Attio OpenAPI description for request
+// validation, pagination detection and shell completion.
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	attiocli "github.com/failup-ventures/attio-cli"
+)
+
+// Pagination styles reported by Route.Pagination.
+const (
+	PaginationNone   = ""
+	PaginationOffset = "offset"
+	PaginationCursor = "cursor"
+)
+
+// Route is one method + path template from the spec, such as
+// "POST /v2/objects/{object}/records/query".
+type Route struct {
+	Method      string
+	Path        string
+	Summary     string
+	QueryParams []string
+	BodyParams  []string
+	Pagination  string
+	// OffsetInBody is true when limit/offset are request body fields rather
+	// than query parameters (the POST query endpoints).
+	OffsetInBody bool
+}
+
+var (
+	routesOnce sync.Once
+	routes     []Route
+	routesErr  error
+)
+
+// Routes returns every route in the bundled spec, sorted by path then method.
+func Routes() ([]Route, error) {
+	routesOnce.Do(func() {
+		routes, routesErr = parseRoutes(attiocli.OpenAPISpec)
+	})
+	return routes, routesErr
+}
+
+type specDocument struct {
+	Paths map[string]map[string]specOperation `json:"paths"`
+}
+
+type specOperation struct {
+	Summary    string `json:"summary"`
+	Parameters []struct {
+		Name string `json:"name"`
+		In   string `json:"in"`
+	} `json:"parameters"`
+	RequestBody struct {
+		Content map[string]struct {
+			Schema struct {
+				Properties map[string]json.RawMessage `json:"properties"`
+			} `json:"schema"`
+		} `json:"content"`
+	} `json:"requestBody"`
+}
+
+func parseRoutes(raw []byte) ([]Route, error) {
+	var doc specDocument
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("parse openapi.json: %w", err)
+	}
+	out := make([]Route, 0, len(doc.Paths)*2)
+	for path, methods := range doc.Paths {
+		for method, op := range methods {
+			route := Route{
+				Method:  strings.ToUpper(method),
+				Path:    path,
+				Summary: op.Summary,
+			}
+			for _, param := range op.Parameters {
+				if param.In == "query" {
+					route.QueryParams = append(route.QueryParams, param.Name)
+				}
+			}
+			for name := range op.RequestBody.Content["application/json"].Schema.Properties {
+				route.BodyParams = append(route.BodyParams, name)
+			}
+			sort.Strings(route.BodyParams)
+			switch {
+			case contains(route.QueryParams, "cursor"):
+				route.Pagination = PaginationCursor
+			case contains(route.QueryParams, "offset"):
+				route.Pagination = PaginationOffset
+			case contains(route.BodyParams, "offset"):
+				route.Pagination = PaginationOffset
+				route.OffsetInBody = true
+			}
+			out = append(out, route)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Path != out[j].Path {
+			return out[i].Path < out[j].Path
+		}
+		return out[i].Method < out[j].Method
+	})
+	return out, nil
+}
+
+// Match finds the route for method and a concrete path (query string
+// ignored). When the path exists but not for method, allowed lists the
+// methods it does support.
+func Match(method string, path string) (route Route, allowed []string, ok bool, err error) {
+	all, err := Routes()
+	if err != nil {
+		return Route{}, nil, false, err
+	}
+	method = strings.ToUpper(method)
+	path, _, _ = strings.Cut(path, "?")
+	segments := splitPath(path)
+	for _, r := range all {
+		if !templateMatches(splitPath(r.Path), segments) {
+			continue
+		}
+		if r.Method == method {
+			return r, nil, true, nil
+		}
+		allowed = append(allowed, r.Method)
+	}
+	return Route{}, allowed, false, nil
+}
+
+// CompletePath suggests paths for a partially typed path, limited to routes
+// supporting method when it is set. Concrete segments the user already typed
+// are kept in place of template parameters.
+func CompletePath(method string, prefix string) ([]string, error) {
+	all, err := Routes()
+	if err != nil {
+		return nil, err
+	}
+	typed := splitPath(prefix)
+	partial := ""
+	if !strings.HasSuffix(prefix, "/") && len(typed) > 0 {
+		partial = typed[len(typed)-1]
+		typed = typed[:len(typed)-1]
+	}
+
+	seen := map[string]bool{}
+	out := make([]string, 0)
+	for _, r := range all {
+		if method != "" && r.Method != strings.ToUpper(method) {
+			continue
+		}
+		template := splitPath(r.Path)
+		if len(template) <= len(typed) || !templateMatches(template[:len(typed)], typed) {
+			continue
+		}
+		next := template[len(typed)]
+		if !strings.HasPrefix(next, partial) && !(isParam(next) && partial == "") {
+			continue
+		}
+		candidate := "/" + strings.Join(append(append([]string{}, typed...), template[len(typed):]...), "/")
+		if !seen[candidate] {
+			seen[candidate] = true
+			out = append(out, candidate)
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+func templateMatches(template []string, segments []string) bool {
+	if len(template) != len(segments) {
+		return false
+	}
+	for i, part := range template {
+		if isParam(part) {
+			if segments[i] == "" {
+				return false
+			}
+			continue
+		}
+		if part != segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+func isParam(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+func contains(values []string, want string) bool {
+	for _, v := range values {
+		if v == want {
+			return true
+		}
+	}
+	return false
+}
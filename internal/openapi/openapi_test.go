@@ -0,0 +1,54 @@
+package openapi
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatchRoutes(t *testing.T) {
+	t.Parallel()
+
+	route, _, ok, err := Match("post", "/v2/objects/people/records/query?x=1")
+	if err != nil || !ok {
+		t.Fatalf("expected match, got ok=%v err=%v", ok, err)
+	}
+	if route.Path != "/v2/objects/{object}/records/query" || route.Pagination != PaginationOffset || !route.OffsetInBody {
+		t.Fatalf("unexpected route: %+v", route)
+	}
+
+	route, _, ok, _ = Match("GET", "/v2/meetings")
+	if !ok || route.Pagination != PaginationCursor {
+		t.Fatalf("expected cursor pagination for meetings, got %+v", route)
+	}
+
+	route, _, ok, _ = Match("GET", "/v2/notes")
+	if !ok || route.Pagination != PaginationOffset || route.OffsetInBody {
+		t.Fatalf("expected query offset pagination for notes, got %+v", route)
+	}
+
+	_, allowed, ok, _ := Match("DELETE", "/v2/objects/people")
+	if ok || strings.Join(allowed, ",") != "GET,PATCH" {
+		t.Fatalf("expected GET,PATCH to be allowed, got ok=%v allowed=%v", ok, allowed)
+	}
+
+	if _, allowed, ok, _ := Match("GET", "/v2/nope"); ok || len(allowed) != 0 {
+		t.Fatalf("expected unknown path, got ok=%v allowed=%v", ok, allowed)
+	}
+}
+
+func TestCompletePath(t *testing.T) {
+	t.Parallel()
+
+	got, err := CompletePath("POST", "/v2/lists/deals/en")
+	if err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	if strings.Join(got, ",") != "/v2/lists/deals/entries,/v2/lists/deals/entries/query" {
+		t.Fatalf("unexpected completions: %v", got)
+	}
+
+	got, _ = CompletePath("", "/v2/wo")
+	if strings.Join(got, ",") != "/v2/workspace_members,/v2/workspace_members/{workspace_member_id}" {
+		t.Fatalf("unexpected completions: %v", got)
+	}
+}
@@ -58,3 +58,22 @@ func WriteJSON(ctx context.Context, w io.Writer, value any) error {
 	}
 	return nil
 }
+
+// WriteNDJSON writes value as one compact JSON line. --select paths apply to
+// the value itself; --results-only has no effect on individual lines.
+func WriteNDJSON(ctx context.Context, w io.Writer, value any) error {
+	if t, ok := JSONTransformFromContext(ctx); ok && len(t.Select) > 0 {
+		transformed, err := applyJSONTransform(value, JSONTransform{Select: t.Select})
+		if err != nil {
+			return fmt.Errorf("transform json: %w", err)
+		}
+		value = transformed
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(value); err != nil {
+		return fmt.Errorf("encode json: %w", err)
+	}
+	return nil
+}
@@ -67,3 +67,18 @@ func TestWriteJSONWithSelect(t *testing.T) {
 		t.Fatalf("unexpected selected workspace name: %#v", out)
 	}
 }
+
+func TestWriteNDJSONWithSelect(t *testing.T) {
+	ctx := WithJSONTransform(context.Background(), JSONTransform{ResultsOnly: true, Select: []string{"id.record_id"}})
+
+	var buf bytes.Buffer
+	for _, id := range []string{"r1", "r2"} {
+		if err := WriteNDJSON(ctx, &buf, map[string]any{"id": map[string]any{"record_id": id}, "values": map[string]any{}}); err != nil {
+			t.Fatalf("write ndjson: %v", err)
+		}
+	}
+	want := "{\"id.record_id\":\"r1\"}\n{\"id.record_id\":\"r2\"}\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected ndjson output: %q", buf.String())
+	}
+}
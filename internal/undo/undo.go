@@ -0,0 +1,197 @@
+// Package undo stores snapshots of records and list entries taken before
+// the CLI changes or deletes them, so the change can be reverted later.
+package undo
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Kinds of snapshotted resources.
+const (
+	KindRecord = "record"
+	KindEntry  = "entry"
+)
+
+// Actions that take a snapshot.
+const (
+	ActionUpdate  = "update"
+	ActionReplace = "replace"
+	ActionDelete  = "delete"
+)
+
+// Retention defaults: older snapshots, and any beyond the newest
+// DefaultMaxOperations, are removed whenever a new one is saved.
+const (
+	DefaultMaxOperations = 50
+	DefaultMaxAge        = 30 * 24 * time.Hour
+)
+
+// ErrNotFound is returned when an operation has no snapshot.
+var ErrNotFound = errors.New("undo: operation not found")
+
+// Item is the state of one record or entry before an operation.
+type Item struct {
+	Kind   string `json:"kind"`
+	Action string `json:"action"`
+	// Parent is the object of a record or the list of an entry.
+	Parent string `json:"parent"`
+	ID     string `json:"id"`
+	// Keys are the attributes an update or replace wrote; undoing it writes
+	// their old values back. Undoing a delete recreates the whole resource.
+	Keys   []string       `json:"keys,omitempty"`
+	Before map[string]any `json:"before"`
+}
+
+// Operation is one command's snapshot.
+type Operation struct {
+	ID      string    `json:"id"`
+	Time    time.Time `json:"time"`
+	Profile string    `json:"profile"`
+	Command string    `json:"command"`
+	Items   []Item    `json:"items"`
+}
+
+// Store keeps one JSON file per operation in Dir.
+type Store struct {
+	Dir           string
+	MaxOperations int
+	MaxAge        time.Duration
+}
+
+// NewStore returns a store in dir with the default retention limits.
+func NewStore(dir string) Store {
+	return Store{Dir: dir, MaxOperations: DefaultMaxOperations, MaxAge: DefaultMaxAge}
+}
+
+// NewID returns a sortable operation ID such as 20260131-140502-9f3c1a.
+func NewID(now time.Time) string {
+	b := make([]byte, 3)
+	_, _ = rand.Read(b)
+	return now.UTC().Format("20060102-150405") + "-" + hex.EncodeToString(b)
+}
+
+func (s Store) path(id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) || strings.HasPrefix(id, ".") {
+		return "", fmt.Errorf("invalid operation id %q", id)
+	}
+	return filepath.Join(s.Dir, id+".json"), nil
+}
+
+// Save writes op, assigning an ID and time when they are missing, and then
+// applies the retention limits.
+func (s Store) Save(op *Operation) error {
+	now := time.Now()
+	if op.Time.IsZero() {
+		op.Time = now.UTC()
+	}
+	if op.ID == "" {
+		op.ID = NewID(op.Time)
+	}
+	path, err := s.path(op.ID)
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(op, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal undo snapshot: %w", err)
+	}
+	if err := os.MkdirAll(s.Dir, 0o700); err != nil {
+		return fmt.Errorf("create undo dir: %w", err)
+	}
+	if err := os.WriteFile(path, append(b, '\n'), 0o600); err != nil {
+		return fmt.Errorf("write undo snapshot: %w", err)
+	}
+	return s.prune(now)
+}
+
+// Load reads the operation with id.
+func (s Store) Load(id string) (*Operation, error) {
+	path, err := s.path(id)
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read undo snapshot: %w", err)
+	}
+	var op Operation
+	if err := json.Unmarshal(b, &op); err != nil {
+		return nil, fmt.Errorf("parse undo snapshot %s: %w", id, err)
+	}
+	return &op, nil
+}
+
+// List returns the stored operations, newest first. A non-empty profile
+// limits them to that profile.
+func (s Store) List(profile string) ([]Operation, error) {
+	names, err := os.ReadDir(s.Dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read undo dir: %w", err)
+	}
+	var ops []Operation
+	for _, entry := range names {
+		id, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		op, err := s.Load(id)
+		if err != nil {
+			continue
+		}
+		if profile == "" || op.Profile == profile {
+			ops = append(ops, *op)
+		}
+	}
+	sort.Slice(ops, func(i, j int) bool {
+		if !ops[i].Time.Equal(ops[j].Time) {
+			return ops[i].Time.After(ops[j].Time)
+		}
+		return ops[i].ID > ops[j].ID
+	})
+	return ops, nil
+}
+
+// Remove deletes the operation with id.
+func (s Store) Remove(id string) error {
+	path, err := s.path(id)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("remove undo snapshot: %w", err)
+	}
+	return nil
+}
+
+func (s Store) prune(now time.Time) error {
+	ops, err := s.List("")
+	if err != nil {
+		return err
+	}
+	for i, op := range ops {
+		tooMany := s.MaxOperations > 0 && i >= s.MaxOperations
+		tooOld := s.MaxAge > 0 && now.Sub(op.Time) > s.MaxAge
+		if tooMany || tooOld {
+			if err := s.Remove(op.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
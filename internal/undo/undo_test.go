@@ -0,0 +1,52 @@
+package undo
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStoreSaveListAndRetention(t *testing.T) {
+	t.Parallel()
+
+	store := Store{Dir: t.TempDir(), MaxOperations: 2, MaxAge: time.Hour}
+	if ops, err := store.List(""); err != nil || len(ops) != 0 {
+		t.Fatalf("expected an empty store, got %v %v", ops, err)
+	}
+
+	now := time.Now().UTC()
+	old := &Operation{Time: now.Add(-2 * time.Hour), Profile: "default", Command: "records update"}
+	if err := store.Save(old); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if _, err := store.Load(old.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected snapshots past MaxAge to be pruned, got %v", err)
+	}
+
+	var ids []string
+	for i, profile := range []string{"default", "work", "default"} {
+		op := &Operation{Time: now.Add(time.Duration(i) * time.Second), Profile: profile, Items: []Item{{Kind: KindRecord, Action: ActionDelete, Parent: "people", ID: "r1"}}}
+		if err := store.Save(op); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+		ids = append(ids, op.ID)
+	}
+	all, err := store.List("")
+	if err != nil || len(all) != 2 || all[0].ID != ids[2] || all[1].ID != ids[1] {
+		t.Fatalf("expected the two newest operations, newest first: %+v %v", all, err)
+	}
+	mine, _ := store.List("default")
+	if len(mine) != 1 || mine[0].Items[0].Parent != "people" {
+		t.Fatalf("unexpected profile filter result: %+v", mine)
+	}
+
+	if err := store.Remove(ids[2]); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if _, err := store.Load(ids[2]); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected the removed operation to be gone, got %v", err)
+	}
+	if _, err := store.Load("../config"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected path-like IDs to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package where
+
+import (
+	"errors"
+	"fmt"
+)
+
+var opConstraints = map[string]string{
+	OpEq:         "$eq",
+	OpNotEq:      "$eq",
+	OpContains:   "$contains",
+	OpNotContain: "$contains",
+	OpStarts:     "$starts_with",
+	OpEnds:       "$ends_with",
+	OpGt:         "$gt",
+	OpGte:        "$gte",
+	OpLt:         "$lt",
+	OpLte:        "$lte",
+	OpIn:         "$in",
+	OpNotIn:      "$in",
+	OpEmpty:      "$not_empty",
+	OpNotEmpty:   "$not_empty",
+}
+
+// Compile converts a parsed expression into Attio filter JSON. root is the
+// object or list slug being queried; it is only needed for "->" path filters.
+func Compile(node Node, root string) (map[string]any, error) {
+	switch n := node.(type) {
+	case *And:
+		terms, err := compileTerms(n.Terms, root, func(t Node) []Node {
+			if and, ok := t.(*And); ok {
+				return and.Terms
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"$and": terms}, nil
+	case *Or:
+		terms, err := compileTerms(n.Terms, root, func(t Node) []Node {
+			if or, ok := t.(*Or); ok {
+				return or.Terms
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"$or": terms}, nil
+	case *Not:
+		inner, err := Compile(n.Term, root)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"$not": inner}, nil
+	case *Comparison:
+		return compileComparison(n, root)
+	default:
+		return nil, fmt.Errorf("unsupported expression node %T", node)
+	}
+}
+
+// ParseAndCompile is a convenience wrapper around Parse and Compile.
+func ParseAndCompile(expr string, root string) (map[string]any, error) {
+	node, err := Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+	return Compile(node, root)
+}
+
+func compileTerms(terms []Node, root string, flatten func(Node) []Node) ([]any, error) {
+	out := make([]any, 0, len(terms))
+	for _, term := range terms {
+		if nested := flatten(term); nested != nil {
+			compiled, err := compileTerms(nested, root, flatten)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, compiled...)
+			continue
+		}
+		compiled, err := Compile(term, root)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, compiled)
+	}
+	return out, nil
+}
+
+func compileComparison(c *Comparison, root string) (map[string]any, error) {
+	op, ok := opConstraints[c.Op]
+	if !ok {
+		return nil, fmt.Errorf("unsupported operator %q", c.Op)
+	}
+
+	var operand any
+	switch c.Op {
+	case OpIn, OpNotIn:
+		operand = c.Values
+	case OpEmpty, OpNotEmpty:
+		operand = true
+	default:
+		if len(c.Values) != 1 {
+			return nil, fmt.Errorf("operator %q takes exactly one value", c.Op)
+		}
+		operand = c.Values[0]
+	}
+
+	var constraint any = map[string]any{op: operand}
+	for i := len(c.Path.Property) - 1; i >= 0; i-- {
+		constraint = map[string]any{c.Path.Property[i]: constraint}
+	}
+
+	var filter map[string]any
+	if len(c.Path.Steps) == 1 {
+		filter = map[string]any{c.Path.Steps[0].Attribute: constraint}
+	} else {
+		if root == "" {
+			return nil, errors.New("path filters (->) need the queried object or list")
+		}
+		path := make([]any, 0, len(c.Path.Steps))
+		for i, step := range c.Path.Steps {
+			object := step.Object
+			if i == 0 {
+				object = root
+			}
+			path = append(path, []any{object, step.Attribute})
+		}
+		filter = map[string]any{"path": path, "constraints": constraint}
+	}
+
+	switch c.Op {
+	case OpNotEq, OpNotContain, OpNotIn, OpEmpty:
+		return map[string]any{"$not": filter}, nil
+	}
+	return filter, nil
+}
@@ -0,0 +1,136 @@
+package where
+
+import (
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+	tokArrow
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int // 0-based byte offset into the expression
+}
+
+func (t token) is(kind tokenKind, text string) bool {
+	return t.kind == kind && strings.EqualFold(t.text, text)
+}
+
+func (t token) describe() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of expression"
+	case tokString:
+		return "string " + quote(t.text)
+	default:
+		return quote(t.text)
+	}
+}
+
+var operators = []string{"!=", "!~", ">=", "<=", "^=", "$=", "==", "=", "~", ">", "<"}
+
+func lex(input string) ([]token, error) {
+	tokens := make([]token, 0, 16)
+	i := 0
+	for i < len(input) {
+		r := rune(input[i])
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokLParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokRParen, text: ")", pos: i})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokComma, text: ",", pos: i})
+			i++
+		case strings.HasPrefix(input[i:], "->"):
+			tokens = append(tokens, token{kind: tokArrow, text: "->", pos: i})
+			i += 2
+		case r == '"' || r == '\'':
+			text, next, err := lexString(input, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokString, text: text, pos: i})
+			i = next
+		default:
+			if op := matchOperator(input[i:]); op != "" {
+				tokens = append(tokens, token{kind: tokOp, text: op, pos: i})
+				i += len(op)
+				continue
+			}
+			start := i
+			for i < len(input) && isWordByte(input, i) {
+				i++
+			}
+			if start == i {
+				return nil, &ParseError{Expr: input, Pos: i, Msg: "unexpected character " + quote(string(input[i]))}
+			}
+			tokens = append(tokens, token{kind: tokWord, text: input[start:i], pos: start})
+		}
+	}
+	tokens = append(tokens, token{kind: tokEOF, pos: len(input)})
+	return tokens, nil
+}
+
+func matchOperator(s string) string {
+	for _, op := range operators {
+		if strings.HasPrefix(s, op) {
+			return op
+		}
+	}
+	return ""
+}
+
+func isWordByte(input string, i int) bool {
+	c := input[i]
+	if c == '-' && strings.HasPrefix(input[i:], "->") {
+		return false
+	}
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		return true
+	case c >= 0x80:
+		return true
+	}
+	return strings.IndexByte("_.-:+@/", c) >= 0
+}
+
+func lexString(input string, start int) (string, int, error) {
+	quoteChar := input[start]
+	var b strings.Builder
+	i := start + 1
+	for i < len(input) {
+		c := input[i]
+		switch {
+		case c == '\\' && i+1 < len(input):
+			b.WriteByte(input[i+1])
+			i += 2
+		case c == quoteChar:
+			return b.String(), i + 1, nil
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return "", 0, &ParseError{Expr: input, Pos: start, Msg: "unterminated string"}
+}
+
+func quote(s string) string {
+	return "\"" + s + "\""
+}
@@ -0,0 +1,340 @@
+// Package where parses human-readable filter expressions such as
+//
+//	name ~ "Ada" and created_at >= 2024-01-01 and stage in ("Won", "Lost")
+//
+// and compiles them to Attio filter JSON.
+package where
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ParseError reports a syntax error and the column it occurred at.
+type ParseError struct {
+	Expr string
+	Pos  int
+	Msg  string
+}
+
+// Column returns the 1-based column of the error.
+func (e *ParseError) Column() int {
+	return e.Pos + 1
+}
+
+func (e *ParseError) Error() string {
+	if e == nil {
+		return ""
+	}
+	return fmt.Sprintf("column %d: %s\n  %s\n  %s^", e.Column(), e.Msg, e.Expr, strings.Repeat(" ", e.Pos))
+}
+
+// Node is an expression tree node: *And, *Or, *Not or *Comparison.
+type Node interface {
+	node()
+}
+
+type And struct{ Terms []Node }
+type Or struct{ Terms []Node }
+type Not struct{ Term Node }
+
+// Comparison applies Op to the attribute addressed by Path.
+type Comparison struct {
+	Path   Path
+	Op     string
+	Values []any
+	Pos    int
+}
+
+// Path addresses an attribute, optionally through record references
+// ("company -> companies.name") and into a value property ("name.first_name").
+type Path struct {
+	Steps    []Step
+	Property []string
+}
+
+// Step is one hop of a path. The first step's Object is empty and refers to the
+// object or list being queried.
+type Step struct {
+	Object    string
+	Attribute string
+}
+
+func (*And) node()        {}
+func (*Or) node()         {}
+func (*Not) node()        {}
+func (*Comparison) node() {}
+
+const (
+	OpEq         = "="
+	OpNotEq      = "!="
+	OpContains   = "~"
+	OpNotContain = "!~"
+	OpStarts     = "^="
+	OpEnds       = "$="
+	OpGt         = ">"
+	OpGte        = ">="
+	OpLt         = "<"
+	OpLte        = "<="
+	OpIn         = "in"
+	OpNotIn      = "not in"
+	OpEmpty      = "is empty"
+	OpNotEmpty   = "is not empty"
+)
+
+type parser struct {
+	expr   string
+	tokens []token
+	pos    int
+}
+
+// Parse parses an expression into a Node tree.
+func Parse(expr string) (Node, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, &ParseError{Expr: expr, Pos: 0, Msg: "empty expression"}
+	}
+	tokens, err := lex(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{expr: expr, tokens: tokens}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, p.errorf(tok, "unexpected %s", tok.describe())
+	}
+	return node, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) errorf(tok token, format string, args ...any) error {
+	return &ParseError{Expr: p.expr, Pos: tok.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) parseOr() (Node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	terms := []Node{left}
+	for p.peek().is(tokWord, "or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, right)
+	}
+	if len(terms) == 1 {
+		return left, nil
+	}
+	return &Or{Terms: terms}, nil
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	terms := []Node{left}
+	for p.peek().is(tokWord, "and") {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, right)
+	}
+	if len(terms) == 1 {
+		return left, nil
+	}
+	return &And{Terms: terms}, nil
+}
+
+func (p *parser) parseNot() (Node, error) {
+	if p.peek().is(tokWord, "not") {
+		p.next()
+		term, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Term: term}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	tok := p.peek()
+	if tok.kind == tokLParen {
+		p.next()
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			return nil, p.errorf(closing, "expected \")\", got %s", closing.describe())
+		}
+		return node, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (Node, error) {
+	start := p.peek()
+	path, err := p.parsePath()
+	if err != nil {
+		return nil, err
+	}
+	cmp := &Comparison{Path: path, Pos: start.pos}
+
+	tok := p.next()
+	switch {
+	case tok.kind == tokOp:
+		cmp.Op = tok.text
+		if cmp.Op == "==" {
+			cmp.Op = OpEq
+		}
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		cmp.Values = []any{value}
+	case tok.is(tokWord, "in"):
+		cmp.Op = OpIn
+		if cmp.Values, err = p.parseValueList(); err != nil {
+			return nil, err
+		}
+	case tok.is(tokWord, "not"):
+		if in := p.next(); !in.is(tokWord, "in") {
+			return nil, p.errorf(in, "expected \"in\" after \"not\", got %s", in.describe())
+		}
+		cmp.Op = OpNotIn
+		if cmp.Values, err = p.parseValueList(); err != nil {
+			return nil, err
+		}
+	case tok.is(tokWord, "is"):
+		cmp.Op = OpEmpty
+		if p.peek().is(tokWord, "not") {
+			p.next()
+			cmp.Op = OpNotEmpty
+		}
+		if empty := p.next(); !empty.is(tokWord, "empty") {
+			return nil, p.errorf(empty, "expected \"empty\", got %s", empty.describe())
+		}
+	default:
+		return nil, p.errorf(tok, "expected an operator (=, !=, ~, !~, ^=, $=, >, >=, <, <=, in, is empty), got %s", tok.describe())
+	}
+	return cmp, nil
+}
+
+func (p *parser) parsePath() (Path, error) {
+	tok := p.next()
+	if tok.kind != tokWord || isKeyword(tok.text) {
+		return Path{}, p.errorf(tok, "expected an attribute, got %s", tok.describe())
+	}
+	parts := strings.Split(tok.text, ".")
+	if hasEmpty(parts) {
+		return Path{}, p.errorf(tok, "invalid attribute path %s", tok.describe())
+	}
+	path := Path{Steps: []Step{{Attribute: parts[0]}}, Property: parts[1:]}
+
+	for p.peek().kind == tokArrow {
+		arrow := p.next()
+		if len(path.Property) > 0 {
+			return Path{}, p.errorf(arrow, "\"->\" must follow a record reference attribute, not a property")
+		}
+		hop := p.next()
+		if hop.kind != tokWord {
+			return Path{}, p.errorf(hop, "expected <object>.<attribute> after \"->\", got %s", hop.describe())
+		}
+		parts := strings.Split(hop.text, ".")
+		if len(parts) < 2 || hasEmpty(parts) {
+			return Path{}, p.errorf(hop, "expected <object>.<attribute> after \"->\", got %s", hop.describe())
+		}
+		path.Steps = append(path.Steps, Step{Object: parts[0], Attribute: parts[1]})
+		path.Property = parts[2:]
+	}
+	return path, nil
+}
+
+func (p *parser) parseValueList() ([]any, error) {
+	if open := p.next(); open.kind != tokLParen {
+		return nil, p.errorf(open, "expected \"(\", got %s", open.describe())
+	}
+	values := make([]any, 0, 4)
+	for {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		tok := p.next()
+		if tok.kind == tokRParen {
+			return values, nil
+		}
+		if tok.kind != tokComma {
+			return nil, p.errorf(tok, "expected \",\" or \")\", got %s", tok.describe())
+		}
+	}
+}
+
+func (p *parser) parseValue() (any, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokString:
+		return tok.text, nil
+	case tokWord:
+		switch strings.ToLower(tok.text) {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		if numberPattern.MatchString(tok.text) {
+			if n, err := strconv.ParseFloat(tok.text, 64); err == nil {
+				return n, nil
+			}
+		}
+		return tok.text, nil
+	default:
+		return nil, p.errorf(tok, "expected a value, got %s", tok.describe())
+	}
+}
+
+// numberPattern matches the bare words read as numbers. Anything else, such as
+// "nan", "Infinity" or a zip code like "02134", stays a string.
+var numberPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?$`)
+
+func isKeyword(word string) bool {
+	switch strings.ToLower(word) {
+	case "and", "or", "not", "in", "is":
+		return true
+	}
+	return false
+}
+
+func hasEmpty(parts []string) bool {
+	for _, part := range parts {
+		if part == "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package where
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func compileJSON(t *testing.T, expr string, root string) string {
+	t.Helper()
+	filter, err := ParseAndCompile(expr, root)
+	if err != nil {
+		t.Fatalf("compile %q: %v", expr, err)
+	}
+	b, err := json.Marshal(filter)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return string(b)
+}
+
+func TestCompileExpressions(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		expr string
+		want string
+	}{
+		{`name = "Ada"`, `{"name":{"$eq":"Ada"}}`},
+		{`name == 'Ada'`, `{"name":{"$eq":"Ada"}}`},
+		{`name != "Ada"`, `{"$not":{"name":{"$eq":"Ada"}}}`},
+		{`name ~ "Ada"`, `{"name":{"$contains":"Ada"}}`},
+		{`name !~ Ada`, `{"$not":{"name":{"$contains":"Ada"}}}`},
+		{`domains.domain $= ".io"`, `{"domains":{"domain":{"$ends_with":".io"}}}`},
+		{`name.first_name ^= "Ad"`, `{"name":{"first_name":{"$starts_with":"Ad"}}}`},
+		{`employees > 10`, `{"employees":{"$gt":10}}`},
+		{`created_at >= 2024-01-01`, `{"created_at":{"$gte":"2024-01-01"}}`},
+		{`score = -2.5`, `{"score":{"$eq":-2.5}}`},
+		{`name = nan`, `{"name":{"$eq":"nan"}}`},
+		{`name = Infinity`, `{"name":{"$eq":"Infinity"}}`},
+		{`name = inf`, `{"name":{"$eq":"inf"}}`},
+		{`zip = 02134`, `{"zip":{"$eq":"02134"}}`},
+		{`is_active = true`, `{"is_active":{"$eq":true}}`},
+		{`stage in ("Won", "Lost")`, `{"stage":{"$in":["Won","Lost"]}}`},
+		{`stage not in ("Won")`, `{"$not":{"stage":{"$in":["Won"]}}}`},
+		{`phone is empty`, `{"$not":{"phone":{"$not_empty":true}}}`},
+		{`phone is not empty`, `{"phone":{"$not_empty":true}}`},
+		{`a = 1 and b = 2 and c = 3`, `{"$and":[{"a":{"$eq":1}},{"b":{"$eq":2}},{"c":{"$eq":3}}]}`},
+		{`a = 1 or b = 2 and c = 3`, `{"$or":[{"a":{"$eq":1}},{"$and":[{"b":{"$eq":2}},{"c":{"$eq":3}}]}]}`},
+		{`(a = 1 or b = 2) and not c = 3`, `{"$and":[{"$or":[{"a":{"$eq":1}},{"b":{"$eq":2}}]},{"$not":{"c":{"$eq":3}}}]}`},
+		{`company -> companies.name ~ "Acme"`, `{"constraints":{"$contains":"Acme"},"path":[["people","company"],["companies","name"]]}`},
+		{`company->companies.team->people.name.full_name = "Ada"`, `{"constraints":{"full_name":{"$eq":"Ada"}},"path":[["people","company"],["companies","team"],["people","name"]]}`},
+	}
+	for _, tc := range cases {
+		if got := compileJSON(t, tc.expr, "people"); got != tc.want {
+			t.Fatalf("%s\n got: %s\nwant: %s", tc.expr, got, tc.want)
+		}
+	}
+}
+
+func TestParseErrorsReportColumn(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		expr   string
+		column int
+		msg    string
+	}{
+		{`name ~ `, 8, "expected a value"},
+		{`name "Ada"`, 6, "expected an operator"},
+		{`name = "Ada`, 8, "unterminated string"},
+		{`(name = 1`, 10, `expected ")"`},
+		{`name = 1 and`, 13, "expected an attribute"},
+		{`stage in "Won"`, 10, `expected "("`},
+		{`name = 1 name = 2`, 10, "unexpected"},
+		{`name # 1`, 6, "unexpected character"},
+		{`name.first -> companies.name = 1`, 12, "must follow a record reference"},
+		{`company -> name = 1`, 12, "<object>.<attribute>"},
+		{`   `, 1, "empty expression"},
+	}
+	for _, tc := range cases {
+		_, err := Parse(tc.expr)
+		var pe *ParseError
+		if !errors.As(err, &pe) {
+			t.Fatalf("%q: expected ParseError, got %v", tc.expr, err)
+		}
+		if pe.Column() != tc.column || !strings.Contains(pe.Msg, tc.msg) {
+			t.Fatalf("%q: got column %d msg %q; want column %d containing %q", tc.expr, pe.Column(), pe.Msg, tc.column, tc.msg)
+		}
+	}
+
+	_, err := Parse(`name ~ `)
+	if !strings.Contains(err.Error(), "\n  name ~ \n         ^") {
+		t.Fatalf("expected caret in error message, got %q", err.Error())
+	}
+}
+
+func TestCompilePathWithoutRoot(t *testing.T) {
+	t.Parallel()
+
+	if _, err := ParseAndCompile(`company -> companies.name = "x"`, ""); err == nil {
+		t.Fatalf("expected error without root object")
+	}
+}
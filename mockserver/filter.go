@@ -0,0 +1,327 @@
+package mockserver
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// matches evaluates an Attio filter against rec. Supported: shorthand
+// equality, the $eq, $contains, $starts_with, $ends_with, $gt, $gte, $lt,
+// $lte, $in and $not_empty operators, attribute properties, $and/$or/$not
+// and path filters that follow record references.
+func (s *store) matches(rec *record, filter any) (bool, error) {
+	if filter == nil {
+		return true, nil
+	}
+	f, ok := filter.(map[string]any)
+	if !ok {
+		return false, badRequest("Filter must be an object, got %T", filter)
+	}
+	if path, ok := f["path"]; ok {
+		return s.matchesPath(rec, path, f["constraints"])
+	}
+	for key, cond := range f {
+		var ok bool
+		var err error
+		switch key {
+		case "$and", "$or":
+			terms, isList := cond.([]any)
+			if !isList {
+				return false, badRequest("%s expects an array of filters", key)
+			}
+			ok = key == "$and"
+			for _, term := range terms {
+				matched, err := s.matches(rec, term)
+				if err != nil {
+					return false, err
+				}
+				if key == "$and" && !matched {
+					ok = false
+					break
+				}
+				if key == "$or" && matched {
+					ok = true
+					break
+				}
+			}
+		case "$not":
+			ok, err = s.matches(rec, cond)
+			ok = !ok
+		case "record_id", "entry_id":
+			ok, err = compareAll([]any{rec.id}, cond)
+		case "parent_record_id":
+			ok, err = compareAll([]any{rec.parentRecordID}, cond)
+		default:
+			ok, err = s.matchesAttribute(rec, key, cond)
+		}
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (s *store) matchesAttribute(rec *record, ref string, cond any) (bool, error) {
+	attr := rec.parent.attribute(ref)
+	if attr == nil {
+		return false, badRequest("Unknown attribute slug or ID %q in filter for %s %q.", ref, strings.TrimSuffix(rec.parent.kind, "s"), rec.parent.slug())
+	}
+	values := rec.activeValues(attr)
+
+	conds, isMap := cond.(map[string]any)
+	if !isMap || isOperatorMap(conds) {
+		if isMap {
+			if want, ok := conds["$not_empty"]; ok && len(conds) == 1 {
+				return (len(values) > 0) == (want == true), nil
+			}
+		}
+		return anyValue(values, attr.typ(), "", cond)
+	}
+	// Property constraints, e.g. {"name": {"first_name": {"$eq": "Ada"}}}.
+	for prop, sub := range conds {
+		ok, err := anyValue(values, attr.typ(), prop, sub)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func anyValue(values []map[string]any, typ string, prop string, cond any) (bool, error) {
+	if m, ok := cond.(map[string]any); ok {
+		if want, ok := m["$not_empty"]; ok && len(m) == 1 {
+			found := false
+			for _, v := range values {
+				for _, c := range propertyCandidates(v, typ, prop) {
+					found = found || (c != nil && c != "")
+				}
+			}
+			return found == (want == true), nil
+		}
+	}
+	for _, v := range values {
+		ok, err := compareAll(propertyCandidates(v, typ, prop), cond)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+	return false, nil
+}
+
+// compareAll reports whether any candidate satisfies cond, which is either
+// a bare value (equality) or an operator map (all operators must hold).
+func compareAll(candidates []any, cond any) (bool, error) {
+	ops, ok := cond.(map[string]any)
+	if !ok {
+		ops = map[string]any{"$eq": cond}
+	}
+	for _, c := range candidates {
+		if c == nil {
+			continue
+		}
+		all := true
+		for op, operand := range ops {
+			ok, err := compare(c, op, operand)
+			if err != nil {
+				return false, err
+			}
+			all = all && ok
+		}
+		if all {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func compare(actual any, op string, operand any) (bool, error) {
+	a, b := fmt.Sprint(actual), fmt.Sprint(operand)
+	switch op {
+	case "$eq":
+		if x, ok := toFloat(actual); ok {
+			if y, ok := toFloat(operand); ok {
+				return x == y, nil
+			}
+		}
+		return strings.EqualFold(a, b), nil
+	case "$contains":
+		return strings.Contains(strings.ToLower(a), strings.ToLower(b)), nil
+	case "$starts_with":
+		return strings.HasPrefix(strings.ToLower(a), strings.ToLower(b)), nil
+	case "$ends_with":
+		return strings.HasSuffix(strings.ToLower(a), strings.ToLower(b)), nil
+	case "$gt", "$gte", "$lt", "$lte":
+		c := compareOrdered(actual, operand)
+		switch op {
+		case "$gt":
+			return c > 0, nil
+		case "$gte":
+			return c >= 0, nil
+		case "$lt":
+			return c < 0, nil
+		default:
+			return c <= 0, nil
+		}
+	case "$in":
+		list, ok := operand.([]any)
+		if !ok {
+			return false, badRequest("$in expects an array")
+		}
+		for _, item := range list {
+			if ok, _ := compare(actual, "$eq", item); ok {
+				return true, nil
+			}
+		}
+		return false, nil
+	case "$not_empty":
+		return (a != "") == (operand == true), nil
+	}
+	return false, badRequest("Unsupported filter operator %q", op)
+}
+
+// compareOrdered compares numerically when both sides are numbers and
+// lexically otherwise, which orders ISO dates and timestamps correctly.
+func compareOrdered(a any, b any) int {
+	if x, ok := toFloat(a); ok {
+		if y, ok := toFloat(b); ok {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(strings.ToLower(fmt.Sprint(a)), strings.ToLower(fmt.Sprint(b)))
+}
+
+func isOperatorMap(m map[string]any) bool {
+	for key := range m {
+		if !strings.HasPrefix(key, "$") {
+			return false
+		}
+	}
+	return len(m) > 0
+}
+
+// matchesPath evaluates {"path": [[object, attribute], ...], "constraints":
+// ...}: every step but the last follows record references, and the
+// constraints apply to the last attribute of the records reached.
+func (s *store) matchesPath(rec *record, rawPath any, constraints any) (bool, error) {
+	steps, ok := rawPath.([]any)
+	if !ok || len(steps) == 0 {
+		return false, badRequest("Filter path must be a non-empty array of [object, attribute] pairs")
+	}
+	current := []*record{rec}
+	for i, rawStep := range steps {
+		step, ok := rawStep.([]any)
+		if !ok || len(step) != 2 {
+			return false, badRequest("Filter path step %d must be an [object, attribute] pair", i)
+		}
+		attrRef, _ := step[1].(string)
+		if i == len(steps)-1 {
+			for _, r := range current {
+				ok, err := s.matchesAttribute(r, attrRef, constraints)
+				if err != nil || ok {
+					return ok, err
+				}
+			}
+			return false, nil
+		}
+		var next []*record
+		for _, r := range current {
+			attr := r.parent.attribute(attrRef)
+			if attr == nil {
+				return false, badRequest("Unknown attribute %q in filter path", attrRef)
+			}
+			if attr.typ() != "record-reference" {
+				return false, badRequest("Filter path attribute %q is not a record reference", attrRef)
+			}
+			for _, v := range r.activeValues(attr) {
+				if target := s.findRecord(stringField(v, "target_object"), stringField(v, "target_record_id")); target != nil {
+					next = append(next, target)
+				}
+			}
+		}
+		current = next
+	}
+	return false, nil
+}
+
+func (s *store) findRecord(object string, recordID string) *record {
+	obj, err := s.object(object)
+	if err != nil {
+		return nil
+	}
+	for _, rec := range s.records[obj.id] {
+		if rec.id == recordID {
+			return rec
+		}
+	}
+	return nil
+}
+
+// filterRows returns the rows matching filter, ordered by sorts.
+func (s *store) filterRows(rows []*record, filter any, sorts any) ([]*record, error) {
+	out := make([]*record, 0, len(rows))
+	for _, rec := range rows {
+		ok, err := s.matches(rec, filter)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			out = append(out, rec)
+		}
+	}
+	if sorts == nil {
+		return out, nil
+	}
+	list, ok := sorts.([]any)
+	if !ok {
+		return nil, badRequest("sorts must be an array")
+	}
+	for i := len(list) - 1; i >= 0; i-- {
+		spec, _ := list[i].(map[string]any)
+		attrRef := stringField(spec, "attribute")
+		if path, ok := spec["path"].([]any); ok && len(path) > 0 {
+			last, _ := path[len(path)-1].([]any)
+			if len(last) == 2 {
+				attrRef, _ = last[1].(string)
+			}
+		}
+		field := stringField(spec, "field")
+		desc := stringField(spec, "direction") == "desc"
+		sort.SliceStable(out, func(a, b int) bool {
+			x, xok := sortValue(out[a], attrRef, field)
+			y, yok := sortValue(out[b], attrRef, field)
+			if !xok || !yok {
+				// Empty values sort last in either direction.
+				return xok && !yok
+			}
+			c := compareOrdered(x, y)
+			if desc {
+				return c > 0
+			}
+			return c < 0
+		})
+	}
+	return out, nil
+}
+
+func sortValue(rec *record, attrRef string, field string) (any, bool) {
+	if attrRef == "created_at" {
+		return rec.createdAt, true
+	}
+	attr := rec.parent.attribute(attrRef)
+	if attr == nil {
+		return nil, false
+	}
+	values := rec.activeValues(attr)
+	if len(values) == 0 {
+		return nil, false
+	}
+	v := propertyCandidates(values[0], attr.typ(), field)[0]
+	return v, v != nil
+}
@@ -0,0 +1,286 @@
+package mockserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Fixture seeds a Server. Objects and lists that already exist (such as the
+// standard people object) gain the listed attributes. Records and entries are
+// keyed by object or list slug and may pin their IDs so other fixture rows
+// can reference them. Notes, tasks, comments, webhooks and meetings use the
+// same shape as the "data" of their create requests.
+type Fixture struct {
+	WorkspaceName string                    `json:"workspace_name,omitempty"`
+	Members       []FixtureMember           `json:"workspace_members,omitempty"`
+	Objects       []FixtureObject           `json:"objects,omitempty"`
+	Lists         []FixtureList             `json:"lists,omitempty"`
+	Records       map[string][]FixtureRow   `json:"records,omitempty"`
+	Entries       map[string][]FixtureEntry `json:"entries,omitempty"`
+	Notes         []map[string]any          `json:"notes,omitempty"`
+	Tasks         []map[string]any          `json:"tasks,omitempty"`
+	Comments      []map[string]any          `json:"comments,omitempty"`
+	Webhooks      []map[string]any          `json:"webhooks,omitempty"`
+	Meetings      []FixtureMeeting          `json:"meetings,omitempty"`
+}
+
+type FixtureMember struct {
+	ID           string `json:"id,omitempty"`
+	FirstName    string `json:"first_name"`
+	LastName     string `json:"last_name"`
+	EmailAddress string `json:"email_address"`
+	AccessLevel  string `json:"access_level,omitempty"`
+}
+
+type FixtureObject struct {
+	APISlug      string             `json:"api_slug"`
+	SingularNoun string             `json:"singular_noun,omitempty"`
+	PluralNoun   string             `json:"plural_noun,omitempty"`
+	Attributes   []FixtureAttribute `json:"attributes,omitempty"`
+}
+
+type FixtureList struct {
+	APISlug         string             `json:"api_slug"`
+	Name            string             `json:"name,omitempty"`
+	ParentObject    string             `json:"parent_object"`
+	WorkspaceAccess string             `json:"workspace_access,omitempty"`
+	Attributes      []FixtureAttribute `json:"attributes,omitempty"`
+}
+
+// FixtureAttribute matches the attribute entries of `attio schema export`.
+type FixtureAttribute struct {
+	APISlug       string          `json:"api_slug"`
+	Title         string          `json:"title,omitempty"`
+	Type          string          `json:"type"`
+	Description   string          `json:"description,omitempty"`
+	IsRequired    bool            `json:"is_required,omitempty"`
+	IsUnique      bool            `json:"is_unique,omitempty"`
+	IsMultiselect bool            `json:"is_multiselect,omitempty"`
+	Config        map[string]any  `json:"config,omitempty"`
+	Options       []FixtureOption `json:"options,omitempty"`
+	Statuses      []FixtureOption `json:"statuses,omitempty"`
+}
+
+type FixtureOption struct {
+	Title              string `json:"title"`
+	CelebrationEnabled bool   `json:"celebration_enabled,omitempty"`
+}
+
+type FixtureRow struct {
+	RecordID string         `json:"record_id,omitempty"`
+	Values   map[string]any `json:"values"`
+}
+
+type FixtureEntry struct {
+	EntryID        string         `json:"entry_id,omitempty"`
+	ParentRecordID string         `json:"parent_record_id"`
+	EntryValues    map[string]any `json:"entry_values,omitempty"`
+}
+
+// FixtureMeeting is a meeting create payload plus optional call recordings,
+// each of which may carry transcript segments.
+type FixtureMeeting struct {
+	Meeting        map[string]any     `json:"meeting"`
+	CallRecordings []FixtureRecording `json:"call_recordings,omitempty"`
+}
+
+type FixtureRecording struct {
+	VideoURL   string           `json:"video_url,omitempty"`
+	Transcript []map[string]any `json:"transcript,omitempty"`
+}
+
+// LoadFixture reads a JSON fixture file.
+func LoadFixture(path string) (*Fixture, error) {
+	b, err := os.ReadFile(path) //nolint:gosec // user-specified fixture path
+	if err != nil {
+		return nil, fmt.Errorf("read fixture: %w", err)
+	}
+	var fixture Fixture
+	if err := json.Unmarshal(b, &fixture); err != nil {
+		return nil, fmt.Errorf("parse fixture %s: %w", path, err)
+	}
+	return &fixture, nil
+}
+
+// defaultFixture mirrors a fresh Attio workspace: the standard people,
+// companies and deals objects and a single admin.
+var defaultFixture = Fixture{
+	Members: []FixtureMember{{FirstName: "Mock", LastName: "Admin", EmailAddress: "admin@example.com", AccessLevel: "admin"}},
+	Objects: []FixtureObject{
+		{APISlug: "people", SingularNoun: "Person", PluralNoun: "People", Attributes: []FixtureAttribute{
+			{APISlug: "name", Title: "Name", Type: "personal-name"},
+			{APISlug: "email_addresses", Title: "Email addresses", Type: "email-address", IsUnique: true, IsMultiselect: true},
+			{APISlug: "phone_numbers", Title: "Phone numbers", Type: "phone-number", IsMultiselect: true},
+			{APISlug: "job_title", Title: "Job title", Type: "text"},
+			{APISlug: "description", Title: "Description", Type: "text"},
+			{APISlug: "company", Title: "Company", Type: "record-reference", Config: recordReferenceConfig("companies")},
+		}},
+		{APISlug: "companies", SingularNoun: "Company", PluralNoun: "Companies", Attributes: []FixtureAttribute{
+			{APISlug: "name", Title: "Name", Type: "text"},
+			{APISlug: "domains", Title: "Domains", Type: "domain", IsUnique: true, IsMultiselect: true},
+			{APISlug: "description", Title: "Description", Type: "text"},
+			{APISlug: "categories", Title: "Categories", Type: "select", IsMultiselect: true, Options: []FixtureOption{{Title: "B2B"}, {Title: "B2C"}, {Title: "SaaS"}}},
+			{APISlug: "team", Title: "Team", Type: "record-reference", IsMultiselect: true, Config: recordReferenceConfig("people")},
+		}},
+		{APISlug: "deals", SingularNoun: "Deal", PluralNoun: "Deals", Attributes: []FixtureAttribute{
+			{APISlug: "name", Title: "Deal name", Type: "text", IsRequired: true},
+			{APISlug: "stage", Title: "Deal stage", Type: "status", IsRequired: true, Statuses: []FixtureOption{{Title: "Lead"}, {Title: "In Progress"}, {Title: "Won 🎉", CelebrationEnabled: true}, {Title: "Lost"}}},
+			{APISlug: "value", Title: "Deal value", Type: "currency", Config: map[string]any{"currency": map[string]any{"default_currency_code": "USD", "display_type": "symbol"}}},
+			{APISlug: "owner", Title: "Deal owner", Type: "actor-reference"},
+			{APISlug: "associated_company", Title: "Associated company", Type: "record-reference", Config: recordReferenceConfig("companies")},
+			{APISlug: "associated_people", Title: "Associated people", Type: "record-reference", IsMultiselect: true, Config: recordReferenceConfig("people")},
+		}},
+	},
+}
+
+func recordReferenceConfig(objects ...string) map[string]any {
+	allowed := make([]any, len(objects))
+	for i, object := range objects {
+		allowed[i] = object
+	}
+	return map[string]any{"record_reference": map[string]any{"allowed_objects": allowed}}
+}
+
+func (s *store) seedDefaults() error {
+	return s.seed(&defaultFixture)
+}
+
+func (s *store) seed(f *Fixture) error {
+	if f.WorkspaceName != "" {
+		s.workspaceName = f.WorkspaceName
+	}
+	for _, m := range f.Members {
+		s.addMember(map[string]any{"id": m.ID, "first_name": m.FirstName, "last_name": m.LastName, "email_address": m.EmailAddress, "access_level": m.AccessLevel})
+	}
+	for _, o := range f.Objects {
+		obj, err := s.object(o.APISlug)
+		if err != nil {
+			if obj, err = s.createObject(map[string]any{"api_slug": o.APISlug, "singular_noun": o.SingularNoun, "plural_noun": o.PluralNoun}); err != nil {
+				return fmt.Errorf("object %s: %w", o.APISlug, err)
+			}
+		}
+		if err := s.seedAttributes(obj, o.Attributes); err != nil {
+			return err
+		}
+	}
+	for _, l := range f.Lists {
+		list, err := s.list(l.APISlug)
+		if err != nil {
+			data := map[string]any{"api_slug": l.APISlug, "name": l.Name, "parent_object": l.ParentObject, "workspace_access": l.WorkspaceAccess}
+			if list, err = s.createList(data); err != nil {
+				return fmt.Errorf("list %s: %w", l.APISlug, err)
+			}
+		}
+		if err := s.seedAttributes(list, l.Attributes); err != nil {
+			return err
+		}
+	}
+
+	// Create every record before writing values so references between
+	// fixture records resolve regardless of order.
+	type pending struct {
+		rec    *record
+		values map[string]any
+	}
+	var rows []pending
+	for _, obj := range s.objects {
+		for _, row := range f.Records[obj.slug()] {
+			rec := &record{id: row.RecordID, parent: obj, createdAt: timestamp(), values: map[string][]map[string]any{}}
+			if rec.id == "" {
+				rec.id = newID()
+			}
+			s.records[obj.id] = append(s.records[obj.id], rec)
+			rows = append(rows, pending{rec: rec, values: row.Values})
+		}
+	}
+	for slug := range f.Records {
+		if _, err := s.object(slug); err != nil {
+			return fmt.Errorf("records: %w", err)
+		}
+	}
+	for _, row := range rows {
+		if err := s.writeValues(row.rec, row.values, writeReplace); err != nil {
+			return fmt.Errorf("%s record %s: %w", row.rec.parent.slug(), row.rec.id, err)
+		}
+	}
+
+	for slug, entries := range f.Entries {
+		list, err := s.list(slug)
+		if err != nil {
+			return fmt.Errorf("entries: %w", err)
+		}
+		for _, e := range entries {
+			data := map[string]any{"parent_record_id": e.ParentRecordID, "entry_values": e.EntryValues}
+			if _, err := s.createEntry(list, data, e.EntryID); err != nil {
+				return fmt.Errorf("%s entry: %w", slug, err)
+			}
+		}
+	}
+	for _, data := range f.Notes {
+		if _, err := s.createNote(data); err != nil {
+			return fmt.Errorf("note: %w", err)
+		}
+	}
+	for _, data := range f.Tasks {
+		if _, err := s.createTask(data); err != nil {
+			return fmt.Errorf("task: %w", err)
+		}
+	}
+	for _, data := range f.Comments {
+		if _, err := s.createComment(data); err != nil {
+			return fmt.Errorf("comment: %w", err)
+		}
+	}
+	for _, data := range f.Webhooks {
+		if _, err := s.createWebhook(data); err != nil {
+			return fmt.Errorf("webhook: %w", err)
+		}
+	}
+	for _, m := range f.Meetings {
+		meeting, err := s.findOrCreateMeeting(m.Meeting)
+		if err != nil {
+			return fmt.Errorf("meeting: %w", err)
+		}
+		for _, rec := range m.CallRecordings {
+			recording, err := s.createRecording(meeting, map[string]any{"video_url": rec.VideoURL})
+			if err != nil {
+				return fmt.Errorf("call recording: %w", err)
+			}
+			id, _ := recording["id"].(map[string]any)
+			s.transcripts[stringField(id, "call_recording_id")] = rec.Transcript
+		}
+	}
+	return nil
+}
+
+func (s *store) seedAttributes(c *container, attrs []FixtureAttribute) error {
+	for _, a := range attrs {
+		attr := c.attribute(a.APISlug)
+		if attr == nil {
+			var err error
+			attr, err = s.createAttribute(c, map[string]any{
+				"api_slug": a.APISlug, "title": a.Title, "type": a.Type, "description": a.Description,
+				"is_required": a.IsRequired, "is_unique": a.IsUnique, "is_multiselect": a.IsMultiselect, "config": a.Config,
+			})
+			if err != nil {
+				return fmt.Errorf("%s attribute %s: %w", c.slug(), a.APISlug, err)
+			}
+		}
+		for _, opt := range a.Options {
+			if findOption(attr.options, "option_id", opt.Title) == nil {
+				if _, err := s.createOption(c, attr, map[string]any{"title": opt.Title}); err != nil {
+					return fmt.Errorf("%s attribute %s: %w", c.slug(), a.APISlug, err)
+				}
+			}
+		}
+		for _, status := range a.Statuses {
+			if findOption(attr.statuses, "status_id", status.Title) == nil {
+				if _, err := s.createStatus(c, attr, map[string]any{"title": status.Title, "celebration_enabled": status.CelebrationEnabled}); err != nil {
+					return fmt.Errorf("%s attribute %s: %w", c.slug(), a.APISlug, err)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,225 @@
+package mockserver
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// findOrCreateMeeting returns the meeting with the same external_ref.ical_uid
+// if one exists, like Attio's find-or-create endpoint.
+func (s *store) findOrCreateMeeting(data map[string]any) (map[string]any, error) {
+	if stringField(data, "title") == "" {
+		return nil, badRequest("Meeting title is required")
+	}
+	start, startOK := data["start"].(map[string]any)
+	end, endOK := data["end"].(map[string]any)
+	if !startOK || !endOK {
+		return nil, badRequest("Meeting start and end are required")
+	}
+	externalRef, _ := data["external_ref"].(map[string]any)
+	if uid := stringField(externalRef, "ical_uid"); uid != "" {
+		for _, meeting := range s.meetings {
+			if ref, _ := meeting["external_ref"].(map[string]any); stringField(ref, "ical_uid") == uid {
+				return meeting, nil
+			}
+		}
+	}
+
+	participants := []any{}
+	items, _ := data["participants"].([]any)
+	for _, item := range items {
+		p, _ := item.(map[string]any)
+		participants = append(participants, map[string]any{
+			"email_address": strings.ToLower(stringField(p, "email_address")),
+			"is_organizer":  boolField(p, "is_organizer"),
+			"status":        stringOr(p, "status", "accepted"),
+		})
+	}
+	linked := []any{}
+	items, _ = data["linked_records"].([]any)
+	for _, item := range items {
+		link, _ := item.(map[string]any)
+		rec, err := s.getRecord(stringField(link, "object"), stringField(link, "record_id"))
+		if err != nil {
+			return nil, badRequest("linked_records: %v", err)
+		}
+		linked = append(linked, map[string]any{"object_slug": rec.parent.slug(), "object_id": rec.parent.id, "record_id": rec.id})
+	}
+
+	meeting := map[string]any{
+		"id":               map[string]any{"workspace_id": s.workspaceID, "meeting_id": newID()},
+		"title":            stringField(data, "title"),
+		"description":      stringField(data, "description"),
+		"is_all_day":       boolField(data, "is_all_day"),
+		"start":            start,
+		"end":              end,
+		"participants":     participants,
+		"linked_records":   linked,
+		"external_ref":     externalRef,
+		"created_at":       timestamp(),
+		"created_by_actor": s.apiActor(),
+	}
+	s.meetings = append(s.meetings, meeting)
+	return meeting, nil
+}
+
+func (s *store) meeting(id string) (map[string]any, error) {
+	meeting, _ := findByID(s.meetings, "meeting_id", id)
+	if meeting == nil {
+		return nil, notFound("Meeting %q not found.", id)
+	}
+	return meeting, nil
+}
+
+func (s *store) createRecording(meeting map[string]any, data map[string]any) (map[string]any, error) {
+	meetingID := meeting["id"].(map[string]any)["meeting_id"].(string)
+	id := newID()
+	recording := map[string]any{
+		"id":               map[string]any{"workspace_id": s.workspaceID, "meeting_id": meetingID, "call_recording_id": id},
+		"status":           "completed",
+		"video_url":        nilIfEmpty(stringField(data, "video_url")),
+		"web_url":          "https://app.attio.com/mock/calls/" + meetingID + "/" + id,
+		"created_by_actor": s.apiActor(),
+		"created_at":       timestamp(),
+	}
+	s.recordings[meetingID] = append(s.recordings[meetingID], recording)
+	return recording, nil
+}
+
+// meetingTime is the sortable start or end of a meeting.
+func meetingTime(meeting map[string]any, key string) string {
+	t, _ := meeting[key].(map[string]any)
+	if dt := stringField(t, "datetime"); dt != "" {
+		return dt
+	}
+	return stringField(t, "date")
+}
+
+func (s *store) listMeetings(r *http.Request) (cursorPage, error) {
+	q := r.URL.Query()
+	participants := map[string]bool{}
+	for _, email := range strings.Split(q.Get("participants"), ",") {
+		if email = strings.ToLower(strings.TrimSpace(email)); email != "" {
+			participants[email] = true
+		}
+	}
+	if (q.Get("linked_object") == "") != (q.Get("linked_record_id") == "") {
+		return cursorPage{}, badRequest("linked_object and linked_record_id must be passed together")
+	}
+	out := []map[string]any{}
+	for _, meeting := range s.meetings {
+		if len(participants) > 0 && !meetingHasParticipant(meeting, participants) {
+			continue
+		}
+		if id := q.Get("linked_record_id"); id != "" && !meetingLinksRecord(meeting, id) {
+			continue
+		}
+		if from := q.Get("ends_from"); from != "" && meetingTime(meeting, "end") < from {
+			continue
+		}
+		if before := q.Get("starts_before"); before != "" && meetingTime(meeting, "start") >= before {
+			continue
+		}
+		out = append(out, meeting)
+	}
+	desc := q.Get("sort") == "start_desc"
+	sort.SliceStable(out, func(i, j int) bool {
+		if desc {
+			return meetingTime(out[i], "start") > meetingTime(out[j], "start")
+		}
+		return meetingTime(out[i], "start") < meetingTime(out[j], "start")
+	})
+	return cursorPaginate(out, r, 50)
+}
+
+func meetingHasParticipant(meeting map[string]any, emails map[string]bool) bool {
+	participants, _ := meeting["participants"].([]any)
+	for _, item := range participants {
+		if p, _ := item.(map[string]any); emails[stringField(p, "email_address")] {
+			return true
+		}
+	}
+	return false
+}
+
+func meetingLinksRecord(meeting map[string]any, recordID string) bool {
+	linked, _ := meeting["linked_records"].([]any)
+	for _, item := range linked {
+		if link, _ := item.(map[string]any); link["record_id"] == recordID {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) meetingRoutes() {
+	s.handle("GET /v2/meetings", func(st *store, r *http.Request, _ map[string]any) (any, error) {
+		return st.listMeetings(r)
+	})
+	s.handle("POST /v2/meetings", func(st *store, _ *http.Request, body map[string]any) (any, error) {
+		data, err := dataObject(body)
+		if err != nil {
+			return nil, err
+		}
+		return st.findOrCreateMeeting(data)
+	})
+	s.handle("GET /v2/meetings/{meeting_id}", func(st *store, r *http.Request, _ map[string]any) (any, error) {
+		return st.meeting(r.PathValue("meeting_id"))
+	})
+	s.handle("GET /v2/meetings/{meeting_id}/call_recordings", func(st *store, r *http.Request, _ map[string]any) (any, error) {
+		if _, err := st.meeting(r.PathValue("meeting_id")); err != nil {
+			return nil, err
+		}
+		return cursorPaginate(st.recordings[r.PathValue("meeting_id")], r, 50)
+	})
+	s.handle("POST /v2/meetings/{meeting_id}/call_recordings", func(st *store, r *http.Request, body map[string]any) (any, error) {
+		meeting, err := st.meeting(r.PathValue("meeting_id"))
+		if err != nil {
+			return nil, err
+		}
+		data, err := dataObject(body)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(stringField(data, "video_url"), "https://") {
+			return nil, badRequest("video_url must be an https URL")
+		}
+		return st.createRecording(meeting, data)
+	})
+	s.handle("GET /v2/meetings/{meeting_id}/call_recordings/{call_recording_id}", func(st *store, r *http.Request, _ map[string]any) (any, error) {
+		recording, _, err := st.recording(r)
+		return recording, err
+	})
+	s.handle("DELETE /v2/meetings/{meeting_id}/call_recordings/{call_recording_id}", func(st *store, r *http.Request, _ map[string]any) (any, error) {
+		_, i, err := st.recording(r)
+		if err != nil {
+			return nil, err
+		}
+		meetingID := r.PathValue("meeting_id")
+		st.recordings[meetingID] = append(st.recordings[meetingID][:i], st.recordings[meetingID][i+1:]...)
+		delete(st.transcripts, r.PathValue("call_recording_id"))
+		return nil, nil
+	})
+	s.handle("GET /v2/meetings/{meeting_id}/call_recordings/{call_recording_id}/transcript", func(st *store, r *http.Request, _ map[string]any) (any, error) {
+		if _, _, err := st.recording(r); err != nil {
+			return nil, err
+		}
+		segments := st.transcripts[r.PathValue("call_recording_id")]
+		if segments == nil {
+			segments = []map[string]any{}
+		}
+		return cursorPaginate(segments, r, 100)
+	})
+}
+
+func (s *store) recording(r *http.Request) (map[string]any, int, error) {
+	if _, err := s.meeting(r.PathValue("meeting_id")); err != nil {
+		return nil, -1, err
+	}
+	recording, i := findByID(s.recordings[r.PathValue("meeting_id")], "call_recording_id", r.PathValue("call_recording_id"))
+	if recording == nil {
+		return nil, -1, notFound("Call recording %q not found.", r.PathValue("call_recording_id"))
+	}
+	return recording, i, nil
+}
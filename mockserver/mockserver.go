@@ -0,0 +1,254 @@
+// Package mockserver is an in-memory implementation of the Attio REST API
+// described by the bundled openapi.json. It backs `attio mock-server` and can
+// be embedded in tests:
+//
+//	srv, _ := mockserver.New(nil)
+//	ts := httptest.NewServer(srv)
+//	defer ts.Close()
+//
+// Every request needs a bearer token, but any token is accepted. State lives
+// only in memory; seed it with a Fixture.
+package mockserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const maxBodyBytes = 10 << 20
+
+// Server is an http.Handler serving the Attio API from an in-memory store.
+// Requests are handled one at a time, so it is safe for concurrent use.
+type Server struct {
+	mu    sync.Mutex
+	store *store
+	mux   *http.ServeMux
+}
+
+// New returns a server with the standard people, companies and deals objects
+// and one workspace member, plus everything in fixture (which may be nil).
+func New(fixture *Fixture) (*Server, error) {
+	s := &Server{store: newStore(), mux: http.NewServeMux()}
+	if err := s.store.seedDefaults(); err != nil {
+		return nil, err
+	}
+	if fixture != nil {
+		if err := s.store.seed(fixture); err != nil {
+			return nil, fmt.Errorf("seed fixture: %w", err)
+		}
+	}
+	s.routes()
+	return s, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, pattern := s.mux.Handler(r); pattern == "" {
+		writeError(w, errorf(http.StatusNotFound, "not_found", "Route %s %s does not exist", r.Method, r.URL.Path))
+		return
+	}
+	if strings.TrimSpace(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")) == "" {
+		writeError(w, errorf(http.StatusUnauthorized, "unauthorized", "Authentication failed: pass an API key as a bearer token"))
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// handlerFunc returns the response data (wrapped in {"data": ...}) or an
+// error. A nil result is written as an empty object.
+type handlerFunc func(st *store, r *http.Request, body map[string]any) (any, error)
+
+func (s *Server) handle(pattern string, fn handlerFunc) {
+	s.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		body, err := readBody(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		s.mu.Lock()
+		result, err := fn(s.store, r, body)
+		s.mu.Unlock()
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		switch v := result.(type) {
+		case nil:
+			writeJSON(w, http.StatusOK, map[string]any{})
+		case rawResponse:
+			writeJSON(w, http.StatusOK, v.body)
+		case cursorPage:
+			var next any
+			if v.next != "" {
+				next = v.next
+			}
+			writeJSON(w, http.StatusOK, map[string]any{"data": v.items, "pagination": map[string]any{"next_cursor": next}})
+		default:
+			writeJSON(w, http.StatusOK, map[string]any{"data": v})
+		}
+	})
+}
+
+// rawResponse is written as-is, without the data envelope.
+type rawResponse struct {
+	body any
+}
+
+// cursorPage is written with a pagination.next_cursor alongside the data.
+type cursorPage struct {
+	items []map[string]any
+	next  string
+}
+
+// apiError is written in Attio's error format.
+type apiError struct {
+	status  int
+	code    string
+	message string
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+func errorf(status int, code string, format string, args ...any) error {
+	return &apiError{status: status, code: code, message: fmt.Sprintf(format, args...)}
+}
+
+func notFound(format string, args ...any) error {
+	return errorf(http.StatusNotFound, "not_found", format, args...)
+}
+
+func badRequest(format string, args ...any) error {
+	return errorf(http.StatusBadRequest, "validation_type", format, args...)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	var ae *apiError
+	if !errors.As(err, &ae) {
+		ae = &apiError{status: http.StatusInternalServerError, code: "internal_error", message: err.Error()}
+	}
+	errType := "invalid_request_error"
+	switch {
+	case ae.status == http.StatusUnauthorized:
+		errType = "auth_error"
+	case ae.status >= 500:
+		errType = "api_error"
+	}
+	writeJSON(w, ae.status, map[string]any{
+		"status_code": ae.status,
+		"type":        errType,
+		"code":        ae.code,
+		"message":     ae.message,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func readBody(r *http.Request) (map[string]any, error) {
+	if r.Body == nil {
+		return map[string]any{}, nil
+	}
+	b, err := io.ReadAll(io.LimitReader(r.Body, maxBodyBytes+1))
+	if err != nil {
+		return nil, badRequest("read request body: %v", err)
+	}
+	if len(b) > maxBodyBytes {
+		return nil, errorf(http.StatusRequestEntityTooLarge, "payload_too_large", "Request body exceeds %d bytes", maxBodyBytes)
+	}
+	body := map[string]any{}
+	if strings.TrimSpace(string(b)) == "" {
+		return body, nil
+	}
+	if err := json.Unmarshal(b, &body); err != nil {
+		return nil, badRequest("Request body must be a JSON object: %v", err)
+	}
+	return body, nil
+}
+
+// dataObject returns body.data, which every write endpoint requires.
+func dataObject(body map[string]any) (map[string]any, error) {
+	data, ok := body["data"].(map[string]any)
+	if !ok {
+		return nil, badRequest("Body payload validation error: \"data\" must be an object")
+	}
+	return data, nil
+}
+
+// queryInt parses an integer query parameter, returning def when absent.
+func queryInt(r *http.Request, name string, def int) (int, error) {
+	raw := strings.TrimSpace(r.URL.Query().Get(name))
+	if raw == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		return 0, badRequest("Query parameter %q must be a non-negative integer", name)
+	}
+	return n, nil
+}
+
+// bodyInt parses an integer body field, returning def when absent.
+func bodyInt(body map[string]any, name string, def int) (int, error) {
+	raw, ok := body[name]
+	if !ok || raw == nil {
+		return def, nil
+	}
+	n, ok := raw.(float64)
+	if !ok || n < 0 || n != float64(int(n)) {
+		return 0, badRequest("Body field %q must be a non-negative integer", name)
+	}
+	return int(n), nil
+}
+
+func queryBool(r *http.Request, name string) bool {
+	v, _ := strconv.ParseBool(r.URL.Query().Get(name))
+	return v
+}
+
+// paginate applies offset pagination. A zero limit means def.
+func paginate[T any](items []T, limit int, offset int, def int) []T {
+	if limit <= 0 {
+		limit = def
+	}
+	if offset >= len(items) {
+		return []T{}
+	}
+	end := min(len(items), offset+limit)
+	return items[offset:end]
+}
+
+// cursorPaginate applies cursor pagination. Cursors are opaque to callers
+// but simply encode the offset of the next page.
+func cursorPaginate(items []map[string]any, r *http.Request, def int) (cursorPage, error) {
+	limit, err := queryInt(r, "limit", def)
+	if err != nil {
+		return cursorPage{}, err
+	}
+	if limit == 0 {
+		limit = def
+	}
+	offset := 0
+	if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+		raw, ok := strings.CutPrefix(cursor, "offset:")
+		n, err := strconv.Atoi(raw)
+		if !ok || err != nil || n < 0 {
+			return cursorPage{}, badRequest("Invalid cursor %q", cursor)
+		}
+		offset = n
+	}
+	page := cursorPage{items: paginate(items, limit, offset, def)}
+	if offset+limit < len(items) {
+		page.next = "offset:" + strconv.Itoa(offset+limit)
+	}
+	return page, nil
+}
@@ -0,0 +1,142 @@
+package mockserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testFixture = `{
+  "objects": [{"api_slug": "people", "attributes": [{"api_slug": "score", "title": "Score", "type": "number"}]}],
+  "lists": [{"api_slug": "hiring", "name": "Hiring", "parent_object": "people",
+    "attributes": [{"api_slug": "stage", "title": "Stage", "type": "status", "statuses": [{"title": "Applied"}, {"title": "Hired"}]}]}],
+  "records": {
+    "companies": [{"record_id": "11111111-1111-4111-8111-111111111111", "values": {"name": "Acme", "domains": ["acme.com"]}}],
+    "people": [
+      {"record_id": "22222222-2222-4222-8222-222222222222", "values": {"name": "Ada Lovelace", "score": 9, "company": "11111111-1111-4111-8111-111111111111"}},
+      {"values": {"name": "Grace Hopper", "score": 7}},
+      {"values": {"name": "Alan Turing"}}
+    ]
+  },
+  "entries": {"hiring": [{"parent_record_id": "22222222-2222-4222-8222-222222222222", "entry_values": {"stage": "Hired"}}]}
+}`
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	if err := os.WriteFile(path, []byte(testFixture), 0o600); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+	fixture, err := LoadFixture(path)
+	if err != nil {
+		t.Fatalf("load fixture: %v", err)
+	}
+	srv, err := New(fixture)
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	return srv
+}
+
+func call(t *testing.T, srv *Server, method string, path string, body any) (int, map[string]any) {
+	t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		_ = json.NewEncoder(&buf).Encode(body)
+	}
+	req := httptest.NewRequest(method, path, &buf)
+	req.Header.Set("Authorization", "Bearer test")
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, req)
+	var out map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+		t.Fatalf("%s %s: invalid JSON %q", method, path, w.Body.String())
+	}
+	return w.Code, out
+}
+
+func queryCount(t *testing.T, srv *Server, filter any) int {
+	t.Helper()
+	status, out := call(t, srv, http.MethodPost, "/v2/objects/people/records/query", map[string]any{"filter": filter})
+	if status != http.StatusOK {
+		t.Fatalf("query %v: status %d: %v", filter, status, out)
+	}
+	return len(out["data"].([]any))
+}
+
+func TestQueryFilters(t *testing.T) {
+	srv := newTestServer(t)
+	cases := []struct {
+		filter any
+		want   int
+	}{
+		{map[string]any{"name": "Ada Lovelace"}, 1},
+		{map[string]any{"name": map[string]any{"first_name": map[string]any{"$starts_with": "a"}}}, 2},
+		{map[string]any{"score": map[string]any{"$gte": 8}}, 1},
+		{map[string]any{"score": map[string]any{"$not_empty": true}}, 2},
+		{map[string]any{"$not": map[string]any{"score": map[string]any{"$not_empty": true}}}, 1},
+		{map[string]any{"$or": []any{map[string]any{"score": 7}, map[string]any{"name": map[string]any{"$contains": "turing"}}}}, 2},
+		{map[string]any{"name": map[string]any{"$in": []any{"Grace Hopper", "Alan Turing"}}}, 2},
+		{map[string]any{"path": []any{[]any{"people", "company"}, []any{"companies", "domains"}}, "constraints": map[string]any{"$eq": "acme.com"}}, 1},
+	}
+	for _, tc := range cases {
+		if got := queryCount(t, srv, tc.filter); got != tc.want {
+			t.Errorf("filter %v matched %d records, want %d", tc.filter, got, tc.want)
+		}
+	}
+
+	status, out := call(t, srv, http.MethodPost, "/v2/objects/people/records/query", map[string]any{"filter": map[string]any{"nope": 1}})
+	if status != http.StatusBadRequest || out["code"] != "validation_type" {
+		t.Fatalf("expected unknown attribute to be rejected, got %d %v", status, out)
+	}
+
+	status, out = call(t, srv, http.MethodPost, "/v2/lists/hiring/entries/query", map[string]any{"filter": map[string]any{"stage": "Hired"}})
+	if status != http.StatusOK || len(out["data"].([]any)) != 1 {
+		t.Fatalf("expected the seeded entry, got %d %v", status, out)
+	}
+}
+
+func TestRecordValueHistoryAndErrors(t *testing.T) {
+	srv := newTestServer(t)
+	ada := "/v2/objects/people/records/22222222-2222-4222-8222-222222222222"
+
+	status, out := call(t, srv, http.MethodPatch, ada, map[string]any{"data": map[string]any{"values": map[string]any{"score": 10}}})
+	if status != http.StatusOK {
+		t.Fatalf("update: %d %v", status, out)
+	}
+	_, out = call(t, srv, http.MethodGet, ada+"/attributes/score/values?show_historic=true", nil)
+	history := out["data"].([]any)
+	if len(history) != 2 || history[0].(map[string]any)["active_until"] == nil || history[1].(map[string]any)["value"] != float64(10) {
+		t.Fatalf("expected historic and current score, got %v", history)
+	}
+
+	status, out = call(t, srv, http.MethodPost, "/v2/objects/people/attributes", map[string]any{"data": map[string]any{"api_slug": "last_email", "type": "interaction"}})
+	if status != http.StatusOK {
+		t.Fatalf("create interaction attribute: %d %v", status, out)
+	}
+	status, out = call(t, srv, http.MethodGet, ada+"/attributes/last_email/values?show_historic=true", nil)
+	if status != http.StatusBadRequest {
+		t.Fatalf("expected show_historic on a COMINT attribute to fail, got %d %v", status, out)
+	}
+
+	status, out = call(t, srv, http.MethodPost, "/v2/objects/deals/records", map[string]any{"data": map[string]any{"values": map[string]any{"name": "Big deal"}}})
+	if status != http.StatusBadRequest {
+		t.Fatalf("expected missing required stage to fail, got %d %v", status, out)
+	}
+
+	status, out = call(t, srv, http.MethodGet, "/v2/unknown", nil)
+	if status != http.StatusNotFound || out["type"] != "invalid_request_error" {
+		t.Fatalf("expected JSON 404, got %d %v", status, out)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v2/self", nil)
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected requests without a token to be rejected, got %d", w.Code)
+	}
+}
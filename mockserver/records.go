@@ -0,0 +1,520 @@
+package mockserver
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+func (s *store) recordJSON(rec *record) map[string]any {
+	return map[string]any{
+		"id":         map[string]any{"workspace_id": s.workspaceID, "object_id": rec.parent.id, "record_id": rec.id},
+		"created_at": rec.createdAt,
+		"web_url":    "https://app.attio.com/mock/" + rec.parent.slug() + "/" + rec.id,
+		"values":     activeValueMap(rec),
+	}
+}
+
+func (s *store) entryJSON(rec *record) map[string]any {
+	return map[string]any{
+		"id":               map[string]any{"workspace_id": s.workspaceID, "list_id": rec.parent.id, "entry_id": rec.id},
+		"parent_record_id": rec.parentRecordID,
+		"parent_object":    rec.parentObject,
+		"created_at":       rec.createdAt,
+		"entry_values":     activeValueMap(rec),
+	}
+}
+
+func activeValueMap(rec *record) map[string]any {
+	values := make(map[string]any, len(rec.parent.attributes))
+	for _, attr := range rec.parent.attributes {
+		if !attr.flag("is_archived") {
+			values[attr.slug()] = rec.activeValues(attr)
+		}
+	}
+	return values
+}
+
+func (s *store) getRecord(object string, recordID string) (*record, error) {
+	obj, err := s.object(object)
+	if err != nil {
+		return nil, err
+	}
+	for _, rec := range s.records[obj.id] {
+		if rec.id == recordID {
+			return rec, nil
+		}
+	}
+	return nil, notFound("Record with ID %q not found.", recordID)
+}
+
+func (s *store) getEntry(list string, entryID string) (*record, error) {
+	l, err := s.list(list)
+	if err != nil {
+		return nil, err
+	}
+	for _, rec := range s.entries[l.id] {
+		if rec.id == entryID {
+			return rec, nil
+		}
+	}
+	return nil, notFound("Entry with ID %q not found.", entryID)
+}
+
+func valuesPayload(data map[string]any, key string) (map[string]any, error) {
+	raw, ok := data[key]
+	if !ok || raw == nil {
+		return map[string]any{}, nil
+	}
+	values, ok := raw.(map[string]any)
+	if !ok {
+		return nil, badRequest("%q must be an object of attribute values", key)
+	}
+	return values, nil
+}
+
+func (s *store) createRecord(obj *container, values map[string]any) (*record, error) {
+	rec := &record{id: newID(), parent: obj, createdAt: timestamp(), values: map[string][]map[string]any{}}
+	if err := s.writeValues(rec, values, writeReplace); err != nil {
+		return nil, err
+	}
+	if err := s.checkRequired(rec); err != nil {
+		return nil, err
+	}
+	s.records[obj.id] = append(s.records[obj.id], rec)
+	return rec, nil
+}
+
+// updateRow writes values to a copy of rec first so a failed required check
+// leaves the stored row untouched.
+func (s *store) updateRow(rec *record, values map[string]any, mode int) error {
+	draft := *rec
+	draft.values = make(map[string][]map[string]any, len(rec.values))
+	for id, vals := range rec.values {
+		copied := make([]map[string]any, len(vals))
+		for i, v := range vals {
+			copied[i] = copyMap(v)
+		}
+		draft.values[id] = copied
+	}
+	if err := s.writeValues(&draft, values, mode); err != nil {
+		return err
+	}
+	if err := s.checkRequired(&draft); err != nil {
+		return err
+	}
+	rec.values = draft.values
+	return nil
+}
+
+func (s *store) createEntry(list *container, data map[string]any, entryID string) (*record, error) {
+	parentID := stringField(data, "parent_record_id")
+	parentObject := stringField(data, "parent_object")
+	if parentObject == "" {
+		if objects, ok := list.data["parent_object"].([]any); ok && len(objects) > 0 {
+			parentObject, _ = objects[0].(string)
+		}
+	}
+	parent, err := s.getRecord(parentObject, parentID)
+	if err != nil {
+		return nil, badRequest("Entry parent: %v", err)
+	}
+	values, err := valuesPayload(data, "entry_values")
+	if err != nil {
+		return nil, err
+	}
+	if entryID == "" {
+		entryID = newID()
+	}
+	rec := &record{id: entryID, parent: list, createdAt: timestamp(), values: map[string][]map[string]any{}, parentRecordID: parent.id, parentObject: parent.parent.slug()}
+	if err := s.writeValues(rec, values, writeReplace); err != nil {
+		return nil, err
+	}
+	if err := s.checkRequired(rec); err != nil {
+		return nil, err
+	}
+	s.entries[list.id] = append(s.entries[list.id], rec)
+	return rec, nil
+}
+
+// recordText is the label search results and record references display.
+func recordText(rec *record) string {
+	for _, slug := range []string{"name", "title"} {
+		if attr := rec.parent.attribute(slug); attr != nil {
+			if values := rec.activeValues(attr); len(values) > 0 {
+				if text, ok := propertyCandidates(values[0], attr.typ(), "")[0].(string); ok {
+					return text
+				}
+			}
+		}
+	}
+	return rec.id
+}
+
+func (s *store) searchRecords(body map[string]any) ([]map[string]any, error) {
+	query := strings.ToLower(strings.TrimSpace(stringField(body, "query")))
+	limit, err := bodyInt(body, "limit", 25)
+	if err != nil {
+		return nil, err
+	}
+	objects := s.objects
+	if raw, ok := body["objects"].([]any); ok && len(raw) > 0 {
+		objects = nil
+		for _, ref := range raw {
+			slug, _ := ref.(string)
+			obj, err := s.object(slug)
+			if err != nil {
+				return nil, err
+			}
+			objects = append(objects, obj)
+		}
+	}
+	out := []map[string]any{}
+	for _, obj := range objects {
+		for _, rec := range s.records[obj.id] {
+			if query != "" && !recordMatchesText(rec, query) {
+				continue
+			}
+			out = append(out, map[string]any{
+				"id":           map[string]any{"workspace_id": s.workspaceID, "object_id": obj.id, "record_id": rec.id},
+				"record_text":  recordText(rec),
+				"record_image": nil,
+				"object_slug":  obj.slug(),
+			})
+		}
+	}
+	return paginate(out, limit, 0, 25), nil
+}
+
+// recordMatchesText looks for query in the record's name, emails, domains
+// and phone numbers, like Attio's search.
+func recordMatchesText(rec *record, query string) bool {
+	if strings.Contains(strings.ToLower(recordText(rec)), query) {
+		return true
+	}
+	for _, attr := range rec.parent.attributes {
+		switch attr.typ() {
+		case "email-address", "domain", "phone-number":
+			for _, v := range rec.activeValues(attr) {
+				if strings.Contains(valueKey(attr.typ(), v), query) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func (s *Server) recordRoutes() {
+	s.handle("POST /v2/objects/{object}/records", func(st *store, r *http.Request, body map[string]any) (any, error) {
+		obj, err := st.object(r.PathValue("object"))
+		if err != nil {
+			return nil, err
+		}
+		data, err := dataObject(body)
+		if err != nil {
+			return nil, err
+		}
+		values, err := valuesPayload(data, "values")
+		if err != nil {
+			return nil, err
+		}
+		rec, err := st.createRecord(obj, values)
+		if err != nil {
+			return nil, err
+		}
+		return st.recordJSON(rec), nil
+	})
+	s.handle("PUT /v2/objects/{object}/records", func(st *store, r *http.Request, body map[string]any) (any, error) {
+		obj, err := st.object(r.PathValue("object"))
+		if err != nil {
+			return nil, err
+		}
+		data, err := dataObject(body)
+		if err != nil {
+			return nil, err
+		}
+		values, err := valuesPayload(data, "values")
+		if err != nil {
+			return nil, err
+		}
+		matching := r.URL.Query().Get("matching_attribute")
+		attr := obj.attribute(matching)
+		if attr == nil {
+			return nil, badRequest("matching_attribute %q is not an attribute of %q.", matching, obj.slug())
+		}
+		if !attr.flag("is_unique") {
+			return nil, badRequest("matching_attribute %q must be a unique attribute.", attr.slug())
+		}
+		raw, ok := values[attr.slug()]
+		if !ok {
+			raw, ok = values[attr.id()]
+		}
+		if !ok {
+			return nil, badRequest("values must include the matching attribute %q.", attr.slug())
+		}
+		for _, rec := range st.records[obj.id] {
+			if st.rowMatchesValue(rec, attr.slug(), raw) {
+				if err := st.updateRow(rec, values, writeAppend); err != nil {
+					return nil, err
+				}
+				return st.recordJSON(rec), nil
+			}
+		}
+		rec, err := st.createRecord(obj, values)
+		if err != nil {
+			return nil, err
+		}
+		return st.recordJSON(rec), nil
+	})
+	s.handle("POST /v2/objects/{object}/records/query", func(st *store, r *http.Request, body map[string]any) (any, error) {
+		obj, err := st.object(r.PathValue("object"))
+		if err != nil {
+			return nil, err
+		}
+		rows, err := st.filterRows(st.records[obj.id], body["filter"], body["sorts"])
+		if err != nil {
+			return nil, err
+		}
+		return st.pageRows(rows, body, st.recordJSON)
+	})
+	s.handle("POST /v2/objects/records/search", func(st *store, _ *http.Request, body map[string]any) (any, error) {
+		return st.searchRecords(body)
+	})
+	s.handle("GET /v2/objects/{object}/records/{record_id}", func(st *store, r *http.Request, _ map[string]any) (any, error) {
+		rec, err := st.getRecord(r.PathValue("object"), r.PathValue("record_id"))
+		if err != nil {
+			return nil, err
+		}
+		return st.recordJSON(rec), nil
+	})
+	for method, mode := range map[string]int{"PATCH": writeAppend, "PUT": writeReplace} {
+		s.handle(method+" /v2/objects/{object}/records/{record_id}", func(st *store, r *http.Request, body map[string]any) (any, error) {
+			rec, err := st.getRecord(r.PathValue("object"), r.PathValue("record_id"))
+			if err != nil {
+				return nil, err
+			}
+			data, err := dataObject(body)
+			if err != nil {
+				return nil, err
+			}
+			values, err := valuesPayload(data, "values")
+			if err != nil {
+				return nil, err
+			}
+			if err := st.updateRow(rec, values, mode); err != nil {
+				return nil, err
+			}
+			return st.recordJSON(rec), nil
+		})
+	}
+	s.handle("DELETE /v2/objects/{object}/records/{record_id}", func(st *store, r *http.Request, _ map[string]any) (any, error) {
+		rec, err := st.getRecord(r.PathValue("object"), r.PathValue("record_id"))
+		if err != nil {
+			return nil, err
+		}
+		st.deleteRecord(rec)
+		return nil, nil
+	})
+	s.handle("GET /v2/objects/{object}/records/{record_id}/attributes/{attribute}/values", func(st *store, r *http.Request, _ map[string]any) (any, error) {
+		rec, err := st.getRecord(r.PathValue("object"), r.PathValue("record_id"))
+		if err != nil {
+			return nil, err
+		}
+		return st.attributeValues(rec, r)
+	})
+	s.handle("GET /v2/objects/{object}/records/{record_id}/entries", func(st *store, r *http.Request, _ map[string]any) (any, error) {
+		rec, err := st.getRecord(r.PathValue("object"), r.PathValue("record_id"))
+		if err != nil {
+			return nil, err
+		}
+		out := []map[string]any{}
+		for _, list := range st.lists {
+			for _, entry := range st.entries[list.id] {
+				if entry.parentRecordID == rec.id {
+					out = append(out, map[string]any{"list_id": list.id, "list_api_slug": list.slug(), "entry_id": entry.id, "created_at": entry.createdAt})
+				}
+			}
+		}
+		limit, offset, err := limitOffset(r, 100)
+		if err != nil {
+			return nil, err
+		}
+		return paginate(out, limit, offset, 100), nil
+	})
+}
+
+func (s *Server) entryRoutes() {
+	s.handle("POST /v2/lists/{list}/entries", func(st *store, r *http.Request, body map[string]any) (any, error) {
+		list, err := st.list(r.PathValue("list"))
+		if err != nil {
+			return nil, err
+		}
+		data, err := dataObject(body)
+		if err != nil {
+			return nil, err
+		}
+		rec, err := st.createEntry(list, data, "")
+		if err != nil {
+			return nil, err
+		}
+		return st.entryJSON(rec), nil
+	})
+	s.handle("PUT /v2/lists/{list}/entries", func(st *store, r *http.Request, body map[string]any) (any, error) {
+		list, err := st.list(r.PathValue("list"))
+		if err != nil {
+			return nil, err
+		}
+		data, err := dataObject(body)
+		if err != nil {
+			return nil, err
+		}
+		parentID := stringField(data, "parent_record_id")
+		for _, rec := range st.entries[list.id] {
+			if rec.parentRecordID == parentID {
+				values, err := valuesPayload(data, "entry_values")
+				if err != nil {
+					return nil, err
+				}
+				if err := st.updateRow(rec, values, writeAppend); err != nil {
+					return nil, err
+				}
+				return st.entryJSON(rec), nil
+			}
+		}
+		rec, err := st.createEntry(list, data, "")
+		if err != nil {
+			return nil, err
+		}
+		return st.entryJSON(rec), nil
+	})
+	s.handle("POST /v2/lists/{list}/entries/query", func(st *store, r *http.Request, body map[string]any) (any, error) {
+		list, err := st.list(r.PathValue("list"))
+		if err != nil {
+			return nil, err
+		}
+		rows, err := st.filterRows(st.entries[list.id], body["filter"], body["sorts"])
+		if err != nil {
+			return nil, err
+		}
+		return st.pageRows(rows, body, st.entryJSON)
+	})
+	s.handle("GET /v2/lists/{list}/entries/{entry_id}", func(st *store, r *http.Request, _ map[string]any) (any, error) {
+		rec, err := st.getEntry(r.PathValue("list"), r.PathValue("entry_id"))
+		if err != nil {
+			return nil, err
+		}
+		return st.entryJSON(rec), nil
+	})
+	for method, mode := range map[string]int{"PATCH": writeAppend, "PUT": writeReplace} {
+		s.handle(method+" /v2/lists/{list}/entries/{entry_id}", func(st *store, r *http.Request, body map[string]any) (any, error) {
+			rec, err := st.getEntry(r.PathValue("list"), r.PathValue("entry_id"))
+			if err != nil {
+				return nil, err
+			}
+			data, err := dataObject(body)
+			if err != nil {
+				return nil, err
+			}
+			values, err := valuesPayload(data, "entry_values")
+			if err != nil {
+				return nil, err
+			}
+			if err := st.updateRow(rec, values, mode); err != nil {
+				return nil, err
+			}
+			return st.entryJSON(rec), nil
+		})
+	}
+	s.handle("DELETE /v2/lists/{list}/entries/{entry_id}", func(st *store, r *http.Request, _ map[string]any) (any, error) {
+		rec, err := st.getEntry(r.PathValue("list"), r.PathValue("entry_id"))
+		if err != nil {
+			return nil, err
+		}
+		st.entries[rec.parent.id] = removeRow(st.entries[rec.parent.id], rec)
+		return nil, nil
+	})
+	s.handle("GET /v2/lists/{list}/entries/{entry_id}/attributes/{attribute}/values", func(st *store, r *http.Request, _ map[string]any) (any, error) {
+		rec, err := st.getEntry(r.PathValue("list"), r.PathValue("entry_id"))
+		if err != nil {
+			return nil, err
+		}
+		return st.attributeValues(rec, r)
+	})
+}
+
+// pageRows applies the limit and offset of a query body.
+func (s *store) pageRows(rows []*record, body map[string]any, render func(*record) map[string]any) ([]map[string]any, error) {
+	limit, err := bodyInt(body, "limit", 500)
+	if err != nil {
+		return nil, err
+	}
+	offset, err := bodyInt(body, "offset", 0)
+	if err != nil {
+		return nil, err
+	}
+	page := paginate(rows, limit, offset, 500)
+	out := make([]map[string]any, len(page))
+	for i, rec := range page {
+		out[i] = render(rec)
+	}
+	return out, nil
+}
+
+func (s *store) attributeValues(rec *record, r *http.Request) ([]map[string]any, error) {
+	attr := rec.parent.attribute(r.PathValue("attribute"))
+	if attr == nil {
+		return nil, notFound("Attribute with slug/ID %q not found.", r.PathValue("attribute"))
+	}
+	values := rec.activeValues(attr)
+	if queryBool(r, "show_historic") && attr.typ() == "interaction" {
+		return nil, badRequest("Cannot set show_historic param to true when querying a COMINT attribute.")
+	}
+	if queryBool(r, "show_historic") {
+		values = append([]map[string]any{}, rec.values[attr.id()]...)
+		sort.SliceStable(values, func(i, j int) bool {
+			return stringField(values[i], "active_from") < stringField(values[j], "active_from")
+		})
+	}
+	limit, offset, err := limitOffset(r, 500)
+	if err != nil {
+		return nil, err
+	}
+	return paginate(values, limit, offset, 500), nil
+}
+
+// deleteRecord removes rec along with its list entries.
+func (s *store) deleteRecord(rec *record) {
+	s.records[rec.parent.id] = removeRow(s.records[rec.parent.id], rec)
+	for listID, entries := range s.entries {
+		kept := entries[:0]
+		for _, entry := range entries {
+			if entry.parentRecordID != rec.id {
+				kept = append(kept, entry)
+			}
+		}
+		s.entries[listID] = kept
+	}
+}
+
+func removeRow(rows []*record, rec *record) []*record {
+	out := rows[:0]
+	for _, row := range rows {
+		if row != rec {
+			out = append(out, row)
+		}
+	}
+	return out
+}
+
+func limitOffset(r *http.Request, def int) (int, int, error) {
+	limit, err := queryInt(r, "limit", def)
+	if err != nil {
+		return 0, 0, err
+	}
+	offset, err := queryInt(r, "offset", 0)
+	if err != nil {
+		return 0, 0, err
+	}
+	return limit, offset, nil
+}
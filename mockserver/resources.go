@@ -0,0 +1,510 @@
+package mockserver
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func (s *store) createNote(data map[string]any) (map[string]any, error) {
+	parent, err := s.getRecord(stringField(data, "parent_object"), stringField(data, "parent_record_id"))
+	if err != nil {
+		return nil, badRequest("Note parent: %v", err)
+	}
+	title := stringField(data, "title")
+	if title == "" {
+		return nil, badRequest("Note title is required")
+	}
+	content, _ := data["content"].(string)
+	id := newID()
+	note := map[string]any{
+		"id":                map[string]any{"workspace_id": s.workspaceID, "note_id": id},
+		"parent_object":     parent.parent.slug(),
+		"parent_record_id":  parent.id,
+		"title":             title,
+		"meeting_id":        nilIfEmpty(stringField(data, "meeting_id")),
+		"content_plaintext": content,
+		"content_markdown":  content,
+		"tags":              []any{},
+		"created_by_actor":  s.apiActor(),
+		"created_at":        stringOr(data, "created_at", timestamp()),
+	}
+	s.notes = append(s.notes, note)
+	return note, nil
+}
+
+func (s *store) linkedRecords(raw any) ([]any, error) {
+	items, _ := raw.([]any)
+	out := make([]any, 0, len(items))
+	for _, item := range items {
+		link, ok := item.(map[string]any)
+		if !ok {
+			return nil, badRequest("linked_records entries must be objects")
+		}
+		obj, err := s.object(stringField(link, "target_object"))
+		if err != nil {
+			return nil, badRequest("linked_records: %v", err)
+		}
+		ref := copyMap(link)
+		ref["target_object"] = obj.slug()
+		target, err := s.normalizeValue(&attribute{data: map[string]any{"api_slug": "linked_records", "type": "record-reference"}}, ref)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, map[string]any{"target_object_id": obj.id, "target_record_id": target["target_record_id"]})
+	}
+	return out, nil
+}
+
+func (s *store) assignees(raw any) ([]any, error) {
+	items, _ := raw.([]any)
+	out := make([]any, 0, len(items))
+	for _, item := range items {
+		a, _ := item.(map[string]any)
+		ref := stringField(a, "referenced_actor_id")
+		if ref == "" {
+			ref = stringField(a, "workspace_member_email_address")
+		}
+		m := s.member(ref)
+		if m == nil {
+			return nil, badRequest("assignees: no workspace member %q", ref)
+		}
+		id, _ := m["id"].(map[string]any)
+		out = append(out, map[string]any{"referenced_actor_type": "workspace-member", "referenced_actor_id": id["workspace_member_id"]})
+	}
+	return out, nil
+}
+
+func (s *store) createTask(data map[string]any) (map[string]any, error) {
+	content, _ := data["content"].(string)
+	if strings.TrimSpace(content) == "" {
+		return nil, badRequest("Task content is required")
+	}
+	links, err := s.linkedRecords(data["linked_records"])
+	if err != nil {
+		return nil, err
+	}
+	assignees, err := s.assignees(data["assignees"])
+	if err != nil {
+		return nil, err
+	}
+	task := map[string]any{
+		"id":                map[string]any{"workspace_id": s.workspaceID, "task_id": newID()},
+		"content_plaintext": content,
+		"deadline_at":       data["deadline_at"],
+		"is_completed":      boolField(data, "is_completed"),
+		"linked_records":    links,
+		"assignees":         assignees,
+		"created_by_actor":  s.apiActor(),
+		"created_at":        timestamp(),
+	}
+	s.tasks = append(s.tasks, task)
+	return task, nil
+}
+
+func (s *store) updateTask(task map[string]any, data map[string]any) error {
+	if raw, ok := data["linked_records"]; ok {
+		links, err := s.linkedRecords(raw)
+		if err != nil {
+			return err
+		}
+		task["linked_records"] = links
+	}
+	if raw, ok := data["assignees"]; ok {
+		assignees, err := s.assignees(raw)
+		if err != nil {
+			return err
+		}
+		task["assignees"] = assignees
+	}
+	if _, ok := data["is_completed"]; ok {
+		task["is_completed"] = boolField(data, "is_completed")
+	}
+	merge(task, data, "deadline_at")
+	return nil
+}
+
+func (s *store) createComment(data map[string]any) (map[string]any, error) {
+	content, _ := data["content"].(string)
+	if strings.TrimSpace(content) == "" {
+		return nil, badRequest("Comment content is required")
+	}
+	author, _ := data["author"].(map[string]any)
+	if author == nil {
+		member := s.members[0]["id"].(map[string]any)
+		author = map[string]any{"type": "workspace-member", "id": member["workspace_member_id"]}
+	}
+
+	var th *thread
+	switch {
+	case stringField(data, "thread_id") != "":
+		th = s.thread(stringField(data, "thread_id"))
+		if th == nil {
+			return nil, badRequest("Thread %q not found.", stringField(data, "thread_id"))
+		}
+	case data["record"] != nil:
+		ref, _ := data["record"].(map[string]any)
+		rec, err := s.getRecord(stringField(ref, "object"), stringField(ref, "record_id"))
+		if err != nil {
+			return nil, badRequest("Comment record: %v", err)
+		}
+		th = &thread{id: newID(), createdAt: timestamp(), record: map[string]any{"object_id": rec.parent.id, "record_id": rec.id}}
+		s.threads = append(s.threads, th)
+	case data["entry"] != nil:
+		ref, _ := data["entry"].(map[string]any)
+		entry, err := s.getEntry(stringField(ref, "list"), stringField(ref, "entry_id"))
+		if err != nil {
+			return nil, badRequest("Comment entry: %v", err)
+		}
+		th = &thread{id: newID(), createdAt: timestamp(), entry: map[string]any{"list_id": entry.parent.id, "entry_id": entry.id}}
+		s.threads = append(s.threads, th)
+	default:
+		return nil, badRequest("Comments need a thread_id, record or entry")
+	}
+
+	comment := map[string]any{
+		"id":                map[string]any{"workspace_id": s.workspaceID, "comment_id": newID()},
+		"thread_id":         th.id,
+		"content_plaintext": content,
+		"entry":             th.entry,
+		"record":            th.record,
+		"resolved_at":       nil,
+		"resolved_by":       nil,
+		"created_at":        stringOr(data, "created_at", timestamp()),
+		"author":            author,
+	}
+	th.comments = append(th.comments, comment)
+	return comment, nil
+}
+
+func (s *store) thread(id string) *thread {
+	for _, th := range s.threads {
+		if th.id == id {
+			return th
+		}
+	}
+	return nil
+}
+
+func (s *store) threadJSON(th *thread) map[string]any {
+	return map[string]any{
+		"id":         map[string]any{"workspace_id": s.workspaceID, "thread_id": th.id},
+		"comments":   th.comments,
+		"created_at": th.createdAt,
+	}
+}
+
+func (s *store) createWebhook(data map[string]any) (map[string]any, error) {
+	target := stringField(data, "target_url")
+	if !strings.HasPrefix(target, "https://") && !strings.HasPrefix(target, "http://") {
+		return nil, badRequest("target_url must be an http(s) URL")
+	}
+	subscriptions, ok := data["subscriptions"].([]any)
+	if !ok {
+		return nil, badRequest("subscriptions must be an array")
+	}
+	webhook := map[string]any{
+		"id":            map[string]any{"workspace_id": s.workspaceID, "webhook_id": newID()},
+		"target_url":    target,
+		"subscriptions": subscriptions,
+		"status":        "active",
+		"created_at":    timestamp(),
+	}
+	s.webhooks = append(s.webhooks, webhook)
+	return webhook, nil
+}
+
+// findByID returns the item whose id object has key == id, and its index.
+func findByID(items []map[string]any, key string, id string) (map[string]any, int) {
+	for i, item := range items {
+		ids, _ := item["id"].(map[string]any)
+		if stringField(ids, key) == id {
+			return item, i
+		}
+	}
+	return nil, -1
+}
+
+func (s *Server) resourceRoutes() {
+	s.handle("GET /v2/workspace_members", func(st *store, _ *http.Request, _ map[string]any) (any, error) {
+		return st.members, nil
+	})
+	s.handle("GET /v2/workspace_members/{workspace_member_id}", func(st *store, r *http.Request, _ map[string]any) (any, error) {
+		m, _ := findByID(st.members, "workspace_member_id", r.PathValue("workspace_member_id"))
+		if m == nil {
+			return nil, notFound("Workspace member %q not found.", r.PathValue("workspace_member_id"))
+		}
+		return m, nil
+	})
+
+	s.handle("GET /v2/notes", func(st *store, r *http.Request, _ map[string]any) (any, error) {
+		q := r.URL.Query()
+		out := []map[string]any{}
+		var parentObject string
+		if ref := q.Get("parent_object"); ref != "" {
+			obj, err := st.object(ref)
+			if err != nil {
+				return nil, err
+			}
+			parentObject = obj.slug()
+		}
+		for _, note := range st.notes {
+			if parentObject != "" && note["parent_object"] != parentObject {
+				continue
+			}
+			if id := q.Get("parent_record_id"); id != "" && note["parent_record_id"] != id {
+				continue
+			}
+			out = append(out, note)
+		}
+		limit, offset, err := limitOffset(r, 10)
+		if err != nil {
+			return nil, err
+		}
+		return paginate(out, limit, offset, 10), nil
+	})
+	s.handle("POST /v2/notes", func(st *store, _ *http.Request, body map[string]any) (any, error) {
+		data, err := dataObject(body)
+		if err != nil {
+			return nil, err
+		}
+		return st.createNote(data)
+	})
+	s.handle("GET /v2/notes/{note_id}", func(st *store, r *http.Request, _ map[string]any) (any, error) {
+		note, _ := findByID(st.notes, "note_id", r.PathValue("note_id"))
+		if note == nil {
+			return nil, notFound("Note %q not found.", r.PathValue("note_id"))
+		}
+		return note, nil
+	})
+	s.handle("DELETE /v2/notes/{note_id}", func(st *store, r *http.Request, _ map[string]any) (any, error) {
+		_, i := findByID(st.notes, "note_id", r.PathValue("note_id"))
+		if i < 0 {
+			return nil, notFound("Note %q not found.", r.PathValue("note_id"))
+		}
+		st.notes = append(st.notes[:i], st.notes[i+1:]...)
+		return nil, nil
+	})
+
+	s.handle("GET /v2/tasks", func(st *store, r *http.Request, _ map[string]any) (any, error) {
+		return st.listTasks(r)
+	})
+	s.handle("POST /v2/tasks", func(st *store, _ *http.Request, body map[string]any) (any, error) {
+		data, err := dataObject(body)
+		if err != nil {
+			return nil, err
+		}
+		return st.createTask(data)
+	})
+	s.handle("GET /v2/tasks/{task_id}", func(st *store, r *http.Request, _ map[string]any) (any, error) {
+		task, _ := findByID(st.tasks, "task_id", r.PathValue("task_id"))
+		if task == nil {
+			return nil, notFound("Task %q not found.", r.PathValue("task_id"))
+		}
+		return task, nil
+	})
+	s.handle("PATCH /v2/tasks/{task_id}", func(st *store, r *http.Request, body map[string]any) (any, error) {
+		task, _ := findByID(st.tasks, "task_id", r.PathValue("task_id"))
+		if task == nil {
+			return nil, notFound("Task %q not found.", r.PathValue("task_id"))
+		}
+		data, err := dataObject(body)
+		if err != nil {
+			return nil, err
+		}
+		if err := st.updateTask(task, data); err != nil {
+			return nil, err
+		}
+		return task, nil
+	})
+	s.handle("DELETE /v2/tasks/{task_id}", func(st *store, r *http.Request, _ map[string]any) (any, error) {
+		_, i := findByID(st.tasks, "task_id", r.PathValue("task_id"))
+		if i < 0 {
+			return nil, notFound("Task %q not found.", r.PathValue("task_id"))
+		}
+		st.tasks = append(st.tasks[:i], st.tasks[i+1:]...)
+		return nil, nil
+	})
+
+	s.handle("POST /v2/comments", func(st *store, _ *http.Request, body map[string]any) (any, error) {
+		data, err := dataObject(body)
+		if err != nil {
+			return nil, err
+		}
+		return st.createComment(data)
+	})
+	s.handle("GET /v2/comments/{comment_id}", func(st *store, r *http.Request, _ map[string]any) (any, error) {
+		for _, th := range st.threads {
+			if comment, _ := findByID(th.comments, "comment_id", r.PathValue("comment_id")); comment != nil {
+				return comment, nil
+			}
+		}
+		return nil, notFound("Comment %q not found.", r.PathValue("comment_id"))
+	})
+	s.handle("DELETE /v2/comments/{comment_id}", func(st *store, r *http.Request, _ map[string]any) (any, error) {
+		for ti, th := range st.threads {
+			_, i := findByID(th.comments, "comment_id", r.PathValue("comment_id"))
+			switch {
+			case i < 0:
+				continue
+			case i == 0:
+				// Deleting the head comment deletes the whole thread.
+				st.threads = append(st.threads[:ti], st.threads[ti+1:]...)
+			default:
+				th.comments = append(th.comments[:i], th.comments[i+1:]...)
+			}
+			return nil, nil
+		}
+		return nil, notFound("Comment %q not found.", r.PathValue("comment_id"))
+	})
+	s.handle("GET /v2/threads", func(st *store, r *http.Request, _ map[string]any) (any, error) {
+		q := r.URL.Query()
+		out := []map[string]any{}
+		for _, th := range st.threads {
+			if q.Get("record_id") != "" && (th.record == nil || th.record["record_id"] != q.Get("record_id")) {
+				continue
+			}
+			if q.Get("entry_id") != "" && (th.entry == nil || th.entry["entry_id"] != q.Get("entry_id")) {
+				continue
+			}
+			out = append(out, st.threadJSON(th))
+		}
+		limit, offset, err := limitOffset(r, 10)
+		if err != nil {
+			return nil, err
+		}
+		return paginate(out, limit, offset, 10), nil
+	})
+	s.handle("GET /v2/threads/{thread_id}", func(st *store, r *http.Request, _ map[string]any) (any, error) {
+		th := st.thread(r.PathValue("thread_id"))
+		if th == nil {
+			return nil, notFound("Thread %q not found.", r.PathValue("thread_id"))
+		}
+		return st.threadJSON(th), nil
+	})
+
+	s.handle("GET /v2/webhooks", func(st *store, r *http.Request, _ map[string]any) (any, error) {
+		limit, offset, err := limitOffset(r, 10)
+		if err != nil {
+			return nil, err
+		}
+		return paginate(st.webhooks, limit, offset, 10), nil
+	})
+	s.handle("POST /v2/webhooks", func(st *store, _ *http.Request, body map[string]any) (any, error) {
+		data, err := dataObject(body)
+		if err != nil {
+			return nil, err
+		}
+		webhook, err := st.createWebhook(data)
+		if err != nil {
+			return nil, err
+		}
+		// The signing secret is only returned on creation.
+		out := copyMap(webhook)
+		out["secret"] = strings.ReplaceAll(newID(), "-", "")
+		return out, nil
+	})
+	s.handle("GET /v2/webhooks/{webhook_id}", func(st *store, r *http.Request, _ map[string]any) (any, error) {
+		webhook, _ := findByID(st.webhooks, "webhook_id", r.PathValue("webhook_id"))
+		if webhook == nil {
+			return nil, notFound("Webhook %q not found.", r.PathValue("webhook_id"))
+		}
+		return webhook, nil
+	})
+	s.handle("PATCH /v2/webhooks/{webhook_id}", func(st *store, r *http.Request, body map[string]any) (any, error) {
+		webhook, _ := findByID(st.webhooks, "webhook_id", r.PathValue("webhook_id"))
+		if webhook == nil {
+			return nil, notFound("Webhook %q not found.", r.PathValue("webhook_id"))
+		}
+		data, err := dataObject(body)
+		if err != nil {
+			return nil, err
+		}
+		merge(webhook, data, "target_url", "subscriptions")
+		return webhook, nil
+	})
+	s.handle("DELETE /v2/webhooks/{webhook_id}", func(st *store, r *http.Request, _ map[string]any) (any, error) {
+		_, i := findByID(st.webhooks, "webhook_id", r.PathValue("webhook_id"))
+		if i < 0 {
+			return nil, notFound("Webhook %q not found.", r.PathValue("webhook_id"))
+		}
+		st.webhooks = append(st.webhooks[:i], st.webhooks[i+1:]...)
+		return nil, nil
+	})
+}
+
+func (s *store) listTasks(r *http.Request) ([]map[string]any, error) {
+	q := r.URL.Query()
+	var linkedObjectID string
+	if ref := q.Get("linked_object"); ref != "" {
+		obj, err := s.object(ref)
+		if err != nil {
+			return nil, err
+		}
+		linkedObjectID = obj.id
+	}
+	var assigneeID any
+	if ref := q.Get("assignee"); ref != "" && ref != "null" {
+		m := s.member(ref)
+		if m == nil {
+			return []map[string]any{}, nil
+		}
+		assigneeID = m["id"].(map[string]any)["workspace_member_id"]
+	}
+	out := []map[string]any{}
+	for _, task := range s.tasks {
+		if raw := q.Get("is_completed"); raw != "" {
+			want, err := strconv.ParseBool(raw)
+			if err != nil {
+				return nil, badRequest("is_completed must be true or false")
+			}
+			if task["is_completed"] != want {
+				continue
+			}
+		}
+		if linkedObjectID != "" || q.Get("linked_record_id") != "" {
+			if !hasLink(task["linked_records"], linkedObjectID, q.Get("linked_record_id")) {
+				continue
+			}
+		}
+		if assigneeID != nil && !hasAssignee(task["assignees"], assigneeID) {
+			continue
+		}
+		if q.Get("assignee") == "null" && len(task["assignees"].([]any)) > 0 {
+			continue
+		}
+		out = append(out, task)
+	}
+	if q.Get("sort") == "created_at:desc" {
+		sort.SliceStable(out, func(i, j int) bool {
+			return stringField(out[i], "created_at") > stringField(out[j], "created_at")
+		})
+	}
+	limit, offset, err := limitOffset(r, 500)
+	if err != nil {
+		return nil, err
+	}
+	return paginate(out, limit, offset, 500), nil
+}
+
+func hasLink(raw any, objectID string, recordID string) bool {
+	links, _ := raw.([]any)
+	for _, item := range links {
+		link, _ := item.(map[string]any)
+		if (objectID == "" || link["target_object_id"] == objectID) && (recordID == "" || link["target_record_id"] == recordID) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAssignee(raw any, memberID any) bool {
+	assignees, _ := raw.([]any)
+	for _, item := range assignees {
+		if a, _ := item.(map[string]any); a["referenced_actor_id"] == memberID {
+			return true
+		}
+	}
+	return false
+}
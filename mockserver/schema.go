@@ -0,0 +1,229 @@
+package mockserver
+
+import (
+	"net/http"
+)
+
+func (s *Server) routes() {
+	s.handle("GET /v2/self", func(st *store, _ *http.Request, _ map[string]any) (any, error) {
+		member := st.members[0]
+		id, _ := member["id"].(map[string]any)
+		return rawResponse{body: map[string]any{
+			"active":                            true,
+			"scope":                             "object_configuration:read-write record_permission:read-write list_configuration:read-write list_entry:read-write note:read-write task:read-write comment:read-write webhook:read-write meeting:read-write call_recording:read-write user_management:read",
+			"client_id":                         "mock-client",
+			"token_type":                        "Bearer",
+			"workspace_id":                      st.workspaceID,
+			"workspace_name":                    st.workspaceName,
+			"workspace_slug":                    "mock-workspace",
+			"workspace_logo_url":                nil,
+			"authorized_by_workspace_member_id": id["workspace_member_id"],
+		}}, nil
+	})
+	s.schemaRoutes()
+	s.recordRoutes()
+	s.entryRoutes()
+	s.resourceRoutes()
+	s.meetingRoutes()
+}
+
+func (s *Server) schemaRoutes() {
+	s.handle("GET /v2/objects", func(st *store, _ *http.Request, _ map[string]any) (any, error) {
+		return containerData(st.objects), nil
+	})
+	s.handle("POST /v2/objects", func(st *store, _ *http.Request, body map[string]any) (any, error) {
+		data, err := dataObject(body)
+		if err != nil {
+			return nil, err
+		}
+		obj, err := st.createObject(data)
+		if err != nil {
+			return nil, err
+		}
+		return obj.data, nil
+	})
+	s.handle("GET /v2/objects/{object}", func(st *store, r *http.Request, _ map[string]any) (any, error) {
+		obj, err := st.object(r.PathValue("object"))
+		if err != nil {
+			return nil, err
+		}
+		return obj.data, nil
+	})
+	s.handle("PATCH /v2/objects/{object}", func(st *store, r *http.Request, body map[string]any) (any, error) {
+		obj, err := st.object(r.PathValue("object"))
+		if err != nil {
+			return nil, err
+		}
+		data, err := dataObject(body)
+		if err != nil {
+			return nil, err
+		}
+		if slug := stringField(data, "api_slug"); slug != "" && slug != obj.slug() {
+			if _, err := st.object(slug); err == nil {
+				return nil, errorf(http.StatusConflict, "slug_conflict", "An object with slug %q already exists.", slug)
+			}
+		}
+		merge(obj.data, data, "api_slug", "singular_noun", "plural_noun")
+		return obj.data, nil
+	})
+
+	s.handle("GET /v2/lists", func(st *store, _ *http.Request, _ map[string]any) (any, error) {
+		return containerData(st.lists), nil
+	})
+	s.handle("POST /v2/lists", func(st *store, _ *http.Request, body map[string]any) (any, error) {
+		data, err := dataObject(body)
+		if err != nil {
+			return nil, err
+		}
+		list, err := st.createList(data)
+		if err != nil {
+			return nil, err
+		}
+		return list.data, nil
+	})
+	s.handle("GET /v2/lists/{list}", func(st *store, r *http.Request, _ map[string]any) (any, error) {
+		list, err := st.list(r.PathValue("list"))
+		if err != nil {
+			return nil, err
+		}
+		return list.data, nil
+	})
+	s.handle("PATCH /v2/lists/{list}", func(st *store, r *http.Request, body map[string]any) (any, error) {
+		list, err := st.list(r.PathValue("list"))
+		if err != nil {
+			return nil, err
+		}
+		data, err := dataObject(body)
+		if err != nil {
+			return nil, err
+		}
+		if slug := stringField(data, "api_slug"); slug != "" && slug != list.slug() {
+			if _, err := st.list(slug); err == nil {
+				return nil, errorf(http.StatusConflict, "slug_conflict", "A list with slug %q already exists.", slug)
+			}
+		}
+		merge(list.data, data, "api_slug", "name", "workspace_access", "workspace_member_access")
+		return list.data, nil
+	})
+
+	s.handle("GET /v2/{target}/{identifier}/attributes", func(st *store, r *http.Request, _ map[string]any) (any, error) {
+		c, err := st.target(r.PathValue("target"), r.PathValue("identifier"))
+		if err != nil {
+			return nil, err
+		}
+		showArchived := queryBool(r, "show_archived")
+		out := []map[string]any{}
+		for _, attr := range c.attributes {
+			if showArchived || !attr.flag("is_archived") {
+				out = append(out, attr.data)
+			}
+		}
+		limit, offset, err := limitOffset(r, len(out))
+		if err != nil {
+			return nil, err
+		}
+		return paginate(out, limit, offset, len(out)), nil
+	})
+	s.handle("POST /v2/{target}/{identifier}/attributes", func(st *store, r *http.Request, body map[string]any) (any, error) {
+		c, err := st.target(r.PathValue("target"), r.PathValue("identifier"))
+		if err != nil {
+			return nil, err
+		}
+		data, err := dataObject(body)
+		if err != nil {
+			return nil, err
+		}
+		attr, err := st.createAttribute(c, data)
+		if err != nil {
+			return nil, err
+		}
+		return attr.data, nil
+	})
+	s.handle("GET /v2/{target}/{identifier}/attributes/{attribute}", func(st *store, r *http.Request, _ map[string]any) (any, error) {
+		_, attr, err := st.targetAttribute(r.PathValue("target"), r.PathValue("identifier"), r.PathValue("attribute"))
+		if err != nil {
+			return nil, err
+		}
+		return attr.data, nil
+	})
+	s.handle("PATCH /v2/{target}/{identifier}/attributes/{attribute}", func(st *store, r *http.Request, body map[string]any) (any, error) {
+		c, attr, err := st.targetAttribute(r.PathValue("target"), r.PathValue("identifier"), r.PathValue("attribute"))
+		if err != nil {
+			return nil, err
+		}
+		data, err := dataObject(body)
+		if err != nil {
+			return nil, err
+		}
+		if slug := stringField(data, "api_slug"); slug != "" && slug != attr.slug() && c.attribute(slug) != nil {
+			return nil, errorf(http.StatusConflict, "slug_conflict", "An attribute with slug %q already exists.", slug)
+		}
+		merge(attr.data, data, "title", "description", "api_slug", "is_required", "is_unique", "default_value", "config", "is_archived")
+		if _, ok := data["default_value"]; ok {
+			attr.data["is_default_value_enabled"] = data["default_value"] != nil
+		}
+		return attr.data, nil
+	})
+
+	for _, kind := range []struct {
+		path  string
+		idKey string
+		items func(*attribute) *[]map[string]any
+		add   func(*store, *container, *attribute, map[string]any) (map[string]any, error)
+		patch []string
+	}{
+		{"options", "option_id", func(a *attribute) *[]map[string]any { return &a.options }, (*store).createOption, []string{"title", "is_archived"}},
+		{"statuses", "status_id", func(a *attribute) *[]map[string]any { return &a.statuses }, (*store).createStatus, []string{"title", "is_archived", "celebration_enabled", "target_time_in_status"}},
+	} {
+		base := "/v2/{target}/{identifier}/attributes/{attribute}/" + kind.path
+		s.handle("GET "+base, func(st *store, r *http.Request, _ map[string]any) (any, error) {
+			_, attr, err := st.targetAttribute(r.PathValue("target"), r.PathValue("identifier"), r.PathValue("attribute"))
+			if err != nil {
+				return nil, err
+			}
+			showArchived := queryBool(r, "show_archived")
+			out := []map[string]any{}
+			for _, item := range *kind.items(attr) {
+				if showArchived || !boolField(item, "is_archived") {
+					out = append(out, item)
+				}
+			}
+			return out, nil
+		})
+		s.handle("POST "+base, func(st *store, r *http.Request, body map[string]any) (any, error) {
+			c, attr, err := st.targetAttribute(r.PathValue("target"), r.PathValue("identifier"), r.PathValue("attribute"))
+			if err != nil {
+				return nil, err
+			}
+			data, err := dataObject(body)
+			if err != nil {
+				return nil, err
+			}
+			return kind.add(st, c, attr, data)
+		})
+		s.handle("PATCH "+base+"/{item}", func(st *store, r *http.Request, body map[string]any) (any, error) {
+			_, attr, err := st.targetAttribute(r.PathValue("target"), r.PathValue("identifier"), r.PathValue("attribute"))
+			if err != nil {
+				return nil, err
+			}
+			item := findOption(*kind.items(attr), kind.idKey, r.PathValue("item"))
+			if item == nil {
+				return nil, notFound("%s %q not found on attribute %q.", kind.path, r.PathValue("item"), attr.slug())
+			}
+			data, err := dataObject(body)
+			if err != nil {
+				return nil, err
+			}
+			merge(item, data, kind.patch...)
+			return item, nil
+		})
+	}
+}
+
+func containerData(items []*container) []map[string]any {
+	out := make([]map[string]any, len(items))
+	for i, c := range items {
+		out[i] = c.data
+	}
+	return out
+}
@@ -0,0 +1,379 @@
+package mockserver
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// store holds the workspace. API representations are kept as maps so they
+// round-trip exactly as the handlers built them; records and entries keep
+// every value they ever had so show_historic works.
+type store struct {
+	workspaceID   string
+	workspaceName string
+	actorID       string
+
+	members  []map[string]any
+	objects  []*container
+	lists    []*container
+	records  map[string][]*record
+	entries  map[string][]*record
+	notes    []map[string]any
+	tasks    []map[string]any
+	threads  []*thread
+	webhooks []map[string]any
+	meetings []map[string]any
+	// recordings and transcripts are keyed by meeting and recording ID.
+	recordings  map[string][]map[string]any
+	transcripts map[string][]map[string]any
+}
+
+// container is an object or a list: both own attributes and rows.
+type container struct {
+	kind       string
+	id         string
+	data       map[string]any
+	attributes []*attribute
+}
+
+type attribute struct {
+	data     map[string]any
+	options  []map[string]any
+	statuses []map[string]any
+}
+
+// record is a record of an object or an entry of a list. values is keyed by
+// attribute ID and includes historic values (active_until set).
+type record struct {
+	id        string
+	parent    *container
+	createdAt string
+	values    map[string][]map[string]any
+
+	// parentRecordID and parentObject are only set for list entries.
+	parentRecordID string
+	parentObject   string
+}
+
+type thread struct {
+	id        string
+	createdAt string
+	record    map[string]any
+	entry     map[string]any
+	comments  []map[string]any
+}
+
+func newStore() *store {
+	return &store{
+		workspaceID:   newID(),
+		workspaceName: "Mock Workspace",
+		actorID:       newID(),
+		records:       map[string][]*record{},
+		entries:       map[string][]*record{},
+		recordings:    map[string][]map[string]any{},
+		transcripts:   map[string][]map[string]any{},
+	}
+}
+
+func newID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// timestamp uses a fixed-width format so timestamps sort as strings.
+func timestamp() string {
+	return time.Now().UTC().Format("2006-01-02T15:04:05.000000000Z")
+}
+
+func (s *store) apiActor() map[string]any {
+	return map[string]any{"type": "api-token", "id": s.actorID}
+}
+
+func (c *container) slug() string {
+	return stringField(c.data, "api_slug")
+}
+
+// idKey is the field naming this container's ID in nested id objects.
+func (c *container) idKey() string {
+	if c.kind == "lists" {
+		return "list_id"
+	}
+	return "object_id"
+}
+
+func (c *container) attribute(identifier string) *attribute {
+	for _, attr := range c.attributes {
+		if attr.slug() == identifier || attr.id() == identifier {
+			return attr
+		}
+	}
+	return nil
+}
+
+func (a *attribute) id() string {
+	id, _ := a.data["id"].(map[string]any)
+	return stringField(id, "attribute_id")
+}
+
+func (a *attribute) slug() string {
+	return stringField(a.data, "api_slug")
+}
+
+func (a *attribute) typ() string {
+	return stringField(a.data, "type")
+}
+
+func (a *attribute) flag(name string) bool {
+	v, _ := a.data[name].(bool)
+	return v
+}
+
+func (s *store) object(identifier string) (*container, error) {
+	for _, obj := range s.objects {
+		if obj.slug() == identifier || obj.id == identifier {
+			return obj, nil
+		}
+	}
+	return nil, notFound("Object with slug/ID %q not found.", identifier)
+}
+
+func (s *store) list(identifier string) (*container, error) {
+	for _, list := range s.lists {
+		if list.slug() == identifier || list.id == identifier {
+			return list, nil
+		}
+	}
+	return nil, notFound("List with slug/ID %q not found.", identifier)
+}
+
+// target resolves the {target}/{identifier} pair used by attribute routes.
+func (s *store) target(target string, identifier string) (*container, error) {
+	switch target {
+	case "objects":
+		return s.object(identifier)
+	case "lists":
+		return s.list(identifier)
+	}
+	return nil, notFound("Unknown attribute target %q (expected objects or lists)", target)
+}
+
+func (s *store) targetAttribute(target string, identifier string, attribute string) (*container, *attribute, error) {
+	c, err := s.target(target, identifier)
+	if err != nil {
+		return nil, nil, err
+	}
+	attr := c.attribute(attribute)
+	if attr == nil {
+		return nil, nil, notFound("Attribute with slug/ID %q not found on %s %q.", attribute, strings.TrimSuffix(target, "s"), identifier)
+	}
+	return c, attr, nil
+}
+
+func (s *store) createObject(data map[string]any) (*container, error) {
+	slug := stringField(data, "api_slug")
+	if slug == "" {
+		return nil, badRequest("Object api_slug is required")
+	}
+	if _, err := s.object(slug); err == nil {
+		return nil, errorf(http.StatusConflict, "slug_conflict", "An object with slug %q already exists.", slug)
+	}
+	id := newID()
+	obj := &container{kind: "objects", id: id, data: map[string]any{
+		"id":            map[string]any{"workspace_id": s.workspaceID, "object_id": id},
+		"api_slug":      slug,
+		"singular_noun": stringOr(data, "singular_noun", slug),
+		"plural_noun":   stringOr(data, "plural_noun", slug),
+		"created_at":    timestamp(),
+	}}
+	s.objects = append(s.objects, obj)
+	return obj, nil
+}
+
+func (s *store) createList(data map[string]any) (*container, error) {
+	slug := stringField(data, "api_slug")
+	if slug == "" {
+		return nil, badRequest("List api_slug is required")
+	}
+	if _, err := s.list(slug); err == nil {
+		return nil, errorf(http.StatusConflict, "slug_conflict", "A list with slug %q already exists.", slug)
+	}
+	parent, err := s.object(stringField(data, "parent_object"))
+	if err != nil {
+		return nil, badRequest("List parent_object: %v", err)
+	}
+	id := newID()
+	access := stringOr(data, "workspace_access", "full-access")
+	memberAccess, _ := data["workspace_member_access"].([]any)
+	if memberAccess == nil {
+		memberAccess = []any{}
+	}
+	list := &container{kind: "lists", id: id, data: map[string]any{
+		"id":                      map[string]any{"workspace_id": s.workspaceID, "list_id": id},
+		"api_slug":                slug,
+		"name":                    stringOr(data, "name", slug),
+		"parent_object":           []any{parent.slug()},
+		"workspace_access":        access,
+		"workspace_member_access": memberAccess,
+		"created_by_actor":        s.apiActor(),
+		"created_at":              timestamp(),
+	}}
+	s.lists = append(s.lists, list)
+	return list, nil
+}
+
+func (s *store) createAttribute(c *container, data map[string]any) (*attribute, error) {
+	slug := stringField(data, "api_slug")
+	if slug == "" {
+		return nil, badRequest("Attribute api_slug is required")
+	}
+	if c.attribute(slug) != nil {
+		return nil, errorf(http.StatusConflict, "slug_conflict", "An attribute with slug %q already exists.", slug)
+	}
+	typ := stringField(data, "type")
+	if typ == "" {
+		return nil, badRequest("Attribute type is required")
+	}
+	config, _ := data["config"].(map[string]any)
+	if config == nil {
+		config = map[string]any{}
+	}
+	id := newID()
+	attr := &attribute{data: map[string]any{
+		"id":                       map[string]any{"workspace_id": s.workspaceID, c.idKey(): c.id, "attribute_id": id},
+		"title":                    stringOr(data, "title", slug),
+		"description":              data["description"],
+		"api_slug":                 slug,
+		"type":                     typ,
+		"is_system_attribute":      false,
+		"is_writable":              true,
+		"is_required":              boolField(data, "is_required"),
+		"is_unique":                boolField(data, "is_unique"),
+		"is_multiselect":           boolField(data, "is_multiselect"),
+		"is_default_value_enabled": data["default_value"] != nil,
+		"is_archived":              false,
+		"default_value":            data["default_value"],
+		"relationship":             nil,
+		"created_at":               timestamp(),
+		"config":                   config,
+	}}
+	c.attributes = append(c.attributes, attr)
+	return attr, nil
+}
+
+func (s *store) createOption(c *container, attr *attribute, data map[string]any) (map[string]any, error) {
+	if attr.typ() != "select" {
+		return nil, badRequest("Attribute %q is not a select attribute", attr.slug())
+	}
+	title := stringField(data, "title")
+	if title == "" {
+		return nil, badRequest("Option title is required")
+	}
+	for _, opt := range attr.options {
+		if opt["title"] == title {
+			return nil, errorf(http.StatusConflict, "slug_conflict", "Option %q already exists.", title)
+		}
+	}
+	opt := map[string]any{
+		"id":          map[string]any{"workspace_id": s.workspaceID, c.idKey(): c.id, "attribute_id": attr.id(), "option_id": newID()},
+		"title":       title,
+		"is_archived": false,
+	}
+	attr.options = append(attr.options, opt)
+	return opt, nil
+}
+
+func (s *store) createStatus(c *container, attr *attribute, data map[string]any) (map[string]any, error) {
+	if attr.typ() != "status" {
+		return nil, badRequest("Attribute %q is not a status attribute", attr.slug())
+	}
+	title := stringField(data, "title")
+	if title == "" {
+		return nil, badRequest("Status title is required")
+	}
+	for _, status := range attr.statuses {
+		if status["title"] == title {
+			return nil, errorf(http.StatusConflict, "slug_conflict", "Status %q already exists.", title)
+		}
+	}
+	status := map[string]any{
+		"id":                    map[string]any{"workspace_id": s.workspaceID, c.idKey(): c.id, "attribute_id": attr.id(), "status_id": newID()},
+		"title":                 title,
+		"is_archived":           false,
+		"celebration_enabled":   boolField(data, "celebration_enabled"),
+		"target_time_in_status": data["target_time_in_status"],
+	}
+	attr.statuses = append(attr.statuses, status)
+	return status, nil
+}
+
+// findOption matches a select option or status by title or ID.
+func findOption(options []map[string]any, idKey string, ref string) map[string]any {
+	for _, opt := range options {
+		id, _ := opt["id"].(map[string]any)
+		if opt["title"] == ref || stringField(id, idKey) == ref {
+			return opt
+		}
+	}
+	return nil
+}
+
+func (s *store) member(ref string) map[string]any {
+	for _, m := range s.members {
+		id, _ := m["id"].(map[string]any)
+		if stringField(id, "workspace_member_id") == ref || strings.EqualFold(stringField(m, "email_address"), ref) {
+			return m
+		}
+	}
+	return nil
+}
+
+func (s *store) addMember(data map[string]any) map[string]any {
+	m := map[string]any{
+		"id":            map[string]any{"workspace_id": s.workspaceID, "workspace_member_id": stringOr(data, "id", newID())},
+		"first_name":    stringField(data, "first_name"),
+		"last_name":     stringField(data, "last_name"),
+		"avatar_url":    nil,
+		"email_address": stringField(data, "email_address"),
+		"created_at":    timestamp(),
+		"access_level":  stringOr(data, "access_level", "admin"),
+	}
+	s.members = append(s.members, m)
+	return m
+}
+
+func stringField(m map[string]any, key string) string {
+	s, _ := m[key].(string)
+	return strings.TrimSpace(s)
+}
+
+func stringOr(m map[string]any, key string, def string) string {
+	if s := stringField(m, key); s != "" {
+		return s
+	}
+	return def
+}
+
+func boolField(m map[string]any, key string) bool {
+	switch v := m[key].(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	}
+	return false
+}
+
+// merge copies the keys of patch that appear in allowed onto dst.
+func merge(dst map[string]any, patch map[string]any, allowed ...string) {
+	for _, key := range allowed {
+		if v, ok := patch[key]; ok {
+			dst[key] = v
+		}
+	}
+}
@@ -0,0 +1,506 @@
+package mockserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Write modes for writeValues. Replace overwrites multiselect attributes
+// (create and PUT); append adds to them (PATCH and assert).
+const (
+	writeReplace = iota
+	writeAppend
+)
+
+// primaryProperty is the value property compared by shorthand filters,
+// matched for uniqueness and used for sorting.
+var primaryProperty = map[string]string{
+	"currency":         "currency_value",
+	"domain":           "domain",
+	"email-address":    "email_address",
+	"phone-number":     "phone_number",
+	"personal-name":    "full_name",
+	"select":           "option",
+	"status":           "status",
+	"record-reference": "target_record_id",
+	"actor-reference":  "referenced_actor_id",
+	"location":         "line_1",
+	"interaction":      "interacted_at",
+}
+
+func propertyFor(typ string) string {
+	if prop, ok := primaryProperty[typ]; ok {
+		return prop
+	}
+	return "value"
+}
+
+// activeValues returns the current values of attr on rec.
+func (rec *record) activeValues(attr *attribute) []map[string]any {
+	out := []map[string]any{}
+	for _, v := range rec.values[attr.id()] {
+		if v["active_until"] == nil {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// writeValues validates input (attribute slug or ID to value(s)) and applies
+// it to rec. Nothing is written if any value is invalid.
+func (s *store) writeValues(rec *record, input map[string]any, mode int) error {
+	type change struct {
+		attr   *attribute
+		values []map[string]any
+	}
+	changes := make([]change, 0, len(input))
+	for key, raw := range input {
+		attr := rec.parent.attribute(key)
+		if attr == nil {
+			return badRequest("Cannot find attribute with slug/ID %q on %s %q.", key, strings.TrimSuffix(rec.parent.kind, "s"), rec.parent.slug())
+		}
+		if !attr.flag("is_writable") {
+			return badRequest("Attribute %q is not writable.", attr.slug())
+		}
+		values, err := s.normalizeValues(attr, raw)
+		if err != nil {
+			return err
+		}
+		if len(values) > 1 && !attr.flag("is_multiselect") {
+			return badRequest("Attribute %q accepts a single value, got %d.", attr.slug(), len(values))
+		}
+		if attr.flag("is_unique") {
+			if err := s.checkUnique(rec, attr, values); err != nil {
+				return err
+			}
+		}
+		changes = append(changes, change{attr: attr, values: values})
+	}
+
+	now := timestamp()
+	for _, c := range changes {
+		active := map[string]bool{}
+		for _, v := range rec.activeValues(c.attr) {
+			active[valueKey(c.attr.typ(), v)] = true
+		}
+		if mode == writeAppend && c.attr.flag("is_multiselect") {
+			for _, v := range c.values {
+				if key := valueKey(c.attr.typ(), v); !active[key] {
+					active[key] = true
+					rec.values[c.attr.id()] = append(rec.values[c.attr.id()], stamp(v, c.attr, now, s.apiActor()))
+				}
+			}
+			continue
+		}
+		if sameKeys(c.attr.typ(), c.values, active) {
+			continue
+		}
+		for _, v := range rec.values[c.attr.id()] {
+			if v["active_until"] == nil {
+				v["active_until"] = now
+			}
+		}
+		for _, v := range c.values {
+			rec.values[c.attr.id()] = append(rec.values[c.attr.id()], stamp(v, c.attr, now, s.apiActor()))
+		}
+	}
+	return nil
+}
+
+func sameKeys(typ string, values []map[string]any, active map[string]bool) bool {
+	if len(values) != len(active) {
+		return false
+	}
+	for _, v := range values {
+		if !active[valueKey(typ, v)] {
+			return false
+		}
+	}
+	return true
+}
+
+func stamp(v map[string]any, attr *attribute, now string, actor map[string]any) map[string]any {
+	v["active_from"] = now
+	v["active_until"] = nil
+	v["created_by_actor"] = actor
+	v["attribute_type"] = attr.typ()
+	return v
+}
+
+func (s *store) checkUnique(rec *record, attr *attribute, values []map[string]any) error {
+	rows := s.records[rec.parent.id]
+	if rec.parent.kind == "lists" {
+		rows = s.entries[rec.parent.id]
+	}
+	for _, v := range values {
+		key := valueKey(attr.typ(), v)
+		for _, other := range rows {
+			if other.id == rec.id {
+				continue
+			}
+			for _, existing := range other.activeValues(attr) {
+				if valueKey(attr.typ(), existing) == key {
+					return errorf(http.StatusBadRequest, "uniqueness_conflict", "Uniqueness constraint violated: another record already has %q for attribute %q.", key, attr.slug())
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkRequired rejects rows missing a required attribute.
+func (s *store) checkRequired(rec *record) error {
+	for _, attr := range rec.parent.attributes {
+		if attr.flag("is_required") && !attr.flag("is_archived") && len(rec.activeValues(attr)) == 0 {
+			return badRequest("Required attribute %q is missing a value.", attr.slug())
+		}
+	}
+	return nil
+}
+
+func (s *store) normalizeValues(attr *attribute, raw any) ([]map[string]any, error) {
+	var items []any
+	switch v := raw.(type) {
+	case nil:
+	case []any:
+		items = v
+	default:
+		items = []any{v}
+	}
+	out := make([]map[string]any, 0, len(items))
+	for _, item := range items {
+		v, err := s.normalizeValue(attr, item)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+// normalizeValue converts one input value (shorthand or full object) to the
+// stored output representation for attr's type.
+func (s *store) normalizeValue(attr *attribute, item any) (map[string]any, error) {
+	obj, isObj := item.(map[string]any)
+	invalid := func(format string, args ...any) error {
+		return badRequest("Invalid value for attribute %q: %s", attr.slug(), fmt.Sprintf(format, args...))
+	}
+	// unwrap returns the scalar for {"value": x} or the item itself.
+	unwrap := func() any {
+		if isObj {
+			return obj["value"]
+		}
+		return item
+	}
+
+	switch attr.typ() {
+	case "text", "date", "timestamp":
+		str, ok := unwrap().(string)
+		if !ok {
+			return nil, invalid("expected a string")
+		}
+		if attr.typ() == "date" && len(str) > 10 {
+			str = str[:10]
+		}
+		return map[string]any{"value": str}, nil
+	case "number", "rating":
+		n, ok := toFloat(unwrap())
+		if !ok {
+			return nil, invalid("expected a number")
+		}
+		if attr.typ() == "rating" && (n < 0 || n > 5) {
+			return nil, invalid("ratings must be between 0 and 5")
+		}
+		return map[string]any{"value": n}, nil
+	case "checkbox":
+		switch b := unwrap().(type) {
+		case bool:
+			return map[string]any{"value": b}, nil
+		case string:
+			if parsed, err := strconv.ParseBool(b); err == nil {
+				return map[string]any{"value": parsed}, nil
+			}
+		}
+		return nil, invalid("expected a boolean")
+	case "currency":
+		raw := item
+		code := ""
+		if isObj {
+			raw = obj["currency_value"]
+			code, _ = obj["currency_code"].(string)
+		}
+		n, ok := toFloat(raw)
+		if !ok {
+			return nil, invalid("expected a number or currency_value")
+		}
+		if code == "" {
+			code = defaultCurrency(attr)
+		}
+		return map[string]any{"currency_value": n, "currency_code": code}, nil
+	case "domain":
+		str := stringValue(item, obj, "domain")
+		if str == "" {
+			return nil, invalid("expected a domain")
+		}
+		str = strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(str, "https://"), "http://"))
+		str = strings.TrimPrefix(strings.TrimSuffix(str, "/"), "www.")
+		return map[string]any{"domain": str, "root_domain": rootDomain(str)}, nil
+	case "email-address":
+		str := stringValue(item, obj, "email_address")
+		local, domain, ok := strings.Cut(strings.ToLower(str), "@")
+		if !ok || local == "" || domain == "" {
+			return nil, invalid("%q is not an email address", str)
+		}
+		return map[string]any{
+			"original_email_address": str,
+			"email_address":          local + "@" + domain,
+			"email_domain":           domain,
+			"email_root_domain":      rootDomain(domain),
+			"email_local_specifier":  local,
+		}, nil
+	case "phone-number":
+		str := stringValue(item, obj, "original_phone_number")
+		if str == "" {
+			str = stringValue(item, obj, "phone_number")
+		}
+		if str == "" {
+			return nil, invalid("expected a phone number")
+		}
+		country, _ := obj["country_code"].(string)
+		normalized := strings.Map(func(r rune) rune {
+			if r == '+' || (r >= '0' && r <= '9') {
+				return r
+			}
+			return -1
+		}, str)
+		return map[string]any{"original_phone_number": str, "phone_number": normalized, "country_code": nilIfEmpty(country)}, nil
+	case "personal-name":
+		first, last, full := "", "", ""
+		if isObj {
+			first, _ = obj["first_name"].(string)
+			last, _ = obj["last_name"].(string)
+			full, _ = obj["full_name"].(string)
+		} else if str, ok := item.(string); ok {
+			full = strings.TrimSpace(str)
+			first, last, _ = strings.Cut(full, " ")
+		} else {
+			return nil, invalid("expected a name string or object")
+		}
+		if full == "" {
+			full = strings.TrimSpace(first + " " + last)
+		}
+		return map[string]any{"first_name": first, "last_name": last, "full_name": full}, nil
+	case "select", "status":
+		key, idKey, options := "option", "option_id", attr.options
+		if attr.typ() == "status" {
+			key, idKey, options = "status", "status_id", attr.statuses
+		}
+		ref := stringValue(item, obj, key)
+		opt := findOption(options, idKey, ref)
+		if opt == nil || boolField(opt, "is_archived") {
+			return nil, invalid("no %s named %q", key, ref)
+		}
+		return map[string]any{key: opt}, nil
+	case "record-reference":
+		return s.normalizeReference(attr, item, obj, invalid)
+	case "actor-reference":
+		ref := stringValue(item, obj, "referenced_actor_id")
+		if ref == "" && isObj {
+			ref, _ = obj["workspace_member_email_address"].(string)
+		}
+		m := s.member(ref)
+		if m == nil {
+			return nil, invalid("no workspace member %q", ref)
+		}
+		id, _ := m["id"].(map[string]any)
+		return map[string]any{"referenced_actor_type": "workspace-member", "referenced_actor_id": id["workspace_member_id"]}, nil
+	case "location":
+		if str, ok := item.(string); ok {
+			return map[string]any{"line_1": str}, nil
+		}
+		if !isObj {
+			return nil, invalid("expected a location object")
+		}
+		return copyMap(obj), nil
+	}
+	if isObj {
+		return copyMap(obj), nil
+	}
+	return map[string]any{"value": item}, nil
+}
+
+func (s *store) normalizeReference(attr *attribute, item any, obj map[string]any, invalid func(string, ...any) error) (map[string]any, error) {
+	targetObject, recordID := "", ""
+	var matchAttr string
+	var matchValue any
+	switch {
+	case obj == nil:
+		recordID, _ = item.(string)
+	default:
+		targetObject, _ = obj["target_object"].(string)
+		recordID, _ = obj["target_record_id"].(string)
+		if recordID == "" {
+			for key, v := range obj {
+				if key != "target_object" && key != "target_record_id" {
+					matchAttr, matchValue = key, v
+				}
+			}
+		}
+	}
+
+	candidates := s.objects
+	if targetObject != "" {
+		target, err := s.object(targetObject)
+		if err != nil {
+			return nil, invalid("%v", err)
+		}
+		candidates = []*container{target}
+	}
+	allowed := allowedObjects(attr)
+	for _, target := range candidates {
+		if len(allowed) > 0 && !allowed[target.slug()] && !allowed[target.id] {
+			continue
+		}
+		for _, rec := range s.records[target.id] {
+			if recordID != "" && rec.id == recordID {
+				return map[string]any{"target_object": target.slug(), "target_record_id": rec.id}, nil
+			}
+			if matchAttr != "" && s.rowMatchesValue(rec, matchAttr, matchValue) {
+				return map[string]any{"target_object": target.slug(), "target_record_id": rec.id}, nil
+			}
+		}
+	}
+	if matchAttr != "" {
+		return nil, invalid("no %s record with %s %v", targetObject, matchAttr, matchValue)
+	}
+	return nil, invalid("record %q not found", recordID)
+}
+
+// rowMatchesValue reports whether rec currently has raw (input form) for attr.
+func (s *store) rowMatchesValue(rec *record, attrRef string, raw any) bool {
+	attr := rec.parent.attribute(attrRef)
+	if attr == nil {
+		return false
+	}
+	values, err := s.normalizeValues(attr, raw)
+	if err != nil || len(values) == 0 {
+		return false
+	}
+	want := valueKey(attr.typ(), values[0])
+	for _, v := range rec.activeValues(attr) {
+		if valueKey(attr.typ(), v) == want {
+			return true
+		}
+	}
+	return false
+}
+
+func allowedObjects(attr *attribute) map[string]bool {
+	config, _ := attr.data["config"].(map[string]any)
+	ref, _ := config["record_reference"].(map[string]any)
+	out := map[string]bool{}
+	for _, key := range []string{"allowed_objects", "allowed_object_ids"} {
+		list, _ := ref[key].([]any)
+		for _, v := range list {
+			if s, ok := v.(string); ok {
+				out[s] = true
+			}
+		}
+	}
+	return out
+}
+
+func defaultCurrency(attr *attribute) string {
+	config, _ := attr.data["config"].(map[string]any)
+	currency, _ := config["currency"].(map[string]any)
+	if code, ok := currency["default_currency_code"].(string); ok && code != "" {
+		return code
+	}
+	return "USD"
+}
+
+// valueKey identifies a value for uniqueness, deduplication and matching.
+func valueKey(typ string, v map[string]any) string {
+	cands := propertyCandidates(v, typ, "")
+	if len(cands) == 0 || cands[0] == nil {
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+	return strings.ToLower(fmt.Sprint(cands[0]))
+}
+
+// propertyCandidates returns the comparable forms of a value's property
+// (its primary property when prop is empty). Select options and statuses
+// match on both title and ID.
+func propertyCandidates(v map[string]any, typ string, prop string) []any {
+	if prop == "" {
+		prop = propertyFor(typ)
+	}
+	if typ == "select" || typ == "status" {
+		nested, _ := v[propertyFor(typ)].(map[string]any)
+		id, _ := nested["id"].(map[string]any)
+		switch prop {
+		case "option", "status", "title":
+			return []any{nested["title"], id["option_id"], id["status_id"]}
+		case "id", "option_id", "status_id":
+			return []any{id["option_id"], id["status_id"]}
+		}
+	}
+	return []any{v[prop]}
+}
+
+func stringValue(item any, obj map[string]any, key string) string {
+	if obj != nil {
+		switch v := obj[key].(type) {
+		case string:
+			return strings.TrimSpace(v)
+		case map[string]any:
+			return stringField(v, "title")
+		}
+		return ""
+	}
+	str, _ := item.(string)
+	return strings.TrimSpace(str)
+}
+
+func rootDomain(domain string) string {
+	parts := strings.Split(domain, ".")
+	if len(parts) <= 2 {
+		return domain
+	}
+	return strings.Join(parts[len(parts)-2:], ".")
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func nilIfEmpty(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func copyMap(m map[string]any) map[string]any {
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,10 @@
+// Package attiocli holds repository assets that are embedded in the attio
+// binary.
+package attiocli
+
+import _ "embed"
+
+// OpenAPISpec is the Attio REST API description bundled with the CLI.
+//
+//go:embed openapi.json
+var OpenAPISpec []byte